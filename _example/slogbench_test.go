@@ -0,0 +1,86 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/gookit/slog"
+	"github.com/gookit/slog/slogbench"
+	"github.com/rs/zerolog"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// In _example/ dir, run:
+//
+//	go test -v -cpu=4 -run=none -bench=Scenarios -benchmem slogbench_test.go
+
+func BenchmarkScenariosGookitSlog(b *testing.B) {
+	slogbench.Run(b, slogbench.Gookit)
+}
+
+func BenchmarkScenariosZap(b *testing.B) {
+	slogbench.Run(b, func(opt slogbench.Options) slogbench.Logger {
+		cfg := zap.NewProductionEncoderConfig()
+		enc := zapcore.NewConsoleEncoder(cfg)
+		if opt.JSON {
+			enc = zapcore.NewJSONEncoder(cfg)
+		}
+
+		level := zapcore.InfoLevel
+		if opt.Disabled {
+			level = zapcore.ErrorLevel
+		}
+
+		logger := zap.New(zapcore.NewCore(enc, zapcore.AddSync(opt.Output), level))
+		if opt.Caller {
+			logger = logger.WithOptions(zap.AddCaller())
+		}
+		return &zapLogger{logger}
+	})
+}
+
+type zapLogger struct {
+	l *zap.Logger
+}
+
+func (z *zapLogger) Info(msg string, fields []slog.Field) {
+	zfs := make([]zap.Field, len(fields))
+	for i, field := range fields {
+		zfs[i] = zap.Any(field.Key, field.Value())
+	}
+	z.l.Info(msg, zfs...)
+}
+
+func BenchmarkScenariosZeroLog(b *testing.B) {
+	slogbench.Run(b, func(opt slogbench.Options) slogbench.Logger {
+		var ctx zerolog.Context
+		if opt.JSON {
+			ctx = zerolog.New(opt.Output).With()
+		} else {
+			ctx = zerolog.New(zerolog.ConsoleWriter{Out: opt.Output, NoColor: true}).With()
+		}
+
+		ctx = ctx.Timestamp()
+		if opt.Caller {
+			ctx = ctx.Caller()
+		}
+
+		level := zerolog.InfoLevel
+		if opt.Disabled {
+			level = zerolog.ErrorLevel
+		}
+		return &zeroLogger{ctx.Logger().Level(level)}
+	})
+}
+
+type zeroLogger struct {
+	l zerolog.Logger
+}
+
+func (z *zeroLogger) Info(msg string, fields []slog.Field) {
+	e := z.l.Info()
+	for _, field := range fields {
+		e = e.Interface(field.Key, field.Value())
+	}
+	e.Msg(msg)
+}
@@ -0,0 +1,47 @@
+// Package adapter provide adapters implementing the logger interfaces expected by
+// popular libraries, so their internal logging routes through slog.
+//
+// The adapters only depend on the interface method sets, not on the libraries.
+package adapter
+
+import (
+	"fmt"
+
+	"github.com/gookit/slog"
+)
+
+// BadKey the field key for the unpaired value in keysAndValues
+const BadKey = "!BADKEY"
+
+// KVToFields convert the keysAndValues list to slog fields.
+//
+// eg: ["key1", val1, "key2", val2] => {"key1": val1, "key2": val2}
+func KVToFields(keysAndValues []any) slog.M {
+	if len(keysAndValues) == 0 {
+		return nil
+	}
+
+	fields := make(slog.M, len(keysAndValues)/2+1)
+	for i := 0; i < len(keysAndValues); i += 2 {
+		if i+1 >= len(keysAndValues) {
+			fields[BadKey] = keysAndValues[i]
+			break
+		}
+
+		key, ok := keysAndValues[i].(string)
+		if !ok {
+			key = fmt.Sprint(keysAndValues[i])
+		}
+		fields[key] = keysAndValues[i+1]
+	}
+	return fields
+}
+
+// log message with keysAndValues
+func logKV(l *slog.Logger, level slog.Level, msg string, keysAndValues []any) {
+	if fields := KVToFields(keysAndValues); len(fields) > 0 {
+		l.WithFields(fields).Log(level, msg)
+	} else {
+		l.Log(level, msg)
+	}
+}
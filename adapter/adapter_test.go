@@ -0,0 +1,104 @@
+package adapter_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/gookit/goutil/byteutil"
+	"github.com/gookit/goutil/testutil/assert"
+	"github.com/gookit/slog"
+	"github.com/gookit/slog/adapter"
+	"github.com/gookit/slog/handler"
+)
+
+func newTestLogger() (*slog.Logger, *byteutil.Buffer) {
+	buf := byteutil.NewBuffer()
+	h := handler.NewIOWriter(buf, slog.AllLevels)
+	h.SetFormatter(slog.NewJSONFormatter())
+	return slog.NewWithHandlers(h), buf
+}
+
+func TestKVToFields(t *testing.T) {
+	assert.Nil(t, adapter.KVToFields(nil))
+	assert.Eq(t, slog.M{"a": 1, "2": "b"}, adapter.KVToFields([]any{"a", 1, 2, "b"}))
+	assert.Eq(t, slog.M{"a": 1, adapter.BadKey: "c"}, adapter.KVToFields([]any{"a", 1, "c"}))
+}
+
+func TestLeveledLogger(t *testing.T) {
+	l, buf := newTestLogger()
+	ll := adapter.NewLeveledLogger(l)
+
+	ll.Debug("performing request", "method", "GET", "url", "http://example.com")
+	assert.StrContains(t, buf.String(), `"level":"DEBUG"`)
+	assert.StrContains(t, buf.String(), `"method":"GET"`)
+
+	buf.Reset()
+	ll.Error("request failed", "error", "timeout")
+	assert.StrContains(t, buf.String(), `"level":"ERROR"`)
+	assert.StrContains(t, buf.String(), `"error":"timeout"`)
+
+	buf.Reset()
+	ll.Warn("retrying")
+	ll.Info("done")
+	assert.StrContains(t, buf.String(), `"message":"retrying"`)
+	assert.StrContains(t, buf.String(), `"level":"INFO"`)
+
+	var rl adapter.RestyLogger = l
+	buf.Reset()
+	rl.Warnf("resty %s", "warn")
+	assert.StrContains(t, buf.String(), `"message":"resty warn"`)
+}
+
+func TestFieldsLogger(t *testing.T) {
+	l, buf := newTestLogger()
+	fl := adapter.NewFieldsLogger(l).With(map[string]any{"topic": "orders"})
+
+	fl.Info("message published", map[string]any{"uuid": "abc"})
+	assert.StrContains(t, buf.String(), `"level":"INFO"`)
+	assert.StrContains(t, buf.String(), `"topic":"orders"`)
+	assert.StrContains(t, buf.String(), `"uuid":"abc"`)
+
+	buf.Reset()
+	fl.Error("publish failed", errors.New("broker down"), nil)
+	assert.StrContains(t, buf.String(), `"error":"broker down"`)
+	assert.StrContains(t, buf.String(), `"topic":"orders"`)
+
+	buf.Reset()
+	fl.Debug("debug message", nil)
+	fl.Trace("trace message", nil)
+	assert.StrContains(t, buf.String(), `"level":"TRACE"`)
+}
+
+func TestStdLogger(t *testing.T) {
+	l, buf := newTestLogger()
+	sl := adapter.NewStdLogger(l, slog.DebugLevel)
+
+	sl.Println("client/metadata fetching metadata")
+	assert.StrContains(t, buf.String(), `"level":"DEBUG","message":"client/metadata fetching metadata"`)
+
+	buf.Reset()
+	sl.Printf("connected to broker at %s\n", "localhost:9092")
+	sl.Print("closed")
+	assert.StrContains(t, buf.String(), `"message":"connected to broker at localhost:9092"`)
+	assert.StrContains(t, buf.String(), `"message":"closed"`)
+}
+
+func TestKgoLogger(t *testing.T) {
+	l, buf := newTestLogger()
+	kl := adapter.NewKgoLogger(l)
+	assert.Eq(t, adapter.KgoLevelInfo, kl.Level())
+
+	kl.MaxLevel = slog.ErrorLevel
+	assert.Eq(t, adapter.KgoLevelError, kl.Level())
+	kl.MaxLevel = slog.TraceLevel
+	assert.Eq(t, adapter.KgoLevelDebug, kl.Level())
+
+	kl.Log(adapter.KgoLevelWarn, "unable to fetch", "broker", 1, "err", "EOF")
+	assert.StrContains(t, buf.String(), `"level":"WARN"`)
+	assert.StrContains(t, buf.String(), `"broker":1`)
+
+	buf.Reset()
+	kl.Log(adapter.KgoLevelNone, "none")
+	assert.Empty(t, buf.String())
+	assert.Eq(t, slog.DebugLevel, adapter.KgoToLevel(adapter.KgoLevelDebug))
+}
@@ -0,0 +1,77 @@
+package adapter
+
+import "github.com/gookit/slog"
+
+// FieldsLogger an adapter for message-bus logger interfaces, which log message with field maps.
+// eg: the watermill LoggerAdapter:
+//
+//	type LoggerAdapter interface {
+//		Error(msg string, err error, fields LogFields)
+//		Info(msg string, fields LogFields)
+//		Debug(msg string, fields LogFields)
+//		Trace(msg string, fields LogFields)
+//		With(fields LogFields) LoggerAdapter
+//	}
+//
+// The watermill.LogFields is a named map type, so need a thin wrapper for implements it:
+//
+//	type wmLogger struct{ *adapter.FieldsLogger }
+//
+//	func (w wmLogger) Error(msg string, err error, fields watermill.LogFields) { w.FieldsLogger.Error(msg, err, fields) }
+//	func (w wmLogger) Info(msg string, fields watermill.LogFields) { w.FieldsLogger.Info(msg, fields) }
+//	func (w wmLogger) Debug(msg string, fields watermill.LogFields) { w.FieldsLogger.Debug(msg, fields) }
+//	func (w wmLogger) Trace(msg string, fields watermill.LogFields) { w.FieldsLogger.Trace(msg, fields) }
+//	func (w wmLogger) With(fields watermill.LogFields) watermill.LoggerAdapter {
+//		return wmLogger{w.FieldsLogger.With(fields)}
+//	}
+type FieldsLogger struct {
+	l *slog.Logger
+	// fields append to all records
+	fields slog.M
+}
+
+// NewFieldsLogger create a FieldsLogger
+func NewFieldsLogger(l *slog.Logger) *FieldsLogger {
+	return &FieldsLogger{l: l}
+}
+
+// With returns a new FieldsLogger with the fields
+func (a *FieldsLogger) With(fields map[string]any) *FieldsLogger {
+	return &FieldsLogger{l: a.l, fields: a.merge(fields)}
+}
+
+// Error log message with error and fields
+func (a *FieldsLogger) Error(msg string, err error, fields map[string]any) {
+	fs := a.merge(fields)
+	if err != nil {
+		fs[slog.FieldKeyError] = err.Error()
+	}
+	a.l.WithFields(fs).Error(msg)
+}
+
+// Info log message with fields
+func (a *FieldsLogger) Info(msg string, fields map[string]any) {
+	a.l.WithFields(a.merge(fields)).Info(msg)
+}
+
+// Debug log message with fields
+func (a *FieldsLogger) Debug(msg string, fields map[string]any) {
+	a.l.WithFields(a.merge(fields)).Debug(msg)
+}
+
+// Trace log message with fields
+func (a *FieldsLogger) Trace(msg string, fields map[string]any) {
+	a.l.WithFields(a.merge(fields)).Trace(msg)
+}
+
+// merge fields to a new map
+func (a *FieldsLogger) merge(fields map[string]any) slog.M {
+	fs := make(slog.M, len(a.fields)+len(fields)+1)
+	for k, v := range a.fields {
+		fs[k] = v
+	}
+	for k, v := range fields {
+		fs[k] = v
+	}
+	return fs
+}
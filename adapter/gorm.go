@@ -0,0 +1,128 @@
+package adapter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gookit/slog"
+)
+
+// levels of the gorm logger.LogLevel
+const (
+	GormSilent int = iota + 1
+	GormError
+	GormWarn
+	GormInfo
+)
+
+// GormLogger for the gorm.io/gorm/logger.Interface, log the sql through slog.
+//
+// The Info, Warn, Error and Trace methods are compatible with gorm logger.Interface.
+// The LogMode use named types, so need a thin wrapper for implements it:
+//
+//	type gormLogger struct{ *adapter.GormLogger }
+//
+//	func (g gormLogger) LogMode(level logger.LogLevel) logger.Interface {
+//		return gormLogger{g.GormLogger.LogMode(int(level))}
+//	}
+//
+//	db, err := gorm.Open(dialector, &gorm.Config{
+//		Logger: gormLogger{adapter.NewGormLogger(l, func(g *adapter.GormLogger) {
+//			g.NotFoundErr = gorm.ErrRecordNotFound
+//		})},
+//	})
+type GormLogger struct {
+	l *slog.Logger
+	// Level the gorm log level. default is GormWarn
+	Level int
+	// SlowThreshold log the sql at WarnLevel on elapsed >= SlowThreshold. 0 is disable.
+	SlowThreshold time.Duration
+	// TraceLevel the slog level for echo all sql on Level=GormInfo. default is slog.TraceLevel
+	TraceLevel slog.Level
+	// NotFoundErr the gorm.ErrRecordNotFound.
+	// if is not nil, will not log error on the sql error is it.
+	NotFoundErr error
+}
+
+// NewGormLogger create a GormLogger
+func NewGormLogger(l *slog.Logger, fns ...func(g *GormLogger)) *GormLogger {
+	g := &GormLogger{
+		l:             l,
+		Level:         GormWarn,
+		SlowThreshold: 200 * time.Millisecond,
+		TraceLevel:    slog.TraceLevel,
+	}
+
+	for _, fn := range fns {
+		fn(g)
+	}
+	return g
+}
+
+// LogMode returns a new GormLogger with the gorm log level
+func (g *GormLogger) LogMode(level int) *GormLogger {
+	ng := *g
+	ng.Level = level
+	return &ng
+}
+
+// Info log message
+func (g *GormLogger) Info(ctx context.Context, msg string, data ...any) {
+	if g.Level >= GormInfo {
+		g.l.WithContext(ctx).Info(fmt.Sprintf(msg, data...))
+	}
+}
+
+// Warn log message
+func (g *GormLogger) Warn(ctx context.Context, msg string, data ...any) {
+	if g.Level >= GormWarn {
+		g.l.WithContext(ctx).Warn(fmt.Sprintf(msg, data...))
+	}
+}
+
+// Error log message
+func (g *GormLogger) Error(ctx context.Context, msg string, data ...any) {
+	if g.Level >= GormError {
+		g.l.WithContext(ctx).Error(fmt.Sprintf(msg, data...))
+	}
+}
+
+// Trace log the sql, rows affected and elapsed time.
+func (g *GormLogger) Trace(ctx context.Context, begin time.Time, fc func() (sql string, rowsAffected int64), err error) {
+	if g.Level <= GormSilent {
+		return
+	}
+
+	elapsed := slog.Since(begin)
+	isErr := err != nil && (g.NotFoundErr == nil || !errors.Is(err, g.NotFoundErr))
+	isSlow := g.SlowThreshold > 0 && elapsed >= g.SlowThreshold
+
+	var level slog.Level
+	switch {
+	case isErr && g.Level >= GormError:
+		level = slog.ErrorLevel
+	case isSlow && g.Level >= GormWarn:
+		level = slog.WarnLevel
+	case g.Level >= GormInfo:
+		level = g.TraceLevel
+	default:
+		return
+	}
+
+	sql, rows := fc()
+	fields := slog.M{
+		"sql":      sql,
+		"rows":     rows,
+		"duration": elapsed.String(),
+	}
+	if isErr {
+		fields[slog.FieldKeyError] = err.Error()
+	}
+	if isSlow {
+		fields["slow"] = true
+	}
+
+	g.l.WithFields(fields).WithContext(ctx).Log(level, "gorm sql")
+}
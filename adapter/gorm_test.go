@@ -0,0 +1,62 @@
+package adapter_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/gookit/goutil/testutil/assert"
+	"github.com/gookit/slog/adapter"
+)
+
+func TestGormLogger(t *testing.T) {
+	l, buf := newTestLogger()
+	errNotFound := errors.New("record not found")
+
+	gl := adapter.NewGormLogger(l, func(g *adapter.GormLogger) {
+		g.SlowThreshold = 10 * time.Millisecond
+		g.NotFoundErr = errNotFound
+	})
+	ctx := context.Background()
+	fc := func() (string, int64) { return "SELECT * FROM users", 2 }
+
+	// default level is warn
+	gl.Info(ctx, "info %s", "message")
+	gl.Trace(ctx, time.Now(), fc, nil)
+	assert.Empty(t, buf.String())
+
+	gl.Warn(ctx, "warn %s", "message")
+	assert.StrContains(t, buf.String(), `"message":"warn message"`)
+
+	// slow sql
+	buf.Reset()
+	gl.Trace(ctx, time.Now().Add(-20*time.Millisecond), fc, nil)
+	assert.StrContains(t, buf.String(), `"level":"WARN"`)
+	assert.StrContains(t, buf.String(), `"slow":true`)
+	assert.StrContains(t, buf.String(), `"rows":2`)
+
+	// error, ignore not found
+	buf.Reset()
+	gl.Trace(ctx, time.Now(), fc, errNotFound)
+	assert.Empty(t, buf.String())
+	gl.Trace(ctx, time.Now(), fc, errors.New("syntax error"))
+	assert.StrContains(t, buf.String(), `"level":"ERROR"`)
+	assert.StrContains(t, buf.String(), `"error":"syntax error"`)
+
+	// info level: echo all sql
+	buf.Reset()
+	il := gl.LogMode(adapter.GormInfo)
+	il.Trace(ctx, time.Now(), fc, nil)
+	il.Info(ctx, "info message")
+	assert.StrContains(t, buf.String(), `"level":"TRACE"`)
+	assert.StrContains(t, buf.String(), `"sql":"SELECT * FROM users"`)
+	assert.StrContains(t, buf.String(), `"message":"info message"`)
+
+	// silent
+	buf.Reset()
+	sl := gl.LogMode(adapter.GormSilent)
+	sl.Trace(ctx, time.Now(), fc, errors.New("syntax error"))
+	sl.Error(ctx, "error message")
+	assert.Empty(t, buf.String())
+}
@@ -0,0 +1,66 @@
+package adapter
+
+import "github.com/gookit/slog"
+
+// LeveledLogger implements the hashicorp/go-retryablehttp LeveledLogger interface:
+//
+//	type LeveledLogger interface {
+//		Error(msg string, keysAndValues ...interface{})
+//		Info(msg string, keysAndValues ...interface{})
+//		Debug(msg string, keysAndValues ...interface{})
+//		Warn(msg string, keysAndValues ...interface{})
+//	}
+//
+// Usage:
+//
+//	client := retryablehttp.NewClient()
+//	client.Logger = adapter.NewLeveledLogger(logger)
+type LeveledLogger struct {
+	l *slog.Logger
+}
+
+// NewLeveledLogger create a LeveledLogger
+func NewLeveledLogger(l *slog.Logger) *LeveledLogger {
+	return &LeveledLogger{l: l}
+}
+
+// Error log message with keysAndValues
+func (a *LeveledLogger) Error(msg string, keysAndValues ...any) {
+	logKV(a.l, slog.ErrorLevel, msg, keysAndValues)
+}
+
+// Warn log message with keysAndValues
+func (a *LeveledLogger) Warn(msg string, keysAndValues ...any) {
+	logKV(a.l, slog.WarnLevel, msg, keysAndValues)
+}
+
+// Info log message with keysAndValues
+func (a *LeveledLogger) Info(msg string, keysAndValues ...any) {
+	logKV(a.l, slog.InfoLevel, msg, keysAndValues)
+}
+
+// Debug log message with keysAndValues
+func (a *LeveledLogger) Debug(msg string, keysAndValues ...any) {
+	logKV(a.l, slog.DebugLevel, msg, keysAndValues)
+}
+
+// RestyLogger the go-resty/resty Logger interface.
+//
+// The *slog.Logger is implemented it, can be used directly:
+//
+//	client := resty.New().SetLogger(logger)
+type RestyLogger interface {
+	Errorf(format string, v ...any)
+	Warnf(format string, v ...any)
+	Debugf(format string, v ...any)
+}
+
+// PrintfLogger the go-retryablehttp Logger interface, *slog.Logger is implemented it.
+type PrintfLogger interface {
+	Printf(format string, v ...any)
+}
+
+var (
+	_ RestyLogger  = (*slog.Logger)(nil)
+	_ PrintfLogger = (*slog.Logger)(nil)
+)
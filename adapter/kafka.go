@@ -0,0 +1,115 @@
+package adapter
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gookit/slog"
+)
+
+// StdLogger implements the sarama StdLogger interface, with custom log level.
+//
+//	type StdLogger interface {
+//		Print(v ...interface{})
+//		Printf(format string, v ...interface{})
+//		Println(v ...interface{})
+//	}
+//
+// Usage:
+//
+//	sarama.Logger = adapter.NewStdLogger(logger, slog.DebugLevel)
+type StdLogger struct {
+	l     *slog.Logger
+	level slog.Level
+}
+
+// NewStdLogger create a StdLogger
+func NewStdLogger(l *slog.Logger, level slog.Level) *StdLogger {
+	return &StdLogger{l: l, level: level}
+}
+
+// Print log message
+func (a *StdLogger) Print(v ...any) {
+	a.l.Log(a.level, strings.TrimSuffix(fmt.Sprint(v...), "\n"))
+}
+
+// Printf log message
+func (a *StdLogger) Printf(format string, v ...any) {
+	a.l.Log(a.level, strings.TrimSuffix(fmt.Sprintf(format, v...), "\n"))
+}
+
+// Println log message
+func (a *StdLogger) Println(v ...any) {
+	a.l.Log(a.level, strings.TrimSuffix(fmt.Sprintln(v...), "\n"))
+}
+
+// levels of the franz-go kgo.LogLevel
+const (
+	KgoLevelNone int8 = iota
+	KgoLevelError
+	KgoLevelWarn
+	KgoLevelInfo
+	KgoLevelDebug
+)
+
+// KgoLogger for the franz-go kgo.Logger interface.
+//
+//	type Logger interface {
+//		Level() LogLevel
+//		Log(level LogLevel, msg string, keyvals ...any)
+//	}
+//
+// The kgo.LogLevel is a named int8 type, so need a thin wrapper for implements it:
+//
+//	type kgoLogger struct{ *adapter.KgoLogger }
+//
+//	func (k kgoLogger) Level() kgo.LogLevel { return kgo.LogLevel(k.KgoLogger.Level()) }
+//	func (k kgoLogger) Log(level kgo.LogLevel, msg string, keyvals ...any) {
+//		k.KgoLogger.Log(int8(level), msg, keyvals...)
+//	}
+type KgoLogger struct {
+	l *slog.Logger
+	// MaxLevel the max slog level for report to kgo. default is slog.InfoLevel
+	MaxLevel slog.Level
+}
+
+// NewKgoLogger create a KgoLogger
+func NewKgoLogger(l *slog.Logger) *KgoLogger {
+	return &KgoLogger{l: l, MaxLevel: slog.InfoLevel}
+}
+
+// Level returns the kgo level value of the MaxLevel
+func (a *KgoLogger) Level() int8 {
+	switch {
+	case a.MaxLevel >= slog.DebugLevel:
+		return KgoLevelDebug
+	case a.MaxLevel >= slog.InfoLevel:
+		return KgoLevelInfo
+	case a.MaxLevel >= slog.WarnLevel:
+		return KgoLevelWarn
+	case a.MaxLevel >= slog.ErrorLevel:
+		return KgoLevelError
+	}
+	return KgoLevelNone
+}
+
+// Log message with the kgo level and keyvals
+func (a *KgoLogger) Log(level int8, msg string, keyvals ...any) {
+	if level <= KgoLevelNone {
+		return
+	}
+	logKV(a.l, KgoToLevel(level), msg, keyvals)
+}
+
+// KgoToLevel convert kgo level value to slog level
+func KgoToLevel(level int8) slog.Level {
+	switch level {
+	case KgoLevelError:
+		return slog.ErrorLevel
+	case KgoLevelWarn:
+		return slog.WarnLevel
+	case KgoLevelInfo:
+		return slog.InfoLevel
+	}
+	return slog.DebugLevel
+}
@@ -0,0 +1,224 @@
+//go:build go1.21
+
+package adapter
+
+import (
+	"context"
+	stdslog "log/slog"
+	"runtime"
+
+	"github.com/gookit/slog"
+)
+
+// ToStdLevel convert the slog level to the log/slog level
+func ToStdLevel(level slog.Level) stdslog.Level {
+	switch level {
+	case slog.PanicLevel:
+		return stdslog.LevelError + 8
+	case slog.FatalLevel:
+		return stdslog.LevelError + 4
+	case slog.ErrorLevel:
+		return stdslog.LevelError
+	case slog.WarnLevel:
+		return stdslog.LevelWarn
+	case slog.NoticeLevel:
+		return stdslog.LevelInfo + 2
+	case slog.InfoLevel:
+		return stdslog.LevelInfo
+	case slog.DebugLevel:
+		return stdslog.LevelDebug
+	default: // trace
+		return stdslog.LevelDebug - 4
+	}
+}
+
+// FromStdLevel convert the log/slog level to the slog level.
+//
+// NOTICE: the levels > LevelError are converted to slog.ErrorLevel,
+// avoid to exit or panic the process on log by log/slog.
+func FromStdLevel(level stdslog.Level) slog.Level {
+	switch {
+	case level >= stdslog.LevelError:
+		return slog.ErrorLevel
+	case level >= stdslog.LevelWarn:
+		return slog.WarnLevel
+	case level > stdslog.LevelInfo:
+		return slog.NoticeLevel
+	case level >= stdslog.LevelInfo:
+		return slog.InfoLevel
+	case level >= stdslog.LevelDebug:
+		return slog.DebugLevel
+	default:
+		return slog.TraceLevel
+	}
+}
+
+// SlogHandler implements the log/slog.Handler, write the records to the slog.Logger.
+//
+// The attrs are converted to the typed fields(Record.Attrs), the group names are joined to
+// the key with ".". eg: "req.method". the TextFormatter renders them after the {{data}} as "key=value".
+type SlogHandler struct {
+	l *slog.Logger
+	// the preset attrs and group prefix
+	attrs []slog.Field
+	group string
+}
+
+// ToSlogHandler create a log/slog.Handler by the slog.Logger
+//
+// Usage:
+//
+//	stdLogger := log/slog.New(adapter.ToSlogHandler(logger))
+func ToSlogHandler(l *slog.Logger) *SlogHandler {
+	return &SlogHandler{l: l}
+}
+
+// NewStdSlog create a log/slog.Logger, write the records to the slog.Logger
+func NewStdSlog(l *slog.Logger) *stdslog.Logger {
+	return stdslog.New(ToSlogHandler(l))
+}
+
+// Enabled check the level is handled by the logger
+func (h *SlogHandler) Enabled(_ context.Context, level stdslog.Level) bool {
+	return h.l.IsHandling(FromStdLevel(level))
+}
+
+// Handle the log/slog record
+func (h *SlogHandler) Handle(ctx context.Context, sr stdslog.Record) error {
+	r := h.l.Record()
+	r.Ctx = ctx
+	r.Time = sr.Time
+	if sr.PC != 0 {
+		frame, _ := runtime.CallersFrames([]uintptr{sr.PC}).Next()
+		r.SetCaller(&frame)
+	}
+
+	r.Attrs = append(r.Attrs, h.attrs...)
+	sr.Attrs(func(a stdslog.Attr) bool {
+		r.Attrs = appendAttr(r.Attrs, h.group, a)
+		return true
+	})
+
+	r.Log(FromStdLevel(sr.Level), sr.Message)
+	return nil
+}
+
+// WithAttrs returns a new handler with the attrs
+func (h *SlogHandler) WithAttrs(attrs []stdslog.Attr) stdslog.Handler {
+	nh := *h
+	nh.attrs = append([]slog.Field(nil), h.attrs...)
+	for _, a := range attrs {
+		nh.attrs = appendAttr(nh.attrs, h.group, a)
+	}
+	return &nh
+}
+
+// WithGroup returns a new handler with the group name
+func (h *SlogHandler) WithGroup(name string) stdslog.Handler {
+	if name == "" {
+		return h
+	}
+
+	nh := *h
+	nh.group = h.group + name + "."
+	return &nh
+}
+
+// convert the log/slog attr to typed fields, and append to fs.
+func appendAttr(fs []slog.Field, prefix string, a stdslog.Attr) []slog.Field {
+	val := a.Value.Resolve()
+	// ignore empty attr
+	if a.Key == "" && val.Kind() != stdslog.KindGroup {
+		return fs
+	}
+
+	key := prefix + a.Key
+	switch val.Kind() {
+	case stdslog.KindString:
+		return append(fs, slog.String(key, val.String()))
+	case stdslog.KindInt64:
+		return append(fs, slog.Int64(key, val.Int64()))
+	case stdslog.KindUint64:
+		return append(fs, slog.Uint64(key, val.Uint64()))
+	case stdslog.KindFloat64:
+		return append(fs, slog.Float64(key, val.Float64()))
+	case stdslog.KindBool:
+		return append(fs, slog.Bool(key, val.Bool()))
+	case stdslog.KindDuration:
+		return append(fs, slog.Duration(key, val.Duration()))
+	case stdslog.KindTime:
+		return append(fs, slog.Time(key, val.Time()))
+	case stdslog.KindGroup:
+		// inline the group on key is empty
+		if a.Key != "" {
+			prefix = key + "."
+		}
+		for _, ga := range val.Group() {
+			fs = appendAttr(fs, prefix, ga)
+		}
+		return fs
+	default:
+		if err, ok := val.Any().(error); ok {
+			return append(fs, slog.NamedErr(key, err))
+		}
+		return append(fs, slog.Any(key, val.Any()))
+	}
+}
+
+// SlogWrapper wrap a log/slog.Handler as slog.Handler.
+//
+// The Record.Channel is added as attr "channel" on it is not slog.DefaultChannelName,
+// the custom fields are added as attrs. see Record.EachField()
+type SlogWrapper struct {
+	h stdslog.Handler
+}
+
+// FromSlogHandler wrap the log/slog.Handler as slog.Handler
+//
+// Usage:
+//
+//	logger.AddHandler(adapter.FromSlogHandler(log/slog.NewJSONHandler(os.Stdout, nil)))
+func FromSlogHandler(h stdslog.Handler) *SlogWrapper {
+	return &SlogWrapper{h: h}
+}
+
+// IsHandling check the level is enabled by the log/slog.Handler
+func (w *SlogWrapper) IsHandling(level slog.Level) bool {
+	return w.h.Enabled(context.Background(), ToStdLevel(level))
+}
+
+// Handle the record
+func (w *SlogWrapper) Handle(r *slog.Record) error {
+	var pc uintptr
+	if r.Caller != nil {
+		// +1 as the return address, log/slog will -1 on resolve it.
+		pc = r.Caller.PC + 1
+	}
+
+	sr := stdslog.NewRecord(r.Time, ToStdLevel(r.Level), r.Message, pc)
+	if r.Channel != "" && r.Channel != slog.DefaultChannelName {
+		sr.AddAttrs(stdslog.String(slog.FieldKeyChannel, r.Channel))
+	}
+	if len(r.Tags) > 0 {
+		sr.AddAttrs(stdslog.Any(slog.FieldKeyTags, r.Tags))
+	}
+	r.EachField(func(key string, val any) {
+		sr.AddAttrs(stdslog.Any(key, val))
+	})
+
+	ctx := r.Ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return w.h.Handle(ctx, sr)
+}
+
+// Flush do nothing
+func (w *SlogWrapper) Flush() error {
+	return nil
+}
+
+// Close do nothing
+func (w *SlogWrapper) Close() error {
+	return nil
+}
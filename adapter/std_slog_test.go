@@ -0,0 +1,97 @@
+//go:build go1.21
+
+package adapter_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	stdslog "log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gookit/goutil/testutil/assert"
+	"github.com/gookit/slog"
+	"github.com/gookit/slog/adapter"
+	"github.com/gookit/slog/handler"
+)
+
+func TestStdLevel(t *testing.T) {
+	for _, level := range []slog.Level{slog.ErrorLevel, slog.WarnLevel, slog.NoticeLevel,
+		slog.InfoLevel, slog.DebugLevel, slog.TraceLevel} {
+		assert.Eq(t, level, adapter.FromStdLevel(adapter.ToStdLevel(level)))
+	}
+
+	// never exit or panic on log by log/slog
+	assert.Eq(t, slog.ErrorLevel, adapter.FromStdLevel(adapter.ToStdLevel(slog.FatalLevel)))
+	assert.Eq(t, slog.ErrorLevel, adapter.FromStdLevel(adapter.ToStdLevel(slog.PanicLevel)))
+}
+
+func TestToSlogHandler(t *testing.T) {
+	l, buf := newTestLogger()
+	l.ReportCaller = true
+
+	sl := adapter.NewStdSlog(l)
+	assert.True(t, sl.Enabled(context.Background(), stdslog.LevelDebug))
+
+	sl.With("app", "demo").WithGroup("req").Info("std message",
+		"method", "GET",
+		"cost", time.Second,
+		stdslog.Group("user", "id", 23),
+		"error", errors.New("an error"),
+	)
+
+	str := buf.ResetAndGet()
+	assert.StrContains(t, str, `"level":"INFO"`)
+	assert.StrContains(t, str, `"message":"std message"`)
+	assert.StrContains(t, str, `"app":"demo"`)
+	assert.StrContains(t, str, `"req.method":"GET"`)
+	assert.StrContains(t, str, `"req.cost":1000000000`)
+	assert.StrContains(t, str, `"req.user.id":23`)
+	assert.StrContains(t, str, `"req.error":"an error"`)
+	// caller is reported by log/slog
+	assert.StrContains(t, str, `"caller":"std_slog_test.go:`)
+
+	sl.Error("std error")
+	assert.StrContains(t, buf.ResetAndGet(), `"level":"ERROR"`)
+
+	// not enabled level
+	l = slog.NewWithHandlers(handler.NewIOWriter(buf, []slog.Level{slog.ErrorLevel}))
+	assert.False(t, adapter.NewStdSlog(l).Enabled(context.Background(), stdslog.LevelInfo))
+}
+
+func TestToSlogHandler_textFormatter(t *testing.T) {
+	buf := new(bytes.Buffer)
+	sl := slog.NewSugared(buf, slog.DebugLevel)
+
+	adapter.NewStdSlog(sl.Logger).With("app", "demo").Info("std message", "user", "bob", "age", 23)
+	assert.StrContains(t, buf.String(), "std message app=demo user=bob age=23")
+}
+
+func TestFromSlogHandler(t *testing.T) {
+	buf := new(bytes.Buffer)
+	h := adapter.FromSlogHandler(stdslog.NewJSONHandler(buf, &stdslog.HandlerOptions{Level: stdslog.LevelInfo}))
+	assert.False(t, h.IsHandling(slog.DebugLevel))
+	assert.True(t, h.IsHandling(slog.NoticeLevel))
+
+	l := slog.NewWithHandlers(h)
+	l.ChannelName = "order"
+	l.WithField("user", "inhere").WithAttrs(slog.Int("age", 23)).Warn("slog message")
+
+	str := buf.String()
+	assert.StrContains(t, str, `"level":"WARN"`)
+	assert.StrContains(t, str, `"msg":"slog message"`)
+	assert.StrContains(t, str, `"channel":"order"`)
+	assert.StrContains(t, str, `"user":"inhere"`)
+	assert.StrContains(t, str, `"age":23`)
+
+	// no duplicate keys on the same key in fields, attrs and data
+	buf.Reset()
+	l.WithField("user", "inhere").WithAttrs(slog.String("user", "attr")).AddData(slog.M{"user": "data"}).Warn("slog message")
+	str = buf.String()
+	assert.Eq(t, 1, strings.Count(str, `"user":`))
+	assert.StrContains(t, str, `"user":"inhere"`)
+	assert.NoErr(t, h.Flush())
+	assert.NoErr(t, h.Close())
+}
@@ -0,0 +1,159 @@
+package bufwrite
+
+import (
+	"io"
+)
+
+// DefaultAdaptiveMinSize default min buffer size for the AdaptiveWriter
+const DefaultAdaptiveMinSize = 1024 * 4
+
+// AdaptiveWriter is a buffered writer, the buffer size will be grown and shrunk
+// by recent throughput, within min and max bounds.
+//
+//   - buffer is full before flush: the throughput is high, double the buffer size.
+//   - on Flush, less than 1/4 buffer is used: the throughput is low, halve the buffer size.
+//
+// Like the LineWriter, the contents of one Write call are never split across flush boundaries.
+//
+// TIP: please add lock before call the Write, Flush method.
+type AdaptiveWriter struct {
+	err error
+	buf []byte
+	wr  io.Writer
+	// min, max size limit for the buffer
+	minSize int
+	maxSize int
+}
+
+// NewAdaptiveWriter create a new AdaptiveWriter, the buffer size starts from the minSize.
+func NewAdaptiveWriter(w io.Writer, minSize, maxSize int) *AdaptiveWriter {
+	if minSize <= 0 {
+		minSize = DefaultAdaptiveMinSize
+	}
+	if maxSize < minSize {
+		maxSize = minSize
+	}
+
+	return &AdaptiveWriter{
+		wr:      w,
+		buf:     make([]byte, 0, minSize),
+		minSize: minSize,
+		maxSize: maxSize,
+	}
+}
+
+// Size returns the current size of the underlying buffer in bytes.
+func (b *AdaptiveWriter) Size() int { return cap(b.buf) }
+
+// Buffered returns the number of bytes that have been written into the current buffer.
+func (b *AdaptiveWriter) Buffered() int { return len(b.buf) }
+
+// Available returns how many bytes are unused in the buffer.
+func (b *AdaptiveWriter) Available() int { return cap(b.buf) - len(b.buf) }
+
+// Write writes the contents of p into the buffer.
+func (b *AdaptiveWriter) Write(p []byte) (int, error) {
+	if b.err != nil {
+		return 0, b.err
+	}
+
+	if len(p) > b.Available() {
+		// buffer is full, flush and grow it.
+		if err := b.flush(); err != nil {
+			return 0, err
+		}
+		b.resize(cap(b.buf) * 2)
+
+		// still too large, write directly
+		if len(p) > b.Available() {
+			var n int
+			n, b.err = b.wr.Write(p)
+			return n, b.err
+		}
+	}
+
+	b.buf = append(b.buf, p...)
+	return len(p), nil
+}
+
+// WriteString to writer
+func (b *AdaptiveWriter) WriteString(s string) (int, error) {
+	return b.Write([]byte(s))
+}
+
+// Flush writes any buffered data to the underlying io.Writer.
+// will shrink the buffer if the throughput is low.
+func (b *AdaptiveWriter) Flush() error {
+	used := len(b.buf)
+	if err := b.flush(); err != nil {
+		return err
+	}
+
+	if used < cap(b.buf)/4 {
+		b.resize(cap(b.buf) / 2)
+	}
+	return nil
+}
+
+func (b *AdaptiveWriter) flush() error {
+	if b.err != nil {
+		return b.err
+	}
+	if len(b.buf) == 0 {
+		return nil
+	}
+
+	n, err := b.wr.Write(b.buf)
+	if n < len(b.buf) && err == nil {
+		err = io.ErrShortWrite
+	}
+	if err != nil {
+		if n > 0 && n < len(b.buf) {
+			b.buf = b.buf[:copy(b.buf, b.buf[n:])]
+		}
+		b.err = err
+		return err
+	}
+
+	b.buf = b.buf[:0]
+	return nil
+}
+
+// resize the empty buffer within min and max bounds
+func (b *AdaptiveWriter) resize(size int) {
+	if size < b.minSize {
+		size = b.minSize
+	} else if size > b.maxSize {
+		size = b.maxSize
+	}
+
+	if size != cap(b.buf) && len(b.buf) == 0 {
+		b.buf = make([]byte, 0, size)
+	}
+}
+
+// Reset discards any un-flushed buffered data, clears any error, and
+// resets b to write its output to w.
+func (b *AdaptiveWriter) Reset(w io.Writer) {
+	b.err = nil
+	b.buf = b.buf[:0]
+	b.wr = w
+}
+
+// Sync implements the Syncer
+func (b *AdaptiveWriter) Sync() error {
+	return b.Flush()
+}
+
+// Close implements the io.Closer
+func (b *AdaptiveWriter) Close() error {
+	if err := b.Flush(); err != nil {
+		return err
+	}
+
+	// is closer
+	if c, ok := b.wr.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
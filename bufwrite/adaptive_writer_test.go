@@ -0,0 +1,60 @@
+package bufwrite_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/gookit/goutil/testutil/assert"
+	"github.com/gookit/slog/bufwrite"
+)
+
+func TestAdaptiveWriter_resize(t *testing.T) {
+	w := new(bytes.Buffer)
+	bw := bufwrite.NewAdaptiveWriter(w, 8, 32)
+	assert.Eq(t, 8, bw.Size())
+
+	// high throughput: grow to max
+	for i := 0; i < 10; i++ {
+		_, err := bw.WriteString("line-" + string(rune('0'+i)) + "\n")
+		assert.NoErr(t, err)
+	}
+	assert.Eq(t, 32, bw.Size())
+	assert.NoErr(t, bw.Flush())
+	assert.Eq(t, 10, strings.Count(w.String(), "\n"))
+
+	// low throughput: shrink to min
+	for i := 0; i < 3; i++ {
+		_, err := bw.WriteString("a\n")
+		assert.NoErr(t, err)
+		assert.NoErr(t, bw.Flush())
+	}
+	assert.Eq(t, 8, bw.Size())
+
+	// oversized record: write directly
+	w.Reset()
+	_, err := bw.WriteString(strings.Repeat("x", 40))
+	assert.NoErr(t, err)
+	assert.Eq(t, 40, w.Len())
+	assert.Eq(t, 0, bw.Buffered())
+	assert.NoErr(t, bw.Close())
+}
+
+func TestAdaptiveWriter_error(t *testing.T) {
+	w := &closeWriter{}
+	bw := bufwrite.NewAdaptiveWriter(w, 0, 0)
+	assert.Eq(t, bufwrite.DefaultAdaptiveMinSize, bw.Size())
+
+	_, err := bw.WriteString("hello")
+	assert.NoErr(t, err)
+
+	w.errOnWrite = true
+	assert.Err(t, bw.Sync())
+	_, err = bw.WriteString("hello")
+	assert.Err(t, err)
+
+	bw.Reset(&closeWriter{errOnClose: true})
+	err = bw.Close()
+	assert.Err(t, err)
+	assert.Eq(t, "close error", err.Error())
+}
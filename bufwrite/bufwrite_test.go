@@ -185,3 +185,31 @@ func TestNewLineWriterSize(t *testing.T) {
 	bw = bufwrite.NewLineWriterSize(w, -12)
 	assert.True(t, bw.Size() > 12)
 }
+
+func TestLineWriter_EnableGrow(t *testing.T) {
+	w := new(bytes.Buffer)
+	bw := bufwrite.NewLineWriterSize(w, 8).EnableGrow(true)
+
+	_, err := bw.WriteString("hi,")
+	assert.NoErr(t, err)
+
+	// oversized record: flush buffered, then grow buffer
+	_, err = bw.WriteString("a oversized record\n")
+	assert.NoErr(t, err)
+	assert.Eq(t, "hi,", w.String())
+	assert.Eq(t, 19, bw.Size())
+	assert.Eq(t, 19, bw.Buffered())
+
+	// shrink back after flush
+	assert.NoErr(t, bw.Flush())
+	assert.Eq(t, "hi,a oversized record\n", w.String())
+	assert.Eq(t, 8, bw.Size())
+
+	// disable grow: write directly
+	w.Reset()
+	bw.EnableGrow(false)
+	_, err = bw.WriteString("a oversized record\n")
+	assert.NoErr(t, err)
+	assert.Eq(t, "a oversized record\n", w.String())
+	assert.Eq(t, 0, bw.Buffered())
+}
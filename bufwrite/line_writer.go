@@ -20,11 +20,18 @@ const (
 // Change:
 //
 // always keep write full line. more difference please see Write
+//
+// Guarantee: the contents of one Write call(eg: one log record) are never
+// split across flush boundaries, even when it exceeds the buffer size.
 type LineWriter struct {
 	err error
 	buf []byte
 	n   int
 	wr  io.Writer
+	// size the origin buffer size. the buf will be shrunk back to it after flush.
+	size int
+	// grow the buffer temporarily for oversized records
+	grow bool
 }
 
 // NewLineWriterSize returns a new LineWriter whose buffer has at least the specified
@@ -41,11 +48,21 @@ func NewLineWriterSize(w io.Writer, size int) *LineWriter {
 	}
 
 	return &LineWriter{
-		buf: make([]byte, size),
-		wr:  w,
+		buf:  make([]byte, size),
+		wr:   w,
+		size: size,
 	}
 }
 
+// EnableGrow allow the buffer to grow temporarily for a record larger than the buffer size,
+// instead of writing it directly to the underlying io.Writer.
+//
+// The grown buffer will be shrunk back to the origin size after the next Flush.
+func (b *LineWriter) EnableGrow(enable bool) *LineWriter {
+	b.grow = enable
+	return b
+}
+
 // NewLineWriter returns a new LineWriter whose buffer has the default size.
 func NewLineWriter(w io.Writer) *LineWriter {
 	return NewLineWriterSize(w, defaultBufSize)
@@ -103,6 +120,10 @@ func (b *LineWriter) Flush() error {
 		return err
 	}
 	b.n = 0
+	// shrink the temporarily grown buffer
+	if len(b.buf) > b.size {
+		b.buf = make([]byte, b.size)
+	}
 	return nil
 }
 
@@ -134,6 +155,19 @@ func (b *LineWriter) Write(p []byte) (nn int, err error) {
 	// 	p = p[n:]
 	// }
 
+	// grow buffer for the oversized record, buffered data will be flushed first.
+	if b.grow && len(p) > b.Available() && b.err == nil {
+		if b.Buffered() > 0 {
+			if err = b.Flush(); err != nil {
+				return 0, err
+			}
+		}
+
+		if len(p) > len(b.buf) {
+			b.buf = make([]byte, len(p))
+		}
+	}
+
 	// UP: 改造一下逻辑，如果 len(p) > b.Available() 就将buf 和 p 都写入 b.wr
 	if len(p) > b.Available() && b.err == nil {
 		nn = b.Buffered()
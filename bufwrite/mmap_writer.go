@@ -0,0 +1,168 @@
+//go:build linux || darwin
+
+package bufwrite
+
+import (
+	"io"
+	"os"
+	"syscall"
+	"unsafe"
+
+	"github.com/gookit/goutil/errorx"
+)
+
+// defaultMmapChunk the default grow size for the mapped region. 4MB
+const defaultMmapChunk = 4 * 1024 * 1024
+
+// DefaultMmapFilePerm the default perm for create the file by NewMmapWriter()
+const DefaultMmapFilePerm os.FileMode = 0664
+
+// MmapWriter append contents to a file by a memory-mapped region,
+// and call msync on Flush/Sync.
+//
+// It is useful for extreme write-rate scenarios, where the write() syscall overhead dominates.
+//
+// The file is grown by chunk and truncated to the real size on Flush/Close, the region will be mapped
+// again on next write. so on open an exists file, the file size is the contents size.
+//
+// NOTICE: if the process crashed before Flush, the unused region(NUL bytes) will be kept in the file.
+//
+// NOTE: the writer is not concurrent safe, please add lock before call the Write method.
+type MmapWriter struct {
+	file *os.File
+	// mapped region of the whole file
+	data []byte
+	// write offset in the mapped region(= file contents size)
+	off int
+	// grow size on the mapped region is full
+	chunk int
+}
+
+// NewMmapWriter create a mmap writer for the file path. chunkSize is the grow size of the mapped region.
+func NewMmapWriter(filePath string, chunkSize int) (*MmapWriter, error) {
+	return NewMmapWriterPerm(filePath, chunkSize, DefaultMmapFilePerm)
+}
+
+// NewMmapWriterPerm create a mmap writer for the file path, with the perm for create the file.
+func NewMmapWriterPerm(filePath string, chunkSize int, perm os.FileMode) (*MmapWriter, error) {
+	f, err := os.OpenFile(filePath, os.O_CREATE|os.O_RDWR, perm)
+	if err != nil {
+		return nil, err
+	}
+
+	st, err := f.Stat()
+	if err != nil {
+		_ = f.Close()
+		return nil, err
+	}
+
+	if chunkSize <= 0 {
+		chunkSize = defaultMmapChunk
+	}
+
+	w := &MmapWriter{file: f, off: int(st.Size()), chunk: chunkSize}
+	if err = w.remap(w.off + chunkSize); err != nil {
+		_ = f.Close()
+		return nil, err
+	}
+	return w, nil
+}
+
+// remap the file with new size
+func (w *MmapWriter) remap(size int) error {
+	if w.data != nil {
+		if err := syscall.Munmap(w.data); err != nil {
+			return err
+		}
+		w.data = nil
+	}
+
+	if err := w.file.Truncate(int64(size)); err != nil {
+		return err
+	}
+
+	data, err := syscall.Mmap(int(w.file.Fd()), 0, size, syscall.PROT_READ|syscall.PROT_WRITE, syscall.MAP_SHARED)
+	if err != nil {
+		return err
+	}
+
+	w.data = data
+	return nil
+}
+
+// Write p to the mapped region, will grow the region on it is full.
+func (w *MmapWriter) Write(p []byte) (int, error) {
+	if w.file == nil {
+		return 0, errorx.Raw("bufwrite: the mmap writer has been closed")
+	}
+
+	if need := w.off + len(p); need > len(w.data) {
+		// grow at least one chunk. the region is unmapped on Flush, map it again.
+		size := len(w.data) + w.chunk
+		if w.data == nil {
+			size = w.off + w.chunk
+		}
+		if size < need {
+			size = need + w.chunk
+		}
+
+		if err := w.remap(size); err != nil {
+			return 0, err
+		}
+	}
+
+	n := copy(w.data[w.off:], p)
+	w.off += n
+	return n, nil
+}
+
+// WriteString to writer
+func (w *MmapWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+// Size of the written contents
+func (w *MmapWriter) Size() int { return w.off }
+
+// Flush call msync for the mapped region, then unmap the region and truncate the file to real size.
+func (w *MmapWriter) Flush() error {
+	if w.data == nil {
+		return nil
+	}
+
+	if w.off > 0 {
+		_, _, errno := syscall.Syscall(syscall.SYS_MSYNC, uintptr(unsafe.Pointer(&w.data[0])), uintptr(w.off), syscall.MS_SYNC)
+		if errno != 0 {
+			return errno
+		}
+	}
+
+	if err := syscall.Munmap(w.data); err != nil {
+		return err
+	}
+
+	w.data = nil
+	return w.file.Truncate(int64(w.off))
+}
+
+// Sync implements the Syncer. alias of Flush()
+func (w *MmapWriter) Sync() error {
+	return w.Flush()
+}
+
+// Close flush and unmap the region, then close the file.
+func (w *MmapWriter) Close() error {
+	if w.file == nil {
+		return nil
+	}
+
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	err := w.file.Close()
+	w.file = nil
+	return err
+}
+
+var _ io.WriteCloser = (*MmapWriter)(nil)
@@ -0,0 +1,45 @@
+//go:build !linux && !darwin
+
+package bufwrite
+
+import (
+	"os"
+
+	"github.com/gookit/goutil/errorx"
+)
+
+// DefaultMmapFilePerm the default perm for create the file by NewMmapWriter()
+const DefaultMmapFilePerm os.FileMode = 0664
+
+var errMmapNotSupported = errorx.Raw("bufwrite: mmap writer is not supported on current platform")
+
+// MmapWriter is not supported on current platform.
+type MmapWriter struct{}
+
+// NewMmapWriter is not supported on current platform, always return error.
+func NewMmapWriter(_ string, _ int) (*MmapWriter, error) {
+	return nil, errMmapNotSupported
+}
+
+// NewMmapWriterPerm is not supported on current platform, always return error.
+func NewMmapWriterPerm(_ string, _ int, _ os.FileMode) (*MmapWriter, error) {
+	return nil, errMmapNotSupported
+}
+
+// Write is not supported, always return error.
+func (w *MmapWriter) Write(_ []byte) (int, error) { return 0, errMmapNotSupported }
+
+// WriteString is not supported, always return error.
+func (w *MmapWriter) WriteString(_ string) (int, error) { return 0, errMmapNotSupported }
+
+// Size of the written contents
+func (w *MmapWriter) Size() int { return 0 }
+
+// Flush is not supported
+func (w *MmapWriter) Flush() error { return nil }
+
+// Sync is not supported
+func (w *MmapWriter) Sync() error { return nil }
+
+// Close is not supported
+func (w *MmapWriter) Close() error { return nil }
@@ -0,0 +1,89 @@
+//go:build linux || darwin
+
+package bufwrite_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gookit/goutil/fsutil"
+	"github.com/gookit/goutil/testutil/assert"
+	"github.com/gookit/slog/bufwrite"
+)
+
+func TestNewMmapWriter(t *testing.T) {
+	logfile := "./testdata/mmap-writer.log"
+	assert.NoErr(t, fsutil.DeleteIfFileExist(logfile))
+
+	w, err := bufwrite.NewMmapWriter(logfile, 8)
+	assert.NoErr(t, err)
+
+	_, err = w.WriteString("hello, ")
+	assert.NoErr(t, err)
+	// will grow the mapped region
+	_, err = w.WriteString("worlds. oh\n")
+	assert.NoErr(t, err)
+	assert.Eq(t, 18, w.Size())
+	assert.NoErr(t, w.Flush())
+	assert.NoErr(t, w.Close())
+	assert.NoErr(t, w.Close())
+
+	_, err = w.WriteString("after close")
+	assert.Err(t, err)
+	assert.Eq(t, "hello, worlds. oh\n", fsutil.ReadString(logfile))
+
+	// append to exists file
+	w, err = bufwrite.NewMmapWriter(logfile, 0)
+	assert.NoErr(t, err)
+	_, err = w.WriteString("line2\n")
+	assert.NoErr(t, err)
+	assert.NoErr(t, w.Sync())
+	assert.NoErr(t, w.Close())
+	assert.Eq(t, "hello, worlds. oh\nline2\n", fsutil.ReadString(logfile))
+}
+
+func TestNewMmapWriter_flush(t *testing.T) {
+	logfile := filepath.Join(t.TempDir(), "mmap-flush.log")
+
+	w, err := bufwrite.NewMmapWriterPerm(logfile, 64, 0600)
+	assert.NoErr(t, err)
+	_, err = w.WriteString("line1\n")
+	assert.NoErr(t, err)
+	assert.NoErr(t, w.Flush())
+
+	st, err := os.Stat(logfile)
+	assert.NoErr(t, err)
+	assert.Eq(t, os.FileMode(0600), st.Mode().Perm())
+	// not closed, the file is truncated to real size on flush.
+	assert.Eq(t, int64(6), st.Size())
+
+	// will map the region again
+	_, err = w.WriteString("line2\n")
+	assert.NoErr(t, err)
+	assert.NoErr(t, w.Flush())
+	assert.Eq(t, "line1\nline2\n", fsutil.ReadString(logfile))
+
+	w2, err := bufwrite.NewMmapWriter(logfile, 0)
+	assert.NoErr(t, err)
+	assert.Eq(t, 12, w2.Size())
+	_, err = w2.WriteString("line3\n")
+	assert.NoErr(t, err)
+	assert.NoErr(t, w2.Close())
+	assert.Eq(t, "line1\nline2\nline3\n", fsutil.ReadString(logfile))
+	assert.NoErr(t, w.Close())
+}
+
+func TestNewMmapWriter_trailingNUL(t *testing.T) {
+	logfile := filepath.Join(t.TempDir(), "mmap-nul.log")
+	assert.NoErr(t, os.WriteFile(logfile, []byte("data\x00\x00"), 0600))
+
+	// the trailing NUL bytes are the file contents, should not be overwritten.
+	w, err := bufwrite.NewMmapWriter(logfile, 8)
+	assert.NoErr(t, err)
+	assert.Eq(t, 6, w.Size())
+	_, err = w.WriteString("line2\n")
+	assert.NoErr(t, err)
+	assert.NoErr(t, w.Close())
+	assert.Eq(t, "data\x00\x00line2\n", fsutil.ReadString(logfile))
+}
@@ -0,0 +1,53 @@
+package slog
+
+import (
+	"os/exec"
+	"path/filepath"
+)
+
+// FieldKeyProcess field name for identifying the subprocess
+const FieldKeyProcess = "process"
+
+// CmdLogOption options for CmdLogger
+type CmdLogOption struct {
+	// StdoutLevel log level for stdout lines. default is InfoLevel
+	StdoutLevel Level
+	// StderrLevel log level for stderr lines. default is ErrorLevel
+	StderrLevel Level
+	// Name of the subprocess. default is base name of cmd.Path
+	Name string
+}
+
+// CmdLogger wire the command stdout and stderr into the logger.
+//
+// Each line will be logged with field "process" and "stream" for identifying the subprocess.
+// Must call the returned close func after the command is done, will flush the incomplete lines.
+//
+// Usage:
+//
+//	cmd := exec.Command("some", "args")
+//	done := slog.CmdLogger(cmd, logger)
+//	err := cmd.Run()
+//	done()
+func CmdLogger(cmd *exec.Cmd, l *Logger, fns ...func(opt *CmdLogOption)) (closeFn func()) {
+	opt := &CmdLogOption{
+		StdoutLevel: InfoLevel,
+		StderrLevel: ErrorLevel,
+		Name:        filepath.Base(cmd.Path),
+	}
+	for _, fn := range fns {
+		fn(opt)
+	}
+
+	stdout := NewLevelWriter(l, opt.StdoutLevel)
+	stdout.Fields = M{FieldKeyProcess: opt.Name, "stream": "stdout"}
+	stderr := NewLevelWriter(l, opt.StderrLevel)
+	stderr.Fields = M{FieldKeyProcess: opt.Name, "stream": "stderr"}
+
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	return func() {
+		_ = stdout.Close()
+		_ = stderr.Close()
+	}
+}
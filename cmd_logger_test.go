@@ -0,0 +1,37 @@
+package slog_test
+
+import (
+	"os/exec"
+	"runtime"
+	"testing"
+
+	"github.com/gookit/goutil/byteutil"
+	"github.com/gookit/goutil/testutil/assert"
+	"github.com/gookit/slog"
+	"github.com/gookit/slog/handler"
+)
+
+func TestCmdLogger(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("skip on windows")
+	}
+
+	buf := byteutil.NewBuffer()
+	h := handler.NewIOWriter(buf, slog.AllLevels)
+	h.SetFormatter(slog.NewJSONFormatter())
+	l := slog.NewWithHandlers(h)
+
+	cmd := exec.Command("sh", "-c", "echo out line; echo err line >&2; printf partial")
+	done := slog.CmdLogger(cmd, l, func(opt *slog.CmdLogOption) {
+		opt.Name = "test-cmd"
+	})
+	assert.NoErr(t, cmd.Run())
+	done()
+
+	str := buf.String()
+	assert.StrContains(t, str, `"level":"INFO","message":"out line"`)
+	assert.StrContains(t, str, `"level":"ERROR","message":"err line"`)
+	assert.StrContains(t, str, `"message":"partial"`)
+	assert.StrContains(t, str, `"process":"test-cmd"`)
+	assert.StrContains(t, str, `"stream":"stderr"`)
+}
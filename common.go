@@ -124,6 +124,12 @@ const (
 	// CallerFlagFcName only report func name.
 	// eg: "TestLogger_ReportCaller"
 	CallerFlagFcName
+	// CallerFlagRelPath report filepath relative to the module root with line. see CallerModulePath
+	// eg: "handler/writer.go:48"
+	CallerFlagRelPath
+	// CallerFlagPkgFile report the last dir name + filename + line.
+	// eg: "slog/logger_test.go:48"
+	CallerFlagPkgFile
 )
 
 var (
@@ -150,8 +156,14 @@ var (
 	FieldKeyLevel = "level"
 	// FieldKeyError Define the key when adding errors using WithError.
 	FieldKeyError = "error"
+	// FieldKeyErrorCauses key name for the unwrapped error causes. see Logger.ErrorStack()
+	FieldKeyErrorCauses = "error.causes"
+	// FieldKeyStack key name for the stack trace. see Record.WithStack()
+	FieldKeyStack = "stack"
 	// FieldKeyExtra key name
 	FieldKeyExtra = "extra"
+	// FieldKeyTags key name for Record.Tags
+	FieldKeyTags = "tags"
 
 	// FieldKeyChannel name
 	FieldKeyChannel = "channel"
@@ -267,18 +279,28 @@ func Name2Level(ln string) (Level, error) {
 // global exit handler
 var exitHandlers = make([]func(), 0)
 
-func runExitHandlers() {
+func runExitHandlers(handlers []func()) {
 	defer func() {
 		if err := recover(); err != nil {
-			printlnStderr("slog: run exit handler(global) recovered, error:", err)
+			reportError("slog: run exit handler(global) recovered, error:", err)
 		}
 	}()
 
-	for _, handler := range exitHandlers {
+	for _, handler := range handlers {
 		handler()
 	}
 }
 
+// run a func and recover the panic
+func runSafely(name string, fn func()) {
+	defer func() {
+		if err := recover(); err != nil {
+			reportError("slog: run "+name+" func recovered, error:", err)
+		}
+	}()
+	fn()
+}
+
 // ExitHandlers get all global exitHandlers
 func ExitHandlers() []func() {
 	return exitHandlers
@@ -0,0 +1,59 @@
+package slog
+
+import "context"
+
+// ContextExtractor extract values from the record context, and add them to the record.
+// eg: trace ID, request ID, tenant ID
+type ContextExtractor func(ctx context.Context, r *Record)
+
+// AddContextExtractor add context extractors to the logger.
+//
+// The extractors will run on write the record that has context(see WithContext()),
+// before the processors. on the child logger, will add to the root logger.
+//
+// Usage:
+//
+//	logger.AddContextExtractor(func(ctx context.Context, r *slog.Record) {
+//		if reqID, ok := ctx.Value(reqIDKey{}).(string); ok {
+//			r.AddField("request_id", reqID)
+//		}
+//	})
+//
+//	logger.WithContext(ctx).Info("request started")
+func (l *Logger) AddContextExtractor(fns ...ContextExtractor) {
+	rl := l.rootLogger()
+	rl.setMu.Lock()
+	rl.ctxExtractors.Store(appendCopy(rl.loadCtxExtractors(), fns))
+	rl.setMu.Unlock()
+}
+
+func (l *Logger) loadCtxExtractors() []ContextExtractor {
+	if fns := l.ctxExtractors.Load(); fns != nil {
+		return *fns
+	}
+	return nil
+}
+
+// run the context extractors on the record has context
+func (l *Logger) extractContext(r *Record) {
+	if r.Ctx == nil {
+		return
+	}
+
+	for _, fn := range l.loadCtxExtractors() {
+		fn(r.Ctx, r)
+	}
+}
+
+// CtxValueExtractor create a ContextExtractor, add the context value of key as field.
+//
+// Usage:
+//
+//	logger.AddContextExtractor(slog.CtxValueExtractor(traceIDKey{}, "trace_id"))
+func CtxValueExtractor(key any, field string) ContextExtractor {
+	return func(ctx context.Context, r *Record) {
+		if val := ctx.Value(key); val != nil {
+			r.AddField(field, val)
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package slog_test
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/gookit/goutil/testutil/assert"
+	"github.com/gookit/slog"
+	"github.com/gookit/slog/handler"
+)
+
+type tenantKey struct{}
+
+func TestLogger_AddContextExtractor(t *testing.T) {
+	buf := new(bytes.Buffer)
+	h := handler.NewIOWriter(buf, slog.AllLevels)
+	h.SetFormatter(slog.NewJSONFormatter())
+	l := slog.NewWithHandlers(h)
+
+	l.AddContextExtractor(
+		slog.CtxValueExtractor("trace_id", "trace_id"),
+		func(ctx context.Context, r *slog.Record) {
+			if tid, ok := ctx.Value(tenantKey{}).(string); ok {
+				r.AddField("tenant", tid)
+			}
+		},
+	)
+
+	ctx := context.WithValue(context.Background(), "trace_id", "t-001")
+	ctx = context.WithValue(ctx, tenantKey{}, "acme")
+
+	l.WithContext(ctx).Info("with context")
+	str := buf.String()
+	assert.StrContains(t, str, `"trace_id":"t-001"`)
+	assert.StrContains(t, str, `"tenant":"acme"`)
+
+	// no context
+	buf.Reset()
+	l.Info("no context")
+	assert.NotContains(t, buf.String(), "trace_id")
+
+	// add on the child logger, will add to the root logger
+	buf.Reset()
+	l.With(slog.M{"a": 1}).AddContextExtractor(slog.CtxValueExtractor("user", "user"))
+	l.WithCtx(context.WithValue(ctx, "user", "inhere")).Info("child extractor")
+	str = buf.String()
+	assert.StrContains(t, str, `"user":"inhere"`)
+	assert.StrContains(t, str, `"tenant":"acme"`)
+}
+
+func TestLogger_extractContext_pooledRecord(t *testing.T) {
+	buf := new(bytes.Buffer)
+	h := handler.NewIOWriter(buf, slog.AllLevels)
+	h.SetFormatter(slog.NewJSONFormatter())
+	l := slog.NewWithHandlers(h)
+	l.AddContextExtractor(slog.CtxValueExtractor("request_id", "request_id"))
+
+	ctx := context.WithValue(context.Background(), "request_id", "req-1")
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 200; j++ {
+				l.WithContext(ctx).Info("with context")
+				l.Info("no context")
+			}
+		}()
+	}
+	wg.Wait()
+
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		if strings.Contains(line, "no context") {
+			assert.NotContains(t, line, "req-1")
+		}
+	}
+}
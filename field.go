@@ -0,0 +1,160 @@
+package slog
+
+import (
+	"math"
+	"strconv"
+	"time"
+)
+
+// FieldKind the value kind of the typed Field
+type FieldKind uint8
+
+// field value kinds
+const (
+	KindAny FieldKind = iota
+	KindString
+	KindInt64
+	KindUint64
+	KindFloat64
+	KindBool
+	KindDuration
+	KindTime
+	KindError
+)
+
+// Field a typed field, the value is stored without interface boxing. see Record.WithAttrs()
+//
+// The TextFormatter encode the value without boxing, the JSON formatters still box the value on encode.
+//
+// Usage:
+//
+//	l.Record().WithAttrs(slog.String("user", "inhere"), slog.Int("age", 23)).Info("message")
+type Field struct {
+	Key  string
+	Kind FieldKind
+	// num for int64, uint64, float64 bits, bool and duration
+	num uint64
+	str string
+	// any value, error or time.Time
+	any any
+}
+
+// String create a string field
+func String(key, val string) Field {
+	return Field{Key: key, Kind: KindString, str: val}
+}
+
+// Int create an int field
+func Int(key string, val int) Field {
+	return Int64(key, int64(val))
+}
+
+// Int64 create an int64 field
+func Int64(key string, val int64) Field {
+	return Field{Key: key, Kind: KindInt64, num: uint64(val)}
+}
+
+// Uint64 create an uint64 field
+func Uint64(key string, val uint64) Field {
+	return Field{Key: key, Kind: KindUint64, num: val}
+}
+
+// Float64 create a float64 field
+func Float64(key string, val float64) Field {
+	return Field{Key: key, Kind: KindFloat64, num: math.Float64bits(val)}
+}
+
+// Bool create a bool field
+func Bool(key string, val bool) Field {
+	var n uint64
+	if val {
+		n = 1
+	}
+	return Field{Key: key, Kind: KindBool, num: n}
+}
+
+// Duration create a time.Duration field
+func Duration(key string, val time.Duration) Field {
+	return Field{Key: key, Kind: KindDuration, num: uint64(val)}
+}
+
+// Time create a time.Time field
+func Time(key string, val time.Time) Field {
+	return Field{Key: key, Kind: KindTime, any: val}
+}
+
+// Err create an error field, the key is FieldKeyError
+func Err(err error) Field {
+	return NamedErr(FieldKeyError, err)
+}
+
+// NamedErr create an error field with custom key
+func NamedErr(key string, err error) Field {
+	if err == nil {
+		return Any(key, nil)
+	}
+	return Field{Key: key, Kind: KindError, any: err}
+}
+
+// Any create a field with any value
+func Any(key string, val any) Field {
+	return Field{Key: key, Kind: KindAny, any: val}
+}
+
+// Value get the field value as interface. NOTICE: will box the value.
+func (f Field) Value() any {
+	switch f.Kind {
+	case KindString:
+		return f.str
+	case KindInt64:
+		return int64(f.num)
+	case KindUint64:
+		return f.num
+	case KindFloat64:
+		return math.Float64frombits(f.num)
+	case KindBool:
+		return f.num == 1
+	case KindDuration:
+		return time.Duration(f.num)
+	case KindTime:
+		return f.time()
+	case KindError:
+		return f.any.(error).Error()
+	default:
+		return f.any
+	}
+}
+
+func (f Field) time() time.Time {
+	t, _ := f.any.(time.Time)
+	return t
+}
+
+// AppendText append the field value as text to dst, without interface boxing.
+func (f Field) AppendText(dst []byte) []byte {
+	switch f.Kind {
+	case KindString:
+		return append(dst, f.str...)
+	case KindInt64:
+		return strconv.AppendInt(dst, int64(f.num), 10)
+	case KindUint64:
+		return strconv.AppendUint(dst, f.num, 10)
+	case KindFloat64:
+		return strconv.AppendFloat(dst, math.Float64frombits(f.num), 'g', -1, 64)
+	case KindBool:
+		return strconv.AppendBool(dst, f.num == 1)
+	case KindDuration:
+		return append(dst, time.Duration(f.num).String()...)
+	case KindTime:
+		return f.time().AppendFormat(dst, time.RFC3339Nano)
+	case KindError:
+		return append(dst, f.any.(error).Error()...)
+	default:
+		return append(dst, EncodeToString(f.any)...)
+	}
+}
+
+// String get the field as "key=value" string
+func (f Field) String() string {
+	return string(f.AppendText(append([]byte(f.Key), '=')))
+}
@@ -0,0 +1,121 @@
+package slog_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/gookit/goutil/byteutil"
+	"github.com/gookit/goutil/testutil/assert"
+	"github.com/gookit/slog"
+	"github.com/gookit/slog/handler"
+)
+
+func TestField_Value(t *testing.T) {
+	tm := time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC)
+	tests := []struct {
+		f    slog.Field
+		kind slog.FieldKind
+		val  any
+		text string
+	}{
+		{slog.String("k", "v"), slog.KindString, "v", "v"},
+		{slog.Int("k", -23), slog.KindInt64, int64(-23), "-23"},
+		{slog.Int64("k", 23), slog.KindInt64, int64(23), "23"},
+		{slog.Uint64("k", 23), slog.KindUint64, uint64(23), "23"},
+		{slog.Float64("k", 2.5), slog.KindFloat64, 2.5, "2.5"},
+		{slog.Bool("k", true), slog.KindBool, true, "true"},
+		{slog.Bool("k", false), slog.KindBool, false, "false"},
+		{slog.Duration("k", 1500*time.Millisecond), slog.KindDuration, 1500 * time.Millisecond, "1.5s"},
+		{slog.Time("k", tm), slog.KindTime, tm, "2023-01-02T03:04:05Z"},
+		{slog.NamedErr("k", errors.New("an error")), slog.KindError, "an error", "an error"},
+		{slog.Any("k", []int{1, 2}), slog.KindAny, []int{1, 2}, "[1 2]"},
+	}
+
+	for _, tt := range tests {
+		assert.Eq(t, tt.kind, tt.f.Kind)
+		assert.Eq(t, tt.val, tt.f.Value())
+		assert.Eq(t, tt.text, string(tt.f.AppendText(nil)))
+		assert.Eq(t, "k="+tt.text, tt.f.String())
+	}
+
+	f := slog.Err(nil)
+	assert.Eq(t, slog.FieldKeyError, f.Key)
+	assert.Nil(t, f.Value())
+
+	// zero time and the time out of the unix nano range
+	f = slog.Time("k", time.Time{})
+	assert.True(t, f.Value().(time.Time).IsZero())
+	assert.Eq(t, "0001-01-01T00:00:00Z", string(f.AppendText(nil)))
+	tm = time.Date(2300, 1, 2, 3, 4, 5, 6, time.UTC)
+	assert.Eq(t, tm, slog.Time("k", tm).Value())
+}
+
+func TestField_zeroAlloc(t *testing.T) {
+	buf := make([]byte, 0, 128)
+	allocs := testing.AllocsPerRun(100, func() {
+		fs := [3]slog.Field{slog.String("a", "b"), slog.Int("n", 23), slog.Duration("d", time.Second)}
+		for _, f := range fs {
+			buf = f.AppendText(buf[:0])
+		}
+	})
+	assert.Eq(t, float64(0), allocs)
+}
+
+func TestRecord_WithAttrs(t *testing.T) {
+	buf := new(byteutil.Buffer)
+	h := handler.NewIOWriter(buf, slog.AllLevels)
+	h.SetFormatter(slog.NewJSONFormatter())
+	l := slog.NewWithHandlers(h)
+
+	r := l.WithAttrs(slog.String("user", "attr")).WithField("user", "field").WithAttrs(
+		slog.Int("age", 23),
+		slog.Err(errors.New("an error")),
+	)
+	assert.Len(t, r.Attrs, 3)
+	assert.Eq(t, "field", r.Field("user"))
+	assert.Eq(t, int64(23), r.Field("age"))
+
+	r.Info("attrs message")
+	str := buf.ResetAndGet()
+	assert.StrContains(t, str, `"user":"field"`)
+	assert.StrContains(t, str, `"age":23`)
+	assert.StrContains(t, str, `"error":"an error"`)
+	assert.NotContains(t, str, `"user":"attr"`)
+
+	// merged and visit order
+	r = l.Record().AddAttrs(slog.Bool("ok", true)).AddData(slog.M{"ok": "data", "d": 1})
+	assert.Eq(t, slog.M{"ok": true, "d": 1}, r.MergedFields())
+
+	var keys []string
+	r.EachField(func(key string, _ any) { keys = append(keys, key) })
+	assert.Eq(t, []string{"ok", "d"}, keys)
+
+	// text formatter with template
+	h.SetFormatter(slog.NewTextFormatter("{{message}} age={{age}}\n"))
+	l.Record().WithAttrs(slog.Int("age", 23)).Info("text message")
+	assert.Eq(t, "text message age=23\n", buf.ResetAndGet())
+
+	// the attrs not in the template are rendered after the data
+	h.SetFormatter(slog.NewTextFormatter("{{message}} age={{age}} {{data}}\n"))
+	l.Record().WithAttrs(slog.Int("age", 23), slog.String("user", "bob")).Info("text message")
+	assert.Eq(t, "text message age=23 user=bob\n", buf.ResetAndGet())
+	l.Record().WithData(slog.M{"a": 1}).WithAttrs(slog.Int("age", 1), slog.String("user", "bob")).Info("text message")
+	assert.Eq(t, "text message age=1 {a:1} user=bob\n", buf.ResetAndGet())
+
+	// default template
+	h.SetFormatter(slog.NewTextFormatter())
+	l.WithAttrs(slog.String("user", "bob")).Info("x")
+	assert.StrContains(t, buf.ResetAndGet(), "x user=bob")
+}
+
+func TestHashFields_attrs(t *testing.T) {
+	r := &slog.Record{Attrs: []slog.Field{slog.String("email", "a@b.com"), slog.Int("age", 23)}}
+	old := r.Attrs
+
+	slog.HashFields("salt", "email").Process(r)
+	assert.Eq(t, slog.HashValue("salt", "a@b.com"), r.Field("email"))
+	assert.Eq(t, int64(23), r.Field("age"))
+	// the original slice is not modified
+	assert.Eq(t, "a@b.com", old[0].Value())
+}
@@ -0,0 +1,72 @@
+// Package fieldkeys provide the well-known field keys and helpers for build the typed fields,
+// so the services can converge on the consistent field naming.
+//
+// Usage:
+//
+//	logger.WithAttrs(
+//		fieldkeys.RequestID(reqID),
+//		fieldkeys.UserID(uid),
+//		fieldkeys.Duration(time.Since(start)),
+//	).Info("request done")
+//
+//	// or use the key constants directly
+//	logger.WithField(fieldkeys.KeyTraceID, traceID).Info("message")
+package fieldkeys
+
+import (
+	"time"
+
+	"github.com/gookit/slog"
+)
+
+// the well-known field keys
+const (
+	KeyRequestID = "request_id"
+	KeyTraceID   = "trace_id"
+	KeySpanID    = "span_id"
+	KeyUserID    = "user_id"
+	KeyService   = "service"
+	KeyComponent = "component"
+
+	// KeyDurationMS the duration in milliseconds
+	KeyDurationMS = "duration_ms"
+
+	KeyHTTPMethod = "http.method"
+	KeyHTTPPath   = "http.path"
+	KeyHTTPStatus = "http.status"
+)
+
+// RequestID field
+func RequestID(id string) slog.Field { return slog.String(KeyRequestID, id) }
+
+// TraceID field
+func TraceID(id string) slog.Field { return slog.String(KeyTraceID, id) }
+
+// SpanID field
+func SpanID(id string) slog.Field { return slog.String(KeySpanID, id) }
+
+// UserID field, the id can be any type. eg: int64, string
+func UserID(id any) slog.Field { return slog.Any(KeyUserID, id) }
+
+// Service field
+func Service(name string) slog.Field { return slog.String(KeyService, name) }
+
+// Component field
+func Component(name string) slog.Field { return slog.String(KeyComponent, name) }
+
+// Duration field, output the duration in milliseconds with the key "duration_ms"
+func Duration(d time.Duration) slog.Field {
+	return slog.Int64(KeyDurationMS, d.Milliseconds())
+}
+
+// HTTPStatus field
+func HTTPStatus(code int) slog.Field { return slog.Int(KeyHTTPStatus, code) }
+
+// HTTP fields for a request: method, path and the response status
+func HTTP(method, path string, status int) []slog.Field {
+	return []slog.Field{
+		slog.String(KeyHTTPMethod, method),
+		slog.String(KeyHTTPPath, path),
+		HTTPStatus(status),
+	}
+}
@@ -0,0 +1,45 @@
+package fieldkeys_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gookit/goutil/byteutil"
+	"github.com/gookit/goutil/testutil/assert"
+	"github.com/gookit/slog"
+	"github.com/gookit/slog/fieldkeys"
+	"github.com/gookit/slog/handler"
+)
+
+func TestFieldKeys(t *testing.T) {
+	buf := byteutil.NewBuffer()
+	h := handler.NewIOWriter(buf, slog.AllLevels)
+	h.SetFormatter(slog.NewJSONFormatter())
+	l := slog.NewWithHandlers(h)
+
+	l.WithAttrs(
+		fieldkeys.RequestID("req-1"),
+		fieldkeys.TraceID("trace-1"),
+		fieldkeys.SpanID("span-1"),
+		fieldkeys.UserID(23),
+		fieldkeys.Service("order"),
+		fieldkeys.Component("db"),
+		fieldkeys.Duration(1500*time.Millisecond),
+	).WithAttrs(fieldkeys.HTTP("GET", "/users", 200)...).Info("request done")
+
+	str := buf.String()
+	assert.StrContains(t, str, `"request_id":"req-1"`)
+	assert.StrContains(t, str, `"trace_id":"trace-1"`)
+	assert.StrContains(t, str, `"span_id":"span-1"`)
+	assert.StrContains(t, str, `"user_id":23`)
+	assert.StrContains(t, str, `"service":"order"`)
+	assert.StrContains(t, str, `"component":"db"`)
+	assert.StrContains(t, str, `"duration_ms":1500`)
+	assert.StrContains(t, str, `"http.method":"GET"`)
+	assert.StrContains(t, str, `"http.path":"/users"`)
+	assert.StrContains(t, str, `"http.status":200`)
+
+	f := fieldkeys.HTTPStatus(404)
+	assert.Eq(t, fieldkeys.KeyHTTPStatus, f.Key)
+	assert.Eq(t, int64(404), f.Value())
+}
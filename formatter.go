@@ -1,6 +1,9 @@
 package slog
 
-import "runtime"
+import (
+	"bytes"
+	"runtime"
+)
 
 //
 // Formatter interface
@@ -20,6 +23,13 @@ func (fn FormatterFunc) Format(r *Record) ([]byte, error) {
 	return fn(r)
 }
 
+// FormatterUnwrapper a formatter wraps another formatter, eg: the formatters created by FormatterMiddleware.
+// see AsTextFormatter(), AsJSONFormatter()
+type FormatterUnwrapper interface {
+	// Unwrap returns the wrapped formatter
+	Unwrap() Formatter
+}
+
 // Formattable interface
 type Formattable interface {
 	// Formatter get the log formatter
@@ -56,21 +66,154 @@ func (f *FormatterWrapper) Format(record *Record) ([]byte, error) {
 	return f.Formatter().Format(record)
 }
 
+// Record separators for the SeparatorFormatter
+const (
+	SepNewline = "\n"
+	SepCRLF    = "\r\n"
+	SepNUL     = "\x00"
+	SepNone    = ""
+)
+
+// SeparatorFormatter wrap a formatter, replace the trailing newline of each
+// formatted record with a custom separator.
+//
+// Useful for consumers like journald or binary framing protocols that don't want the implicit newline.
+type SeparatorFormatter struct {
+	Formatter
+	// Separator for each record. see SepNewline, SepCRLF, SepNUL, SepNone
+	Separator string
+}
+
+// NewSeparatorFormatter create new SeparatorFormatter
+func NewSeparatorFormatter(f Formatter, sep string) *SeparatorFormatter {
+	return &SeparatorFormatter{Formatter: f, Separator: sep}
+}
+
+// Unwrap returns the wrapped formatter
+func (f *SeparatorFormatter) Unwrap() Formatter { return f.Formatter }
+
+// Format a log record, then replace the trailing newline with the separator
+func (f *SeparatorFormatter) Format(r *Record) ([]byte, error) {
+	bts, err := f.Formatter.Format(r)
+	if err != nil {
+		return bts, err
+	}
+
+	if bytes.HasSuffix(bts, []byte{'\r', '\n'}) {
+		bts = bts[:len(bts)-2]
+	} else if bytes.HasSuffix(bts, []byte{'\n'}) {
+		bts = bts[:len(bts)-1]
+	}
+	return append(bts, f.Separator...), nil
+}
+
+// FormatterMiddleware decorate a formatter, returns the new formatter.
+//
+// eg: add prefix, change the separator, post-process the formatted bytes(encrypt, compress)
+type FormatterMiddleware func(next Formatter) Formatter
+
+// ChainFormatter decorate the formatter by the middlewares,
+// the formatted bytes are processed by the middlewares in order.
+//
+// Usage:
+//
+//	h.SetFormatter(slog.ChainFormatter(h.Formatter(),
+//		slog.PrefixMiddleware("[worker-3] "),
+//		slog.BytesMiddleware(encrypt),
+//	))
+func ChainFormatter(f Formatter, mws ...FormatterMiddleware) Formatter {
+	for _, mw := range mws {
+		f = mw(f)
+	}
+	return f
+}
+
+// PrefixMiddleware add a static prefix to each formatted record
+func PrefixMiddleware(prefix string) FormatterMiddleware {
+	return BytesMiddleware(func(bts []byte) ([]byte, error) {
+		return append([]byte(prefix), bts...), nil
+	})
+}
+
+// SuffixMiddleware add a static suffix to each formatted record, before the trailing newline.
+func SuffixMiddleware(suffix string) FormatterMiddleware {
+	return BytesMiddleware(func(bts []byte) ([]byte, error) {
+		n := len(bts)
+		if bytes.HasSuffix(bts, []byte{'\r', '\n'}) {
+			n -= 2
+		} else if bytes.HasSuffix(bts, []byte{'\n'}) {
+			n--
+		}
+
+		nb := make([]byte, 0, len(bts)+len(suffix))
+		nb = append(append(nb, bts[:n]...), suffix...)
+		return append(nb, bts[n:]...), nil
+	})
+}
+
+// SeparatorMiddleware replace the trailing newline with the separator. see SeparatorFormatter
+func SeparatorMiddleware(sep string) FormatterMiddleware {
+	return func(next Formatter) Formatter {
+		return NewSeparatorFormatter(next, sep)
+	}
+}
+
+// BytesMiddleware post-process the formatted bytes by the func. eg: encrypt, compress
+//
+// NOTICE: the input bytes may be reused by the formatter, should not keep it.
+func BytesMiddleware(fn func(bts []byte) ([]byte, error)) FormatterMiddleware {
+	return func(next Formatter) Formatter {
+		return &bytesFormatter{next: next, fn: fn}
+	}
+}
+
+// bytesFormatter post-process the formatted bytes of the wrapped formatter. see BytesMiddleware()
+type bytesFormatter struct {
+	next Formatter
+	fn   func(bts []byte) ([]byte, error)
+}
+
+// Unwrap returns the wrapped formatter
+func (f *bytesFormatter) Unwrap() Formatter { return f.next }
+
+// Format a log record by the wrapped formatter, then process the bytes
+func (f *bytesFormatter) Format(r *Record) ([]byte, error) {
+	bts, err := f.next.Format(r)
+	if err != nil {
+		return nil, err
+	}
+	return f.fn(bts)
+}
+
 // CallerFormatFn caller format func
 type CallerFormatFn func(rf *runtime.Frame) (cs string)
 
-// AsTextFormatter util func
+// AsTextFormatter util func. will unwrap the formatter by FormatterUnwrapper
 func AsTextFormatter(f Formatter) *TextFormatter {
-	if tf, ok := f.(*TextFormatter); ok {
-		return tf
+	for f != nil {
+		if tf, ok := f.(*TextFormatter); ok {
+			return tf
+		}
+		f = unwrapFormatter(f)
 	}
 	panic("slog: cannot cast input as *TextFormatter")
 }
 
-// AsJSONFormatter util func
+// AsJSONFormatter util func. will unwrap the formatter by FormatterUnwrapper
 func AsJSONFormatter(f Formatter) *JSONFormatter {
-	if jf, ok := f.(*JSONFormatter); ok {
-		return jf
+	for f != nil {
+		if jf, ok := f.(*JSONFormatter); ok {
+			return jf
+		}
+		f = unwrapFormatter(f)
 	}
 	panic("slog: cannot cast input as *JSONFormatter")
 }
+
+// returns the wrapped formatter, nil on it cannot be unwrapped.
+func unwrapFormatter(f Formatter) Formatter {
+	if uw, ok := f.(FormatterUnwrapper); ok {
+		return uw.Unwrap()
+	}
+	return nil
+}
@@ -0,0 +1,107 @@
+package slog
+
+import "fmt"
+
+// ECSVersion the Elastic Common Schema version of the ECSFormatter output
+const ECSVersion = "8.11.0"
+
+// ECSTimeFormat the time layout for the "@timestamp" field
+const ECSTimeFormat = "2006-01-02T15:04:05.000Z07:00"
+
+// ECSFieldKeys the default mapping of the common custom field keys to the ECS field names.
+var ECSFieldKeys = StringMap{
+	"trace_id":   "trace.id",
+	"span_id":    "span.id",
+	"request_id": "http.request.id",
+	"user_id":    "user.id",
+	"service":    "service.name",
+}
+
+// ECSFormatter output the record as Elastic Common Schema(ECS) compliant JSON.
+// The output can be ingested by Filebeat, Elasticsearch without custom JSON key mappings.
+//
+// Fields mapping:
+//
+//   - "@timestamp", "message", "tags", "ecs.version"
+//   - "log.level", "log.logger"(channel), "log.origin.*"(caller)
+//   - "error.message", "error.type", "error.stack_trace" from the field FieldKeyError, FieldKeyStack
+//   - the custom fields are mapped by KeyMap, eg: "trace_id" => "trace.id"
+//
+// All dotted keys are expanded to nested objects.
+type ECSFormatter struct {
+	// ServiceName the "service.name" of the records. default is empty, not output.
+	ServiceName string
+	// KeyMap mapping the custom field keys to ECS field names. default is ECSFieldKeys
+	KeyMap StringMap
+	// PrettyPrint will indent all json logs
+	PrettyPrint bool
+	// EncodeOptions limit the depth, elements and render []byte for field values. see JSONFormatter.EncodeOptions
+	EncodeOptions
+	// OnError on a field value cannot be encoded. see JSONFormatter.OnError
+	OnError func(err error)
+}
+
+// NewECSFormatter create new ECSFormatter
+//
+// Usage:
+//
+//	h := handler.MustFileHandler("/var/log/app.json")
+//	h.SetFormatter(slog.NewECSFormatter(func(f *slog.ECSFormatter) {
+//		f.ServiceName = "order-api"
+//	}))
+func NewECSFormatter(fns ...func(f *ECSFormatter)) *ECSFormatter {
+	f := &ECSFormatter{KeyMap: ECSFieldKeys}
+	for _, fn := range fns {
+		fn(f)
+	}
+	return f
+}
+
+// Format a log record to ECS JSON
+func (f *ECSFormatter) Format(r *Record) ([]byte, error) {
+	data := M{
+		"@timestamp":  r.Time.Format(ECSTimeFormat),
+		"log.level":   r.Level.LowerName(),
+		"message":     r.Message,
+		"ecs.version": ECSVersion,
+	}
+
+	if r.Channel != "" {
+		data["log.logger"] = r.Channel
+	}
+	if f.ServiceName != "" {
+		data["service.name"] = f.ServiceName
+	}
+	if len(r.Tags) > 0 {
+		data["tags"] = r.Tags
+	}
+	if r.Caller != nil {
+		data["log.origin.file.name"] = r.Caller.File
+		data["log.origin.file.line"] = r.Caller.Line
+		data["log.origin.function"] = r.Caller.Function
+	}
+
+	for key, val := range r.MergedFields() {
+		switch key {
+		case FieldKeyError:
+			if err, ok := val.(error); ok && err != nil {
+				data["error.message"] = err.Error()
+				data["error.type"] = fmt.Sprintf("%T", err)
+				continue
+			}
+		case FieldKeyStack:
+			data["error.stack_trace"] = val
+			continue
+		}
+
+		if name, ok := f.KeyMap[key]; ok {
+			key = name
+		}
+		// don't override the built-in fields
+		if _, has := data[key]; !has {
+			data[key] = val
+		}
+	}
+
+	return encodeJSON(expandDotKeys(data), f.PrettyPrint, &f.EncodeOptions, f.OnError)
+}
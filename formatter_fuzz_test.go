@@ -0,0 +1,121 @@
+package slog_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/gookit/goutil/testutil/assert"
+	"github.com/gookit/slog"
+)
+
+func newFuzzRecord(msg, key, val string) *slog.Record {
+	r := &slog.Record{
+		Level:   slog.InfoLevel,
+		Time:    time.Now(),
+		Message: msg,
+		Fields:  slog.M{key: val},
+		Data:    slog.M{key: val, "nested": slog.M{key: []string{val}}},
+		Attrs:   []slog.Field{slog.String(key, val)},
+		Tags:    []string{val},
+	}
+	r.Init(false)
+	return r
+}
+
+var fuzzSeeds = [][3]string{
+	{"hello", "key", "value"},
+	{"line1\nline2\r\n", "k\"ey\n", "v\\al\"ue\n"},
+	{"\x00\x1b[31mred\x7f", "\xff\xfe", "\xc3\x28invalid"},
+	{"sep\u2028\u2029\u0085", "message", "level"},
+	{strings.Repeat("big", 1000), "", strings.Repeat("x", 1<<12)},
+}
+
+// go test -run none -fuzz FuzzJSONFormatter
+func FuzzJSONFormatter(f *testing.F) {
+	for _, seed := range fuzzSeeds {
+		f.Add(seed[0], seed[1], seed[2])
+	}
+
+	jf := slog.NewJSONFormatter(func(f *slog.JSONFormatter) {
+		f.MaxStringLen = 1024
+		f.OnError = func(err error) {}
+	})
+	jf.AddField(slog.FieldKeyTags)
+
+	f.Fuzz(func(t *testing.T, msg, key, val string) {
+		bs, err := jf.Format(newFuzzRecord(msg, key, val))
+		assert.NoErr(t, err)
+
+		// one record per line
+		assert.Eq(t, 1, bytes.Count(bs, []byte{'\n'}))
+		assert.True(t, bytes.HasSuffix(bs, []byte{'\n'}))
+		assert.True(t, json.Valid(bs), string(bs))
+		assert.True(t, utf8.Valid(bs))
+
+		mp := make(map[string]any)
+		assert.NoErr(t, json.Unmarshal(bs, &mp))
+		if utf8.ValidString(msg) && len(msg) <= 1024 {
+			assert.Eq(t, msg, mp["message"])
+		}
+	})
+}
+
+// go test -run none -fuzz FuzzTextFormatter
+func FuzzTextFormatter(f *testing.F) {
+	for _, seed := range fuzzSeeds {
+		f.Add(seed[0], seed[1], seed[2])
+	}
+
+	tf := slog.NewTextFormatter("[{{datetime}}] [{{level}}] {{message}} {{data}} {{tags}} key={{key}}\n")
+	tf.SingleLine = true
+	tf.MaxStringLen = 1024
+
+	f.Fuzz(func(t *testing.T, msg, key, val string) {
+		bs, err := tf.Format(newFuzzRecord(msg, key, val))
+		assert.NoErr(t, err)
+
+		// one record per line
+		assert.Eq(t, 1, bytes.Count(bs, []byte{'\n'}))
+		assert.True(t, bytes.HasSuffix(bs, []byte{'\n'}))
+		assert.Eq(t, -1, bytes.IndexByte(bs, '\r'))
+		assert.True(t, utf8.Valid(bs))
+	})
+}
+
+func TestEscapeLine(t *testing.T) {
+	assert.Eq(t, "abc\tdef", slog.EscapeLine("abc\tdef"))
+	assert.Eq(t, `a\nb\r\n`, slog.EscapeLine("a\nb\r\n"))
+	assert.Eq(t, `\x00\x1b[31m\x7f`, slog.EscapeLine("\x00\x1b[31m\x7f"))
+	assert.Eq(t, "�(中文", slog.EscapeLine("\xc3(中文"))
+	assert.Eq(t, `a\u2028b\u0085`, slog.EscapeLine("a\u2028b\u0085"))
+}
+
+func TestTruncateString(t *testing.T) {
+	assert.Eq(t, "hello", slog.TruncateString("hello", 5))
+	assert.Eq(t, "hello…+6 bytes", slog.TruncateString("hello world", 5))
+	// not split the UTF-8 char
+	assert.Eq(t, "a…+6 bytes", slog.TruncateString("a中文", 2))
+}
+
+func TestTextFormatter_SingleLine(t *testing.T) {
+	tf := slog.NewTextFormatter("{{message}} {{data}} {{user}}\n")
+	r := &slog.Record{
+		Time:    time.Now(),
+		Message: "multi\nline",
+		Data:    slog.M{"key": "in\nhere"},
+		Attrs:   []slog.Field{slog.String("user", "in\rhere")},
+	}
+
+	bs, err := tf.Format(r)
+	assert.NoErr(t, err)
+	assert.Eq(t, 3, bytes.Count(bs, []byte{'\n'}))
+
+	tf.SingleLine = true
+	bs, err = tf.Format(r)
+	assert.NoErr(t, err)
+	assert.Eq(t, `multi\nline {key:in\nhere} in\rhere`+"\n", string(bs))
+}
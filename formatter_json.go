@@ -2,6 +2,9 @@ package slog
 
 import (
 	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
 
 	"github.com/valyala/bytebufferpool"
 )
@@ -33,17 +36,45 @@ type JSONFormatter struct {
 	// Fields exported log fields. default is DefaultFields
 	Fields []string
 	// Aliases for output fields. you can change export field name.
+	// the custom fields in the Record.Fields, Attrs can also be renamed.
 	//
 	// item: `"field" : "output name"`
 	// eg: {"message": "msg"} export field will display "msg"
 	Aliases StringMap
+	// ExpandDotKeys expand the dotted keys to the nested objects, eg: for the ECS-based schema.
+	//
+	// eg: {"http.method": "GET", "http.status": 200} => {"http": {"method": "GET", "status": 200}}
+	//
+	// on the key conflicts with a non-object value, the dotted key will be kept as is.
+	ExpandDotKeys bool
 
+	// FlattenFields flatten the Record.Fields, Data and Extra to top level of the output,
+	// instead of export Data and Extra as "data" and "extra" objects.
+	//
+	// precedence on same key: Fields > Attrs > Data > Extra. see Record.MergedFields()
+	FlattenFields bool
 	// PrettyPrint will indent all json logs
 	PrettyPrint bool
 	// TimeFormat the time format layout. default is DefaultTimeFormat
 	TimeFormat string
+	// DateLocale render the date with the localized month, weekday names. default is nil
+	DateLocale *DateLocale
+	// LevelNames custom display names of the levels. eg: {slog.WarnLevel: "SEVERE"}
+	//
+	// Only change the output, the canonical level names are still used for parse and filtering.
+	LevelNames map[Level]string
 	// CallerFormatFunc the caller format layout. default is defined by CallerFlag
 	CallerFormatFunc CallerFormatFn
+	// EncodeOptions limit the depth, elements and render []byte for field values.
+	//
+	// if any option is set, will check the cycle references and unsupported values before encoding.
+	// otherwise, only check them on encode error.
+	EncodeOptions
+	// OnError on a field value cannot be encoded. eg: chan, func, NaN
+	//
+	// The bad value will be replaced with a placeholder, instead of failing the whole record.
+	// default will print the error to stderr.
+	OnError func(err error)
 }
 
 // NewJSONFormatter create new JSONFormatter
@@ -87,7 +118,11 @@ func (f *JSONFormatter) Format(r *Record) ([]byte, error) {
 
 		switch {
 		case field == FieldKeyDatetime:
-			logData[outName] = r.Time.Format(f.TimeFormat)
+			if f.DateLocale != nil {
+				logData[outName] = f.DateLocale.Format(r.Time, f.TimeFormat)
+			} else {
+				logData[outName] = r.Time.Format(f.TimeFormat)
+			}
 		case field == FieldKeyTimestamp:
 			logData[outName] = r.timestamp()
 		case field == FieldKeyCaller && r.Caller != nil:
@@ -97,30 +132,60 @@ func (f *JSONFormatter) Format(r *Record) ([]byte, error) {
 				logData[outName] = formatCaller(r.Caller, r.CallerFlag)
 			}
 		case field == FieldKeyLevel:
-			logData[outName] = r.LevelName()
+			logData[outName] = displayLevelName(f.LevelNames, r)
 		case field == FieldKeyChannel:
 			logData[outName] = r.Channel
 		case field == FieldKeyMessage:
 			logData[outName] = r.Message
-		case field == FieldKeyData:
+		case field == FieldKeyData && !f.FlattenFields:
 			logData[outName] = r.Data
-		case field == FieldKeyExtra:
+		case field == FieldKeyExtra && !f.FlattenFields:
 			logData[outName] = r.Extra
+		case field == FieldKeyTags:
+			logData[outName] = r.Tags
 			// default:
 			// 	logData[outName] = r.Fields[field]
 		}
 	}
 
+	custom := r.Fields
+	if f.FlattenFields {
+		custom = r.MergedFields()
+	}
+
 	// exported custom fields
-	for field, value := range r.Fields {
+	setField := func(field string, value any) {
+		if alias, ok := f.Aliases[field]; ok {
+			field = alias
+		}
+
 		fieldKey := field
 		if _, has := logData[field]; has {
 			fieldKey = "fields." + field
 		}
-
 		logData[fieldKey] = value
 	}
 
+	for field, value := range custom {
+		setField(field, value)
+	}
+	// typed fields, has been merged on FlattenFields
+	if !f.FlattenFields {
+		for _, attr := range r.Attrs {
+			if _, ok := r.Fields[attr.Key]; !ok {
+				setField(attr.Key, attr.Value())
+			}
+		}
+	}
+
+	if f.ExpandDotKeys {
+		logData = expandDotKeys(logData)
+	}
+	return encodeJSON(logData, f.PrettyPrint, &f.EncodeOptions, f.OnError)
+}
+
+// encode the log data to JSON, will replace the bad values with placeholder on encode error.
+func encodeJSON(logData M, pretty bool, opts *EncodeOptions, onErr func(err error)) ([]byte, error) {
 	// sort.Interface()
 	buf := jsonPool.Get()
 	// buf.Reset()
@@ -129,12 +194,92 @@ func (f *JSONFormatter) Format(r *Record) ([]byte, error) {
 	// buf.Reset()
 	// buf.Grow(256)
 
+	if opts.enabled() {
+		logData = opts.newEncoder(true).safeMap(logData)
+	}
+
 	encoder := json.NewEncoder(buf)
-	if f.PrettyPrint {
+	if pretty {
 		encoder.SetIndent("", "  ")
 	}
 
 	// has been added newline in Encode().
 	err := encoder.Encode(logData)
+	if err != nil {
+		// replace the bad values with placeholder, then encode again.
+		if onErr != nil {
+			onErr(err)
+		} else {
+			reportError("slog: json formatter encode error:", err)
+		}
+		err = encoder.Encode(opts.newEncoder(true).safeMap(logData))
+	}
 	return buf.Bytes(), err
 }
+
+// expand the dotted keys to the nested objects, the maps are copied.
+func expandDotKeys(mp map[string]any) M {
+	nm := make(M, len(mp))
+	var dotKeys []string
+	for k, v := range mp {
+		if strings.IndexByte(k, '.') > 0 {
+			dotKeys = append(dotKeys, k)
+		} else {
+			nm[k] = expandDotValue(v)
+		}
+	}
+
+	// sort for stable output on the keys are conflict. eg: "a.b", "a.b.c"
+	sort.Strings(dotKeys)
+	for _, k := range dotKeys {
+		setDotKey(nm, k, expandDotValue(mp[k]))
+	}
+	return nm
+}
+
+func expandDotValue(v any) any {
+	switch tv := v.(type) {
+	case M:
+		return expandDotKeys(tv)
+	case map[string]any:
+		return expandDotKeys(tv)
+	}
+	return v
+}
+
+func setDotKey(nm M, key string, v any) {
+	parts := strings.Split(key, ".")
+	cur := nm
+	for i, p := range parts {
+		if p == "" {
+			break
+		}
+
+		sub, has := cur[p]
+		if i == len(parts)-1 {
+			if !has {
+				cur[p] = v
+				return
+			}
+			break
+		}
+
+		if !has {
+			sm := make(M)
+			cur[p] = sm
+			cur = sm
+		} else if sm, ok := sub.(M); ok {
+			cur = sm
+		} else {
+			break
+		}
+	}
+
+	// conflict, keep the dotted key
+	nm[key] = v
+}
+
+// JSONBadValue format the placeholder for a value cannot be encoded to JSON.
+func JSONBadValue(v any, err error) string {
+	return fmt.Sprintf("!BADVALUE(%T): %s", v, err.Error())
+}
@@ -1,11 +1,18 @@
 package slog_test
 
 import (
+	"bytes"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"math"
 	"runtime"
+	"strconv"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/gookit/color"
 	"github.com/gookit/goutil/byteutil"
 	"github.com/gookit/goutil/dump"
 	"github.com/gookit/goutil/testutil/assert"
@@ -95,6 +102,42 @@ func TestTextFormatter_Format(t *testing.T) {
 	assert.NotContains(t, logTxt, "}}")
 }
 
+func TestTextFormatter_template(t *testing.T) {
+	r := newLogRecord("template message")
+	r.Fields = slog.M{"user_id": 23, "empty": ""}
+	r.Data = slog.M{"sub": slog.M{"key": "val"}}
+	r.Extra = nil
+	r.Init(false)
+
+	f := slog.NewTextFormatter("[{{level:-6}}] {{fields.user_id}} {{data.sub.key}}{{#extra}} [{{extra}}]{{/extra}}{{#data}} ok{{/data}}\n")
+	bs, err := f.Format(r)
+	assert.NoErr(t, err)
+	assert.Eq(t, "[INFO  ] 23 val ok\n", string(bs))
+	assert.Eq(t, []string{"level", "fields.user_id", "data.sub.key", "extra"}, f.Fields())
+
+	// empty field and not exists path
+	f.SetTemplate("{{message:20}}{{#empty}} empty={{empty}}{{/empty}}{{#fields.user_id}} uid={{user_id}}{{/fields.user_id}}{{extra.none}}|")
+	bs, err = f.Format(r)
+	assert.NoErr(t, err)
+	assert.Eq(t, "    template message uid=23|", string(bs))
+
+	// custom funcs
+	f.SetTemplate("{{level}} {{app}}{{#none}} {{none}}{{/none}}")
+	f.AddFunc("app", func(r *slog.Record) string { return "order-api" })
+	f.AddFunc("none", func(r *slog.Record) string { return "" })
+	f.AddFunc("level", func(r *slog.Record) string { return "L" + strconv.Itoa(int(r.Level)) })
+	bs, err = f.Format(r)
+	assert.NoErr(t, err)
+	assert.Eq(t, "L600 order-api", string(bs))
+
+	// with color, pad before render color
+	f = slog.NewTextFormatter("{{level:-6}}|")
+	f.EnableColor = true
+	bs, err = f.Format(r)
+	assert.NoErr(t, err)
+	assert.Eq(t, color.FgGreen.Render("INFO  ")+"|", string(bs))
+}
+
 func TestNewJSONFormatter(t *testing.T) {
 	f := slog.NewJSONFormatter()
 	f.AddField(slog.FieldKeyTimestamp)
@@ -142,3 +185,336 @@ func TestNewJSONFormatter(t *testing.T) {
 
 	})
 }
+
+func TestSeparatorFormatter_Format(t *testing.T) {
+	r := newLogRecord("TEST_LOG_MESSAGE")
+
+	tests := []struct {
+		sep, suffix string
+	}{
+		{slog.SepNewline, "}\n"},
+		{slog.SepCRLF, "}\r\n"},
+		{slog.SepNUL, "}\x00"},
+		{slog.SepNone, "}"},
+	}
+
+	for _, tt := range tests {
+		f := slog.NewSeparatorFormatter(slog.NewJSONFormatter(), tt.sep)
+		bts, err := f.Format(r)
+		assert.NoErr(t, err)
+		assert.True(t, strings.HasSuffix(string(bts), tt.suffix))
+	}
+
+	f := slog.NewSeparatorFormatter(slog.NewTextFormatter("{{message}}\r\n"), slog.SepNUL)
+	bts, err := f.Format(r)
+	assert.NoErr(t, err)
+	assert.Eq(t, "TEST_LOG_MESSAGE\x00", string(bts))
+
+	// formatter error
+	f = slog.NewSeparatorFormatter(slog.FormatterFunc(func(r *slog.Record) ([]byte, error) {
+		return nil, fmt.Errorf("format error")
+	}), slog.SepNone)
+	_, err = f.Format(r)
+	assert.Err(t, err)
+}
+
+func TestJSONFormatter_FlattenFields(t *testing.T) {
+	r := newLogRecord("TEST_LOG_MESSAGE")
+	r.Fields = slog.M{"user": "inhere", "level": "custom"}
+	r.Data = slog.M{"user": "data-user", "order": 23}
+	r.Extra = slog.M{"order": 0, "hostname": "host1"}
+
+	mp := r.MergedFields()
+	assert.Eq(t, "inhere", mp["user"])
+	assert.Eq(t, 23, mp["order"])
+	assert.Eq(t, "host1", mp["hostname"])
+
+	f := slog.NewJSONFormatter(func(f *slog.JSONFormatter) {
+		f.FlattenFields = true
+	})
+	bts, err := f.Format(r)
+	assert.NoErr(t, err)
+
+	str := string(bts)
+	assert.StrContains(t, str, `"user":"inhere"`)
+	assert.StrContains(t, str, `"order":23`)
+	assert.StrContains(t, str, `"hostname":"host1"`)
+	assert.StrContains(t, str, `"fields.level":"custom"`)
+	assert.NotContains(t, str, `"data":`)
+	assert.NotContains(t, str, `"extra":`)
+}
+
+func TestJSONFormatter_ExpandDotKeys(t *testing.T) {
+	r := newLogRecord("TEST_LOG_MESSAGE")
+	r.Fields = slog.M{
+		"http.method":   "GET",
+		"http.status":   200,
+		"user":          "inhere",
+		"app":           "demo",
+		"app.id":        23,
+		"trace.span.id": "abc",
+	}
+	r.Data = slog.M{"sub.key": "val"}
+
+	f := slog.NewJSONFormatter(func(f *slog.JSONFormatter) {
+		f.ExpandDotKeys = true
+		f.Aliases = slog.StringMap{
+			slog.FieldKeyDatetime: "ts",
+			slog.FieldKeyMessage:  "msg",
+			"user":                "username",
+		}
+	})
+	bts, err := f.Format(r)
+	assert.NoErr(t, err)
+
+	str := string(bts)
+	assert.StrContains(t, str, `"http":{"method":"GET","status":200}`)
+	assert.StrContains(t, str, `"trace":{"span":{"id":"abc"}}`)
+	assert.StrContains(t, str, `"data":{"sub":{"key":"val"}}`)
+	assert.StrContains(t, str, `"msg":"TEST_LOG_MESSAGE"`)
+	assert.StrContains(t, str, `"ts":`)
+	assert.StrContains(t, str, `"username":"inhere"`)
+	// conflict with the non-object value, keep the dotted key
+	assert.StrContains(t, str, `"app":"demo"`)
+	assert.StrContains(t, str, `"app.id":23`)
+	assert.NotContains(t, str, `"http.method"`)
+
+	// not expand by default
+	f.ExpandDotKeys = false
+	bts, err = f.Format(r)
+	assert.NoErr(t, err)
+	assert.StrContains(t, string(bts), `"http.method":"GET"`)
+}
+
+func TestECSFormatter(t *testing.T) {
+	r := newLogRecord("TEST_LOG_MESSAGE")
+	r.Level = slog.ErrorLevel
+	r.Tags = []string{"order"}
+	r.Fields = slog.M{
+		"trace_id":         "trace-1",
+		"user_id":          23,
+		slog.FieldKeyError: errors.New("db error"),
+		slog.FieldKeyStack: "main.main\n\tmain.go:10",
+	}
+	r.SetCaller(&runtime.Frame{Function: "main.createOrder", File: "/app/order.go", Line: 23})
+
+	f := slog.NewECSFormatter(func(f *slog.ECSFormatter) {
+		f.ServiceName = "order-api"
+	})
+	bts, err := f.Format(r)
+	assert.NoErr(t, err)
+
+	mp := make(map[string]any)
+	assert.NoErr(t, json.Unmarshal(bts, &mp))
+	assert.Eq(t, "TEST_LOG_MESSAGE", mp["message"])
+	assert.Eq(t, []any{"order"}, mp["tags"])
+	assert.Eq(t, slog.ECSVersion, mp["ecs"].(map[string]any)["version"])
+	assert.Eq(t, "order-api", mp["service"].(map[string]any)["name"])
+	assert.Eq(t, "trace-1", mp["trace"].(map[string]any)["id"])
+	assert.Eq(t, float64(23), mp["user"].(map[string]any)["id"])
+
+	ts, err := time.Parse(slog.ECSTimeFormat, mp["@timestamp"].(string))
+	assert.NoErr(t, err)
+	assert.Eq(t, r.Time.UnixMilli(), ts.UnixMilli())
+
+	logMp := mp["log"].(map[string]any)
+	assert.Eq(t, "error", logMp["level"])
+	assert.Eq(t, r.Channel, logMp["logger"])
+	origin := logMp["origin"].(map[string]any)
+	assert.Eq(t, "main.createOrder", origin["function"])
+	assert.Eq(t, map[string]any{"name": "/app/order.go", "line": float64(23)}, origin["file"])
+
+	errMp := mp["error"].(map[string]any)
+	assert.Eq(t, "db error", errMp["message"])
+	assert.Eq(t, "*errors.errorString", errMp["type"])
+	assert.StrContains(t, errMp["stack_trace"].(string), "main.go:10")
+}
+
+func TestJSONFormatter_badValues(t *testing.T) {
+	var errs []error
+	f := slog.NewJSONFormatter(func(f *slog.JSONFormatter) {
+		f.Fields = []string{slog.FieldKeyMessage, slog.FieldKeyData}
+		f.OnError = func(err error) {
+			errs = append(errs, err)
+		}
+	})
+
+	tests := []struct {
+		name  string
+		value any
+		want  string
+	}{
+		{"chan", make(chan int), `"key":"!BADVALUE(chan int): json: unsupported type: chan int"`},
+		{"func", func() {}, `"key":"!BADVALUE(func()): json: unsupported type: func()"`},
+		{"NaN", math.NaN(), `"key":"!BADVALUE(float64): json: unsupported value: NaN"`},
+		{"nested", slog.M{"sub": []any{1, math.Inf(1)}}, `"key":{"sub":[1,"!BADVALUE(float64): json: unsupported value: +Inf"]}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newLogRecord("bad value")
+			r.Data = slog.M{"key": tt.value, "ok": 23}
+
+			bts, err := f.Format(r)
+			assert.NoErr(t, err)
+			str := string(bts)
+			assert.StrContains(t, str, tt.want)
+			assert.StrContains(t, str, `"ok":23`)
+			assert.StrContains(t, str, `"message":"bad value"`)
+		})
+	}
+	assert.Len(t, errs, len(tests))
+}
+
+type cycleNode struct {
+	Name string      `json:"name"`
+	Next *cycleNode  `json:"next"`
+	Skip string      `json:"-"`
+	Meta interface{} `json:"meta,omitempty"`
+}
+
+func TestJSONFormatter_cycleAndDepth(t *testing.T) {
+	var errs int
+	f := slog.NewJSONFormatter(func(f *slog.JSONFormatter) {
+		f.Fields = []string{slog.FieldKeyData}
+		f.OnError = func(err error) { errs++ }
+	})
+
+	// self-referencing map
+	mp := map[string]any{"a": 1}
+	mp["self"] = mp
+	r := newLogRecord("cycle map")
+	r.Data = slog.M{"mp": mp}
+
+	bts, err := f.Format(r)
+	assert.NoErr(t, err)
+	assert.StrContains(t, string(bts), `"self":"!CYCLE(map[string]interface {})"`)
+	assert.StrContains(t, string(bts), `"a":1`)
+
+	// self-referencing struct pointer
+	node := &cycleNode{Name: "n1", Skip: "skip"}
+	node.Next = &cycleNode{Name: "n2", Next: node}
+	r.Data = slog.M{"node": node}
+
+	bts, err = f.Format(r)
+	assert.NoErr(t, err)
+	str := string(bts)
+	assert.StrContains(t, str, `{"name":"n1","next":{"name":"n2","next":"!CYCLE(*slog_test.cycleNode)"}}`)
+	assert.NotContains(t, str, "skip")
+	assert.Eq(t, 2, errs)
+
+	// max depth
+	f.MaxDepth = 2
+	r.Data = slog.M{"deep": slog.M{"l2": slog.M{"l3": "val"}}, "list": []int{1, 2}}
+	bts, err = f.Format(r)
+	assert.NoErr(t, err)
+	str = string(bts)
+	assert.StrContains(t, str, `"deep":{"l2":"!MAXDEPTH(slog.M)"}`)
+	assert.StrContains(t, str, `"list":[1,2]`)
+	assert.Eq(t, 2, errs)
+}
+
+func TestFormatter_MaxElements(t *testing.T) {
+	r := newLogRecord("TEST_LOG_MESSAGE")
+	r.Data = slog.M{
+		"list": []int{1, 2, 3, 4, 5},
+		"mp":   map[string]int{"a": 1, "b": 2, "c": 3},
+	}
+	r.Extra = nil
+
+	jf := slog.NewJSONFormatter(func(f *slog.JSONFormatter) {
+		f.Fields = []string{slog.FieldKeyData}
+		f.MaxElements = 2
+	})
+	bts, err := jf.Format(r)
+	assert.NoErr(t, err)
+	str := string(bts)
+	assert.StrContains(t, str, `"list":[1,2,"…+3 more"]`)
+	assert.StrContains(t, str, `"mp":{"a":1,"b":2,"…":"+1 more"}`)
+
+	tf := slog.NewTextFormatter("{{data}}")
+	tf.MaxElements = 2
+	tf.MaxDepth = 1
+	bts, err = tf.Format(r)
+	assert.NoErr(t, err)
+	str = string(bts)
+	assert.StrContains(t, str, "list:!MAXDEPTH([]int)")
+
+	tf.MaxDepth = 0
+	bts, err = tf.Format(r)
+	assert.NoErr(t, err)
+	str = string(bts)
+	assert.StrContains(t, str, "list:[1 2 …+3 more]")
+	assert.StrContains(t, str, "…:+1 more")
+}
+
+func TestFormatter_BytesFormat(t *testing.T) {
+	r := newLogRecord("TEST_LOG_MESSAGE")
+	r.Data = slog.M{"bin": []byte("hello")}
+	r.Extra = nil
+
+	tests := []struct {
+		bf   slog.BytesFormat
+		json string
+		text string
+	}{
+		{slog.BytesDefault, `"bin":"aGVsbG8="`, "bin:hello"},
+		{slog.BytesHex, `"bin":"68656c6c6f"`, "bin:68656c6c6f"},
+		{slog.BytesBase64, `"bin":"aGVsbG8="`, "bin:aGVsbG8="},
+		{slog.BytesLen, `"bin":"[]byte(len=5)"`, "bin:[]byte(len=5)"},
+	}
+
+	for _, tt := range tests {
+		jf := slog.NewJSONFormatter()
+		jf.BytesFormat = tt.bf
+		bts, err := jf.Format(r)
+		assert.NoErr(t, err)
+		assert.StrContains(t, string(bts), tt.json)
+
+		tf := slog.NewTextFormatter("{{data}}")
+		tf.BytesFormat = tt.bf
+		bts, err = tf.Format(r)
+		assert.NoErr(t, err)
+		assert.StrContains(t, string(bts), tt.text)
+	}
+}
+
+func TestChainFormatter(t *testing.T) {
+	r := newLogRecord("chain message")
+
+	f := slog.ChainFormatter(slog.NewTextFormatter("{{level}} {{message}}\n"),
+		slog.PrefixMiddleware("[worker-3] "),
+		slog.SeparatorMiddleware(slog.SepCRLF),
+		slog.BytesMiddleware(func(bts []byte) ([]byte, error) {
+			return bytes.ToUpper(bts), nil
+		}),
+	)
+
+	bts, err := f.Format(r)
+	assert.NoErr(t, err)
+	assert.Eq(t, "[WORKER-3] INFO CHAIN MESSAGE\r\n", string(bts))
+	assert.Eq(t, "{{level}} {{message}}\n", slog.AsTextFormatter(f).Template())
+	assert.Panics(t, func() { slog.AsJSONFormatter(f) })
+
+	// error on the middleware
+	f = slog.ChainFormatter(slog.NewJSONFormatter(), slog.BytesMiddleware(func(bts []byte) ([]byte, error) {
+		return nil, errors.New("encrypt error")
+	}))
+	_, err = f.Format(r)
+	assert.ErrMsg(t, err, "encrypt error")
+
+	// suffix
+	f = slog.ChainFormatter(slog.NewTextFormatter("{{message}}\n"), slog.SuffixMiddleware(" [host-1]"))
+	bts, err = f.Format(r)
+	assert.NoErr(t, err)
+	assert.Eq(t, "chain message [host-1]\n", string(bts))
+
+	f = slog.ChainFormatter(slog.NewTextFormatter("{{message}}"), slog.SuffixMiddleware(" [host-1]"))
+	bts, err = f.Format(r)
+	assert.NoErr(t, err)
+	assert.Eq(t, "chain message [host-1]", string(bts))
+
+	// no middlewares
+	jf := slog.NewJSONFormatter()
+	assert.Eq(t, slog.Formatter(jf), slog.ChainFormatter(jf))
+}
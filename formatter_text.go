@@ -1,6 +1,10 @@
 package slog
 
 import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
 	"github.com/gookit/color"
 	"github.com/valyala/bytebufferpool"
 )
@@ -23,21 +27,47 @@ var ColorTheme = map[Level]color.Color{
 	// TraceLevel:  color.FgLightGreen,
 }
 
+// TemplateFunc a custom render func for the text template. see TextFormatter.Funcs
+type TemplateFunc func(r *Record) string
+
 // TextFormatter definition
+//
+// The template syntax:
+//
+//   - {{level}} render the field. the unknown field name will be rendered as is.
+//   - {{fields.user_id}}, {{data.sub.key}}, {{extra.host}} render the value in the Record.Fields, Data, Extra
+//   - {{level:-7s}} render the field with padding/width, the verb is same as the fmt. the "s" can be omitted.
+//   - {{#data}} [{{data}}]{{/data}} the conditional section, only render it on the field is not empty.
+//     the empty check is like the JSON omitempty.
+//   - {{myfunc}} render by the custom func. see TextFormatter.Funcs
+//
+// The Record.Attrs that not named in the template are rendered after the {{data}} as "key=value".
 type TextFormatter struct {
 	// template text template for render output log messages
 	template string
-	// fields list, parsed from template string.
-	// NOTICE: fields contains no-field items.
-	// eg: ["level", "}}"}
-	fields []string
+	// nodes parsed from template string.
+	nodes []*tplNode
+	// the field names in the template
+	tplFields map[string]bool
 
 	// TimeFormat the time format layout. default is DefaultTimeFormat
 	TimeFormat string
+	// DateLocale render the date with the localized month, weekday names. default is nil
+	DateLocale *DateLocale
+	// LevelNames custom display names of the levels. eg: {slog.WarnLevel: "SEVERE"}
+	//
+	// Only change the output, the canonical level names are still used for parse and filtering.
+	LevelNames map[Level]string
 	// Enable color on print log to terminal
 	EnableColor bool
 	// ColorTheme setting on render color on terminal
 	ColorTheme map[Level]color.Color
+	// FieldColors the colors of the other fields. eg: {"datetime": color.Gray}
+	FieldColors map[string]color.Color
+	// KeyColor the color of the field keys in the template. eg: "level=" in "level={{level}}". 0 is not colored.
+	KeyColor color.Color
+	// PlainMessage don't render the message by the level color
+	PlainMessage bool
 	// FullDisplay Whether to display when record.Data, record.Extra, etc. are empty
 	FullDisplay bool
 	// EncodeFunc data encode for Record.Data, Record.Extra, etc.
@@ -46,6 +76,15 @@ type TextFormatter struct {
 	EncodeFunc func(v any) string
 	// CallerFormatFunc the caller format layout. default is defined by CallerFlag
 	CallerFormatFunc CallerFormatFn
+	// EncodeOptions limit the depth, elements and render []byte for field values.
+	EncodeOptions
+	// SingleLine escape the line breaks, control chars and invalid UTF-8 in the rendered values,
+	// guarantee one record per line. see EscapeLine()
+	SingleLine bool
+	// Funcs custom render funcs, the name can be used as field in the template. eg: {{hostname}}
+	//
+	// The func has higher priority than the built-in fields.
+	Funcs map[string]TemplateFunc
 
 	// TODO BeforeFunc call it before format, update fields or other
 	// BeforeFunc func(r *Record)
@@ -99,7 +138,12 @@ func (f *TextFormatter) WithOptions(fns ...TextFormatterFn) *TextFormatter {
 // SetTemplate set the log format template and update field-map
 func (f *TextFormatter) SetTemplate(fmtTpl string) {
 	f.template = fmtTpl
-	f.fields = parseTemplateToFields(fmtTpl)
+	f.nodes = parseTemplate(fmtTpl)
+
+	f.tplFields = make(map[string]bool)
+	for _, field := range appendNodeFields(nil, f.nodes) {
+		f.tplFields[field] = true
+	}
 }
 
 // Template get
@@ -107,6 +151,15 @@ func (f *TextFormatter) Template() string {
 	return f.template
 }
 
+// AddFunc add a custom render func for the template. see TextFormatter.Funcs
+func (f *TextFormatter) AddFunc(name string, fn TemplateFunc) *TextFormatter {
+	if f.Funcs == nil {
+		f.Funcs = make(map[string]TemplateFunc)
+	}
+	f.Funcs[name] = fn
+	return f
+}
+
 // WithEnableColor enable color on print log to terminal
 func (f *TextFormatter) WithEnableColor(enable bool) *TextFormatter {
 	f.EnableColor = enable
@@ -115,10 +168,15 @@ func (f *TextFormatter) WithEnableColor(enable bool) *TextFormatter {
 
 // Fields get export field list
 func (f *TextFormatter) Fields() []string {
-	ss := make([]string, 0, len(f.fields)/2)
-	for _, s := range f.fields {
-		if s[0] >= 'a' && s[0] <= 'z' {
-			ss = append(ss, s)
+	return appendNodeFields(nil, f.nodes)
+}
+
+func appendNodeFields(ss []string, nodes []*tplNode) []string {
+	for _, nd := range nodes {
+		if nd.isSec {
+			ss = appendNodeFields(ss, nd.section)
+		} else if nd.field != "" {
+			ss = append(ss, nd.field)
 		}
 	}
 	return ss
@@ -134,66 +192,226 @@ func (f *TextFormatter) Format(r *Record) ([]byte, error) {
 	buf := textPool.Get()
 	defer textPool.Put(buf)
 
-	for _, field := range f.fields {
-		// is not field name. eg: "}}] "
-		if field[0] < 'a' || field[0] > 'z' {
-			// remove left "}}"
-			if len(field) > 1 && field[0:2] == "}}" {
-				buf.WriteString(field[2:])
-			} else {
-				buf.WriteString(field)
-			}
-			continue
-		}
+	f.renderNodes(buf, r, f.nodes)
+	// return buf.Bytes(), nil
+	return buf.B, nil
+}
 
+func (f *TextFormatter) renderNodes(buf *bytebufferpool.ByteBuffer, r *Record, nodes []*tplNode) {
+	for _, nd := range nodes {
 		switch {
-		case field == FieldKeyDatetime:
-			buf.B = r.Time.AppendFormat(buf.B, f.TimeFormat)
-		case field == FieldKeyTimestamp:
-			buf.WriteString(r.timestamp())
-		case field == FieldKeyCaller && r.Caller != nil:
-			var callerLog string
-			if f.CallerFormatFunc != nil {
-				callerLog = f.CallerFormatFunc(r.Caller)
+		case nd.field == "":
+			if f.EnableColor && f.KeyColor != 0 {
+				buf.WriteString(f.renderKeyColor(nd.text))
 			} else {
-				callerLog = formatCaller(r.Caller, r.CallerFlag)
+				buf.WriteString(nd.text)
 			}
-			buf.WriteString(callerLog)
-		case field == FieldKeyLevel:
-			// output colored logs for console
-			if f.EnableColor {
-				buf.WriteString(f.renderColorByLevel(r.LevelName(), r.Level))
-			} else {
-				buf.WriteString(r.LevelName())
+		case nd.isSec:
+			if !f.isEmptyField(r, nd.field) {
+				f.renderNodes(buf, r, nd.section)
+			}
+		case nd.verb == "" && (!f.EnableColor || len(f.FieldColors) == 0):
+			f.writeField(buf, r, nd.field, f.EnableColor)
+		default:
+			// render without color, then pad and color it
+			tmp := textPool.Get()
+			f.writeField(tmp, r, nd.field, false)
+			s := tmp.String()
+			textPool.Put(tmp)
+
+			if nd.verb != "" {
+				s = fmt.Sprintf(nd.verb, s)
 			}
-		case field == FieldKeyChannel:
-			buf.WriteString(r.Channel)
-		case field == FieldKeyMessage:
-			// output colored logs for console
 			if f.EnableColor {
-				buf.WriteString(f.renderColorByLevel(r.Message, r.Level))
-			} else {
-				buf.WriteString(r.Message)
+				if c, ok := f.fieldColor(r, nd.field); ok {
+					s = c.Render(s)
+				}
 			}
-		case field == FieldKeyData:
-			if f.FullDisplay || len(r.Data) > 0 {
-				buf.WriteString(f.EncodeFunc(r.Data))
+			buf.WriteString(s)
+		}
+	}
+}
+
+// write the field value to the buffer
+func (f *TextFormatter) writeField(buf *bytebufferpool.ByteBuffer, r *Record, field string, colored bool) {
+	if fn, ok := f.Funcs[field]; ok {
+		f.writeString(buf, fn(r))
+		return
+	}
+
+	switch {
+	case field == FieldKeyDatetime:
+		if f.DateLocale != nil {
+			buf.B = f.DateLocale.AppendFormat(buf.B, r.Time, f.TimeFormat)
+		} else {
+			buf.B = r.Time.AppendFormat(buf.B, f.TimeFormat)
+		}
+	case field == FieldKeyTimestamp:
+		buf.WriteString(r.timestamp())
+	case field == FieldKeyCaller && r.Caller != nil:
+		if f.CallerFormatFunc != nil {
+			f.writeString(buf, f.CallerFormatFunc(r.Caller))
+		} else {
+			f.writeString(buf, formatCaller(r.Caller, r.CallerFlag))
+		}
+	case field == FieldKeyLevel:
+		// output colored logs for console
+		if colored {
+			buf.WriteString(f.renderColorByLevel(displayLevelName(f.LevelNames, r), r.Level))
+		} else {
+			buf.WriteString(displayLevelName(f.LevelNames, r))
+		}
+	case field == FieldKeyChannel:
+		f.writeString(buf, r.Channel)
+	case field == FieldKeyMessage:
+		// output colored logs for console
+		if colored && !f.PlainMessage {
+			msg := r.Message
+			if f.SingleLine {
+				msg = EscapeLine(msg)
 			}
-		case field == FieldKeyExtra:
-			if f.FullDisplay || len(r.Extra) > 0 {
-				buf.WriteString(f.EncodeFunc(r.Extra))
+			buf.WriteString(f.renderColorByLevel(msg, r.Level))
+		} else {
+			f.writeString(buf, r.Message)
+		}
+	case field == FieldKeyData:
+		if f.FullDisplay || len(r.Data) > 0 {
+			f.writeString(buf, f.encode(r.Data))
+			f.writeAttrs(buf, r, true)
+		} else {
+			f.writeAttrs(buf, r, false)
+		}
+	case field == FieldKeyExtra:
+		if f.FullDisplay || len(r.Extra) > 0 {
+			f.writeString(buf, f.encode(r.Extra))
+		}
+	case field == FieldKeyTags:
+		for i, tag := range r.Tags {
+			if i > 0 {
+				buf.WriteByte(',')
 			}
-		default:
-			if _, ok := r.Fields[field]; ok {
-				buf.WriteString(f.EncodeFunc(r.Fields[field]))
+			f.writeString(buf, tag)
+		}
+	default:
+		if v, ok := r.Fields[field]; ok {
+			f.writeString(buf, f.encode(v))
+		} else if attr, ok := r.Attr(field); ok {
+			if f.SingleLine {
+				buf.B = appendEscapeLine(buf.B, string(attr.AppendText(nil)))
 			} else {
-				buf.WriteString(field)
+				buf.B = attr.AppendText(buf.B)
 			}
+		} else if v, ok, isPath := lookupRecordPath(r, field); isPath {
+			if ok {
+				f.writeString(buf, f.encode(v))
+			}
+		} else {
+			buf.WriteString(field)
 		}
 	}
+}
 
-	// return buf.Bytes(), nil
-	return buf.B, nil
+// write the Attrs that not named in the template, as "key=value". sep - add a space before the first one.
+func (f *TextFormatter) writeAttrs(buf *bytebufferpool.ByteBuffer, r *Record, sep bool) {
+	for _, attr := range r.Attrs {
+		if f.inTemplate(attr.Key) {
+			continue
+		}
+
+		if sep {
+			buf.WriteByte(' ')
+		}
+		sep = true
+
+		f.writeString(buf, attr.Key)
+		buf.WriteByte('=')
+		if f.SingleLine {
+			buf.B = appendEscapeLine(buf.B, string(attr.AppendText(nil)))
+		} else {
+			buf.B = attr.AppendText(buf.B)
+		}
+	}
+}
+
+// check the attr key is named in the template. eg: {{user}}, {{fields.user}}
+func (f *TextFormatter) inTemplate(key string) bool {
+	return f.tplFields[key] || f.tplFields["fields."+key]
+}
+
+// check has the Attrs that not named in the template
+func (f *TextFormatter) hasAttrs(r *Record) bool {
+	for _, attr := range r.Attrs {
+		if !f.inTemplate(attr.Key) {
+			return true
+		}
+	}
+	return false
+}
+
+// check the field value is empty, for the conditional section
+func (f *TextFormatter) isEmptyField(r *Record, field string) bool {
+	if fn, ok := f.Funcs[field]; ok {
+		return fn(r) == ""
+	}
+
+	switch field {
+	case FieldKeyDatetime, FieldKeyTimestamp, FieldKeyLevel:
+		return false
+	case FieldKeyCaller:
+		return r.Caller == nil
+	case FieldKeyChannel:
+		return r.Channel == ""
+	case FieldKeyMessage:
+		return r.Message == ""
+	case FieldKeyData:
+		return len(r.Data) == 0 && !f.hasAttrs(r)
+	case FieldKeyExtra:
+		return len(r.Extra) == 0
+	case FieldKeyTags:
+		return len(r.Tags) == 0
+	}
+
+	if v, ok := r.Fields[field]; ok {
+		return isEmptyAny(v)
+	}
+	if attr, ok := r.Attr(field); ok {
+		return isEmptyAny(attr.Value())
+	}
+
+	v, ok, _ := lookupRecordPath(r, field)
+	return !ok || isEmptyAny(v)
+}
+
+// lookup the value by the field path. eg: "fields.user_id", "data.sub.key", "extra.host"
+func lookupRecordPath(r *Record, field string) (v any, ok, isPath bool) {
+	prefix, path, found := strings.Cut(field, ".")
+	if !found {
+		return nil, false, false
+	}
+
+	switch prefix {
+	case "fields":
+		if v, ok = lookupPath(r.Fields, path); !ok {
+			if attr, has := r.Attr(path); has {
+				v, ok = attr.Value(), true
+			}
+		}
+	case FieldKeyData:
+		v, ok = lookupPath(r.Data, path)
+	case FieldKeyExtra:
+		v, ok = lookupPath(r.Extra, path)
+	default:
+		return nil, false, false
+	}
+	return v, ok, true
+}
+
+// encode the value, will apply the EncodeOptions on it is set.
+func (f *TextFormatter) encode(v any) string {
+	if f.EncodeOptions.enabled() {
+		v = f.newEncoder(false).safeValue(v)
+	}
+	return f.EncodeFunc(v)
 }
 
 func (f *TextFormatter) beforeFormat() {
@@ -206,6 +424,86 @@ func (f *TextFormatter) beforeFormat() {
 	}
 }
 
+// write the rendered value, will escape it on SingleLine is enabled.
+func (f *TextFormatter) writeString(buf *bytebufferpool.ByteBuffer, s string) {
+	if f.SingleLine {
+		buf.B = appendEscapeLine(buf.B, s)
+	} else {
+		buf.WriteString(s)
+	}
+}
+
+// EscapeLine escape the string to a single line: "\n", "\r" are escaped as `\n`, `\r`,
+// the other control chars(except tab) are escaped as `\x00`, and the invalid UTF-8 bytes
+// are replaced with U+FFFD.
+func EscapeLine(s string) string {
+	for i := 0; i < len(s); i++ {
+		if c := s[i]; c < 0x20 && c != '\t' || c >= 0x7f {
+			return string(appendEscapeLine(make([]byte, 0, len(s)+8), s))
+		}
+	}
+	return s
+}
+
+const hexDigits = "0123456789abcdef"
+
+func appendEscapeLine(dst []byte, s string) []byte {
+	for i := 0; i < len(s); {
+		c := s[i]
+		if c >= 0x20 && c < 0x7f || c == '\t' {
+			dst = append(dst, c)
+			i++
+			continue
+		}
+
+		switch c {
+		case '\n':
+			dst = append(dst, '\\', 'n')
+			i++
+			continue
+		case '\r':
+			dst = append(dst, '\\', 'r')
+			i++
+			continue
+		}
+
+		if c < utf8.RuneSelf {
+			dst = append(dst, '\\', 'x', hexDigits[c>>4], hexDigits[c&0xf])
+			i++
+			continue
+		}
+
+		r, size := utf8.DecodeRuneInString(s[i:])
+		switch {
+		case r == utf8.RuneError && size == 1:
+			dst = utf8.AppendRune(dst, utf8.RuneError)
+		case r == '\u2028' || r == '\u2029' || r == '\u0085':
+			// unicode line separators
+			dst = append(dst, `\u`...)
+			dst = append(dst, hexDigits[r>>12&0xf], hexDigits[r>>8&0xf], hexDigits[r>>4&0xf], hexDigits[r&0xf])
+		default:
+			dst = append(dst, s[i:i+size]...)
+		}
+		i += size
+	}
+	return dst
+}
+
+// get the color of the field
+func (f *TextFormatter) fieldColor(r *Record, field string) (c color.Color, ok bool) {
+	switch field {
+	case FieldKeyLevel:
+		c, ok = f.ColorTheme[r.Level]
+	case FieldKeyMessage:
+		if !f.PlainMessage {
+			c, ok = f.ColorTheme[r.Level]
+		}
+	default:
+		c, ok = f.FieldColors[field]
+	}
+	return
+}
+
 func (f *TextFormatter) renderColorByLevel(s string, l Level) string {
 	if theme, ok := f.ColorTheme[l]; ok {
 		return theme.Render(s)
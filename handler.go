@@ -1,6 +1,9 @@
 package slog
 
-import "io"
+import (
+	"io"
+	"sync/atomic"
+)
 
 //
 // Handler interface
@@ -23,6 +26,12 @@ type Handler interface {
 	Handle(*Record) error
 }
 
+// Reopener the handler can reopen the output file, for the files are rotated by external tools.
+// eg: logrotate move the file then send SIGHUP. see Logger.ReopenAll()
+type Reopener interface {
+	Reopen() error
+}
+
 // LevelFormattable support limit log levels and provide formatter
 type LevelFormattable interface {
 	Formattable
@@ -54,24 +63,27 @@ func NewLvFormatter(maxLv Level) *LevelWithFormatter {
 	return &LevelWithFormatter{Level: maxLv}
 }
 
-// SetMaxLevel set max level for log message
+// SetMaxLevel set max level for log message. it is safe to call concurrently with logging.
 func (h *LevelWithFormatter) SetMaxLevel(maxLv Level) {
-	h.Level = maxLv
+	atomic.StoreUint32((*uint32)(&h.Level), uint32(maxLv))
 }
 
 // IsHandling Check if the current level can be handling
 func (h *LevelWithFormatter) IsHandling(level Level) bool {
-	return h.Level.ShouldHandling(level)
+	return Level(atomic.LoadUint32((*uint32)(&h.Level))).ShouldHandling(level)
 }
 
 // LevelsWithFormatter struct definition
 //
 // - support set log formatter
 // - support setting multi log levels
+// - support change to max level mode by SetMaxLevel(), it is safe to call concurrently with logging.
 type LevelsWithFormatter struct {
 	FormattableTrait
 	// Levels for log message
 	Levels []Level
+	// the max level set by SetMaxLevel(). 0 is use the Levels
+	maxLevel atomic.Uint32
 }
 
 // NewLvsFormatter create new instance
@@ -82,10 +94,20 @@ func NewLvsFormatter(levels []Level) *LevelsWithFormatter {
 // SetLimitLevels set limit levels for log message
 func (h *LevelsWithFormatter) SetLimitLevels(levels []Level) {
 	h.Levels = levels
+	h.maxLevel.Store(0)
+}
+
+// SetMaxLevel set max level for log message, the Levels will be ignored.
+func (h *LevelsWithFormatter) SetMaxLevel(maxLv Level) {
+	h.maxLevel.Store(uint32(maxLv))
 }
 
 // IsHandling Check if the current level can be handling
 func (h *LevelsWithFormatter) IsHandling(level Level) bool {
+	if maxLv := Level(h.maxLevel.Load()); maxLv > 0 {
+		return maxLv.ShouldHandling(level)
+	}
+
 	for _, l := range h.Levels {
 		if l == level {
 			return true
@@ -117,34 +139,41 @@ const (
 )
 
 // LevelHandling struct definition
+//
+// The levels can be changed concurrently with logging.
 type LevelHandling struct {
 	// level check mode. default is LevelModeList
-	lvMode LevelMode
+	lvMode atomic.Uint32
 	// max level for log message. if current level <= Level will log message
-	maxLevel Level
+	maxLevel atomic.Uint32
 	// levels limit for log message
-	levels []Level
+	levels atomic.Pointer[[]Level]
 }
 
 // SetMaxLevel set max level for log message
 func (h *LevelHandling) SetMaxLevel(maxLv Level) {
-	h.lvMode = LevelModeMax
-	h.maxLevel = maxLv
+	h.maxLevel.Store(uint32(maxLv))
+	h.lvMode.Store(uint32(LevelModeMax))
 }
 
 // SetLimitLevels set limit levels for log message
 func (h *LevelHandling) SetLimitLevels(levels []Level) {
-	h.lvMode = LevelModeList
-	h.levels = levels
+	h.levels.Store(&levels)
+	h.lvMode.Store(uint32(LevelModeList))
 }
 
 // IsHandling Check if the current level can be handling
 func (h *LevelHandling) IsHandling(level Level) bool {
-	if h.lvMode == LevelModeMax {
-		return h.maxLevel.ShouldHandling(level)
+	if LevelMode(h.lvMode.Load()) == LevelModeMax {
+		return Level(h.maxLevel.Load()).ShouldHandling(level)
+	}
+
+	levels := h.levels.Load()
+	if levels == nil {
+		return false
 	}
 
-	for _, l := range h.levels {
+	for _, l := range *levels {
 		if l == level {
 			return true
 		}
@@ -0,0 +1,416 @@
+package handler
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gookit/slog"
+)
+
+// FieldKeyDeliveryDelay field name for the async delivery delay, in milliseconds.
+const FieldKeyDeliveryDelay = "delivery_delay_ms"
+
+// DefaultAsyncQueueSize default queue size for AsyncHandler
+const DefaultAsyncQueueSize = 1024
+
+// ErrAsyncClosed error on handle record after the AsyncHandler is closed
+var ErrAsyncClosed = errors.New("slog: async handler has been closed")
+
+// ErrDrainTimeout error on the queued records are not drained within AsyncHandler.DrainTimeout
+var ErrDrainTimeout = errors.New("slog: async handler drain timeout")
+
+// OverflowPolicy the policy on the AsyncHandler queue is full
+type OverflowPolicy uint8
+
+// built-in overflow policies
+const (
+	// OverflowBlock block the caller until the queue has space. it is default.
+	OverflowBlock OverflowPolicy = iota
+	// OverflowDropOldest discard the oldest queued record, then enqueue the new record.
+	OverflowDropOldest
+	// OverflowDropNewest discard the new record.
+	OverflowDropNewest
+)
+
+// AsyncHandler wrap a handler, handle the records in a background goroutine.
+//
+// The records level <= PriorityLevel use a dedicated high-priority lane, so critical
+// records still get through promptly when the queue is saturated with debug output.
+// NOTICE: the priority records may be delivered before the earlier normal records.
+//
+// The Flush() request is also sent by the high-priority lane, so the Logger flush on
+// an error record will not wait for the normal queued records.
+//
+// The record will be cloned before enqueue, and stamped with the enqueue time.
+// On delivery, will add the field "delivery_delay_ms" to the record,
+// and warn when the delay exceeds the StaleThreshold, making pipeline lag observable.
+type AsyncHandler struct {
+	// the wrapped handler
+	slog.Handler
+	// StaleThreshold emit a warning record on the delivery delay exceeds it.
+	// at most once per threshold duration. 0 is disable.
+	StaleThreshold time.Duration
+	// DelayField add the delivery delay field to records. default is true
+	DelayField bool
+	// OnError on the wrapped handler handle record error. default will print to stderr.
+	OnError func(err error)
+	// PriorityLevel the records level <= it will use the high-priority lane.
+	// default is slog.ErrorLevel, 0 is disable.
+	PriorityLevel slog.Level
+	// ShedRules load shedding rules by queue occupancy. default is nil, not shed records.
+	//
+	// eg: DefaultShedRules - shed Debug records on occupancy >= 50%, then Info records on >= 80%
+	ShedRules []ShedRule
+	// QueueSize the max queued records. default is DefaultAsyncQueueSize
+	QueueSize int
+	// Overflow policy on the queue or the high-priority lane is full. default is OverflowBlock
+	Overflow OverflowPolicy
+	// DrainTimeout max wait time for drain the queued records on Close(). 0 is wait until done.
+	//
+	// On timeout, Close() returns ErrDrainTimeout and the wrapped handler will not be closed.
+	DrainTimeout time.Duration
+
+	mu     sync.RWMutex
+	closed bool
+	queue  chan *asyncItem
+	// the high-priority lane
+	prio chan *asyncItem
+	done chan struct{}
+	// dropped records count by the overflow policy, and last report time(unix nano)
+	dropped    uint64
+	lastReport int64
+	// stale records count, and last warning time
+	stale    uint64
+	lastWarn time.Time
+	// shed records count by level
+	shedMu sync.Mutex
+	shed   map[slog.Level]uint64
+}
+
+// ShedRule shed the records level >= Level(less severe) on the queue occupancy >= Occupancy
+type ShedRule struct {
+	Level slog.Level
+	// Occupancy ratio of the queue, 0.0 - 1.0
+	Occupancy float64
+}
+
+// DefaultShedRules shed Debug, Trace records on occupancy >= 50%, then Info, Notice on >= 80%
+var DefaultShedRules = []ShedRule{
+	{Level: slog.DebugLevel, Occupancy: 0.5},
+	{Level: slog.NoticeLevel, Occupancy: 0.8},
+}
+
+type asyncItem struct {
+	r  *slog.Record
+	at time.Time
+	// flush signal, not nil on is a flush request
+	flushed chan error
+}
+
+// Async wrap a handler as AsyncHandler, alias of NewAsyncHandler(h, 0, fns...)
+//
+// Usage:
+//
+//	h := handler.Async(fileHandler, func(h *handler.AsyncHandler) {
+//		h.QueueSize = 4096
+//		h.Overflow = handler.OverflowDropOldest
+//		h.DrainTimeout = 5 * time.Second
+//	})
+//	defer h.Close()
+func Async(h slog.Handler, fns ...func(h *AsyncHandler)) *AsyncHandler {
+	return NewAsyncHandler(h, 0, fns...)
+}
+
+// NewAsyncHandler create new AsyncHandler, and start the background goroutine.
+//
+// Usage:
+//
+//	h := handler.NewAsyncHandler(fileHandler, 4096, func(h *handler.AsyncHandler) {
+//		h.StaleThreshold = 3 * time.Second
+//	})
+//	defer h.Close()
+func NewAsyncHandler(h slog.Handler, size int, fns ...func(h *AsyncHandler)) *AsyncHandler {
+	ah := &AsyncHandler{
+		Handler:       h,
+		QueueSize:     size,
+		DelayField:    true,
+		PriorityLevel: slog.ErrorLevel,
+		done:          make(chan struct{}),
+		shed:          make(map[slog.Level]uint64),
+	}
+
+	for _, fn := range fns {
+		fn(ah)
+	}
+
+	if ah.QueueSize <= 0 {
+		ah.QueueSize = DefaultAsyncQueueSize
+	}
+
+	prioSize := ah.QueueSize / 4
+	if prioSize < 16 {
+		prioSize = 16
+	}
+
+	ah.queue = make(chan *asyncItem, ah.QueueSize)
+	ah.prio = make(chan *asyncItem, prioSize)
+
+	go ah.run()
+	return ah
+}
+
+// Handle clone the record and put it to the queue.
+// on the queue is full, will block or drop records by the Overflow policy.
+func (h *AsyncHandler) Handle(r *slog.Record) error {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	if h.closed {
+		return ErrAsyncClosed
+	}
+
+	if h.PriorityLevel > 0 && r.Level <= h.PriorityLevel {
+		h.enqueue(h.prio, &asyncItem{r: r.Clone(), at: time.Now()})
+		return nil
+	}
+
+	if h.shouldShed(r.Level) {
+		h.shedMu.Lock()
+		h.shed[r.Level]++
+		h.shedMu.Unlock()
+		return nil
+	}
+
+	h.enqueue(h.queue, &asyncItem{r: r.Clone(), at: time.Now()})
+	return nil
+}
+
+// put the item to the queue, on the queue is full, will block or drop items by the Overflow policy.
+func (h *AsyncHandler) enqueue(queue chan *asyncItem, it *asyncItem) {
+	switch h.Overflow {
+	case OverflowDropNewest:
+		select {
+		case queue <- it:
+		default:
+			h.discard(it)
+		}
+	case OverflowDropOldest:
+		for {
+			select {
+			case queue <- it:
+				return
+			default:
+			}
+
+			// discard the oldest one, then retry
+			select {
+			case old := <-queue:
+				h.discard(old)
+			default:
+			}
+		}
+	default: // OverflowBlock
+		queue <- it
+	}
+}
+
+// discard the item by the Overflow policy
+func (h *AsyncHandler) discard(it *asyncItem) {
+	if it.flushed != nil {
+		// the flush request is dropped, will not wait for it.
+		it.flushed <- nil
+	} else {
+		h.drop()
+	}
+}
+
+// count the dropped record, and report it at most once per second.
+func (h *AsyncHandler) drop() {
+	total := atomic.AddUint64(&h.dropped, 1)
+
+	now := time.Now().UnixNano()
+	last := atomic.LoadInt64(&h.lastReport)
+	if now-last >= int64(time.Second) && atomic.CompareAndSwapInt64(&h.lastReport, last, now) {
+		slog.ReportInternal(slog.WarnLevel, "slog: async handler queue is full, total dropped records:", total)
+	}
+}
+
+// Dropped get the count of records that dropped by the Overflow policy
+func (h *AsyncHandler) Dropped() uint64 {
+	return atomic.LoadUint64(&h.dropped)
+}
+
+func (h *AsyncHandler) shouldShed(level slog.Level) bool {
+	if len(h.ShedRules) == 0 {
+		return false
+	}
+
+	occupancy := float64(len(h.queue)) / float64(cap(h.queue))
+	for _, rule := range h.ShedRules {
+		if level >= rule.Level && occupancy >= rule.Occupancy {
+			return true
+		}
+	}
+	return false
+}
+
+// Shed get the shed records count by level
+func (h *AsyncHandler) Shed() map[slog.Level]uint64 {
+	h.shedMu.Lock()
+	defer h.shedMu.Unlock()
+
+	mp := make(map[slog.Level]uint64, len(h.shed))
+	for level, n := range h.shed {
+		mp[level] = n
+	}
+	return mp
+}
+
+// Len get the queued records count, contains the priority lane.
+func (h *AsyncHandler) Len() int {
+	return len(h.queue) + len(h.prio)
+}
+
+// Stale get the count of records that delivery delay exceeds the StaleThreshold
+func (h *AsyncHandler) Stale() uint64 {
+	return atomic.LoadUint64(&h.stale)
+}
+
+// Flush wait for the records queued in the high-priority lane are handled, then flush the wrapped handler.
+//
+// NOTICE: it will not wait for the normal queued records, they are delivered in the background.
+// please call Close() to drain all queued records.
+func (h *AsyncHandler) Flush() error {
+	h.mu.RLock()
+	if h.closed {
+		h.mu.RUnlock()
+		return nil
+	}
+
+	it := &asyncItem{flushed: make(chan error, 1)}
+	h.enqueue(h.prio, it)
+	h.mu.RUnlock()
+
+	return <-it.flushed
+}
+
+// Close the queue, wait for all queued records are handled, then close the wrapped handler.
+//
+// If DrainTimeout > 0 and the records are not drained in time, will return ErrDrainTimeout.
+func (h *AsyncHandler) Close() error {
+	h.mu.Lock()
+	if h.closed {
+		h.mu.Unlock()
+		return nil
+	}
+
+	h.closed = true
+	close(h.queue)
+	close(h.prio)
+	h.mu.Unlock()
+
+	if h.DrainTimeout > 0 {
+		timer := time.NewTimer(h.DrainTimeout)
+		defer timer.Stop()
+
+		select {
+		case <-h.done:
+		case <-timer.C:
+			return ErrDrainTimeout
+		}
+	} else {
+		<-h.done
+	}
+	return h.Handler.Close()
+}
+
+func (h *AsyncHandler) run() {
+	defer close(h.done)
+
+	queue, prio := h.queue, h.prio
+	for queue != nil || prio != nil {
+		// prefer the priority lane
+		if prio != nil {
+			select {
+			case it, ok := <-prio:
+				if ok {
+					h.process(it)
+				} else {
+					prio = nil
+				}
+				continue
+			default:
+			}
+		}
+
+		select {
+		case it, ok := <-prio:
+			if !ok {
+				prio = nil
+				continue
+			}
+			h.process(it)
+		case it, ok := <-queue:
+			if !ok {
+				queue = nil
+				continue
+			}
+			h.process(it)
+		}
+	}
+}
+
+// deliver the record or handle the flush request
+func (h *AsyncHandler) process(it *asyncItem) {
+	if it.flushed != nil {
+		it.flushed <- h.Handler.Flush()
+	} else {
+		h.deliver(it)
+	}
+}
+
+func (h *AsyncHandler) deliver(it *asyncItem) {
+	delay := slog.Since(it.at)
+	if h.DelayField {
+		it.r.AddField(FieldKeyDeliveryDelay, delay.Milliseconds())
+	}
+
+	if h.StaleThreshold > 0 && delay > h.StaleThreshold {
+		atomic.AddUint64(&h.stale, 1)
+		if time.Since(h.lastWarn) >= h.StaleThreshold {
+			h.lastWarn = time.Now()
+			h.warnStale(delay)
+		}
+	}
+
+	if err := h.Handler.Handle(it.r); err != nil {
+		if h.OnError != nil {
+			h.OnError(err)
+		} else {
+			reportError("slog: async handler handle record error:", err)
+		}
+	}
+}
+
+// emit a warning record to the wrapped handler
+func (h *AsyncHandler) warnStale(delay time.Duration) {
+	if !h.Handler.IsHandling(slog.WarnLevel) {
+		return
+	}
+
+	wr := &slog.Record{
+		Level:   slog.WarnLevel,
+		Time:    time.Now(),
+		Channel: slog.DefaultChannelName,
+		Message: "slog: async delivery delay exceeds the threshold",
+		Data: slog.M{
+			FieldKeyDeliveryDelay: delay.Milliseconds(),
+			"stale_threshold":     h.StaleThreshold.String(),
+			"queued":              len(h.queue),
+		},
+	}
+	wr.Init(false)
+	_ = h.Handler.Handle(wr)
+}
@@ -0,0 +1,259 @@
+package handler_test
+
+import (
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gookit/goutil/testutil/assert"
+	"github.com/gookit/slog"
+	"github.com/gookit/slog/handler"
+)
+
+// syncBuffer a goroutine safe buffer
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf []byte
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.buf = append(b.buf, p...)
+	return len(p), nil
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return string(b.buf)
+}
+
+func TestAsyncHandler_Handle(t *testing.T) {
+	buf := &syncBuffer{}
+	wh := handler.NewIOWriter(buf, slog.AllLevels)
+	wh.SetFormatter(slog.NewJSONFormatter())
+
+	h := handler.NewAsyncHandler(wh, 10)
+	l := slog.NewWithHandlers(h)
+
+	l.Info("async message")
+	l.WithField("key", "value").Debug("async message2")
+	assert.NoErr(t, h.Flush())
+	// drain all queued records
+	assert.NoErr(t, h.Close())
+
+	str := buf.String()
+	assert.StrContains(t, str, `"message":"async message"`)
+	assert.StrContains(t, str, `"key":"value"`)
+	assert.StrContains(t, str, `"delivery_delay_ms":`)
+	assert.StrContains(t, str, `"caller":"async_test.go`)
+	assert.Eq(t, 0, h.Len())
+
+	assert.NoErr(t, h.Close())
+	assert.Eq(t, handler.ErrAsyncClosed, h.Handle(newLogRecord("after close")))
+}
+
+type slowHandler struct {
+	handler.NopFlushClose
+	slog.LevelWithFormatter
+	delay time.Duration
+	mu    sync.Mutex
+	msgs  []string
+}
+
+func (h *slowHandler) Handle(r *slog.Record) error {
+	time.Sleep(h.delay)
+	h.mu.Lock()
+	h.msgs = append(h.msgs, r.Message)
+	h.mu.Unlock()
+	return nil
+}
+
+func (h *slowHandler) Messages() []string {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return append([]string(nil), h.msgs...)
+}
+
+func TestAsyncHandler_StaleThreshold(t *testing.T) {
+	sh := &slowHandler{delay: 10 * time.Millisecond}
+	sh.Level = slog.TraceLevel
+
+	h := handler.NewAsyncHandler(sh, 10, func(h *handler.AsyncHandler) {
+		h.StaleThreshold = 5 * time.Millisecond
+	})
+
+	for i := 0; i < 3; i++ {
+		assert.NoErr(t, h.Handle(newLogRecord("message")))
+	}
+	assert.NoErr(t, h.Close())
+
+	assert.True(t, h.Stale() >= 1)
+	assert.Contains(t, sh.msgs, "slog: async delivery delay exceeds the threshold")
+}
+
+func TestAsyncHandler_PriorityLevel(t *testing.T) {
+	sh := &slowHandler{delay: 5 * time.Millisecond}
+	sh.Level = slog.TraceLevel
+
+	h := handler.NewAsyncHandler(sh, 20)
+	for i := 0; i < 10; i++ {
+		assert.NoErr(t, h.Handle(newLogRecord("debug message")))
+	}
+
+	er := newLogRecord("error message")
+	er.Level = slog.ErrorLevel
+	assert.NoErr(t, h.Handle(er))
+	assert.NoErr(t, h.Close())
+
+	// error record is delivered before most of the debug records
+	assert.Len(t, sh.msgs, 11)
+	idx := -1
+	for i, msg := range sh.msgs {
+		if msg == "error message" {
+			idx = i
+		}
+	}
+	assert.True(t, idx >= 0 && idx < 5)
+}
+
+func TestAsyncHandler_errorNotBlock(t *testing.T) {
+	sh := &slowHandler{delay: 20 * time.Millisecond}
+	sh.Level = slog.TraceLevel
+
+	h := handler.NewAsyncHandler(sh, 20)
+	l := slog.NewWithHandlers(h)
+
+	// a full and slow queue, need 400ms for drain it
+	for i := 0; i < 20; i++ {
+		l.Info("info message")
+	}
+
+	// the logger flush on error record, should not wait for the normal queue
+	start := time.Now()
+	l.Error("error message")
+	assert.True(t, time.Since(start) < 200*time.Millisecond)
+	assert.Contains(t, sh.Messages(), "error message")
+	assert.True(t, len(sh.Messages()) < 10)
+
+	assert.NoErr(t, h.Close())
+	assert.Len(t, sh.msgs, 21)
+}
+
+func TestAsyncHandler_priorityOverflow(t *testing.T) {
+	sh := &slowHandler{delay: 20 * time.Millisecond}
+	sh.Level = slog.TraceLevel
+
+	h := handler.NewAsyncHandler(sh, 4, func(h *handler.AsyncHandler) {
+		h.Overflow = handler.OverflowDropNewest
+	})
+
+	// the high-priority lane size is 16, should not block on it is full
+	done := make(chan struct{})
+	go func() {
+		for i := 0; i < 30; i++ {
+			r := newLogRecord("error message")
+			r.Level = slog.ErrorLevel
+			assert.NoErr(t, h.Handle(r))
+		}
+		assert.NoErr(t, h.Flush())
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(300 * time.Millisecond):
+		t.Fatal("the Handle is blocked on the high-priority lane is full")
+	}
+
+	assert.NoErr(t, h.Close())
+	assert.True(t, h.Dropped() >= 10)
+	assert.Len(t, sh.msgs, 30-int(h.Dropped()))
+}
+
+func TestAsyncHandler_ShedRules(t *testing.T) {
+	sh := &slowHandler{delay: 20 * time.Millisecond}
+	sh.Level = slog.TraceLevel
+
+	h := handler.NewAsyncHandler(sh, 10, func(h *handler.AsyncHandler) {
+		h.ShedRules = handler.DefaultShedRules
+	})
+
+	newRecord := func(level slog.Level) *slog.Record {
+		r := newLogRecord(level.Name() + " message")
+		r.Level = level
+		return r
+	}
+
+	for i := 0; i < 9; i++ {
+		assert.NoErr(t, h.Handle(newRecord(slog.WarnLevel)))
+	}
+	// queue occupancy >= 80%
+	assert.NoErr(t, h.Handle(newRecord(slog.DebugLevel)))
+	assert.NoErr(t, h.Handle(newRecord(slog.InfoLevel)))
+	assert.NoErr(t, h.Handle(newRecord(slog.ErrorLevel)))
+	assert.NoErr(t, h.Close())
+
+	shed := h.Shed()
+	assert.Eq(t, uint64(1), shed[slog.DebugLevel])
+	assert.Eq(t, uint64(1), shed[slog.InfoLevel])
+	assert.Contains(t, sh.msgs, "ERROR message")
+	assert.NotContains(t, sh.msgs, "DEBUG message")
+}
+
+func TestAsync_Overflow(t *testing.T) {
+	t.Run("drop newest", func(t *testing.T) {
+		sh := &slowHandler{delay: 20 * time.Millisecond}
+		sh.Level = slog.TraceLevel
+
+		h := handler.Async(sh, func(h *handler.AsyncHandler) {
+			h.QueueSize = 2
+			h.Overflow = handler.OverflowDropNewest
+		})
+		assert.Eq(t, 2, h.QueueSize)
+
+		for i := 0; i < 10; i++ {
+			assert.NoErr(t, h.Handle(newLogRecord("message"+strconv.Itoa(i))))
+		}
+		assert.NoErr(t, h.Close())
+
+		assert.True(t, h.Dropped() >= 6)
+		assert.Len(t, sh.msgs, 10-int(h.Dropped()))
+		assert.Eq(t, "message0", sh.msgs[0])
+	})
+
+	t.Run("drop oldest", func(t *testing.T) {
+		sh := &slowHandler{delay: 20 * time.Millisecond}
+		sh.Level = slog.TraceLevel
+
+		h := handler.Async(sh, func(h *handler.AsyncHandler) {
+			h.QueueSize = 2
+			h.Overflow = handler.OverflowDropOldest
+		})
+
+		for i := 0; i < 10; i++ {
+			assert.NoErr(t, h.Handle(newLogRecord("message"+strconv.Itoa(i))))
+		}
+		assert.NoErr(t, h.Close())
+
+		assert.True(t, h.Dropped() >= 6)
+		assert.Len(t, sh.msgs, 10-int(h.Dropped()))
+		assert.Eq(t, "message9", sh.msgs[len(sh.msgs)-1])
+	})
+}
+
+func TestAsync_DrainTimeout(t *testing.T) {
+	sh := &slowHandler{delay: 20 * time.Millisecond}
+	sh.Level = slog.TraceLevel
+
+	h := handler.Async(sh, func(h *handler.AsyncHandler) {
+		h.DrainTimeout = 10 * time.Millisecond
+	})
+
+	for i := 0; i < 5; i++ {
+		assert.NoErr(t, h.Handle(newLogRecord("message")))
+	}
+	assert.Eq(t, handler.ErrDrainTimeout, h.Close())
+}
@@ -0,0 +1,157 @@
+package handler
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gookit/slog"
+)
+
+// BudgetHandler wrap a handler, limit the max handling duration for each record.
+//
+// On the wrapped handler exceeds the Budget, the record will be handed to the Fallback handler,
+// and the wrapped handler is flagged as slow. While the slow handling is still running,
+// the new records are handed to the Fallback directly.
+//
+// The delivery is at-least-once: on timeout, the record is not delivered by the wrapped handler if the
+// handling is not started. but the started handling can not be cancelled, the record will be delivered by
+// both the wrapped and the Fallback handler.
+//
+// NOTICE: each record costs a goroutine, a timer and a clone(so it can be released by the logger on timeout),
+// please use it for the slow handlers, eg: network handlers.
+type BudgetHandler struct {
+	// the wrapped handler
+	slog.Handler
+	// Budget max handling duration for each record. 0 is not limit
+	Budget time.Duration
+	// Fallback handler on exceed the budget. eg: a local file handler. can be nil, will drop the record.
+	Fallback slog.Handler
+
+	// held on the wrapped handler is running
+	running sync.Mutex
+	// records count that exceed the budget or skipped on the wrapped handler is slow
+	exceeded uint64
+	slow     int32
+}
+
+// the handling states of a record
+const (
+	budgetPending int32 = iota
+	budgetStarted
+	budgetCancelled
+)
+
+// NewBudgetHandler create new BudgetHandler
+//
+// Usage:
+//
+//	h := handler.NewBudgetHandler(httpHandler, 50*time.Millisecond, func(h *handler.BudgetHandler) {
+//		h.Fallback = fileHandler
+//	})
+func NewBudgetHandler(h slog.Handler, budget time.Duration, fns ...func(h *BudgetHandler)) *BudgetHandler {
+	bh := &BudgetHandler{
+		Handler: h,
+		Budget:  budget,
+	}
+
+	for _, fn := range fns {
+		fn(bh)
+	}
+	return bh
+}
+
+// Handle a log record, hand it to the Fallback on exceeded the budget.
+func (h *BudgetHandler) Handle(r *slog.Record) error {
+	if h.Budget <= 0 {
+		h.running.Lock()
+		defer h.running.Unlock()
+		return h.Handler.Handle(r)
+	}
+
+	// the slow handling is still running
+	if !h.running.TryLock() {
+		return h.fallback(r)
+	}
+
+	var state int32
+	done := make(chan error, 1)
+	go func(r *slog.Record) {
+		defer h.running.Unlock()
+
+		// timeout before start, the record has been handed to the Fallback.
+		if !atomic.CompareAndSwapInt32(&state, budgetPending, budgetStarted) {
+			atomic.CompareAndSwapInt32(&h.slow, 1, 0)
+			return
+		}
+
+		err := h.Handler.Handle(r)
+		if atomic.CompareAndSwapInt32(&h.slow, 1, 0) && err != nil {
+			reportError("slog: budget handler slow handle record error:", err)
+		}
+		done <- err
+	}(r.Clone())
+
+	timer := time.NewTimer(h.Budget)
+	defer timer.Stop()
+
+	select {
+	case err := <-done:
+		return err
+	case <-timer.C:
+	}
+
+	if atomic.CompareAndSwapInt32(&h.slow, 0, 1) {
+		slog.ReportInternal(slog.WarnLevel, "slog: budget handler exceeds the budget", h.Budget.String())
+	}
+
+	// drop the late delivery if the handling is not started. after set the slow flag, it will be reset by the goroutine.
+	atomic.CompareAndSwapInt32(&state, budgetPending, budgetCancelled)
+	return h.fallback(r)
+}
+
+func (h *BudgetHandler) fallback(r *slog.Record) error {
+	atomic.AddUint64(&h.exceeded, 1)
+	if h.Fallback == nil || !h.Fallback.IsHandling(r.Level) {
+		return nil
+	}
+	return h.Fallback.Handle(r)
+}
+
+// Exceeded get the count of records that handed to the Fallback
+func (h *BudgetHandler) Exceeded() uint64 {
+	return atomic.LoadUint64(&h.exceeded)
+}
+
+// Slow check the wrapped handler is running over the budget
+func (h *BudgetHandler) Slow() bool {
+	return atomic.LoadInt32(&h.slow) == 1
+}
+
+// Flush wait for the running handling done, then flush the wrapped and fallback handler.
+func (h *BudgetHandler) Flush() error {
+	h.running.Lock()
+	err := h.Handler.Flush()
+	h.running.Unlock()
+
+	if h.Fallback != nil {
+		if ferr := h.Fallback.Flush(); err == nil {
+			err = ferr
+		}
+	}
+	return err
+}
+
+// Close wait for the running handling done, then close the wrapped and fallback handler.
+func (h *BudgetHandler) Close() error {
+	h.running.Lock()
+	err := h.Handler.Close()
+	h.running.Unlock()
+
+	if h.Fallback != nil {
+		if ferr := h.Fallback.Close(); err == nil {
+			err = ferr
+		}
+	}
+	return err
+}
@@ -0,0 +1,61 @@
+package handler_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gookit/goutil/testutil/assert"
+	"github.com/gookit/slog"
+	"github.com/gookit/slog/handler"
+)
+
+func TestBudgetHandler_Handle(t *testing.T) {
+	sh := &slowHandler{delay: 50 * time.Millisecond}
+	sh.Level = slog.TraceLevel
+
+	buf := &syncBuffer{}
+	fh := handler.NewIOWriter(buf, slog.AllLevels)
+	fh.SetFormatter(slog.NewJSONFormatter())
+
+	h := handler.NewBudgetHandler(sh, 10*time.Millisecond, func(h *handler.BudgetHandler) {
+		h.Fallback = fh
+	})
+	l := slog.NewWithHandlers(h)
+
+	// exceed the budget
+	l.Info("slow message")
+	assert.True(t, h.Slow())
+	assert.StrContains(t, buf.String(), `"message":"slow message"`)
+
+	// the slow handling is still running, hand to fallback directly
+	l.Info("skipped message")
+	assert.StrContains(t, buf.String(), `"message":"skipped message"`)
+	assert.Eq(t, uint64(2), h.Exceeded())
+
+	// wait for the slow handling done.
+	// at-least-once: the started handling is not cancelled, delivered by both handlers.
+	assert.NoErr(t, h.Flush())
+	assert.False(t, h.Slow())
+	assert.Eq(t, []string{"slow message"}, sh.msgs)
+	assert.NotContains(t, sh.msgs, "skipped message")
+
+	// within the budget
+	sh.delay = 0
+	l.Info("fast message")
+	assert.Eq(t, []string{"slow message", "fast message"}, sh.msgs)
+	assert.Eq(t, uint64(2), h.Exceeded())
+	assert.NotContains(t, buf.String(), "fast message")
+
+	assert.NoErr(t, h.Close())
+}
+
+func TestBudgetHandler_noBudget(t *testing.T) {
+	sh := &slowHandler{delay: time.Millisecond}
+	sh.Level = slog.TraceLevel
+
+	h := handler.NewBudgetHandler(sh, 0)
+	assert.NoErr(t, h.Handle(newLogRecord("no budget")))
+	assert.Eq(t, []string{"no budget"}, sh.msgs)
+	assert.Eq(t, uint64(0), h.Exceeded())
+	assert.NoErr(t, h.Close())
+}
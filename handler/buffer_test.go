@@ -39,6 +39,31 @@ func TestNewBufferedHandler(t *testing.T) {
 	assert.NoErr(t, err)
 }
 
+func TestBufferedHandler_FlushOnLevel(t *testing.T) {
+	logfile := "./testdata/buffer-flush-on-level.log"
+	assert.NoErr(t, fsutil.DeleteIfFileExist(logfile))
+
+	h, err := handler.NewBuffFileHandler(logfile, 1024, handler.WithFlushOnLevel(slog.ErrorLevel))
+	assert.NoErr(t, err)
+	assert.Eq(t, slog.ErrorLevel, h.FlushOnLevel)
+
+	assert.NoErr(t, h.Handle(newLogRecord("buffered info message")))
+	bts, err := os.ReadFile(logfile)
+	assert.NoErr(t, err)
+	assert.Empty(t, bts)
+
+	r := newLogRecord("buffered error message")
+	r.Level = slog.ErrorLevel
+	assert.NoErr(t, h.Handle(r))
+	bts, err = os.ReadFile(logfile)
+	assert.NoErr(t, err)
+
+	str := string(bts)
+	assert.Contains(t, str, "buffered info message")
+	assert.Contains(t, str, "buffered error message")
+	assert.NoErr(t, h.Close())
+}
+
 func TestLineBufferedFile(t *testing.T) {
 	logfile := "./testdata/line-buff-file.log"
 	assert.NoErr(t, fsutil.DeleteIfFileExist(logfile))
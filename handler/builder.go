@@ -17,6 +17,14 @@ import (
 type Builder struct {
 	*Config
 	Output io.Writer
+	// more outputs for build multi handlers at once. see AddOutput(), AddLogfile()
+	items []*buildItem
+}
+
+// buildItem an output and config for build a handler
+type buildItem struct {
+	cfg *Config
+	out io.Writer
 }
 
 // NewBuilder create
@@ -51,6 +59,12 @@ func (b *Builder) WithLogfile(logfile string) *Builder {
 	return b
 }
 
+// WithHandlerName setting
+func (b *Builder) WithHandlerName(name string) *Builder {
+	b.Name = name
+	return b
+}
+
 // WithLevelMode setting
 func (b *Builder) WithLevelMode(mode slog.LevelMode) *Builder {
 	b.LevelMode = mode
@@ -75,12 +89,24 @@ func (b *Builder) WithBuffMode(bufMode string) *Builder {
 	return b
 }
 
+// WithBuffGrow setting
+func (b *Builder) WithBuffGrow(enable bool) *Builder {
+	b.BuffGrow = enable
+	return b
+}
+
 // WithBuffSize setting
 func (b *Builder) WithBuffSize(bufSize int) *Builder {
 	b.BuffSize = bufSize
 	return b
 }
 
+// WithFlushOnLevel setting
+func (b *Builder) WithFlushOnLevel(level slog.Level) *Builder {
+	b.FlushOnLevel = level
+	return b
+}
+
 // WithMaxSize setting
 func (b *Builder) WithMaxSize(maxSize uint64) *Builder {
 	b.MaxSize = maxSize
@@ -105,6 +131,65 @@ func (b *Builder) WithUseJSON(useJSON bool) *Builder {
 	return b
 }
 
+// AddOutput add an output for build multi handlers, the config is copied from current builder config.
+//
+// Usage:
+//
+//	hs := handler.NewBuilder().
+//		AddOutput(os.Stdout, handler.WithLogLevels(slog.NormalLevels)).
+//		AddLogfile("logs/error.log", handler.WithLogLevels(slog.DangerLevels)).
+//		BuildHandlers()
+func (b *Builder) AddOutput(w io.Writer, fns ...ConfigFn) *Builder {
+	b.items = append(b.items, &buildItem{cfg: b.copyConfig(fns), out: w})
+	return b
+}
+
+// AddLogfile add a logfile for build multi handlers, the config is copied from current builder config.
+func (b *Builder) AddLogfile(logfile string, fns ...ConfigFn) *Builder {
+	cfg := b.copyConfig(fns).With(WithLogfile(logfile))
+	b.items = append(b.items, &buildItem{cfg: cfg})
+	return b
+}
+
+func (b *Builder) copyConfig(fns []ConfigFn) *Config {
+	cfg := *b.Config
+	cfg.Levels = append([]slog.Level(nil), b.Levels...)
+	return cfg.With(fns...)
+}
+
+// BuildHandlers build all handlers from the added outputs, logfiles.
+// if the builder Output or Logfile is set, will also build it as the first handler.
+func (b *Builder) BuildHandlers() []slog.Handler {
+	items := b.items
+	hs := make([]slog.Handler, 0, len(items)+1)
+	if b.Output != nil || b.Logfile != "" {
+		hs = append(hs, b.Build())
+	}
+
+	for _, it := range items {
+		if it.out != nil {
+			hs = append(hs, buildFromWriter(it.cfg, it.out))
+			continue
+		}
+
+		h, err := it.cfg.CreateHandler()
+		if err != nil {
+			panic(err)
+		}
+		hs = append(hs, h)
+	}
+
+	b.reset()
+	return hs
+}
+
+// BuildLogger build all handlers and create a new logger with them.
+func (b *Builder) BuildLogger(fns ...slog.LoggerFn) *slog.Logger {
+	l := slog.New(fns...)
+	l.AddHandlers(b.BuildHandlers()...)
+	return l
+}
+
 // Build slog handler.
 func (b *Builder) Build() slog.FormattableHandler {
 	if b.Output != nil {
@@ -125,6 +210,11 @@ func (b *Builder) Build() slog.FormattableHandler {
 // Build slog handler.
 func (b *Builder) buildFromWriter(w io.Writer) (h slog.FormattableHandler) {
 	defer b.reset()
+	return buildFromWriter(b.Config, w)
+}
+
+// build slog handler by config and writer.
+func buildFromWriter(b *Config, w io.Writer) (h slog.FormattableHandler) {
 	bufSize := b.BuffSize
 	lf := b.newLevelFormattable()
 
@@ -133,13 +223,17 @@ func (b *Builder) buildFromWriter(w io.Writer) (h slog.FormattableHandler) {
 			scw = b.wrapBuffer(scw)
 		}
 
-		h = NewSyncCloserWithLF(scw, lf)
+		sh := NewSyncCloserWithLF(scw, lf)
+		sh.FlushOnLevel = b.FlushOnLevel
+		h = sh
 	} else if fcw, ok := w.(FlushCloseWriter); ok {
 		if bufSize > 0 {
 			fcw = b.wrapBuffer(fcw)
 		}
 
-		h = NewFlushCloserWithLF(fcw, lf)
+		fh := NewFlushCloserWithLF(fcw, lf)
+		fh.FlushOnLevel = b.FlushOnLevel
+		h = fh
 	} else if wc, ok := w.(io.WriteCloser); ok {
 		if bufSize > 0 {
 			wc = b.wrapBuffer(wc)
@@ -158,11 +252,16 @@ func (b *Builder) buildFromWriter(w io.Writer) (h slog.FormattableHandler) {
 	if b.UseJSON {
 		h.SetFormatter(slog.NewJSONFormatter())
 	}
+	b.wrapFormatter(h)
+	if b.Name != "" {
+		h.(interface{ SetName(string) }).SetName(b.Name)
+	}
 	return
 }
 
 // rest builder.
 func (b *Builder) reset() {
+	b.items = nil
 	b.Output = nil
 	b.Config = NewEmptyConfig()
 }
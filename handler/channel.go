@@ -0,0 +1,148 @@
+package handler
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/gookit/slog"
+)
+
+// ChannelRouter route records to handlers by the record channel name.
+//
+// Handlers can subscribe to a channel prefix like "app.db.*", the longest prefix
+// will be matched, so new sub-channels automatically route correctly.
+//
+// Pattern examples:
+//
+//	"app.db"   - only match the channel "app.db"
+//	"app.db.*" - match "app.db" and all sub-channels. eg: "app.db.mysql", "app.db.mysql.slow"
+//	"*"        - match all channels
+type ChannelRouter struct {
+	// Fallback handler on no pattern matched. can be nil
+	Fallback slog.Handler
+
+	routes map[string]slog.Handler
+	// patterns sorted by prefix length, longest first.
+	patterns []string
+}
+
+// NewChannelRouter create new ChannelRouter
+//
+// Usage:
+//
+//	cr := handler.NewChannelRouter(appHandler)
+//	cr.Subscribe("app.db.*", dbHandler)
+//	cr.Subscribe("app.db.redis", redisHandler)
+func NewChannelRouter(fallback slog.Handler) *ChannelRouter {
+	return &ChannelRouter{
+		Fallback: fallback,
+		routes:   make(map[string]slog.Handler),
+	}
+}
+
+// Subscribe channel pattern with handler
+func (cr *ChannelRouter) Subscribe(pattern string, h slog.Handler) *ChannelRouter {
+	if _, ok := cr.routes[pattern]; !ok {
+		cr.patterns = append(cr.patterns, pattern)
+		sort.SliceStable(cr.patterns, func(i, j int) bool {
+			return channelPrefix(cr.patterns[i]) > channelPrefix(cr.patterns[j])
+		})
+	}
+
+	cr.routes[pattern] = h
+	return cr
+}
+
+// channelPrefix rank of the pattern for sort, by the prefix length.
+// exact pattern is ranked above the wildcard of same prefix. eg: "app.db" > "app.db.*"
+func channelPrefix(pattern string) int {
+	if pattern == "*" {
+		return 0
+	}
+	if strings.HasSuffix(pattern, ".*") {
+		return 2*(len(pattern)-2) - 1
+	}
+	return 2 * len(pattern)
+}
+
+// Match the handler by channel name. will return Fallback on not matched.
+func (cr *ChannelRouter) Match(channel string) slog.Handler {
+	for _, pattern := range cr.patterns {
+		if matchChannel(pattern, channel) {
+			return cr.routes[pattern]
+		}
+	}
+	return cr.Fallback
+}
+
+func matchChannel(pattern, channel string) bool {
+	if pattern == "*" || pattern == channel {
+		return true
+	}
+
+	if prefix := strings.TrimSuffix(pattern, ".*"); len(prefix) < len(pattern) {
+		return channel == prefix || strings.HasPrefix(channel, prefix+".")
+	}
+	return false
+}
+
+// IsHandling Check if the current level can be handling
+func (cr *ChannelRouter) IsHandling(level slog.Level) bool {
+	if cr.Fallback != nil && cr.Fallback.IsHandling(level) {
+		return true
+	}
+
+	for _, h := range cr.routes {
+		if h.IsHandling(level) {
+			return true
+		}
+	}
+	return false
+}
+
+// Handle a log record
+func (cr *ChannelRouter) Handle(r *slog.Record) error {
+	h := cr.Match(r.Channel)
+	if h == nil || !h.IsHandling(r.Level) {
+		return nil
+	}
+	return routeErr(h, h.Handle(r))
+}
+
+// Flush all handlers
+func (cr *ChannelRouter) Flush() error {
+	return cr.visit(func(h slog.Handler) error {
+		return h.Flush()
+	})
+}
+
+// Close all handlers
+func (cr *ChannelRouter) Close() error {
+	return cr.visit(func(h slog.Handler) error {
+		return h.Close()
+	})
+}
+
+func (cr *ChannelRouter) visit(fn func(h slog.Handler) error) error {
+	if cr.Fallback != nil {
+		if err := fn(cr.Fallback); err != nil {
+			return routeErr(cr.Fallback, err)
+		}
+	}
+
+	for _, pattern := range cr.patterns {
+		if err := fn(cr.routes[pattern]); err != nil {
+			return routeErr(cr.routes[pattern], err)
+		}
+	}
+	return nil
+}
+
+// add the handler name to the error, so can know which handler failed.
+func routeErr(h slog.Handler, err error) error {
+	if err == nil {
+		return nil
+	}
+	return fmt.Errorf("%s: %w", slog.HandlerName(h), err)
+}
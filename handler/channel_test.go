@@ -0,0 +1,68 @@
+package handler_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/gookit/goutil/testutil/assert"
+	"github.com/gookit/slog"
+	"github.com/gookit/slog/handler"
+)
+
+func TestChannelRouter_Match(t *testing.T) {
+	appH := handler.NewIOWriter(new(bytes.Buffer), slog.AllLevels)
+	dbH := handler.NewIOWriter(new(bytes.Buffer), slog.AllLevels)
+	dbExactH := handler.NewIOWriter(new(bytes.Buffer), slog.AllLevels)
+	mysqlH := handler.NewIOWriter(new(bytes.Buffer), slog.AllLevels)
+	exactH := handler.NewIOWriter(new(bytes.Buffer), slog.AllLevels)
+
+	cr := handler.NewChannelRouter(nil)
+	assert.Nil(t, cr.Match("app"))
+	assert.False(t, cr.IsHandling(slog.InfoLevel))
+
+	cr.Subscribe("*", appH).
+		Subscribe("app.db.*", dbH).
+		Subscribe("app.db.mysql.*", mysqlH).
+		Subscribe("app.db.mysql", exactH)
+
+	// the handlers are structurally equal, must compare the pointers.
+	assert.Same(t, appH, cr.Match("app"))
+	assert.Same(t, appH, cr.Match("app.dbx"))
+	assert.Same(t, dbH, cr.Match("app.db"))
+	assert.Same(t, dbH, cr.Match("app.db.redis"))
+	assert.Same(t, exactH, cr.Match("app.db.mysql"))
+	assert.Same(t, mysqlH, cr.Match("app.db.mysql.slow"))
+	assert.True(t, cr.IsHandling(slog.InfoLevel))
+
+	// exact pattern is not shadowed by the wildcard of same prefix
+	cr.Subscribe("app.db", dbExactH)
+	assert.Same(t, dbExactH, cr.Match("app.db"))
+	assert.Same(t, dbH, cr.Match("app.db.redis"))
+	assert.Same(t, exactH, cr.Match("app.db.mysql"))
+}
+
+func TestChannelRouter_Handle(t *testing.T) {
+	appBuf := new(bytes.Buffer)
+	dbBuf := new(bytes.Buffer)
+
+	cr := handler.NewChannelRouter(handler.NewIOWriter(appBuf, slog.AllLevels))
+	cr.Subscribe("app.db.*", handler.NewIOWriter(dbBuf, slog.DangerLevels))
+
+	l := slog.NewWithHandlers(cr)
+	l.Info("app message")
+
+	l = slog.NewWithConfig(func(l *slog.Logger) {
+		l.ChannelName = "app.db.mysql"
+	})
+	l.AddHandler(cr)
+	l.Info("db info message")
+	l.Warn("db warn message")
+
+	assert.StrContains(t, appBuf.String(), "app message")
+	assert.NotContains(t, appBuf.String(), "db warn message")
+	assert.NotContains(t, dbBuf.String(), "db info message")
+	assert.StrContains(t, dbBuf.String(), "[app.db.mysql] [WARN] ")
+
+	assert.NoErr(t, cr.Flush())
+	assert.NoErr(t, cr.Close())
+}
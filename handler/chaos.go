@@ -0,0 +1,171 @@
+package handler
+
+import (
+	"errors"
+	"io"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/gookit/slog"
+)
+
+// ErrChaos the default error injected by the ChaosHandler, ChaosWriter
+var ErrChaos = errors.New("slog: chaos injected error")
+
+// Chaos the common failure injection options for the ChaosHandler and ChaosWriter
+type Chaos struct {
+	mu sync.Mutex
+	// count of the calls, and the injected failures
+	count  uint64
+	failed uint64
+
+	// Err the injected error. default is ErrChaos
+	Err error
+	// FailRate the probability of failure, range is [0, 1]. 0 is disable.
+	FailRate float64
+	// FailEvery fail at every N-th call. 0 is disable.
+	FailEvery int
+	// Latency add the latency before each call. 0 is disable.
+	Latency time.Duration
+	// Rand custom the random func for FailRate, returns a number in [0, 1). default is math/rand.Float64
+	Rand func() float64
+}
+
+// check should fail the current call
+func (c *Chaos) shouldFail() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.count++
+	fail := c.FailEvery > 0 && c.count%uint64(c.FailEvery) == 0
+	if !fail && c.FailRate > 0 {
+		rnd := c.Rand
+		if rnd == nil {
+			rnd = rand.Float64
+		}
+		fail = rnd() < c.FailRate
+	}
+
+	if fail {
+		c.failed++
+	}
+	return fail
+}
+
+func (c *Chaos) err() error {
+	if c.Err != nil {
+		return c.Err
+	}
+	return ErrChaos
+}
+
+func (c *Chaos) delay() {
+	if c.Latency > 0 {
+		time.Sleep(c.Latency)
+	}
+}
+
+// Failed get the count of the injected failures
+func (c *Chaos) Failed() uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.failed
+}
+
+// ChaosHandler wrap a handler, inject the errors and latency for testing.
+//
+// Useful for test the error handling, failover and retry configurations. eg: BudgetHandler, AsyncHandler.OnError.
+type ChaosHandler struct {
+	slog.Handler
+	Chaos
+	// FailFlush inject the error on Flush
+	FailFlush bool
+	// FailClose inject the error on Close
+	FailClose bool
+}
+
+// NewChaosHandler create new ChaosHandler
+//
+// Usage:
+//
+//	// fail 10% records, and add 50ms latency for each record
+//	h := handler.NewChaosHandler(fileHandler, func(h *handler.ChaosHandler) {
+//		h.FailRate = 0.1
+//		h.Latency = 50 * time.Millisecond
+//	})
+func NewChaosHandler(h slog.Handler, fns ...func(h *ChaosHandler)) *ChaosHandler {
+	ch := &ChaosHandler{Handler: h}
+	for _, fn := range fns {
+		fn(ch)
+	}
+	return ch
+}
+
+// Handle a log record, the record is not passed to the wrapped handler on failure.
+func (h *ChaosHandler) Handle(r *slog.Record) error {
+	h.delay()
+	if h.shouldFail() {
+		return h.err()
+	}
+	return h.Handler.Handle(r)
+}
+
+// Flush the wrapped handler, or returns the injected error on FailFlush=true
+func (h *ChaosHandler) Flush() error {
+	if h.FailFlush {
+		return h.err()
+	}
+	return h.Handler.Flush()
+}
+
+// Close the wrapped handler, or returns the injected error on FailClose=true
+func (h *ChaosHandler) Close() error {
+	if h.FailClose {
+		return h.err()
+	}
+	return h.Handler.Close()
+}
+
+// ChaosWriter wrap a writer, inject the errors, latency and partial writes for testing.
+type ChaosWriter struct {
+	io.Writer
+	Chaos
+	// Partial on failure, write the first half of the data then returns io.ErrShortWrite.
+	Partial bool
+}
+
+// NewChaosWriter create new ChaosWriter
+//
+// Usage:
+//
+//	w := handler.NewChaosWriter(file, func(w *handler.ChaosWriter) {
+//		w.FailEvery = 3
+//		w.Partial = true
+//	})
+//	h := handler.NewIOWriter(w, slog.AllLevels)
+func NewChaosWriter(w io.Writer, fns ...func(w *ChaosWriter)) *ChaosWriter {
+	cw := &ChaosWriter{Writer: w}
+	for _, fn := range fns {
+		fn(cw)
+	}
+	return cw
+}
+
+// Write data to the wrapped writer
+func (w *ChaosWriter) Write(p []byte) (int, error) {
+	w.delay()
+	if !w.shouldFail() {
+		return w.Writer.Write(p)
+	}
+
+	if !w.Partial {
+		return 0, w.err()
+	}
+
+	n, err := w.Writer.Write(p[:len(p)/2])
+	if err != nil {
+		return n, err
+	}
+	return n, io.ErrShortWrite
+}
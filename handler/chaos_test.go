@@ -0,0 +1,80 @@
+package handler_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/gookit/goutil/testutil/assert"
+	"github.com/gookit/slog"
+	"github.com/gookit/slog/handler"
+)
+
+func TestChaosHandler(t *testing.T) {
+	buf := new(bytes.Buffer)
+	h := handler.NewChaosHandler(handler.NewSimple(buf, slog.DebugLevel), func(h *handler.ChaosHandler) {
+		h.FailEvery = 2
+	})
+
+	assert.NoErr(t, h.Handle(newLogRecord("message1")))
+	assert.ErrIs(t, h.Handle(newLogRecord("message2")), handler.ErrChaos)
+	assert.NoErr(t, h.Handle(newLogRecord("message3")))
+	assert.Eq(t, uint64(1), h.Failed())
+	assert.NotContains(t, buf.String(), "message2")
+
+	// fail by rate, and custom error
+	myErr := errors.New("disk full")
+	h.FailEvery = 0
+	h.FailRate = 0.5
+	h.Err = myErr
+	h.Rand = func() float64 { return 0.3 }
+	assert.ErrIs(t, h.Handle(newLogRecord("message4")), myErr)
+	h.Rand = func() float64 { return 0.6 }
+	assert.NoErr(t, h.Handle(newLogRecord("message5")))
+	assert.Eq(t, uint64(2), h.Failed())
+
+	// latency
+	h.Latency = 10 * time.Millisecond
+	start := time.Now()
+	assert.NoErr(t, h.Handle(newLogRecord("message6")))
+	assert.Gt(t, int64(time.Since(start)), int64(9*time.Millisecond))
+
+	h.FailFlush, h.FailClose = true, true
+	assert.ErrIs(t, h.Flush(), myErr)
+	assert.ErrIs(t, h.Close(), myErr)
+
+	// used with the logger
+	l := slog.NewWithHandlers(handler.NewChaosHandler(handler.NewSimple(buf, slog.DebugLevel), func(h *handler.ChaosHandler) {
+		h.FailRate = 1
+	}))
+	l.Info("chaos message")
+	assert.ErrIs(t, l.LastErr(), handler.ErrChaos)
+}
+
+func TestChaosWriter(t *testing.T) {
+	buf := new(bytes.Buffer)
+	w := handler.NewChaosWriter(buf, func(w *handler.ChaosWriter) {
+		w.FailEvery = 2
+		w.Partial = true
+	})
+
+	n, err := w.Write([]byte("abcd"))
+	assert.NoErr(t, err)
+	assert.Eq(t, 4, n)
+
+	n, err = w.Write([]byte("efgh"))
+	assert.ErrIs(t, err, io.ErrShortWrite)
+	assert.Eq(t, 2, n)
+	assert.Eq(t, "abcdef", buf.String())
+
+	// not partial
+	w.Partial = false
+	_, _ = w.Write([]byte("ijkl"))
+	n, err = w.Write([]byte("mnop"))
+	assert.ErrIs(t, err, handler.ErrChaos)
+	assert.Eq(t, 0, n)
+	assert.Eq(t, "abcdefijkl", buf.String())
+	assert.Eq(t, uint64(2), w.Failed())
+}
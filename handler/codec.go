@@ -0,0 +1,127 @@
+package handler
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"sync"
+)
+
+// Codec compression codec for the network handlers payload. used by the HTTPHandler,
+// NetHandler, GELFHandler and OTLPHandler.
+//
+// The gzip, deflate and none codecs are built-in. The zstd, snappy are not built-in
+// for avoid the third-party dependencies, please implement them and register by RegisterCodec().
+//
+// Usage:
+//
+//	// eg: use github.com/klauspost/compress/zstd
+//	type ZstdCodec struct{ enc *zstd.Encoder }
+//
+//	func (c *ZstdCodec) Name() string { return "zstd" }
+//	func (c *ZstdCodec) Encode(data []byte) ([]byte, error) { return c.enc.EncodeAll(data, nil), nil }
+//
+//	handler.RegisterCodec(&ZstdCodec{enc: enc})
+type Codec interface {
+	// Name of the codec, is also the Content-Encoding value. eg: "gzip"
+	Name() string
+	// Encode compress the data
+	Encode(data []byte) ([]byte, error)
+}
+
+// built-in codec names
+const (
+	CodecNone    = "none"
+	CodecGzip    = "gzip"
+	CodecDeflate = "deflate"
+)
+
+var (
+	codecMu sync.RWMutex
+	codecs  = map[string]Codec{
+		CodecNone:    NoneCodec{},
+		CodecGzip:    &GzipCodec{Level: gzip.DefaultCompression},
+		CodecDeflate: &DeflateCodec{Level: flate.DefaultCompression},
+	}
+)
+
+// RegisterCodec register a codec, will override the exists codec with same name.
+func RegisterCodec(c Codec) {
+	codecMu.Lock()
+	codecs[c.Name()] = c
+	codecMu.Unlock()
+}
+
+// GetCodec get codec by name. returns NoneCodec on name is empty.
+func GetCodec(name string) (Codec, bool) {
+	if name == "" {
+		return NoneCodec{}, true
+	}
+
+	codecMu.RLock()
+	defer codecMu.RUnlock()
+	c, ok := codecs[name]
+	return c, ok
+}
+
+// NoneCodec not compress the data
+type NoneCodec struct{}
+
+// Name of the codec
+func (NoneCodec) Name() string { return CodecNone }
+
+// Encode returns the data directly
+func (NoneCodec) Encode(data []byte) ([]byte, error) { return data, nil }
+
+// GzipCodec compress the data by gzip
+type GzipCodec struct {
+	// Level compression level. see gzip.DefaultCompression
+	Level int
+}
+
+// Name of the codec
+func (c *GzipCodec) Name() string { return CodecGzip }
+
+// Encode compress the data
+func (c *GzipCodec) Encode(data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	w, err := gzip.NewWriterLevel(&buf, c.Level)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err = w.Write(data); err != nil {
+		return nil, err
+	}
+	if err = w.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// DeflateCodec compress the data by zlib format deflate. same as the HTTP "deflate" Content-Encoding.
+type DeflateCodec struct {
+	// Level compression level. see flate.DefaultCompression
+	Level int
+}
+
+// Name of the codec
+func (c *DeflateCodec) Name() string { return CodecDeflate }
+
+// Encode compress the data
+func (c *DeflateCodec) Encode(data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	w, err := zlib.NewWriterLevel(&buf, c.Level)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err = w.Write(data); err != nil {
+		return nil, err
+	}
+	if err = w.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,286 @@
+package handler
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/gookit/slog"
+)
+
+// collectorRecord the wire format of the records between the collector client and server, one JSON per line.
+type collectorRecord struct {
+	Time    time.Time  `json:"time"`
+	Level   slog.Level `json:"level"`
+	Channel string     `json:"channel,omitempty"`
+	Message string     `json:"message"`
+	Fields  slog.M     `json:"fields,omitempty"`
+	Data    slog.M     `json:"data,omitempty"`
+	Extra   slog.M     `json:"extra,omitempty"`
+	Tags    []string   `json:"tags,omitempty"`
+	// the caller info
+	Func string `json:"func,omitempty"`
+	File string `json:"file,omitempty"`
+	Line int    `json:"line,omitempty"`
+}
+
+// collectorFormatter encode the record to the collector wire format
+type collectorFormatter struct{}
+
+// Format the record to a JSON line
+func (collectorFormatter) Format(r *slog.Record) ([]byte, error) {
+	cr := collectorRecord{
+		Time:    r.Time,
+		Level:   r.Level,
+		Channel: r.Channel,
+		Message: r.Message,
+		Data:    collectorValues(r.Data),
+		Extra:   collectorValues(r.Extra),
+		Tags:    r.Tags,
+	}
+
+	if len(r.Fields) > 0 || len(r.Attrs) > 0 {
+		cr.Fields = make(slog.M, len(r.Fields)+len(r.Attrs))
+		for _, attr := range r.Attrs {
+			cr.Fields[attr.Key] = collectorValue(attr.Value())
+		}
+		for k, v := range r.Fields {
+			cr.Fields[k] = collectorValue(v)
+		}
+	}
+
+	if r.Caller != nil {
+		cr.Func, cr.File, cr.Line = r.Caller.Function, r.Caller.File, r.Caller.Line
+	}
+
+	bts, err := json.Marshal(cr)
+	if err != nil {
+		// has the values cannot be encoded, use the string of them.
+		cr.Fields, cr.Data, cr.Extra = collectorStrings(cr.Fields), collectorStrings(cr.Data), collectorStrings(cr.Extra)
+		if bts, err = json.Marshal(cr); err != nil {
+			return nil, err
+		}
+	}
+	return append(bts, '\n'), nil
+}
+
+func collectorValues(mp slog.M) slog.M {
+	if len(mp) == 0 {
+		return nil
+	}
+
+	nm := make(slog.M, len(mp))
+	for k, v := range mp {
+		nm[k] = collectorValue(v)
+	}
+	return nm
+}
+
+// the error is encoded as "{}" by json, use the message of it.
+func collectorValue(v any) any {
+	if err, ok := v.(error); ok && err != nil {
+		return err.Error()
+	}
+	return v
+}
+
+func collectorStrings(mp slog.M) slog.M {
+	for k, v := range mp {
+		if _, err := json.Marshal(v); err != nil {
+			mp[k] = fmt.Sprint(v)
+		}
+	}
+	return mp
+}
+
+// NewCollectorClient create a handler send the records to the CollectorServer by unix socket.
+// It is a NetHandler with the collector wire format, don't change the formatter of it.
+//
+// Usage:
+//
+//	h := handler.NewCollectorClient("/var/run/app-log.sock")
+//	slog.PushHandler(h)
+func NewCollectorClient(sockPath string, fns ...func(h *NetHandler)) *NetHandler {
+	h := NewNetHandler("unix", sockPath)
+	h.SetFormatter(collectorFormatter{})
+
+	for _, fn := range fns {
+		fn(h)
+	}
+	return h
+}
+
+// CollectorServer a local collector listen on the unix socket, receive the records from the
+// collector clients(see NewCollectorClient) and write them to the logger.
+//
+// So multiple processes on one host can funnel the records into a single rotating file owned
+// by one process, eliminating the file-lock contention.
+type CollectorServer struct {
+	// Path of the unix socket
+	Path string
+	// MaxLineSize max bytes of a record. default is 1MB
+	MaxLineSize int
+
+	l  *slog.Logger
+	ln net.Listener
+
+	mu     sync.Mutex
+	conns  map[net.Conn]struct{}
+	closed bool
+	wg     sync.WaitGroup
+}
+
+// NewCollectorServer create a CollectorServer, the received records are written by the logger.WriteRecord()
+//
+// Usage:
+//
+//	l := slog.NewWithHandlers(handler.MustRotateFile("/var/log/app.log", handler.EveryDay))
+//	srv := handler.NewCollectorServer("/var/run/app-log.sock", l)
+//	go srv.ListenAndServe()
+//	defer srv.Close()
+func NewCollectorServer(sockPath string, l *slog.Logger) *CollectorServer {
+	return &CollectorServer{
+		Path:        sockPath,
+		MaxLineSize: 1024 * 1024,
+		l:           l,
+		conns:       make(map[net.Conn]struct{}),
+	}
+}
+
+// Listen on the unix socket, will remove the stale socket file.
+func (s *CollectorServer) Listen() error {
+	if fi, err := os.Stat(s.Path); err == nil && fi.Mode()&os.ModeSocket != 0 {
+		_ = os.Remove(s.Path)
+	}
+
+	ln, err := net.Listen("unix", s.Path)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	s.ln = ln
+	s.mu.Unlock()
+	return nil
+}
+
+// ListenAndServe listen on the unix socket and serve the connections. see Serve()
+func (s *CollectorServer) ListenAndServe() error {
+	if err := s.Listen(); err != nil {
+		return err
+	}
+	return s.Serve()
+}
+
+// Serve accept and handle the connections, will block until the server is closed.
+// returns nil on the server is closed.
+func (s *CollectorServer) Serve() error {
+	for {
+		conn, err := s.ln.Accept()
+		if err != nil {
+			if s.isClosed() || errors.Is(err, net.ErrClosed) {
+				return nil
+			}
+			return err
+		}
+
+		s.mu.Lock()
+		if s.closed {
+			s.mu.Unlock()
+			_ = conn.Close()
+			return nil
+		}
+		s.conns[conn] = struct{}{}
+		s.wg.Add(1)
+		s.mu.Unlock()
+
+		go s.handleConn(conn)
+	}
+}
+
+func (s *CollectorServer) isClosed() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.closed
+}
+
+func (s *CollectorServer) handleConn(conn net.Conn) {
+	defer func() {
+		_ = conn.Close()
+		s.mu.Lock()
+		delete(s.conns, conn)
+		s.mu.Unlock()
+		s.wg.Done()
+	}()
+
+	sc := bufio.NewScanner(conn)
+	sc.Buffer(make([]byte, 0, 4096), s.MaxLineSize)
+	for sc.Scan() {
+		if err := s.writeLine(sc.Bytes()); err != nil {
+			slog.ReportInternal(slog.WarnLevel, "slog: collector server decode record error:", err)
+		}
+	}
+
+	if err := sc.Err(); err != nil && !s.isClosed() {
+		slog.ReportInternal(slog.WarnLevel, "slog: collector server read error:", err)
+	}
+}
+
+// decode a record line and write it to the logger
+func (s *CollectorServer) writeLine(line []byte) error {
+	var cr collectorRecord
+	dec := json.NewDecoder(bytes.NewReader(line))
+	dec.UseNumber()
+	if err := dec.Decode(&cr); err != nil {
+		return err
+	}
+
+	r := s.l.NewRecordAt(cr.Time, cr.Level, cr.Message, cr.Fields)
+	if cr.Channel != "" {
+		r.Channel = cr.Channel
+	}
+	if cr.Data != nil {
+		r.Data = cr.Data
+	}
+	r.Extra = cr.Extra
+	r.Tags = cr.Tags
+	if cr.File != "" {
+		r.SetCaller(&runtime.Frame{Function: cr.Func, File: cr.File, Line: cr.Line})
+	}
+
+	s.l.WriteRecord(r)
+	return nil
+}
+
+// Close the listener and all connections, wait for the handling records are written.
+// then flush the logger handlers. the logger is not closed.
+func (s *CollectorServer) Close() error {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return nil
+	}
+	s.closed = true
+
+	var err error
+	if s.ln != nil {
+		err = s.ln.Close()
+	}
+	for conn := range s.conns {
+		_ = conn.Close()
+	}
+	s.mu.Unlock()
+
+	s.wg.Wait()
+	if err1 := s.l.Flush(); err == nil {
+		err = err1
+	}
+	return err
+}
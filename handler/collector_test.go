@@ -0,0 +1,93 @@
+package handler_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gookit/goutil/testutil/assert"
+	"github.com/gookit/slog"
+	"github.com/gookit/slog/handler"
+)
+
+type lockedBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *lockedBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *lockedBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func TestCollectorServer(t *testing.T) {
+	sockPath := filepath.Join(t.TempDir(), "collector.sock")
+
+	out := &lockedBuffer{}
+	sh := handler.NewIOWriter(out, slog.AllLevels)
+	sh.SetFormatter(slog.NewJSONFormatter(func(f *slog.JSONFormatter) {
+		f.Fields = append(f.Fields, slog.FieldKeyTags)
+	}))
+	srv := handler.NewCollectorServer(sockPath, slog.NewWithHandlers(sh))
+	assert.NoErr(t, srv.Listen())
+
+	served := make(chan error, 1)
+	go func() { served <- srv.Serve() }()
+
+	// multi clients, like in multiple processes
+	var wg sync.WaitGroup
+	for i := 0; i < 3; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			l := slog.NewWithHandlers(handler.NewCollectorClient(sockPath))
+			l.ChannelName = "worker"
+			l.ReportCaller = true
+			defer l.MustClose()
+
+			for j := 0; j < 10; j++ {
+				l.WithAttrs(slog.Int("worker", i), slog.Err(errors.New("some error"))).
+					WithTags("collected").
+					WithData(slog.M{"key": "val"}).
+					Warn("message from worker")
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	// wait for the records are received
+	for i := 0; i < 100 && strings.Count(out.String(), "\n") < 30; i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+	assert.NoErr(t, srv.Close())
+	assert.NoErr(t, <-served)
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	assert.Len(t, lines, 30)
+
+	var data map[string]any
+	assert.NoErr(t, json.Unmarshal([]byte(lines[0]), &data))
+	assert.Eq(t, "worker", data["channel"])
+	assert.Eq(t, "WARN", data["level"])
+	assert.Eq(t, "message from worker", data["message"])
+	assert.Eq(t, "some error", data["error"])
+	assert.Eq(t, map[string]any{"key": "val"}, data["data"])
+	assert.Eq(t, []any{"collected"}, data["tags"])
+	assert.StrContains(t, data["caller"].(string), "collector_test.go")
+	assert.Contains(t, []any{float64(0), float64(1), float64(2)}, data["worker"])
+
+	// close again
+	assert.NoErr(t, srv.Close())
+}
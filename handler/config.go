@@ -15,6 +15,14 @@ import (
 const (
 	BuffModeLine = "line"
 	BuffModeBite = "bite"
+	// BuffModeMmap write logs by memory-mapped file. only for non-rotate logfile on linux, darwin.
+	//
+	// NOTICE: the MaxSize and RotateTime must be 0, NewConfig() set them by default,
+	// please use NewEmptyConfig() or reset them. otherwise CreateWriter() will return an error.
+	BuffModeMmap = "mmap"
+	// BuffModeAdaptive the buffer size will be adjusted by recent throughput, BuffSize is the max size.
+	// the min size is bufwrite.DefaultAdaptiveMinSize, or the BuffSize on it is smaller.
+	BuffModeAdaptive = "adaptive"
 )
 
 const (
@@ -29,6 +37,9 @@ type ConfigFn func(c *Config)
 
 // Config struct
 type Config struct {
+	// Name for the created handler, use for diagnostics. see slog.HandlerName()
+	Name string `json:"name" yaml:"name"`
+
 	// Logfile for write logs
 	Logfile string `json:"logfile" yaml:"logfile"`
 
@@ -47,12 +58,27 @@ type Config struct {
 	// UseJSON for format logs
 	UseJSON bool `json:"use_json" yaml:"use_json"`
 
-	// BuffMode type name. allow: line, bite
+	// Prefix add a static prefix to each formatted record. eg: "[worker-3] "
+	//
+	// the handler formatter is wrapped, use slog.AsTextFormatter(), AsJSONFormatter() to get the concrete formatter.
+	Prefix string `json:"prefix" yaml:"prefix"`
+
+	// Suffix add a static suffix to each formatted record, before the trailing newline.
+	Suffix string `json:"suffix" yaml:"suffix"`
+
+	// BuffMode type name. allow: line, bite, mmap, adaptive
 	BuffMode string `json:"buff_mode" yaml:"buff_mode"`
 
 	// BuffSize for enable buffer, unit is bytes. set 0 to disable buffer
 	BuffSize int `json:"buff_size" yaml:"buff_size"`
 
+	// BuffGrow allow the line buffer to grow temporarily for a record larger than the BuffSize,
+	// instead of writing it directly to the file. only for BuffModeLine. see bufwrite.LineWriter.EnableGrow
+	BuffGrow bool `json:"buff_grow" yaml:"buff_grow"`
+
+	// FlushOnLevel flush the buffer immediately on handle a record level <= FlushOnLevel. 0 is disable.
+	FlushOnLevel slog.Level `json:"flush_on_level" yaml:"flush_on_level"`
+
 	// RotateTime for rotate file, unit is seconds.
 	RotateTime rotatefile.RotateTime `json:"rotate_time" yaml:"rotate_time"`
 
@@ -79,6 +105,26 @@ type Config struct {
 	// 0 is not limit, default is a week.
 	BackupTime uint `json:"backup_time" yaml:"backup_time"`
 
+	// MaxTotalSize max total size of the backup files, unit is bytes.
+	// see rotatefile.Config.MaxTotalSize
+	//
+	// 0 is not limit, default is 0.
+	MaxTotalSize uint64 `json:"max_total_size" yaml:"max_total_size"`
+
+	// MultiProcess enable for multiple processes write the same logfile.
+	//
+	// The buffer is disabled, each record is written by one write call on O_APPEND mode.
+	// and the rotate writer will use the file lock on write and rotating. see rotatefile.Config.MultiProcess
+	MultiProcess bool `json:"multi_process" yaml:"multi_process"`
+
+	// Reopenable create the FileWriter for the non-rotate logfile, it can be reopened on the file
+	// is moved by external tools. see Logger.ReopenAll(). default will use the *os.File
+	Reopenable bool `json:"reopenable" yaml:"reopenable"`
+
+	// ManifestFile write the info(name, size, sha256, time range) of the rotated files to it.
+	// see rotatefile.Config.ManifestFile
+	ManifestFile string `json:"manifest_file" yaml:"manifest_file"`
+
 	// RenameFunc build filename for rotate file
 	RenameFunc func(filepath string, rotateNum uint) string
 
@@ -140,14 +186,32 @@ func (c *Config) CreateHandler() (*SyncCloseHandler, error) {
 		Output: output,
 		// with log level and formatter
 		LevelFormattable: c.newLevelFormattable(),
+		FlushOnLevel:     c.FlushOnLevel,
 	}
 
 	if c.UseJSON {
 		h.SetFormatter(slog.NewJSONFormatter())
 	}
+	c.wrapFormatter(h)
+	h.SetName(c.Name)
 	return h, nil
 }
 
+// wrap the handler formatter for add the prefix and suffix
+func (c *Config) wrapFormatter(h slog.Formattable) {
+	var mws []slog.FormatterMiddleware
+	if c.Prefix != "" {
+		mws = append(mws, slog.PrefixMiddleware(c.Prefix))
+	}
+	if c.Suffix != "" {
+		mws = append(mws, slog.SuffixMiddleware(c.Suffix))
+	}
+
+	if len(mws) > 0 {
+		h.SetFormatter(slog.ChainFormatter(h.Formatter(), mws...))
+	}
+}
+
 // RotateWriter build rotate writer by config
 func (c *Config) RotateWriter() (output SyncCloseWriter, err error) {
 	if c.MaxSize == 0 && c.RotateTime == 0 {
@@ -162,6 +226,9 @@ func (c *Config) CreateWriter() (output SyncCloseWriter, err error) {
 	if c.Logfile == "" {
 		return nil, errorx.Raw("slog: logfile cannot be empty for create writer")
 	}
+	if c.BuffMode == BuffModeMmap && (c.MaxSize > 0 || c.RotateTime > 0) {
+		return nil, errorx.Raw("slog: the mmap buffer mode cannot be used with rotate file, please set MaxSize and RotateTime to 0")
+	}
 	if c.FilePerm == 0 {
 		c.FilePerm = rotatefile.DefaultFilePerm
 	}
@@ -182,7 +249,10 @@ func (c *Config) CreateWriter() (output SyncCloseWriter, err error) {
 		rc.RotateMode = c.RotateMode
 		rc.BackupNum = c.BackupNum
 		rc.BackupTime = c.BackupTime
+		rc.MaxTotalSize = c.MaxTotalSize
 		rc.Compress = c.Compress
+		rc.MultiProcess = c.MultiProcess
+		rc.ManifestFile = c.ManifestFile
 
 		if c.RenameFunc != nil {
 			rc.RenameFunc = c.RenameFunc
@@ -192,6 +262,16 @@ func (c *Config) CreateWriter() (output SyncCloseWriter, err error) {
 		}
 
 		output, err = rc.Create()
+	} else if c.BuffMode == BuffModeMmap && !c.MultiProcess {
+		// create a mmap file writer, the BuffSize is the grow size of the mapped region.
+		mw, err := bufwrite.NewMmapWriterPerm(c.Logfile, c.BuffSize, c.FilePerm)
+		if err != nil {
+			return nil, err
+		}
+		return mw, nil
+	} else if c.Reopenable {
+		// create a file writer, can be reopened.
+		output, err = OpenFileWriter(c.Logfile, c.FilePerm)
 	} else {
 		// create a file writer
 		output, err = fsutil.OpenAppendFile(c.Logfile, c.FilePerm)
@@ -201,13 +281,31 @@ func (c *Config) CreateWriter() (output SyncCloseWriter, err error) {
 		return nil, err
 	}
 
-	// wrap buffer
-	if c.BuffSize > 0 {
-		output = c.wrapBuffer(output)
+	// wrap buffer. the buffered writes may split the records on multi process.
+	if c.BuffSize > 0 && !c.MultiProcess {
+		bw := c.wrapBuffer(output)
+		if ro, ok := output.(slog.Reopener); ok {
+			return bufferedReopener{bw, ro}, nil
+		}
+		output = bw
 	}
 	return
 }
 
+// the buffered writer can reopen the underlying file
+type bufferedReopener struct {
+	flushSyncCloseWriter
+	ro slog.Reopener
+}
+
+// Reopen flush the buffered data, then reopen the underlying file
+func (w bufferedReopener) Reopen() error {
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	return w.ro.Reopen()
+}
+
 type flushSyncCloseWriter interface {
 	FlushCloseWriter
 	Sync() error
@@ -215,9 +313,17 @@ type flushSyncCloseWriter interface {
 
 // wrap buffer for the writer
 func (c *Config) wrapBuffer(w io.Writer) (bw flushSyncCloseWriter) {
-	if c.BuffMode == BuffModeLine {
-		bw = bufwrite.NewLineWriterSize(w, c.BuffSize)
-	} else {
+	switch c.BuffMode {
+	case BuffModeLine:
+		bw = bufwrite.NewLineWriterSize(w, c.BuffSize).EnableGrow(c.BuffGrow)
+	case BuffModeAdaptive:
+		// start from the default min size, but not larger than the BuffSize
+		minSize := bufwrite.DefaultAdaptiveMinSize
+		if c.BuffSize < minSize {
+			minSize = c.BuffSize
+		}
+		bw = bufwrite.NewAdaptiveWriter(w, minSize, c.BuffSize)
+	default:
 		bw = bufwrite.NewBufIOWriterSize(w, c.BuffSize)
 	}
 	return bw
@@ -234,6 +340,11 @@ func WithLogfile(logfile string) ConfigFn {
 	return func(c *Config) { c.Logfile = logfile }
 }
 
+// WithHandlerName setting the handler name
+func WithHandlerName(name string) ConfigFn {
+	return func(c *Config) { c.Name = name }
+}
+
 // WithFilePerm setting
 func WithFilePerm(filePerm fs.FileMode) ConfigFn {
 	return func(c *Config) { c.FilePerm = filePerm }
@@ -291,16 +402,33 @@ func WithBackupTime(bt uint) ConfigFn {
 	return func(c *Config) { c.BackupTime = bt }
 }
 
-// WithBuffMode setting buffer mode
+// WithMaxTotalSize setting max total size of the backup files
+func WithMaxTotalSize(maxTotalSize uint64) ConfigFn {
+	return func(c *Config) { c.MaxTotalSize = maxTotalSize }
+}
+
+// WithBuffMode setting buffer mode.
+//
+// NOTICE: the BuffModeMmap cannot be used with rotate file, see BuffModeMmap
 func WithBuffMode(buffMode string) ConfigFn {
 	return func(c *Config) { c.BuffMode = buffMode }
 }
 
+// WithBuffGrow setting allow the line buffer to grow for oversized records. see Config.BuffGrow
+func WithBuffGrow(enable bool) ConfigFn {
+	return func(c *Config) { c.BuffGrow = enable }
+}
+
 // WithBuffSize setting buffer size
 func WithBuffSize(buffSize int) ConfigFn {
 	return func(c *Config) { c.BuffSize = buffSize }
 }
 
+// WithFlushOnLevel setting flush the buffer immediately on handle a record level <= the level
+func WithFlushOnLevel(level slog.Level) ConfigFn {
+	return func(c *Config) { c.FlushOnLevel = level }
+}
+
 // WithMaxSize setting max size for rotate file
 func WithMaxSize(maxSize uint64) ConfigFn {
 	return func(c *Config) { c.MaxSize = maxSize }
@@ -311,10 +439,35 @@ func WithCompress(compress bool) ConfigFn {
 	return func(c *Config) { c.Compress = compress }
 }
 
+// WithReopenable setting the non-rotate logfile can be reopened. see Config.Reopenable
+func WithReopenable(reopenable bool) ConfigFn {
+	return func(c *Config) { c.Reopenable = reopenable }
+}
+
+// WithMultiProcess setting for multiple processes write the same logfile
+func WithMultiProcess(multiProcess bool) ConfigFn {
+	return func(c *Config) { c.MultiProcess = multiProcess }
+}
+
+// WithManifestFile setting the manifest file for the rotated files
+func WithManifestFile(manifestFile string) ConfigFn {
+	return func(c *Config) { c.ManifestFile = manifestFile }
+}
+
 // WithUseJSON setting use json format
 func WithUseJSON(useJSON bool) ConfigFn {
 	return func(c *Config) { c.UseJSON = useJSON }
 }
 
+// WithPrefix setting the prefix of each formatted record
+func WithPrefix(prefix string) ConfigFn {
+	return func(c *Config) { c.Prefix = prefix }
+}
+
+// WithSuffix setting the suffix of each formatted record
+func WithSuffix(suffix string) ConfigFn {
+	return func(c *Config) { c.Suffix = suffix }
+}
+
 // WithDebugMode setting for debug mode
 func WithDebugMode(c *Config) { c.DebugMode = true }
@@ -2,6 +2,10 @@ package handler_test
 
 import (
 	"bytes"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
 	"testing"
 
 	"github.com/gookit/goutil/errorx"
@@ -177,3 +181,223 @@ func TestNewBuilder_buildFromWriter(t *testing.T) {
 		assert.NoErr(t, h.Close())
 	})
 }
+
+func TestConfig_CreateWriter_mmap(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("mmap writer is not supported on windows")
+	}
+
+	logfile := "./testdata/file-mmap.log"
+	assert.NoErr(t, fsutil.DeleteIfFileExist(logfile))
+
+	h, err := handler.NewEmptyConfig(
+		handler.WithLogfile(logfile),
+		handler.WithBuffMode(handler.BuffModeMmap),
+		handler.WithBuffSize(64),
+	).CreateHandler()
+	assert.NoErr(t, err)
+
+	h.SetFormatter(newTestFormatter())
+	for i := 0; i < 10; i++ {
+		assert.NoErr(t, h.Handle(newLogRecord("mmap message\n")))
+	}
+	assert.NoErr(t, h.Close())
+
+	str := fsutil.ReadString(logfile)
+	assert.Eq(t, 10, strings.Count(str, "mmap message\n"))
+	assert.Eq(t, 130, len(str))
+
+	// mmap cannot be used with rotate file
+	_, err = handler.NewConfig(
+		handler.WithLogfile(logfile),
+		handler.WithBuffMode(handler.BuffModeMmap),
+	).CreateWriter()
+	assert.ErrSubMsg(t, err, "mmap buffer mode cannot be used with rotate file")
+}
+
+func TestConfig_CreateWriter_adaptive(t *testing.T) {
+	logfile := "./testdata/file-adaptive-buff.log"
+	assert.NoErr(t, fsutil.DeleteIfFileExist(logfile))
+
+	h, err := handler.NewEmptyConfig(
+		handler.WithLogfile(logfile),
+		handler.WithBuffMode(handler.BuffModeAdaptive),
+		handler.WithBuffSize(1024*16),
+	).CreateHandler()
+	assert.NoErr(t, err)
+
+	h.SetFormatter(newTestFormatter())
+	assert.NoErr(t, h.Handle(newLogRecord("adaptive message\n")))
+	assert.Eq(t, "", fsutil.ReadString(logfile))
+
+	assert.NoErr(t, h.Flush())
+	assert.Eq(t, "adaptive message\n", fsutil.ReadString(logfile))
+	assert.NoErr(t, h.Close())
+
+	// the BuffSize smaller than the default min size
+	assert.NoErr(t, fsutil.DeleteIfFileExist(logfile))
+	h, err = handler.NewEmptyConfig(
+		handler.WithLogfile(logfile),
+		handler.WithBuffMode(handler.BuffModeAdaptive),
+		handler.WithBuffSize(16),
+	).CreateHandler()
+	assert.NoErr(t, err)
+
+	// oversized record, write directly
+	h.SetFormatter(newTestFormatter())
+	assert.NoErr(t, h.Handle(newLogRecord("adaptive message\n")))
+	assert.Eq(t, "adaptive message\n", fsutil.ReadString(logfile))
+	assert.NoErr(t, h.Close())
+}
+
+func TestConfig_CreateWriter_buffGrow(t *testing.T) {
+	logfile := "./testdata/file-buff-grow.log"
+	assert.NoErr(t, fsutil.DeleteIfFileExist(logfile))
+
+	h, err := handler.NewEmptyConfig(
+		handler.WithLogfile(logfile),
+		handler.WithBuffMode(handler.BuffModeLine),
+		handler.WithBuffSize(8),
+		handler.WithBuffGrow(true),
+	).CreateHandler()
+	assert.NoErr(t, err)
+
+	// the oversized record is buffered, not written directly
+	h.SetFormatter(newTestFormatter())
+	assert.NoErr(t, h.Handle(newLogRecord("a oversized record\n")))
+	assert.Eq(t, "", fsutil.ReadString(logfile))
+
+	assert.NoErr(t, h.Flush())
+	assert.Eq(t, "a oversized record\n", fsutil.ReadString(logfile))
+	assert.NoErr(t, h.Close())
+}
+
+func TestConfig_CreateWriter_multiProcess(t *testing.T) {
+	logfile := filepath.Join(t.TempDir(), "multi-process.log")
+
+	// the buffer is disabled
+	w, err := handler.NewEmptyConfig(
+		handler.WithLogfile(logfile),
+		handler.WithBuffSize(1024),
+		handler.WithMultiProcess(true),
+	).CreateWriter()
+	assert.NoErr(t, err)
+	_, ok := w.(*os.File)
+	assert.True(t, ok)
+	assert.NoErr(t, w.Close())
+
+	// rotate writer with file lock
+	h, err := handler.NewEmptyConfig(
+		handler.WithLogfile(logfile),
+		handler.WithMaxSize(64),
+		handler.WithBuffSize(1024),
+		handler.WithMultiProcess(true),
+	).CreateHandler()
+	assert.NoErr(t, err)
+	rw, ok := h.Output.(*rotatefile.Writer)
+	assert.True(t, ok)
+	assert.True(t, rw.Config().MultiProcess)
+
+	h.SetFormatter(newTestFormatter())
+	for i := 0; i < 5; i++ {
+		assert.NoErr(t, h.Handle(newLogRecord("multi process message\n")))
+	}
+	assert.NoErr(t, h.Close())
+
+	files, err := filepath.Glob(logfile + ".*")
+	assert.NoErr(t, err)
+	assert.NotEmpty(t, files)
+}
+
+func TestBuilder_BuildHandlers(t *testing.T) {
+	logfile := "./testdata/builder-error.log"
+	assert.NoErr(t, fsutil.DeleteIfFileExist(logfile))
+
+	out1 := new(bytes.Buffer)
+	out2 := new(bytes.Buffer)
+	b := handler.NewBuilder().
+		WithOutput(out1).
+		AddOutput(out2, handler.WithLogLevels(slog.NormalLevels), handler.WithUseJSON(true)).
+		AddLogfile(logfile, handler.WithLogLevels(slog.DangerLevels))
+
+	hs := b.BuildHandlers()
+	assert.Len(t, hs, 3)
+
+	// builder is reset
+	assert.Len(t, b.BuildHandlers(), 0)
+
+	l := slog.NewWithHandlers(hs...)
+	l.Info("info message")
+	l.Error("error message")
+
+	assert.StrContains(t, out1.String(), "info message")
+	assert.StrContains(t, out1.String(), "error message")
+	assert.StrContains(t, out2.String(), `"message":"info message"`)
+	assert.NotContains(t, out2.String(), "error message")
+
+	assert.NoErr(t, l.Close())
+	str := fsutil.ReadString(logfile)
+	assert.StrContains(t, str, "error message")
+	assert.NotContains(t, str, "info message")
+
+	l = handler.NewBuilder().AddOutput(out1).BuildLogger(func(l *slog.Logger) {
+		l.ChannelName = "built"
+	})
+	assert.Eq(t, 1, l.HandlersNum())
+	l.Info("from built logger")
+	assert.StrContains(t, out1.String(), "[built] [INFO]")
+}
+
+func TestConfig_prefixSuffix(t *testing.T) {
+	buf := new(bytes.Buffer)
+	h := handler.NewBuilder().
+		WithOutput(buf).
+		WithConfigFn(handler.WithPrefix("[worker-3] "), handler.WithSuffix(" [host-1]")).
+		Build()
+
+	assert.NoErr(t, h.Handle(newLogRecord("prefix message")))
+	s := buf.String()
+	assert.True(t, strings.HasPrefix(s, "[worker-3] ["))
+	assert.True(t, strings.HasSuffix(s, " [host-1]\n"))
+	// the concrete formatter is still reachable
+	assert.NotNil(t, slog.AsTextFormatter(h.Formatter()))
+
+	// file handler
+	logfile := "./testdata/prefix-suffix.log"
+	assert.NoErr(t, fsutil.DeleteIfFileExist(logfile))
+	fh, err := handler.NewFileHandler(logfile, handler.WithUseJSON(true), handler.WithPrefix("app: "))
+	assert.NoErr(t, err)
+	assert.NoErr(t, fh.Handle(newLogRecord("prefix message")))
+	assert.NotNil(t, slog.AsJSONFormatter(fh.Formatter()))
+	assert.NoErr(t, fh.Close())
+	assert.StrContains(t, fsutil.ReadString(logfile), `app: {"channel":"handler_test"`)
+}
+
+func TestWithManifestFile(t *testing.T) {
+	dir := t.TempDir()
+	h, err := handler.NewEmptyConfig(
+		handler.WithLogfile(filepath.Join(dir, "app.log")),
+		handler.WithMaxSize(64),
+		handler.WithManifestFile(filepath.Join(dir, "app.manifest.jsonl")),
+	).CreateHandler()
+	assert.NoErr(t, err)
+
+	rw, ok := h.Output.(*rotatefile.Writer)
+	assert.True(t, ok)
+	assert.Eq(t, filepath.Join(dir, "app.manifest.jsonl"), rw.Config().ManifestFile)
+	assert.NoErr(t, h.Close())
+}
+
+func TestWithMaxTotalSize(t *testing.T) {
+	h, err := handler.NewEmptyConfig(
+		handler.WithLogfile(filepath.Join(t.TempDir(), "app.log")),
+		handler.WithMaxSize(64),
+		handler.WithMaxTotalSize(1024),
+	).CreateHandler()
+	assert.NoErr(t, err)
+
+	rw, ok := h.Output.(*rotatefile.Writer)
+	assert.True(t, ok)
+	assert.Eq(t, uint64(1024), rw.Config().MaxTotalSize)
+	assert.NoErr(t, h.Close())
+}
@@ -3,7 +3,6 @@ package handler
 import (
 	"os"
 
-	"github.com/gookit/color"
 	"github.com/gookit/slog"
 )
 
@@ -21,7 +20,7 @@ func NewConsoleWithLF(lf slog.LevelFormattable) *ConsoleHandler {
 	// default use text formatter
 	f := slog.NewTextFormatter()
 	// default enable color on console
-	f.WithEnableColor(color.SupportColor())
+	f.WithEnableColor(slog.ColorEnabled(os.Stdout))
 
 	h.SetFormatter(f)
 	return h
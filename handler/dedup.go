@@ -0,0 +1,131 @@
+package handler
+
+import (
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gookit/slog"
+)
+
+// DefaultDedupWindow default window for the DedupHandler
+var DefaultDedupWindow = 10 * time.Second
+
+// DedupHandler wrap a handler, collapse the identical consecutive records in a time window,
+// then emit a summary record: "last message repeated N times".
+//
+// The summary record is emitted on a different record comes, the window is expired, or on Close.
+// On Flush, the summary is emitted only if the window is expired,
+// so the flush on each error record will not break the collapsing.
+type DedupHandler struct {
+	// the wrapped handler
+	slog.Handler
+	// Window the max duration for collapse the records, start from the first record.
+	Window time.Duration
+	// KeyFunc build the key for check records identical. default is: channel + slog.SampleKey()
+	KeyFunc func(r *slog.Record) string
+
+	mu    sync.Mutex
+	key   string
+	start time.Time
+	// the repeated count and the latest repeated record
+	repeated int
+	last     *slog.Record
+}
+
+// NewDedupHandler create new DedupHandler
+//
+// Usage:
+//
+//	h := handler.NewDedupHandler(fileHandler, func(h *handler.DedupHandler) {
+//		h.Window = time.Minute
+//	})
+func NewDedupHandler(h slog.Handler, fns ...func(h *DedupHandler)) *DedupHandler {
+	dh := &DedupHandler{
+		Handler: h,
+		Window:  DefaultDedupWindow,
+		KeyFunc: dedupKey,
+	}
+
+	for _, fn := range fns {
+		fn(dh)
+	}
+	return dh
+}
+
+func dedupKey(r *slog.Record) string {
+	return r.Channel + ":" + slog.SampleKey(r)
+}
+
+// Handle a log record, the identical record in the window will be dropped.
+func (h *DedupHandler) Handle(r *slog.Record) error {
+	key := h.KeyFunc(r)
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if key == h.key && r.Time.Sub(h.start) < h.Window {
+		h.repeated++
+		// clone the record, it will be reused by the logger
+		h.last = r.Clone()
+		return nil
+	}
+
+	err := h.emitRepeated()
+	h.key, h.start = key, r.Time
+
+	if herr := h.Handler.Handle(r); herr != nil {
+		return herr
+	}
+	return err
+}
+
+// emit the summary record for the repeated records. h.mu is held.
+func (h *DedupHandler) emitRepeated() error {
+	if h.repeated == 0 {
+		return nil
+	}
+
+	sr := h.last
+	sr.Data = slog.M{"repeated": h.repeated, "repeated_message": sr.Message}
+	sr.Message = "last message repeated " + strconv.Itoa(h.repeated) + " times"
+
+	h.repeated, h.last = 0, nil
+	return h.Handler.Handle(sr)
+}
+
+// Repeated get the collapsed count of the current identical records
+func (h *DedupHandler) Repeated() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.repeated
+}
+
+// Flush emit the summary record on the window is expired, then flush the wrapped handler.
+func (h *DedupHandler) Flush() error {
+	h.mu.Lock()
+	var err error
+	if h.repeated > 0 && time.Since(h.start) >= h.Window {
+		err = h.emitRepeated()
+		h.key = ""
+	}
+	h.mu.Unlock()
+
+	if ferr := h.Handler.Flush(); ferr != nil {
+		return ferr
+	}
+	return err
+}
+
+// Close emit the summary record, then close the wrapped handler.
+func (h *DedupHandler) Close() error {
+	h.mu.Lock()
+	err := h.emitRepeated()
+	h.key = ""
+	h.mu.Unlock()
+
+	if cerr := h.Handler.Close(); cerr != nil {
+		return cerr
+	}
+	return err
+}
@@ -0,0 +1,70 @@
+package handler_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gookit/goutil/testutil/assert"
+	"github.com/gookit/slog"
+	"github.com/gookit/slog/handler"
+)
+
+func TestDedupHandler_Handle(t *testing.T) {
+	buf := new(bytes.Buffer)
+	h := handler.NewDedupHandler(handler.NewSimple(buf, slog.DebugLevel))
+	l := slog.NewWithHandlers(h)
+
+	for i := 0; i < 5; i++ {
+		l.Error("connect db failed")
+	}
+	assert.Eq(t, 4, h.Repeated())
+	assert.Eq(t, 1, strings.Count(buf.String(), "connect db failed"))
+
+	// a different record, emit the summary
+	l.Info("db connected")
+	assert.Eq(t, 0, h.Repeated())
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	assert.Len(t, lines, 3)
+	assert.StrContains(t, lines[1], "ERROR")
+	assert.StrContains(t, lines[1], "last message repeated 4 times")
+	assert.StrContains(t, lines[1], "repeated_message:connect db failed")
+	assert.StrContains(t, lines[2], "db connected")
+
+	// emit the summary on close
+	buf.Reset()
+	l.Info("db connected")
+	assert.Eq(t, 1, h.Repeated())
+	assert.NoErr(t, l.Close())
+	assert.StrContains(t, buf.String(), "last message repeated 1 times")
+}
+
+func TestDedupHandler_window(t *testing.T) {
+	buf := new(bytes.Buffer)
+	h := handler.NewDedupHandler(handler.NewSimple(buf, slog.DebugLevel), func(h *handler.DedupHandler) {
+		h.Window = 20 * time.Millisecond
+	})
+
+	r := newLogRecord("repeated message")
+	assert.NoErr(t, h.Handle(r))
+	assert.NoErr(t, h.Handle(r))
+	assert.NoErr(t, h.Handle(r))
+
+	// window not expired, the summary is not emitted on flush
+	assert.NoErr(t, h.Flush())
+	assert.Eq(t, 2, h.Repeated())
+	assert.Eq(t, 1, strings.Count(buf.String(), "repeated message"))
+
+	time.Sleep(25 * time.Millisecond)
+	assert.NoErr(t, h.Flush())
+	assert.Eq(t, 0, h.Repeated())
+	assert.StrContains(t, buf.String(), "last message repeated 2 times")
+
+	// the window is expired, the record will be handled
+	buf.Reset()
+	r.Time = time.Now()
+	assert.NoErr(t, h.Handle(r))
+	assert.StrContains(t, buf.String(), "repeated message")
+}
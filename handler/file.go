@@ -1,7 +1,12 @@
 package handler
 
 import (
+	"io/fs"
+	"os"
+	"sync"
+
 	"github.com/gookit/goutil/basefn"
+	"github.com/gookit/goutil/fsutil"
 	"github.com/gookit/slog"
 )
 
@@ -10,6 +15,11 @@ func JSONFileHandler(logfile string, fns ...ConfigFn) (*SyncCloseHandler, error)
 	return NewFileHandler(logfile, append(fns, WithUseJSON(true))...)
 }
 
+// MustJSONFile create new FileHandler with JSON formatter, will panic on error
+func MustJSONFile(logfile string, fns ...ConfigFn) *SyncCloseHandler {
+	return basefn.Must(JSONFileHandler(logfile, fns...))
+}
+
 // NewBuffFileHandler create file handler with buff size
 func NewBuffFileHandler(logfile string, buffSize int, fns ...ConfigFn) (*SyncCloseHandler, error) {
 	return NewFileHandler(logfile, append(fns, WithBuffSize(buffSize))...)
@@ -59,3 +69,65 @@ func NewSimpleFileHandler(filePath string, maxLv ...slog.Level) (*SyncCloseHandl
 	h := SyncCloserWithMaxLevel(file, basefn.FirstOr(maxLv, slog.InfoLevel))
 	return h, nil
 }
+
+//
+// ------------- reopenable file writer -------------
+//
+
+// FileWriter an append mode file writer, it can be reopened on the file is moved by external tools.
+// see slog.Reopener, Logger.ReopenAll()
+type FileWriter struct {
+	mu   sync.Mutex
+	path string
+	perm fs.FileMode
+	file *os.File
+}
+
+// OpenFileWriter open the file for append write, will create it on not exists.
+func OpenFileWriter(path string, perm fs.FileMode) (*FileWriter, error) {
+	w := &FileWriter{path: path, perm: perm}
+	if err := w.open(); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+func (w *FileWriter) open() (err error) {
+	w.file, err = fsutil.OpenAppendFile(w.path, w.perm)
+	return err
+}
+
+// Path of the file
+func (w *FileWriter) Path() string { return w.path }
+
+// Write data to the file
+func (w *FileWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.file.Write(p)
+}
+
+// Sync data to disk
+func (w *FileWriter) Sync() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.file.Sync()
+}
+
+// Close the file
+func (w *FileWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.file.Close()
+}
+
+// Reopen close the current file, then open the path again.
+func (w *FileWriter) Reopen() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if err := w.file.Close(); err != nil {
+		return err
+	}
+	return w.open()
+}
@@ -2,6 +2,8 @@ package handler_test
 
 import (
 	"io/ioutil"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/gookit/goutil/fsutil"
@@ -147,3 +149,51 @@ func TestNewSimpleFileHandler(t *testing.T) {
 	assert.Contains(t, str, "[INFO]")
 	assert.Contains(t, str, slog.WarnLevel.Name())
 }
+
+func TestFileWriter_Reopen(t *testing.T) {
+	logfile := filepath.Join(t.TempDir(), "app.log")
+	w, err := handler.OpenFileWriter(logfile, 0644)
+	assert.NoErr(t, err)
+	assert.Eq(t, logfile, w.Path())
+
+	_, err = w.Write([]byte("before rotate\n"))
+	assert.NoErr(t, err)
+	assert.NoErr(t, os.Rename(logfile, logfile+".1"))
+	assert.NoErr(t, w.Reopen())
+
+	_, err = w.Write([]byte("after rotate\n"))
+	assert.NoErr(t, err)
+	assert.NoErr(t, w.Sync())
+	assert.NoErr(t, w.Close())
+
+	assert.Eq(t, "before rotate\n", string(fsutil.MustReadFile(logfile+".1")))
+	assert.Eq(t, "after rotate\n", string(fsutil.MustReadFile(logfile)))
+}
+
+func TestSyncCloseHandler_Reopen(t *testing.T) {
+	logfile := filepath.Join(t.TempDir(), "app.log")
+	h, err := handler.NewBuffFileHandler(logfile, 1024, handler.WithReopenable(true))
+	assert.NoErr(t, err)
+
+	// forward by the wrapper handlers
+	var ro slog.Reopener = handler.Named(handler.WithLevels(h, slog.AllLevels), "file")
+
+	assert.NoErr(t, h.Handle(newLogRecord("before rotate")))
+	assert.NoErr(t, os.Rename(logfile, logfile+".1"))
+	// the buffered data is flushed to the old file
+	assert.NoErr(t, ro.Reopen())
+	assert.NoErr(t, h.Handle(newLogRecord("after rotate")))
+	assert.NoErr(t, h.Close())
+
+	str := string(fsutil.MustReadFile(logfile + ".1"))
+	assert.StrContains(t, str, "before rotate")
+	assert.NotContains(t, str, "after rotate")
+	assert.StrContains(t, string(fsutil.MustReadFile(logfile)), "after rotate")
+
+	// the output not support reopen
+	f, err := handler.QuickOpenFile(filepath.Join(t.TempDir(), "plain.log"))
+	assert.NoErr(t, err)
+	h = handler.NewSyncCloser(f, slog.AllLevels)
+	assert.NoErr(t, h.Reopen())
+	assert.NoErr(t, h.Close())
+}
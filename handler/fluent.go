@@ -0,0 +1,104 @@
+package handler
+
+import (
+	"strings"
+
+	"github.com/gookit/slog"
+)
+
+// DefaultFluentTag default tag template for the FluentHandler
+var DefaultFluentTag = "slog.{{channel}}"
+
+// FluentHandler send the log records to Fluentd or Fluent Bit by the forward protocol.
+//
+// Each record is sent as a forward "Message Mode" event: [tag, time, record], encoded by msgpack.
+// The record Fields and Attrs are added to the top level of the event record,
+// the Data, Extra and Tags are added by the keys: "data", "extra", "tags".
+//
+// The connection, reconnection and TLS are provided by the embedded NetHandler,
+// NOTICE: the formatter of the NetHandler is not used.
+type FluentHandler struct {
+	*NetHandler
+	// TagTemplate the template for build the event tag, allow vars: {{channel}}, {{level}}.
+	//
+	// default is DefaultFluentTag. eg: "app.{{channel}}.{{level}}" => "app.application.info"
+	TagTemplate string
+}
+
+// NewFluentHandler create new FluentHandler. network allow: tcp, tcp4, tcp6, unix
+//
+// Usage:
+//
+//	h := handler.NewFluentHandler("tcp", "127.0.0.1:24224", func(h *handler.FluentHandler) {
+//		h.TagTemplate = "myapp.{{level}}"
+//	})
+func NewFluentHandler(network, addr string, fns ...func(h *FluentHandler)) *FluentHandler {
+	h := &FluentHandler{
+		NetHandler:  NewNetHandler(network, addr),
+		TagTemplate: DefaultFluentTag,
+	}
+
+	for _, fn := range fns {
+		fn(h)
+	}
+	return h
+}
+
+// Handle a log record
+func (h *FluentHandler) Handle(r *slog.Record) error {
+	return h.send(h.Encode(r))
+}
+
+// Tag build the event tag for the record by TagTemplate
+func (h *FluentHandler) Tag(r *slog.Record) string {
+	tpl := h.TagTemplate
+	if tpl == "" {
+		tpl = DefaultFluentTag
+	}
+	if !strings.Contains(tpl, "{{") {
+		return tpl
+	}
+
+	return strings.NewReplacer(
+		"{{channel}}", r.Channel,
+		"{{level}}", r.Level.LowerName(),
+	).Replace(tpl)
+}
+
+// Encode the record to the forward protocol message: [tag, time, record]
+func (h *FluentHandler) Encode(r *slog.Record) []byte {
+	b := make([]byte, 0, 256)
+	b = mpAppendArrayHeader(b, 3)
+	b = mpAppendString(b, h.Tag(r))
+	b = mpAppendEventTime(b, r.Time)
+	return mpAppendMap(b, fluentRecord(r))
+}
+
+// build the event record
+func fluentRecord(r *slog.Record) map[string]any {
+	m := make(map[string]any, len(r.Fields)+len(r.Attrs)+6)
+	for k, v := range r.Fields {
+		m[k] = v
+	}
+	for _, attr := range r.Attrs {
+		m[attr.Key] = attr.Value()
+	}
+
+	m[slog.FieldKeyMessage] = r.Message
+	m[slog.FieldKeyLevel] = r.Level.Name()
+	m[slog.FieldKeyChannel] = r.Channel
+	if caller := r.CallerString(); caller != "" {
+		m[slog.FieldKeyCaller] = caller
+	}
+
+	if len(r.Data) > 0 {
+		m[slog.FieldKeyData] = r.Data
+	}
+	if len(r.Extra) > 0 {
+		m[slog.FieldKeyExtra] = r.Extra
+	}
+	if len(r.Tags) > 0 {
+		m[slog.FieldKeyTags] = r.Tags
+	}
+	return m
+}
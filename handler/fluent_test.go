@@ -0,0 +1,84 @@
+package handler_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/gookit/goutil/testutil/assert"
+	"github.com/gookit/slog"
+	"github.com/gookit/slog/handler"
+)
+
+func TestFluentHandler_Encode(t *testing.T) {
+	h := handler.NewFluentHandler("tcp", "127.0.0.1:24224", func(h *handler.FluentHandler) {
+		h.TagTemplate = "app.{{channel}}.{{level}}"
+	})
+
+	r := newLogRecord("fluent message")
+	r.Time = time.Unix(1700000000, 123)
+	r.Data = slog.M{"n": 1, "neg": -2, "ok": true}
+	r.Tags = []string{"audit"}
+	r.Attrs = []slog.Field{slog.String("user", "inhere")}
+	assert.Eq(t, "app.handler_test.info", h.Tag(r))
+
+	bs := h.Encode(r)
+	// [tag, time, record]
+	assert.Eq(t, byte(0x93), bs[0])
+	tag := "app.handler_test.info"
+	assert.Eq(t, byte(0xa0|len(tag)), bs[1])
+	assert.Eq(t, tag, string(bs[2:2+len(tag)]))
+
+	// EventTime ext
+	et := bs[2+len(tag):]
+	assert.Eq(t, []byte{0xd7, 0x00}, et[:2])
+	assert.Eq(t, uint32(1700000000), binary.BigEndian.Uint32(et[2:6]))
+	assert.Eq(t, uint32(123), binary.BigEndian.Uint32(et[6:10]))
+
+	assert.True(t, bytes.Contains(bs, []byte("\xa7message\xaefluent message")))
+	assert.True(t, bytes.Contains(bs, []byte("\xa4user\xa6inhere")))
+	assert.True(t, bytes.Contains(bs, []byte("\xa1n\x01")))
+	assert.True(t, bytes.Contains(bs, []byte("\xa3neg\xfe")))
+	assert.True(t, bytes.Contains(bs, []byte("\xa2ok\xc3")))
+	assert.True(t, bytes.Contains(bs, []byte("\xa4tags\x91\xa5audit")))
+
+	// default tag
+	h.TagTemplate = ""
+	assert.Eq(t, "slog.handler_test", h.Tag(r))
+}
+
+func TestFluentHandler_Handle(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoErr(t, err)
+	defer ln.Close()
+
+	received := make(chan []byte, 1)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		bs, _ := io.ReadAll(conn)
+		received <- bs
+	}()
+
+	h := handler.NewFluentHandler("tcp", ln.Addr().String())
+	l := slog.NewWithHandlers(h)
+	l.Info("fluent message1")
+	l.Warn("fluent message2")
+	assert.NoErr(t, l.Close())
+
+	select {
+	case bs := <-received:
+		assert.Eq(t, 2, bytes.Count(bs, []byte("\xb0slog.application")))
+		assert.True(t, bytes.Contains(bs, []byte("fluent message1")))
+		assert.True(t, bytes.Contains(bs, []byte("\xa5level\xa4WARN")))
+	case <-time.After(2 * time.Second):
+		t.Fatal("timeout for receive the events")
+	}
+}
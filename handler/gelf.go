@@ -0,0 +1,205 @@
+package handler
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"os"
+	"strings"
+
+	"github.com/gookit/goutil/strutil"
+	"github.com/gookit/slog"
+)
+
+// GELF chunking limits. see https://go2docs.graylog.org/current/getting_in_log_data/gelf.html
+const (
+	// GELFChunkSizeWAN the default chunk size for the UDP mode
+	GELFChunkSizeWAN = 1420
+	// GELFChunkSizeLAN the chunk size for the local network
+	GELFChunkSizeLAN = 8154
+
+	gelfChunkHeaderLen = 12
+	gelfMaxChunks      = 128
+)
+
+// ErrGELFTooManyChunks the message is too large for the UDP mode, exceeds the max 128 chunks.
+var ErrGELFTooManyChunks = errors.New("slog: gelf message exceeds the max 128 chunks")
+
+// GELFHandler send the log records to Graylog by the GELF 1.1 format.
+//
+//   - UDP mode: the message can be compressed by the Codec, and will be chunked on larger than the ChunkSize.
+//   - TCP mode: the message is terminated by a null byte, and not compressed.
+//
+// The level is mapped to the syslog severity. The record Fields, Data, Extra and Attrs are
+// promoted to the additional fields with prefix "_", the nested values are encoded to JSON string.
+//
+// The connection, reconnection and TLS are provided by the embedded NetHandler,
+// NOTICE: the formatter of the NetHandler is not used.
+type GELFHandler struct {
+	*NetHandler
+	// Host the "host" field of the message. default is os.Hostname()
+	Host string
+	// ChunkSize max datagram size for the UDP mode. default is GELFChunkSizeWAN
+	ChunkSize int
+	// Codec compress the message for the UDP mode. allow: gzip, deflate(zlib). default is nil, not compress.
+	Codec Codec
+}
+
+// NewGELFHandler create new GELFHandler. network allow: udp, tcp and the variants.
+//
+// Usage:
+//
+//	h := handler.NewGELFHandler("udp", "graylog.example.com:12201", func(h *handler.GELFHandler) {
+//		h.Codec, _ = handler.GetCodec(handler.CodecGzip)
+//	})
+func NewGELFHandler(network, addr string, fns ...func(h *GELFHandler)) *GELFHandler {
+	host, _ := os.Hostname()
+	h := &GELFHandler{
+		NetHandler: NewNetHandler(network, addr),
+		Host:       host,
+		ChunkSize:  GELFChunkSizeWAN,
+	}
+
+	for _, fn := range fns {
+		fn(h)
+	}
+	return h
+}
+
+// Handle a log record
+func (h *GELFHandler) Handle(r *slog.Record) error {
+	bts, err := h.Encode(r)
+	if err != nil {
+		return err
+	}
+
+	if !strings.HasPrefix(h.Network, "udp") {
+		return h.send(append(bts, 0))
+	}
+
+	if h.Codec != nil {
+		if bts, err = h.Codec.Encode(bts); err != nil {
+			return err
+		}
+	}
+
+	chunks, err := h.chunks(bts)
+	if err != nil {
+		return err
+	}
+	for _, chunk := range chunks {
+		if err = h.send(chunk); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Encode the record to the GELF 1.1 JSON message
+func (h *GELFHandler) Encode(r *slog.Record) ([]byte, error) {
+	msg := map[string]any{
+		"version":   "1.1",
+		"host":      h.Host,
+		"timestamp": float64(r.Time.UnixMilli()) / 1000,
+		"level":     SyslogSeverity(r.Level),
+	}
+
+	// the short message is the first line, full message contains all lines.
+	short, _, multiline := strings.Cut(r.Message, "\n")
+	msg["short_message"] = short
+	if multiline {
+		msg["full_message"] = r.Message
+	}
+
+	for k, v := range r.Fields {
+		msg[gelfFieldKey(k)] = gelfFieldValue(v)
+	}
+	for k, v := range r.Data {
+		msg[gelfFieldKey(k)] = gelfFieldValue(v)
+	}
+	for k, v := range r.Extra {
+		msg[gelfFieldKey(k)] = gelfFieldValue(v)
+	}
+	for _, attr := range r.Attrs {
+		msg[gelfFieldKey(attr.Key)] = gelfFieldValue(attr.Value())
+	}
+
+	msg["_"+slog.FieldKeyChannel] = r.Channel
+	msg["_level_name"] = r.Level.Name()
+	if caller := r.CallerString(); caller != "" {
+		msg["_"+slog.FieldKeyCaller] = caller
+	}
+	if len(r.Tags) > 0 {
+		msg["_"+slog.FieldKeyTags] = strings.Join(r.Tags, ",")
+	}
+	return json.Marshal(msg)
+}
+
+// split the message to chunks, returns the message itself on not larger than the ChunkSize.
+func (h *GELFHandler) chunks(bts []byte) ([][]byte, error) {
+	size := h.ChunkSize
+	if size <= gelfChunkHeaderLen {
+		size = GELFChunkSizeWAN
+	}
+	if len(bts) <= size {
+		return [][]byte{bts}, nil
+	}
+
+	dataLen := size - gelfChunkHeaderLen
+	count := (len(bts) + dataLen - 1) / dataLen
+	if count > gelfMaxChunks {
+		return nil, ErrGELFTooManyChunks
+	}
+
+	// the chunk header: magic bytes(2), message id(8), sequence number(1), sequence count(1)
+	msgID := make([]byte, 8)
+	if _, err := rand.Read(msgID); err != nil {
+		return nil, err
+	}
+
+	chunks := make([][]byte, 0, count)
+	for i := 0; i < count; i++ {
+		end := (i + 1) * dataLen
+		if end > len(bts) {
+			end = len(bts)
+		}
+
+		chunk := make([]byte, 0, gelfChunkHeaderLen+end-i*dataLen)
+		chunk = append(chunk, 0x1e, 0x0f)
+		chunk = append(chunk, msgID...)
+		chunk = append(chunk, byte(i), byte(count))
+		chunks = append(chunks, append(chunk, bts[i*dataLen:end]...))
+	}
+	return chunks, nil
+}
+
+// the additional field key, allow chars: \w . -, and "_id" is reserved.
+func gelfFieldKey(key string) string {
+	if key == "id" {
+		return "__id"
+	}
+
+	return "_" + strings.Map(func(r rune) rune {
+		if r == '.' || r == '-' || r == '_' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') {
+			return r
+		}
+		return '_'
+	}, key)
+}
+
+// the additional field value only allow string or number
+func gelfFieldValue(v any) any {
+	switch tv := v.(type) {
+	case string, int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
+		return tv
+	case nil:
+		return ""
+	case error:
+		return tv.Error()
+	case map[string]any, slog.M, []any, []string:
+		if bs, err := json.Marshal(tv); err == nil {
+			return string(bs)
+		}
+	}
+	return strutil.SafeString(v)
+}
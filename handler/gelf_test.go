@@ -0,0 +1,173 @@
+package handler_test
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gookit/goutil/testutil/assert"
+	"github.com/gookit/slog"
+	"github.com/gookit/slog/handler"
+)
+
+// start an udp server, returns the address and the received datagrams chan
+func newUDPServer(t *testing.T) (string, chan []byte) {
+	pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+	assert.NoErr(t, err)
+	t.Cleanup(func() { _ = pc.Close() })
+
+	packets := make(chan []byte, 200)
+	go func() {
+		buf := make([]byte, 65536)
+		for {
+			n, _, err := pc.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+			packets <- append([]byte(nil), buf[:n]...)
+		}
+	}()
+	return pc.LocalAddr().String(), packets
+}
+
+func recvPacket(t *testing.T, packets chan []byte) []byte {
+	select {
+	case bs := <-packets:
+		return bs
+	case <-time.After(2 * time.Second):
+		t.Fatal("timeout for receive the packet")
+	}
+	return nil
+}
+
+func TestGELFHandler_Encode(t *testing.T) {
+	h := handler.NewGELFHandler("udp", "127.0.0.1:12201", func(h *handler.GELFHandler) {
+		h.Host = "web-01"
+	})
+
+	r := newLogRecord("first line\nsecond line")
+	r.Level = slog.ErrorLevel
+	r.Time = time.UnixMilli(1700000000123)
+	r.Fields = slog.M{"id": 23, "user name": "inhere"}
+	r.Attrs = []slog.Field{slog.Any("ids", []int{1, 2})}
+
+	bs, err := h.Encode(r)
+	assert.NoErr(t, err)
+
+	msg := map[string]any{}
+	assert.NoErr(t, json.Unmarshal(bs, &msg))
+	assert.Eq(t, "1.1", msg["version"])
+	assert.Eq(t, "web-01", msg["host"])
+	assert.Eq(t, "first line", msg["short_message"])
+	assert.Eq(t, "first line\nsecond line", msg["full_message"])
+	assert.Eq(t, 1700000000.123, msg["timestamp"])
+	assert.Eq(t, float64(3), msg["level"])
+	assert.Eq(t, "ERROR", msg["_level_name"])
+	assert.Eq(t, "handler_test", msg["_channel"])
+	// promoted fields
+	assert.Eq(t, float64(23), msg["__id"])
+	assert.Eq(t, "inhere", msg["_user_name"])
+	assert.Eq(t, "inhere", msg["_name"])
+	assert.Eq(t, "hello", msg["_extra_key0"])
+	assert.Eq(t, `{"sub_key1":"val0"}`, msg["_sub"])
+	assert.Eq(t, "[1 2]", msg["_ids"])
+}
+
+func TestGELFHandler_udp(t *testing.T) {
+	addr, packets := newUDPServer(t)
+	h := handler.NewGELFHandler("udp", addr)
+	l := slog.NewWithHandlers(h)
+
+	l.Info("gelf message")
+	msg := map[string]any{}
+	assert.NoErr(t, json.Unmarshal(recvPacket(t, packets), &msg))
+	assert.Eq(t, "gelf message", msg["short_message"])
+	assert.Eq(t, float64(6), msg["level"])
+
+	// compress by gzip
+	h.Codec, _ = handler.GetCodec(handler.CodecGzip)
+	l.Warn("gzip message")
+	zr, err := gzip.NewReader(bytes.NewReader(recvPacket(t, packets)))
+	assert.NoErr(t, err)
+	bs, err := io.ReadAll(zr)
+	assert.NoErr(t, err)
+	assert.StrContains(t, string(bs), `"short_message":"gzip message"`)
+
+	// chunked message
+	h.Codec = nil
+	h.ChunkSize = 100
+	long := strings.Repeat("a", 500)
+	l.Info(long)
+
+	var payload []byte
+	var msgID []byte
+	for i := 0; ; i++ {
+		chunk := recvPacket(t, packets)
+		assert.Eq(t, []byte{0x1e, 0x0f}, chunk[:2])
+		if msgID == nil {
+			msgID = chunk[2:10]
+		}
+		assert.Eq(t, msgID, chunk[2:10])
+		assert.Eq(t, byte(i), chunk[10])
+		assert.Lt(t, len(chunk), 101)
+
+		payload = append(payload, chunk[12:]...)
+		if int(chunk[11]) == i+1 {
+			break
+		}
+	}
+	msg = map[string]any{}
+	assert.NoErr(t, json.Unmarshal(payload, &msg))
+	assert.Eq(t, long, msg["short_message"])
+
+	// too many chunks
+	h.ChunkSize = 20
+	err = h.Handle(newLogRecord(strings.Repeat("a", 2000)))
+	assert.ErrIs(t, err, handler.ErrGELFTooManyChunks)
+	assert.NoErr(t, l.Close())
+}
+
+func TestGELFHandler_tcp(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoErr(t, err)
+	defer ln.Close()
+
+	msgs := make(chan string, 2)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		br := bufio.NewReader(conn)
+		for {
+			line, err := br.ReadString(0)
+			if err != nil {
+				return
+			}
+			msgs <- strings.TrimSuffix(line, "\x00")
+		}
+	}()
+
+	h := handler.NewGELFHandler("tcp", ln.Addr().String())
+	l := slog.NewWithHandlers(h)
+	l.Info("tcp message1")
+	l.Info("tcp message2")
+
+	for _, want := range []string{"tcp message1", "tcp message2"} {
+		select {
+		case s := <-msgs:
+			assert.StrContains(t, s, `"short_message":"`+want+`"`)
+		case <-time.After(2 * time.Second):
+			t.Fatal("timeout for receive the message")
+		}
+	}
+	assert.NoErr(t, l.Close())
+}
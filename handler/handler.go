@@ -66,6 +66,60 @@ type LevelWithFormatter = slog.LevelWithFormatter
 // Deprecated: please use slog.LevelsWithFormatter instead.
 type LevelsWithFormatter = slog.LevelsWithFormatter
 
+// set max level for the LevelFormattable. will replace it with slog.LevelWithFormatter
+// and keep the formatter, on it not support set max level.
+//
+// NOTICE: the replace is not safe on concurrent logging, the built-in LevelFormattable
+// all support set max level.
+func setMaxLevel(lf *slog.LevelFormattable, maxLv slog.Level) {
+	if ls, ok := (*lf).(slog.MaxLevelSetter); ok {
+		ls.SetMaxLevel(maxLv)
+		return
+	}
+
+	nlf := slog.NewLvFormatter(maxLv)
+	nlf.SetFormatter((*lf).Formatter())
+	*lf = nlf
+}
+
+// NameTrait provide the handler name, use for diagnostics. see slog.HandlerName()
+type NameTrait struct {
+	name string
+}
+
+// Name get the handler name
+func (nt *NameTrait) Name() string { return nt.name }
+
+// SetName set the handler name
+func (nt *NameTrait) SetName(name string) { nt.name = name }
+
+// NamedHandler wrap a handler, give it a name for diagnostics.
+type NamedHandler struct {
+	slog.Handler
+	NameTrait
+}
+
+// Named wrap the handler with a name, the name will be used in error reporting,
+// Logger.RecentErrors() and the LevelServer. see slog.HandlerName()
+//
+// Usage:
+//
+//	l.AddHandler(handler.Named(httpHandler, "audit-http"))
+func Named(h slog.Handler, name string) *NamedHandler {
+	return &NamedHandler{Handler: h, NameTrait: NameTrait{name: name}}
+}
+
+// Reopen the wrapped handler, on it implements slog.Reopener
+func (h *NamedHandler) Reopen() error { return reopenHandler(h.Handler) }
+
+// reopen the handler on it implements slog.Reopener
+func reopenHandler(h slog.Handler) error {
+	if ro, ok := h.(slog.Reopener); ok {
+		return ro.Reopen()
+	}
+	return nil
+}
+
 // NopFlushClose no operation.
 //
 // provide empty Flush(), Close() methods, useful for tests.
@@ -115,3 +169,8 @@ func (lw *LockWrapper) LockEnabled() bool {
 func QuickOpenFile(filepath string) (*os.File, error) {
 	return fsutil.OpenFile(filepath, DefaultFileFlags, DefaultFilePerm)
 }
+
+// report an internal error. see slog.ReportInternal()
+func reportError(args ...any) {
+	slog.ReportInternal(slog.ErrorLevel, args...)
+}
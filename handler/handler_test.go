@@ -1,6 +1,7 @@
 package handler_test
 
 import (
+	"bytes"
 	"fmt"
 	"testing"
 
@@ -125,6 +126,40 @@ func TestLevelsWithFormatter(t *testing.T) {
 	assert.True(t, lsf.IsHandling(slog.DebugLevel))
 }
 
+func TestNamed(t *testing.T) {
+	th := newTestHandler()
+	th.errOnHandle = true
+
+	h := handler.Named(th, "audit")
+	assert.Eq(t, "audit", h.Name())
+	assert.Eq(t, "audit", slog.HandlerName(h))
+	assert.Eq(t, "*handler_test.testHandler", slog.HandlerName(th))
+
+	l := slog.NewWithHandlers(h)
+	l.Info("a message")
+	ers := l.RecentErrors()
+	assert.Len(t, ers, 1)
+	assert.Eq(t, "audit", ers[0].Handler)
+
+	// the route error contains the handler name
+	cr := handler.NewChannelRouter(h)
+	err := cr.Handle(newLogRecord("a message"))
+	assert.Err(t, err)
+	assert.Eq(t, "audit: handle error", err.Error())
+
+	// name by config
+	ch, err := handler.NewEmptyConfig(
+		handler.WithLogfile("testdata/named.log"),
+		handler.WithHandlerName("app-file"),
+	).CreateHandler()
+	assert.NoErr(t, err)
+	assert.Eq(t, "app-file", slog.HandlerName(ch))
+	assert.NoErr(t, ch.Close())
+
+	bh := handler.NewBuilder().WithOutput(new(bytes.Buffer)).WithHandlerName("app-buf").Build()
+	assert.Eq(t, "app-buf", slog.HandlerName(bh))
+}
+
 func TestNopFlushClose_Flush(t *testing.T) {
 	nfc := handler.NopFlushClose{}
 
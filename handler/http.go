@@ -0,0 +1,215 @@
+package handler
+
+import (
+	"bytes"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/gookit/slog"
+)
+
+// HTTPHandler send the log records to a remote HTTP endpoint. eg: webhook, log collector
+//
+// The formatted records are batched, and sent on the batch is full or on Flush().
+type HTTPHandler struct {
+	slog.LevelWithFormatter
+	mu sync.Mutex
+	// URL the remote endpoint
+	URL string
+	// Method the request method. default is POST
+	Method string
+	// Headers custom request headers
+	Headers map[string]string
+	// ContentType of the payload. default is "application/x-ndjson"
+	ContentType string
+	// Codec for compress the payload. default is NoneCodec
+	Codec Codec
+	// Client the http client. default timeout is 10s
+	//
+	// can set custom http.Transport for it. see WithHTTPProxy(), WithHTTPDialer()
+	Client *http.Client
+	// BatchSize max records in a request. default is 1, send each record directly.
+	BatchSize int
+	// Auth for the requests. eg: BearerAuth(), BasicAuth(), HMACAuth()
+	Auth HTTPAuth
+	// Throttle limit the upload bandwidth. default is nil, not limit.
+	Throttle *Throttle
+
+	buf   bytes.Buffer
+	count int
+}
+
+// NewHTTPHandler create new HTTPHandler
+//
+// Usage:
+//
+//	h := handler.NewHTTPHandler("https://logs.example.com/ingest", func(h *handler.HTTPHandler) {
+//		h.BatchSize = 100
+//		h.Codec, _ = handler.GetCodec(handler.CodecGzip)
+//	})
+func NewHTTPHandler(url string, fns ...func(h *HTTPHandler)) *HTTPHandler {
+	h := &HTTPHandler{
+		URL:         url,
+		Method:      http.MethodPost,
+		ContentType: "application/x-ndjson",
+		Codec:       NoneCodec{},
+		Client:      &http.Client{Timeout: 10 * time.Second},
+		BatchSize:   1,
+	}
+	h.Level = slog.DebugLevel
+	h.SetFormatter(slog.NewJSONFormatter())
+
+	for _, fn := range fns {
+		fn(h)
+	}
+	return h
+}
+
+// Handle a log record
+func (h *HTTPHandler) Handle(r *slog.Record) error {
+	bts, err := h.Format(r)
+	if err != nil {
+		return err
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.buf.Write(bts)
+	h.count++
+	if h.count >= h.BatchSize {
+		return h.send()
+	}
+	return nil
+}
+
+// Flush send the batched records
+func (h *HTTPHandler) Flush() error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.send()
+}
+
+// Close the handler, will send the batched records
+func (h *HTTPHandler) Close() error {
+	return h.Flush()
+}
+
+// send the buffered records. must hold the lock.
+func (h *HTTPHandler) send() error {
+	if h.count == 0 {
+		return nil
+	}
+
+	payload := h.buf.Bytes()
+	defer func() {
+		h.buf.Reset()
+		h.count = 0
+	}()
+
+	if h.Codec != nil {
+		var err error
+		if payload, err = h.Codec.Encode(payload); err != nil {
+			return err
+		}
+	}
+
+	var body io.Reader = bytes.NewReader(payload)
+	if h.Throttle != nil {
+		body = h.Throttle.Reader(body)
+	}
+
+	req, err := http.NewRequest(h.Method, h.URL, body)
+	if err != nil {
+		return err
+	}
+	req.ContentLength = int64(len(payload))
+
+	req.Header.Set("Content-Type", h.ContentType)
+	if h.Codec != nil && h.Codec.Name() != CodecNone {
+		req.Header.Set("Content-Encoding", h.Codec.Name())
+	}
+	for k, v := range h.Headers {
+		req.Header.Set(k, v)
+	}
+	if h.Auth != nil {
+		if err = h.Auth.Apply(req, payload); err != nil {
+			return err
+		}
+	}
+
+	resp, err := h.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("slog: http handler request failed, status: %s", resp.Status)
+	}
+	return nil
+}
+
+// WithHTTPProxy set the proxy for HTTPHandler. supported schemes: http, https, socks5
+//
+// Usage:
+//
+//	h := handler.NewHTTPHandler(endpoint, handler.WithHTTPProxy("socks5://127.0.0.1:1080"))
+func WithHTTPProxy(proxyURL string) func(h *HTTPHandler) {
+	return func(h *HTTPHandler) {
+		u, err := url.Parse(proxyURL)
+		if err != nil {
+			panic("slog: invalid proxy url: " + err.Error())
+		}
+		h.transport().Proxy = http.ProxyURL(u)
+	}
+}
+
+// WithHTTPDialer set the custom dialer for HTTPHandler. eg: connect by unix socket
+//
+// Usage:
+//
+//	h := handler.NewHTTPHandler("http://unix/ingest", handler.WithHTTPDialer(
+//		func(ctx context.Context, _, _ string) (net.Conn, error) {
+//			var d net.Dialer
+//			return d.DialContext(ctx, "unix", "/var/run/collector.sock")
+//		},
+//	))
+func WithHTTPDialer(dial DialFunc) func(h *HTTPHandler) {
+	return func(h *HTTPHandler) {
+		h.transport().DialContext = dial
+	}
+}
+
+// WithHTTPTLS set the TLS config for HTTPHandler. eg: use mTLS with rotated client certificate
+//
+// Usage:
+//
+//	cr, err := handler.NewCertReloader("client.crt", "client.key")
+//	h := handler.NewHTTPHandler(endpoint, handler.WithHTTPTLS(cr.TLSConfig(nil)))
+func WithHTTPTLS(cfg *tls.Config) func(h *HTTPHandler) {
+	return func(h *HTTPHandler) {
+		h.transport().TLSClientConfig = cfg
+	}
+}
+
+// get or init the *http.Transport of the client
+func (h *HTTPHandler) transport() *http.Transport {
+	if h.Client == nil {
+		h.Client = &http.Client{Timeout: 10 * time.Second}
+	}
+
+	if tr, ok := h.Client.Transport.(*http.Transport); ok {
+		return tr
+	}
+
+	tr := http.DefaultTransport.(*http.Transport).Clone()
+	h.Client.Transport = tr
+	return tr
+}
@@ -0,0 +1,83 @@
+package handler
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// HTTPAuth authenticate the requests of HTTPHandler
+type HTTPAuth interface {
+	// Apply auth to the request, body is the request payload(after encoded by codec).
+	Apply(req *http.Request, body []byte) error
+}
+
+// HTTPAuthFunc func for implements the HTTPAuth
+type HTTPAuthFunc func(req *http.Request, body []byte) error
+
+// Apply auth to the request
+func (fn HTTPAuthFunc) Apply(req *http.Request, body []byte) error {
+	return fn(req, body)
+}
+
+// BearerAuth set the header "Authorization: Bearer TOKEN"
+func BearerAuth(token string) HTTPAuth {
+	return HTTPAuthFunc(func(req *http.Request, _ []byte) error {
+		req.Header.Set("Authorization", "Bearer "+token)
+		return nil
+	})
+}
+
+// BasicAuth set the basic auth for request
+func BasicAuth(username, password string) HTTPAuth {
+	return HTTPAuthFunc(func(req *http.Request, _ []byte) error {
+		req.SetBasicAuth(username, password)
+		return nil
+	})
+}
+
+// TokenAuth fetch the token on each request, then set as bearer token.
+// useful for the short-lived tokens, the fetch func can cache and refresh the token.
+func TokenAuth(fetch func() (string, error)) HTTPAuth {
+	return HTTPAuthFunc(func(req *http.Request, _ []byte) error {
+		token, err := fetch()
+		if err != nil {
+			return err
+		}
+
+		req.Header.Set("Authorization", "Bearer "+token)
+		return nil
+	})
+}
+
+// HMACSignHeader default header name for the HMAC signature
+const HMACSignHeader = "X-Signature"
+
+// HMACAuth sign the request body by HMAC-SHA256, set the signature to header.
+//
+// The header value format: "t=TIMESTAMP,sig=HEX_SIGNATURE",
+// the signature is HMAC-SHA256(secret, TIMESTAMP + "." + BODY).
+// header default is HMACSignHeader
+func HMACAuth(secret []byte, header string) HTTPAuth {
+	if header == "" {
+		header = HMACSignHeader
+	}
+
+	return HTTPAuthFunc(func(req *http.Request, body []byte) error {
+		ts := strconv.FormatInt(time.Now().Unix(), 10)
+		req.Header.Set(header, "t="+ts+",sig="+HMACSign(secret, ts, body))
+		return nil
+	})
+}
+
+// HMACSign returns the hex signature of HMAC-SHA256(secret, timestamp + "." + body)
+func HMACSign(secret []byte, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte{'.'})
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
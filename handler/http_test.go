@@ -0,0 +1,136 @@
+package handler_test
+
+import (
+	"compress/gzip"
+	"compress/zlib"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/gookit/goutil/testutil/assert"
+	"github.com/gookit/slog"
+	"github.com/gookit/slog/handler"
+)
+
+type testCollector struct {
+	mu       sync.Mutex
+	bodies   []string
+	encoding []string
+	headers  []http.Header
+	status   int
+}
+
+func newTestCollector() (*testCollector, *httptest.Server) {
+	tc := &testCollector{status: http.StatusOK}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		var rd io.Reader = req.Body
+		switch req.Header.Get("Content-Encoding") {
+		case "gzip":
+			rd, _ = gzip.NewReader(req.Body)
+		case "deflate":
+			rd, _ = zlib.NewReader(req.Body)
+		}
+
+		bs, _ := io.ReadAll(rd)
+		tc.mu.Lock()
+		tc.bodies = append(tc.bodies, string(bs))
+		tc.encoding = append(tc.encoding, req.Header.Get("Content-Encoding"))
+		tc.headers = append(tc.headers, req.Header.Clone())
+		status := tc.status
+		tc.mu.Unlock()
+		w.WriteHeader(status)
+	}))
+	return tc, srv
+}
+
+func TestGetCodec(t *testing.T) {
+	c, ok := handler.GetCodec("")
+	assert.True(t, ok)
+	assert.Eq(t, handler.CodecNone, c.Name())
+
+	_, ok = handler.GetCodec("not-exists")
+	assert.False(t, ok)
+
+	handler.RegisterCodec(&handler.GzipCodec{Level: gzip.BestSpeed})
+	c, ok = handler.GetCodec(handler.CodecGzip)
+	assert.True(t, ok)
+	bs, err := c.Encode([]byte("hello"))
+	assert.NoErr(t, err)
+	assert.NotEmpty(t, bs)
+}
+
+func TestHTTPHandler_Handle(t *testing.T) {
+	tc, srv := newTestCollector()
+	defer srv.Close()
+
+	for _, name := range []string{handler.CodecNone, handler.CodecGzip, handler.CodecDeflate} {
+		codec, _ := handler.GetCodec(name)
+		h := handler.NewHTTPHandler(srv.URL, func(h *handler.HTTPHandler) {
+			h.BatchSize = 2
+			h.Codec = codec
+			h.Headers = map[string]string{"X-Api-Key": "abc"}
+		})
+
+		l := slog.NewWithHandlers(h)
+		l.Info("message1")
+		assert.Len(t, tc.bodies, 0)
+		l.Info("message2")
+		l.Info("message3")
+		assert.Len(t, tc.bodies, 1)
+		assert.NoErr(t, h.Close())
+		assert.Len(t, tc.bodies, 2)
+
+		assert.Eq(t, 2, strings.Count(tc.bodies[0], "\n"))
+		assert.StrContains(t, tc.bodies[0], `"message":"message2"`)
+		assert.StrContains(t, tc.bodies[1], `"message":"message3"`)
+		assert.Eq(t, "abc", tc.headers[0].Get("X-Api-Key"))
+		if name != handler.CodecNone {
+			assert.Eq(t, name, tc.encoding[0])
+		}
+
+		tc.bodies, tc.encoding, tc.headers = nil, nil, nil
+	}
+
+	// error status
+	tc.status = http.StatusBadRequest
+	h := handler.NewHTTPHandler(srv.URL)
+	assert.Err(t, h.Handle(newLogRecord("message")))
+}
+
+func TestHTTPHandler_Auth(t *testing.T) {
+	tc, srv := newTestCollector()
+	defer srv.Close()
+
+	h := handler.NewHTTPHandler(srv.URL)
+	tests := []struct {
+		auth   handler.HTTPAuth
+		header string
+		want   string
+	}{
+		{handler.BearerAuth("token1"), "Authorization", "Bearer token1"},
+		{handler.BasicAuth("user", "pass"), "Authorization", "Basic dXNlcjpwYXNz"},
+		{handler.TokenAuth(func() (string, error) { return "fresh", nil }), "Authorization", "Bearer fresh"},
+	}
+
+	for i, tt := range tests {
+		h.Auth = tt.auth
+		assert.NoErr(t, h.Handle(newLogRecord("auth message")))
+		assert.Eq(t, tt.want, tc.headers[i].Get(tt.header))
+	}
+
+	// hmac sign
+	h.Auth = handler.HMACAuth([]byte("secret"), "")
+	assert.NoErr(t, h.Handle(newLogRecord("auth message")))
+	sign := tc.headers[3].Get(handler.HMACSignHeader)
+	ts, sig, ok := strings.Cut(strings.TrimPrefix(sign, "t="), ",sig=")
+	assert.True(t, ok)
+	assert.Eq(t, handler.HMACSign([]byte("secret"), ts, []byte(tc.bodies[3])), sig)
+
+	// token fetch error
+	h.Auth = handler.TokenAuth(func() (string, error) { return "", errors.New("refresh failed") })
+	assert.ErrMsg(t, h.Handle(newLogRecord("auth message")), "refresh failed")
+}
@@ -0,0 +1,164 @@
+package handler
+
+import (
+	"io"
+	"os"
+
+	"github.com/gookit/slog"
+)
+
+// LevelRouter route the log records to different writers and handlers by the levels.
+//
+// The record is formatted only once for all matched writers, instead of one
+// handler for each output, and each formatting the record again.
+//
+// NOTICE: the routed handlers use their own formatter.
+type LevelRouter struct {
+	NameTrait
+	slog.FormatterWrapper
+	routes []levelRoute
+}
+
+type levelRoute struct {
+	levels   slog.Levels
+	writers  []io.Writer
+	handlers []slog.Handler
+}
+
+// NewLevelRouter create new LevelRouter
+//
+// Usage:
+//
+//	errFile, _ := handler.QuickOpenFile("error.log")
+//	appFile, _ := handler.QuickOpenFile("app.log")
+//
+//	h := handler.NewLevelRouter().
+//		Route(slog.Levels{slog.PanicLevel, slog.FatalLevel, slog.ErrorLevel}, os.Stderr, errFile).
+//		Route(slog.NormalLevels, appFile)
+//	logger.AddHandler(h)
+func NewLevelRouter(fns ...func(h *LevelRouter)) *LevelRouter {
+	h := &LevelRouter{}
+	for _, fn := range fns {
+		fn(h)
+	}
+	return h
+}
+
+// Route write the formatted records with the levels to the writers
+func (h *LevelRouter) Route(levels []slog.Level, ws ...io.Writer) *LevelRouter {
+	h.routes = append(h.routes, levelRoute{levels: levels, writers: ws})
+	return h
+}
+
+// RouteHandler pass the records with the levels to the handlers
+func (h *LevelRouter) RouteHandler(levels []slog.Level, hs ...slog.Handler) *LevelRouter {
+	h.routes = append(h.routes, levelRoute{levels: levels, handlers: hs})
+	return h
+}
+
+// IsHandling check the level is matched by any route
+func (h *LevelRouter) IsHandling(level slog.Level) bool {
+	for _, rt := range h.routes {
+		if rt.levels.Contains(level) {
+			return true
+		}
+	}
+	return false
+}
+
+// Handle a log record. will continue to write others on a route failed, and returns the first error.
+func (h *LevelRouter) Handle(r *slog.Record) (err error) {
+	var bts []byte
+	for _, rt := range h.routes {
+		if !rt.levels.Contains(r.Level) {
+			continue
+		}
+
+		if len(rt.writers) > 0 && bts == nil {
+			if bts, err = h.Format(r); err != nil {
+				return err
+			}
+		}
+
+		for _, w := range rt.writers {
+			if _, werr := w.Write(bts); werr != nil && err == nil {
+				err = werr
+			}
+		}
+
+		for _, rh := range rt.handlers {
+			if rh.IsHandling(r.Level) {
+				if herr := rh.Handle(r); herr != nil && err == nil {
+					err = herr
+				}
+			}
+		}
+	}
+	return err
+}
+
+// Flush the writers and handlers
+func (h *LevelRouter) Flush() (err error) {
+	h.each(func(w io.Writer) {
+		var ferr error
+		switch fw := w.(type) {
+		case interface{ Flush() error }:
+			ferr = fw.Flush()
+		case interface{ Sync() error }:
+			// ignore sync error of the stdout, stderr. eg: sync /dev/stdout: invalid argument
+			if w != os.Stdout && w != os.Stderr {
+				ferr = fw.Sync()
+			}
+		}
+
+		if ferr != nil && err == nil {
+			err = ferr
+		}
+	}, func(rh slog.Handler) {
+		if ferr := rh.Flush(); ferr != nil && err == nil {
+			err = ferr
+		}
+	})
+	return err
+}
+
+// Close the writers and handlers. the os.Stdout, os.Stderr will not be closed.
+func (h *LevelRouter) Close() (err error) {
+	h.each(func(w io.Writer) {
+		if w == os.Stdout || w == os.Stderr {
+			return
+		}
+
+		if wc, ok := w.(io.Closer); ok {
+			if cerr := wc.Close(); cerr != nil && err == nil {
+				err = cerr
+			}
+		}
+	}, func(rh slog.Handler) {
+		if cerr := rh.Close(); cerr != nil && err == nil {
+			err = cerr
+		}
+	})
+	return err
+}
+
+// call each unique writer and handler, one writer can be used by multi routes.
+func (h *LevelRouter) each(wFn func(w io.Writer), hFn func(rh slog.Handler)) {
+	seenW := make(map[io.Writer]bool)
+	seenH := make(map[slog.Handler]bool)
+
+	for _, rt := range h.routes {
+		for _, w := range rt.writers {
+			if !seenW[w] {
+				seenW[w] = true
+				wFn(w)
+			}
+		}
+		for _, rh := range rt.handlers {
+			if !seenH[rh] {
+				seenH[rh] = true
+				hFn(rh)
+			}
+		}
+	}
+}
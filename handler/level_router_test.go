@@ -0,0 +1,50 @@
+package handler_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/gookit/goutil/testutil/assert"
+	"github.com/gookit/slog"
+	"github.com/gookit/slog/handler"
+)
+
+func TestLevelRouter(t *testing.T) {
+	errBuf := new(bytes.Buffer)
+	allBuf := new(bytes.Buffer)
+	appBuf := new(bytes.Buffer)
+	hdBuf := new(bytes.Buffer)
+
+	var formatted int
+	h := handler.NewLevelRouter(func(h *handler.LevelRouter) {
+		h.SetFormatter(slog.FormatterFunc(func(r *slog.Record) ([]byte, error) {
+			formatted++
+			return []byte(r.Level.Name() + " " + r.Message + "\n"), nil
+		}))
+	}).
+		Route(slog.Levels{slog.PanicLevel, slog.FatalLevel, slog.ErrorLevel}, errBuf, allBuf).
+		Route(slog.NormalLevels, appBuf, allBuf).
+		RouteHandler(slog.DangerLevels, handler.NewSimple(hdBuf, slog.WarnLevel))
+
+	assert.True(t, h.IsHandling(slog.WarnLevel))
+	assert.False(t, h.IsHandling(slog.Level(900)))
+
+	l := slog.NewWithHandlers(h)
+	l.Info("info message")
+	l.Warn("warn message")
+	l.Error("error message")
+
+	// format once for each record
+	assert.Eq(t, 2, formatted)
+	assert.Eq(t, "ERROR error message\n", errBuf.String())
+	assert.Eq(t, "INFO info message\n", appBuf.String())
+	assert.Eq(t, "INFO info message\nERROR error message\n", allBuf.String())
+
+	s := hdBuf.String()
+	assert.StrContains(t, s, "warn message")
+	assert.StrContains(t, s, "error message")
+	assert.NotContains(t, s, "info message")
+
+	assert.NoErr(t, h.Flush())
+	assert.NoErr(t, h.Close())
+}
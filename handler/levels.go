@@ -0,0 +1,42 @@
+package handler
+
+import "github.com/gookit/slog"
+
+// LevelsHandler wrap a handler, override the handling levels of it.
+//
+// Useful for reuse one constructed handler at different levels in different loggers,
+// and the levels can be adjusted at runtime by SetMaxLevel(), SetLimitLevels().
+//
+// NOTICE: the wrapped handler is shared, so it will be flushed and closed by each logger.
+type LevelsHandler struct {
+	slog.Handler
+	slog.LevelHandling
+}
+
+// WithLevels wrap the handler, only handle the records with the levels.
+//
+// Usage:
+//
+//	fh := handler.MustFileHandler("app.log")
+//	appLogger := slog.NewWithHandlers(handler.WithLevels(fh, slog.NormalLevels))
+//	dbLogger := slog.NewWithHandlers(handler.WithLevels(fh, slog.DangerLevels))
+func WithLevels(h slog.Handler, levels []slog.Level) *LevelsHandler {
+	lh := &LevelsHandler{Handler: h}
+	lh.SetLimitLevels(levels)
+	return lh
+}
+
+// WithMaxLevel wrap the handler, only handle the records with level <= maxLevel
+func WithMaxLevel(h slog.Handler, maxLevel slog.Level) *LevelsHandler {
+	lh := &LevelsHandler{Handler: h}
+	lh.SetMaxLevel(maxLevel)
+	return lh
+}
+
+// Reopen the wrapped handler, on it implements slog.Reopener
+func (h *LevelsHandler) Reopen() error { return reopenHandler(h.Handler) }
+
+// IsHandling check the level is handled by the override levels
+func (h *LevelsHandler) IsHandling(level slog.Level) bool {
+	return h.LevelHandling.IsHandling(level)
+}
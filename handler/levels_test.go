@@ -0,0 +1,33 @@
+package handler_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/gookit/goutil/testutil/assert"
+	"github.com/gookit/slog"
+	"github.com/gookit/slog/handler"
+)
+
+func TestWithLevels(t *testing.T) {
+	buf := new(bytes.Buffer)
+	h := handler.NewIOWriter(buf, slog.AllLevels)
+	h.SetFormatter(slog.NewTextFormatter("{{level}} {{message}}\n"))
+
+	errLogger := slog.NewWithHandlers(handler.WithLevels(h, slog.DangerLevels))
+	mh := handler.WithMaxLevel(h, slog.InfoLevel)
+	infoLogger := slog.NewWithHandlers(mh)
+
+	errLogger.Info("info to err logger")
+	errLogger.Warn("warn to err logger")
+	infoLogger.Debug("debug to info logger")
+	infoLogger.Info("info to info logger")
+	assert.Eq(t, "WARN warn to err logger\nINFO info to info logger\n", buf.String())
+
+	// adjust at runtime
+	buf.Reset()
+	mh.SetMaxLevel(slog.DebugLevel)
+	infoLogger.Debug("debug to info logger")
+	assert.Eq(t, "DEBUG debug to info logger\n", buf.String())
+	assert.NoErr(t, mh.Flush())
+}
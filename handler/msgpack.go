@@ -0,0 +1,189 @@
+package handler
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/gookit/slog"
+)
+
+// a minimal msgpack encoder for the FluentHandler.
+// see https://github.com/msgpack/msgpack/blob/master/spec.md
+
+func mpAppendNil(b []byte) []byte { return append(b, 0xc0) }
+
+func mpAppendBool(b []byte, v bool) []byte {
+	if v {
+		return append(b, 0xc3)
+	}
+	return append(b, 0xc2)
+}
+
+func mpAppendInt(b []byte, v int64) []byte {
+	switch {
+	case v >= 0:
+		return mpAppendUint(b, uint64(v))
+	case v >= -32:
+		return append(b, byte(v))
+	case v >= math.MinInt8:
+		return append(b, 0xd0, byte(v))
+	case v >= math.MinInt16:
+		return binary.BigEndian.AppendUint16(append(b, 0xd1), uint16(v))
+	case v >= math.MinInt32:
+		return binary.BigEndian.AppendUint32(append(b, 0xd2), uint32(v))
+	}
+	return binary.BigEndian.AppendUint64(append(b, 0xd3), uint64(v))
+}
+
+func mpAppendUint(b []byte, v uint64) []byte {
+	switch {
+	case v <= 0x7f:
+		return append(b, byte(v))
+	case v <= math.MaxUint8:
+		return append(b, 0xcc, byte(v))
+	case v <= math.MaxUint16:
+		return binary.BigEndian.AppendUint16(append(b, 0xcd), uint16(v))
+	case v <= math.MaxUint32:
+		return binary.BigEndian.AppendUint32(append(b, 0xce), uint32(v))
+	}
+	return binary.BigEndian.AppendUint64(append(b, 0xcf), v)
+}
+
+func mpAppendFloat(b []byte, v float64) []byte {
+	return binary.BigEndian.AppendUint64(append(b, 0xcb), math.Float64bits(v))
+}
+
+func mpAppendString(b []byte, s string) []byte {
+	n := len(s)
+	switch {
+	case n <= 31:
+		b = append(b, 0xa0|byte(n))
+	case n <= math.MaxUint8:
+		b = append(b, 0xd9, byte(n))
+	case n <= math.MaxUint16:
+		b = binary.BigEndian.AppendUint16(append(b, 0xda), uint16(n))
+	default:
+		b = binary.BigEndian.AppendUint32(append(b, 0xdb), uint32(n))
+	}
+	return append(b, s...)
+}
+
+func mpAppendBytes(b []byte, bs []byte) []byte {
+	n := len(bs)
+	switch {
+	case n <= math.MaxUint8:
+		b = append(b, 0xc4, byte(n))
+	case n <= math.MaxUint16:
+		b = binary.BigEndian.AppendUint16(append(b, 0xc5), uint16(n))
+	default:
+		b = binary.BigEndian.AppendUint32(append(b, 0xc6), uint32(n))
+	}
+	return append(b, bs...)
+}
+
+func mpAppendArrayHeader(b []byte, n int) []byte {
+	switch {
+	case n <= 15:
+		return append(b, 0x90|byte(n))
+	case n <= math.MaxUint16:
+		return binary.BigEndian.AppendUint16(append(b, 0xdc), uint16(n))
+	}
+	return binary.BigEndian.AppendUint32(append(b, 0xdd), uint32(n))
+}
+
+func mpAppendMapHeader(b []byte, n int) []byte {
+	switch {
+	case n <= 15:
+		return append(b, 0x80|byte(n))
+	case n <= math.MaxUint16:
+		return binary.BigEndian.AppendUint16(append(b, 0xde), uint16(n))
+	}
+	return binary.BigEndian.AppendUint32(append(b, 0xdf), uint32(n))
+}
+
+// append the fluentd EventTime. it is the ext type 0, contains seconds and nanoseconds.
+func mpAppendEventTime(b []byte, t time.Time) []byte {
+	b = append(b, 0xd7, 0x00)
+	b = binary.BigEndian.AppendUint32(b, uint32(t.Unix()))
+	return binary.BigEndian.AppendUint32(b, uint32(t.Nanosecond()))
+}
+
+func mpAppendMap(b []byte, m map[string]any) []byte {
+	b = mpAppendMapHeader(b, len(m))
+	for k, v := range m {
+		b = mpAppendString(b, k)
+		b = mpAppendAny(b, v)
+	}
+	return b
+}
+
+// append any value. the unsupported types will be encoded as string by fmt.Sprint()
+func mpAppendAny(b []byte, v any) []byte {
+	switch tv := v.(type) {
+	case nil:
+		return mpAppendNil(b)
+	case bool:
+		return mpAppendBool(b, tv)
+	case string:
+		return mpAppendString(b, tv)
+	case []byte:
+		return mpAppendBytes(b, tv)
+	case int:
+		return mpAppendInt(b, int64(tv))
+	case int8:
+		return mpAppendInt(b, int64(tv))
+	case int16:
+		return mpAppendInt(b, int64(tv))
+	case int32:
+		return mpAppendInt(b, int64(tv))
+	case int64:
+		return mpAppendInt(b, tv)
+	case uint:
+		return mpAppendUint(b, uint64(tv))
+	case uint8:
+		return mpAppendUint(b, uint64(tv))
+	case uint16:
+		return mpAppendUint(b, uint64(tv))
+	case uint32:
+		return mpAppendUint(b, uint64(tv))
+	case uint64:
+		return mpAppendUint(b, tv)
+	case float32:
+		return mpAppendFloat(b, float64(tv))
+	case float64:
+		return mpAppendFloat(b, tv)
+	case time.Duration:
+		return mpAppendString(b, tv.String())
+	case time.Time:
+		return mpAppendString(b, tv.Format(time.RFC3339Nano))
+	case error:
+		return mpAppendString(b, tv.Error())
+	case slog.M:
+		return mpAppendMap(b, tv)
+	case map[string]any:
+		return mpAppendMap(b, tv)
+	case map[string]string:
+		b = mpAppendMapHeader(b, len(tv))
+		for k, s := range tv {
+			b = mpAppendString(mpAppendString(b, k), s)
+		}
+		return b
+	case []any:
+		b = mpAppendArrayHeader(b, len(tv))
+		for _, item := range tv {
+			b = mpAppendAny(b, item)
+		}
+		return b
+	case []string:
+		b = mpAppendArrayHeader(b, len(tv))
+		for _, s := range tv {
+			b = mpAppendString(b, s)
+		}
+		return b
+	case fmt.Stringer:
+		return mpAppendString(b, tv.String())
+	}
+	return mpAppendString(b, fmt.Sprint(v))
+}
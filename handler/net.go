@@ -0,0 +1,289 @@
+package handler
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/gookit/slog"
+)
+
+// DialFunc custom dialer for the remote handlers. eg: SOCKS5 proxy, unix socket
+//
+// eg: use golang.org/x/net/proxy for SOCKS5
+//
+//	dialer, _ := proxy.SOCKS5("tcp", "127.0.0.1:1080", nil, proxy.Direct)
+//	h.Dial = dialer.(proxy.ContextDialer).DialContext
+type DialFunc func(ctx context.Context, network, addr string) (net.Conn, error)
+
+// NetHandler send the log records to a remote address by tcp, udp or unix socket.
+//
+// The connection is created on first write, and will reconnect on write failed.
+//
+// If ResolveInterval > 0, the hostname of Addr will be resolved periodically, and the
+// handler rotates among the A/AAAA records on reconnect. When the current address is
+// removed from the DNS records, will reconnect to a new one. So log shipping follows
+// the DNS-based failover of collectors.
+type NetHandler struct {
+	slog.LevelWithFormatter
+	mu sync.Mutex
+	// Network name. eg: tcp, tcp4, tcp6, udp, unix
+	Network string
+	// Addr the remote address. eg: "127.0.0.1:5140", "/var/run/log.sock"
+	Addr string
+	// Dial custom dialer. default use net.Dialer with DialTimeout
+	Dial DialFunc
+	// DialTimeout default is 5s
+	DialTimeout time.Duration
+	// WriteTimeout default is 5s, 0 is not limit.
+	WriteTimeout time.Duration
+	// ResolveInterval re-resolve the hostname of Addr on interval. 0 is disable.
+	//
+	// tips: network "tcp4", "udp4" only use the IPv4 records, "tcp6", "udp6" only use IPv6 records.
+	ResolveInterval time.Duration
+	// LookupHost custom the resolver. default is net.DefaultResolver.LookupHost
+	LookupHost func(ctx context.Context, host string) ([]string, error)
+	// Throttle limit the send bandwidth. default is nil, not limit.
+	Throttle *Throttle
+	// TLSConfig for connect to the remote by TLS. default is nil, not use TLS.
+	//
+	// tips: use CertReloader.TLSConfig() for reload the client certificate on rotated.
+	TLSConfig *tls.Config
+	// Codec compress the payload of each record. default is nil, not compress.
+	//
+	// tips: each record is encoded separately, on the stream network(tcp, unix), the receiver
+	// should read the concatenated streams. eg: gzip.Reader read the multiple gzip members.
+	Codec Codec
+
+	conn net.Conn
+	// the address of current conn
+	connAddr string
+	// the resolved addresses, and next index for dial
+	addrs    []string
+	next     int
+	resolved time.Time
+}
+
+// NewNetHandler create new NetHandler
+//
+// Usage:
+//
+//	h := handler.NewNetHandler("tcp", "logs.example.com:5140")
+func NewNetHandler(network, addr string, fns ...func(h *NetHandler)) *NetHandler {
+	h := &NetHandler{
+		Network:      network,
+		Addr:         addr,
+		DialTimeout:  5 * time.Second,
+		WriteTimeout: 5 * time.Second,
+	}
+	h.Level = slog.DebugLevel
+	h.SetFormatter(slog.NewJSONFormatter())
+
+	for _, fn := range fns {
+		fn(h)
+	}
+	return h
+}
+
+// Handle a log record
+func (h *NetHandler) Handle(r *slog.Record) error {
+	bts, err := h.Format(r)
+	if err != nil {
+		return err
+	}
+
+	if h.Codec != nil {
+		if bts, err = h.Codec.Encode(bts); err != nil {
+			return err
+		}
+	}
+	return h.send(bts)
+}
+
+// send the data, will retry once with a new connection on write failed
+func (h *NetHandler) send(bts []byte) (err error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.Throttle != nil {
+		h.Throttle.Wait(len(bts))
+	}
+
+	if err = h.write(bts); err != nil {
+		slog.ReportInternal(slog.NoticeLevel, "slog: net handler reconnect on write error:", err)
+		h.closeConn()
+		err = h.write(bts)
+	}
+	return err
+}
+
+func (h *NetHandler) write(bts []byte) (err error) {
+	if h.ResolveInterval > 0 && time.Since(h.resolved) >= h.ResolveInterval {
+		h.resolve()
+		// current address has been removed from DNS records
+		if h.conn != nil && len(h.addrs) > 0 && !containsStr(h.addrs, h.connAddr) {
+			h.closeConn()
+		}
+	}
+
+	if h.conn == nil {
+		if h.conn, err = h.dial(); err != nil {
+			return err
+		}
+	}
+
+	if h.WriteTimeout > 0 {
+		_ = h.conn.SetWriteDeadline(time.Now().Add(h.WriteTimeout))
+	}
+	_, err = h.conn.Write(bts)
+	return err
+}
+
+// resolve the hostname of Addr, keep the old addresses on failed.
+func (h *NetHandler) resolve() {
+	h.resolved = time.Now()
+	host, port, err := net.SplitHostPort(h.Addr)
+	if err != nil || net.ParseIP(host) != nil {
+		return // unix socket or IP address
+	}
+
+	ctx := context.Background()
+	if h.DialTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, h.DialTimeout)
+		defer cancel()
+	}
+
+	lookup := h.LookupHost
+	if lookup == nil {
+		lookup = net.DefaultResolver.LookupHost
+	}
+
+	ips, err := lookup(ctx, host)
+	if err != nil {
+		slog.ReportInternal(slog.WarnLevel, "slog: net handler resolve host error:", err)
+		return
+	}
+
+	addrs := make([]string, 0, len(ips))
+	for _, ip := range ips {
+		if matchIPNetwork(h.Network, ip) {
+			addrs = append(addrs, net.JoinHostPort(ip, port))
+		}
+	}
+
+	if len(addrs) > 0 {
+		h.addrs = addrs
+	}
+}
+
+// check the IP address family is matched the network. eg: tcp4 only IPv4
+func matchIPNetwork(network, ip string) bool {
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return false
+	}
+	if network == "" {
+		return true
+	}
+
+	switch network[len(network)-1] {
+	case '4':
+		return parsed.To4() != nil
+	case '6':
+		return parsed.To4() == nil
+	}
+	return true
+}
+
+func containsStr(ss []string, s string) bool {
+	for _, v := range ss {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+func (h *NetHandler) dial() (conn net.Conn, err error) {
+	ctx := context.Background()
+	if h.DialTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, h.DialTimeout)
+		defer cancel()
+	}
+
+	if h.ResolveInterval <= 0 || len(h.addrs) == 0 {
+		conn, err = h.dialAddr(ctx, h.Addr)
+	} else {
+		// rotate among the resolved addresses, try next one on failed.
+		for i := 0; i < len(h.addrs); i++ {
+			addr := h.addrs[h.next%len(h.addrs)]
+			h.next++
+
+			if conn, err = h.dialAddr(ctx, addr); err == nil {
+				h.connAddr = addr
+				break
+			}
+		}
+
+		// all addresses are failed, re-resolve on next dial
+		if err != nil {
+			h.resolved = time.Time{}
+		}
+	}
+
+	if err != nil || h.TLSConfig == nil {
+		return conn, err
+	}
+	return h.tlsClient(ctx, conn)
+}
+
+func (h *NetHandler) dialAddr(ctx context.Context, addr string) (net.Conn, error) {
+	if h.Dial != nil {
+		return h.Dial(ctx, h.Network, addr)
+	}
+
+	var d net.Dialer
+	return d.DialContext(ctx, h.Network, addr)
+}
+
+func (h *NetHandler) tlsClient(ctx context.Context, conn net.Conn) (net.Conn, error) {
+	cfg := h.TLSConfig
+	if cfg.ServerName == "" {
+		if host, _, err := net.SplitHostPort(h.Addr); err == nil {
+			cfg = cfg.Clone()
+			cfg.ServerName = host
+		}
+	}
+
+	tc := tls.Client(conn, cfg)
+	if err := tc.HandshakeContext(ctx); err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+	return tc, nil
+}
+
+func (h *NetHandler) closeConn() {
+	if h.conn != nil {
+		_ = h.conn.Close()
+		h.conn = nil
+	}
+}
+
+// Flush logs. the records are written directly, do nothing.
+func (h *NetHandler) Flush() error {
+	return nil
+}
+
+// Close the connection
+func (h *NetHandler) Close() error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.closeConn()
+	return nil
+}
@@ -0,0 +1,205 @@
+package handler_test
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gookit/goutil/testutil/assert"
+	"github.com/gookit/slog"
+	"github.com/gookit/slog/handler"
+)
+
+// start a tcp server, returns the address and the received lines chan
+func newTCPServer(t *testing.T) (string, chan string) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoErr(t, err)
+	t.Cleanup(func() { _ = ln.Close() })
+
+	lines := make(chan string, 10)
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+
+			go func() {
+				defer conn.Close()
+				sc := bufio.NewScanner(conn)
+				for sc.Scan() {
+					lines <- sc.Text()
+				}
+			}()
+		}
+	}()
+	return ln.Addr().String(), lines
+}
+
+func TestNetHandler_Handle(t *testing.T) {
+	addr, lines := newTCPServer(t)
+
+	var dialed int32
+	h := handler.NewNetHandler("tcp", addr, func(h *handler.NetHandler) {
+		h.Dial = func(ctx context.Context, network, addr string) (net.Conn, error) {
+			atomic.AddInt32(&dialed, 1)
+			var d net.Dialer
+			return d.DialContext(ctx, network, addr)
+		}
+	})
+
+	l := slog.NewWithHandlers(h)
+	l.Info("net message1")
+	l.Warn("net message2")
+
+	assert.StrContains(t, <-lines, `"message":"net message1"`)
+	assert.StrContains(t, <-lines, `"message":"net message2"`)
+	assert.Eq(t, int32(1), atomic.LoadInt32(&dialed))
+	assert.NoErr(t, h.Flush())
+	assert.NoErr(t, h.Close())
+
+	// dial error
+	h = handler.NewNetHandler("tcp", "127.0.0.1:1")
+	assert.Err(t, h.Handle(newLogRecord("message")))
+}
+
+func TestNetHandler_Codec(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoErr(t, err)
+	defer ln.Close()
+
+	received := make(chan []byte, 1)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		bs, _ := io.ReadAll(conn)
+		received <- bs
+	}()
+
+	codec, _ := handler.GetCodec(handler.CodecGzip)
+	h := handler.NewNetHandler("tcp", ln.Addr().String(), func(h *handler.NetHandler) {
+		h.Codec = codec
+	})
+
+	l := slog.NewWithHandlers(h)
+	l.Info("net message1")
+	l.Warn("net message2")
+	assert.NoErr(t, h.Close())
+
+	// read the multiple gzip members
+	gr, err := gzip.NewReader(bytes.NewReader(<-received))
+	assert.NoErr(t, err)
+	bs, err := io.ReadAll(gr)
+	assert.NoErr(t, err)
+
+	str := string(bs)
+	assert.Eq(t, 2, strings.Count(str, "\n"))
+	assert.StrContains(t, str, `"message":"net message1"`)
+	assert.StrContains(t, str, `"message":"net message2"`)
+}
+
+func TestNetHandler_ResolveInterval(t *testing.T) {
+	srvAddr, lines := newTCPServer(t)
+
+	var mu sync.Mutex
+	var dialed []string
+	ips := []string{"10.0.0.1", "10.0.0.2", "2001:db8::1"}
+
+	h := handler.NewNetHandler("tcp", "logs.example.com:5140", func(h *handler.NetHandler) {
+		h.ResolveInterval = 5 * time.Millisecond
+		h.LookupHost = func(_ context.Context, host string) ([]string, error) {
+			assert.Eq(t, "logs.example.com", host)
+			mu.Lock()
+			defer mu.Unlock()
+			return ips, nil
+		}
+		h.Dial = func(ctx context.Context, network, addr string) (net.Conn, error) {
+			dialed = append(dialed, addr)
+			if addr == "10.0.0.1:5140" {
+				return nil, errors.New("connect refused")
+			}
+
+			var d net.Dialer
+			return d.DialContext(ctx, network, srvAddr)
+		}
+	})
+
+	// rotate to the next address on dial failed
+	assert.NoErr(t, h.Handle(newLogRecord("resolve message1")))
+	assert.StrContains(t, <-lines, "resolve message1")
+	assert.Eq(t, []string{"10.0.0.1:5140", "10.0.0.2:5140"}, dialed)
+
+	// current address is removed from DNS records, reconnect to IPv6 address
+	mu.Lock()
+	ips = []string{"2001:db8::1"}
+	mu.Unlock()
+	time.Sleep(10 * time.Millisecond)
+
+	assert.NoErr(t, h.Handle(newLogRecord("resolve message2")))
+	assert.StrContains(t, <-lines, "resolve message2")
+	assert.Eq(t, "[2001:db8::1]:5140", dialed[len(dialed)-1])
+	assert.NoErr(t, h.Close())
+
+	// only use the IPv4 records on tcp4
+	dialed = dialed[:0]
+	h.Network = "tcp4"
+	mu.Lock()
+	ips = []string{"2001:db8::1", "10.0.0.2"}
+	mu.Unlock()
+	time.Sleep(10 * time.Millisecond)
+
+	assert.NoErr(t, h.Handle(newLogRecord("resolve message3")))
+	assert.StrContains(t, <-lines, "resolve message3")
+	assert.Eq(t, []string{"10.0.0.2:5140"}, dialed)
+	assert.NoErr(t, h.Close())
+}
+
+func TestWithHTTPDialer(t *testing.T) {
+	tc, srv := newTestCollector()
+	defer srv.Close()
+
+	var dialed int32
+	h := handler.NewHTTPHandler("http://logs.example.com/ingest", handler.WithHTTPDialer(
+		func(ctx context.Context, network, _ string) (net.Conn, error) {
+			atomic.AddInt32(&dialed, 1)
+			var d net.Dialer
+			return d.DialContext(ctx, network, strings.TrimPrefix(srv.URL, "http://"))
+		},
+	))
+
+	assert.NoErr(t, h.Handle(newLogRecord("dialer message")))
+	assert.Eq(t, int32(1), atomic.LoadInt32(&dialed))
+	assert.StrContains(t, tc.bodies[0], "dialer message")
+}
+
+func TestWithHTTPProxy(t *testing.T) {
+	var proxied int32
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		atomic.AddInt32(&proxied, 1)
+		assert.Eq(t, "logs.example.com", req.Host)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer proxy.Close()
+
+	h := handler.NewHTTPHandler("http://logs.example.com/ingest", handler.WithHTTPProxy(proxy.URL))
+	assert.NoErr(t, h.Handle(newLogRecord("proxy message")))
+	assert.Eq(t, int32(1), atomic.LoadInt32(&proxied))
+
+	assert.Panics(t, func() {
+		handler.WithHTTPProxy("://bad")(h)
+	})
+}
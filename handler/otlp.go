@@ -0,0 +1,450 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gookit/slog"
+)
+
+// OTLP export protocols
+const (
+	// OTLPHTTPProtobuf OTLP/HTTP with binary protobuf payload. eg endpoint: http://localhost:4318/v1/logs
+	OTLPHTTPProtobuf = "http/protobuf"
+	// OTLPHTTPJSON OTLP/HTTP with JSON payload
+	OTLPHTTPJSON = "http/json"
+	// OTLPGRPC OTLP/gRPC, only support the TLS endpoint. eg: https://collector:4317
+	OTLPGRPC = "grpc"
+)
+
+// the OTLP/gRPC export method path
+const otlpGRPCPath = "/opentelemetry.proto.collector.logs.v1.LogsService/Export"
+
+// DefaultOTLPMaxQueueSize default max queued records for OTLPHandler
+const DefaultOTLPMaxQueueSize = 2048
+
+// ErrOTLPClosed error on handle record after the OTLPHandler is closed
+var ErrOTLPClosed = errors.New("slog: otlp handler has been closed")
+
+// ErrOTLPGRPCInsecure the OTLP/gRPC is over HTTP/2, the go http client only support it with TLS.
+var ErrOTLPGRPCInsecure = errors.New("slog: otlp grpc requires the https endpoint, use the http/protobuf protocol for plaintext")
+
+// OTLPHandler export the log records by the OpenTelemetry Logs protocol(OTLP), to the collectors directly.
+//
+//   - the level is mapped to the severity number, see OTLPSeverity()
+//   - the record Fields, Data, Extra and Attrs are mapped to the attributes, and the caller to the code.* attributes.
+//   - the trace id, span id are read from the record fields by the TraceIDKey, SpanIDKey
+//
+// The records are queued and exported by a background goroutine like the OTel BatchLogRecordProcessor,
+// exported on the batch is full, the ScheduledDelay elapsed, or on Flush().
+// On the queue is full, the new records are dropped, see Dropped().
+type OTLPHandler struct {
+	NameTrait
+	slog.LevelHandling
+	// Endpoint the collector URL. eg: http://localhost:4318/v1/logs, https://collector:4317
+	Endpoint string
+	// Protocol for export. allow: OTLPHTTPProtobuf, OTLPHTTPJSON, OTLPGRPC. default is OTLPHTTPProtobuf
+	Protocol string
+	// Headers custom request headers. eg: authorization
+	Headers map[string]string
+	// Codec for compress the payload, the OTLP only support gzip. default is nil, not compress.
+	Codec Codec
+	// Client the http client, the timeout is the export timeout. default timeout is 10s
+	Client *http.Client
+	// Resource the resource attributes. default contains the "service.name"
+	Resource map[string]any
+	// ScopeName the instrumentation scope name. default is "github.com/gookit/slog"
+	ScopeName string
+	// MaxExportBatchSize max records in an export request. default is 512
+	MaxExportBatchSize int
+	// MaxQueueSize max queued records waiting for export. default is DefaultOTLPMaxQueueSize
+	MaxQueueSize int
+	// ScheduledDelay export the queued records at the interval. default is 1s, 0 to disable.
+	ScheduledDelay time.Duration
+	// TraceIDKey, SpanIDKey the field names for get the hex trace id, span id from Record.Fields, Record.Data
+	TraceIDKey, SpanIDKey string
+
+	// mu protect the queue and closed flag, not held on export.
+	mu     sync.Mutex
+	closed bool
+	queue  []*otlpLogRecord
+	// dropped records count on the queue is full, and last report time(unix nano)
+	dropped    uint64
+	lastReport int64
+
+	// the export goroutine, started on first use
+	startOnce sync.Once
+	kick      chan struct{}
+	flushes   chan chan error
+	quit      chan struct{}
+	done      chan struct{}
+}
+
+// NewOTLPHandler create new OTLPHandler
+//
+// Usage:
+//
+//	h := handler.NewOTLPHandler("http://localhost:4318/v1/logs", func(h *handler.OTLPHandler) {
+//		h.Resource["service.name"] = "order-api"
+//		h.Codec, _ = handler.GetCodec(handler.CodecGzip)
+//	})
+func NewOTLPHandler(endpoint string, fns ...func(h *OTLPHandler)) *OTLPHandler {
+	h := &OTLPHandler{
+		Endpoint: endpoint,
+		Protocol: OTLPHTTPProtobuf,
+		Client:   &http.Client{Timeout: 10 * time.Second},
+		Resource: map[string]any{
+			"service.name": "unknown_service:" + filepath.Base(os.Args[0]),
+		},
+		ScopeName:          "github.com/gookit/slog",
+		MaxExportBatchSize: 512,
+		MaxQueueSize:       DefaultOTLPMaxQueueSize,
+		ScheduledDelay:     time.Second,
+		TraceIDKey:         "trace_id",
+		SpanIDKey:          "span_id",
+		kick:               make(chan struct{}, 1),
+		flushes:            make(chan chan error),
+		quit:               make(chan struct{}),
+		done:               make(chan struct{}),
+	}
+	h.SetMaxLevel(slog.TraceLevel)
+
+	for _, fn := range fns {
+		fn(h)
+	}
+
+	if h.MaxQueueSize <= 0 {
+		h.MaxQueueSize = DefaultOTLPMaxQueueSize
+	}
+	return h
+}
+
+// OTLPSeverity get the OTel severity number of the level
+func OTLPSeverity(level slog.Level) int {
+	switch level {
+	case slog.PanicLevel:
+		return 22 // FATAL2
+	case slog.FatalLevel:
+		return 21
+	case slog.ErrorLevel:
+		return 17
+	case slog.WarnLevel:
+		return 13
+	case slog.NoticeLevel:
+		return 10 // INFO2
+	case slog.InfoLevel:
+		return 9
+	case slog.DebugLevel:
+		return 5
+	default: // trace
+		return 1
+	}
+}
+
+// Handle convert the record and put it to the queue, the export is in the background goroutine.
+//
+// On the queue is full, the record is dropped. the export errors are reported to stderr,
+// call Flush() to get the export error.
+func (h *OTLPHandler) Handle(r *slog.Record) error {
+	lr := h.convert(r)
+	h.startOnce.Do(h.start)
+
+	h.mu.Lock()
+	if h.closed {
+		h.mu.Unlock()
+		return ErrOTLPClosed
+	}
+	if len(h.queue) >= h.MaxQueueSize {
+		h.mu.Unlock()
+		h.drop()
+		return nil
+	}
+
+	h.queue = append(h.queue, lr)
+	full := len(h.queue) >= h.MaxExportBatchSize
+	h.mu.Unlock()
+
+	// notify the goroutine to export
+	if full {
+		select {
+		case h.kick <- struct{}{}:
+		default:
+		}
+	}
+	return nil
+}
+
+// count the dropped record, and report it at most once per second.
+func (h *OTLPHandler) drop() {
+	total := atomic.AddUint64(&h.dropped, 1)
+
+	now := time.Now().UnixNano()
+	last := atomic.LoadInt64(&h.lastReport)
+	if now-last >= int64(time.Second) && atomic.CompareAndSwapInt64(&h.lastReport, last, now) {
+		slog.ReportInternal(slog.WarnLevel, "slog: otlp handler queue is full, total dropped records:", total)
+	}
+}
+
+// Dropped get the count of records that dropped on the queue is full
+func (h *OTLPHandler) Dropped() uint64 {
+	return atomic.LoadUint64(&h.dropped)
+}
+
+// Flush wait for the queued records are exported, returns the first export error.
+func (h *OTLPHandler) Flush() error {
+	h.startOnce.Do(h.start)
+
+	ch := make(chan error, 1)
+	select {
+	case h.flushes <- ch:
+		return <-ch
+	case <-h.done:
+		return nil
+	}
+}
+
+// Close the handler, will export the queued records and stop the background goroutine.
+func (h *OTLPHandler) Close() error {
+	h.mu.Lock()
+	if h.closed {
+		h.mu.Unlock()
+		return nil
+	}
+	h.closed = true
+	h.mu.Unlock()
+
+	err := h.Flush()
+	close(h.quit)
+	<-h.done
+	return err
+}
+
+func (h *OTLPHandler) start() { go h.run() }
+
+func (h *OTLPHandler) run() {
+	defer close(h.done)
+
+	var tick <-chan time.Time
+	if h.ScheduledDelay > 0 {
+		ticker := time.NewTicker(h.ScheduledDelay)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+
+	for {
+		select {
+		case <-h.kick:
+			h.exportQueued(true)
+		case <-tick:
+			h.exportQueued(true)
+		case ch := <-h.flushes:
+			ch <- h.exportQueued(false)
+		case <-h.quit:
+			return
+		}
+	}
+}
+
+// swap out the queued records in the lock, then export them by batches without the lock.
+func (h *OTLPHandler) exportQueued(report bool) (err error) {
+	h.mu.Lock()
+	logs := h.queue
+	h.queue = nil
+	h.mu.Unlock()
+
+	for len(logs) > 0 {
+		n := h.MaxExportBatchSize
+		if n <= 0 || n > len(logs) {
+			n = len(logs)
+		}
+
+		if err1 := h.export(logs[:n]); err1 != nil {
+			if report {
+				reportError("slog: otlp handler export error:", err1)
+			}
+			if err == nil {
+				err = err1
+			}
+		}
+		logs = logs[n:]
+	}
+	return
+}
+
+// convert the record to OTLP log record
+func (h *OTLPHandler) convert(r *slog.Record) *otlpLogRecord {
+	lr := &otlpLogRecord{
+		time:     r.Time,
+		observed: time.Now(),
+		sevNum:   OTLPSeverity(r.Level),
+		sevText:  r.Level.Name(),
+		body:     r.Message,
+	}
+
+	attrs := make(map[string]any, len(r.Fields)+len(r.Data)+len(r.Extra)+len(r.Attrs)+5)
+	for k, v := range r.Extra {
+		attrs[k] = v
+	}
+	for k, v := range r.Data {
+		attrs[k] = v
+	}
+	for k, v := range r.Fields {
+		attrs[k] = v
+	}
+	for _, attr := range r.Attrs {
+		attrs[attr.Key] = attr.Value()
+	}
+
+	if id, ok := otlpHexID(attrs[h.TraceIDKey], 16); ok {
+		lr.traceID = id
+		delete(attrs, h.TraceIDKey)
+	}
+	if id, ok := otlpHexID(attrs[h.SpanIDKey], 8); ok {
+		lr.spanID = id
+		delete(attrs, h.SpanIDKey)
+	}
+
+	if r.Channel != "" {
+		attrs[slog.FieldKeyChannel] = r.Channel
+	}
+	if len(r.Tags) > 0 {
+		attrs[slog.FieldKeyTags] = r.Tags
+	}
+	if r.Caller != nil {
+		attrs["code.function"] = r.Caller.Function
+		attrs["code.filepath"] = r.Caller.File
+		attrs["code.lineno"] = r.Caller.Line
+	}
+
+	lr.attrs = otlpKVsOf(attrs)
+	return lr
+}
+
+// decode the hex trace id, span id
+func otlpHexID(v any, size int) ([]byte, bool) {
+	s, ok := v.(string)
+	if !ok || len(s) != size*2 {
+		return nil, false
+	}
+
+	id, err := hex.DecodeString(s)
+	return id, err == nil
+}
+
+// export the records by an export request. called by the background goroutine.
+func (h *OTLPHandler) export(logs []*otlpLogRecord) (err error) {
+	resource := otlpKVsOf(h.Resource)
+
+	var payload []byte
+	contentType := "application/x-protobuf"
+	if h.Protocol == OTLPHTTPJSON {
+		contentType = "application/json"
+		if payload, err = json.Marshal(otlpJSONRequest(resource, h.ScopeName, logs)); err != nil {
+			return err
+		}
+	} else {
+		payload = otlpEncodeProto(resource, h.ScopeName, logs)
+	}
+
+	compressed := h.Codec != nil && h.Codec.Name() != CodecNone
+	if compressed {
+		if payload, err = h.Codec.Encode(payload); err != nil {
+			return err
+		}
+	}
+
+	if h.Protocol == OTLPGRPC {
+		return h.exportGRPC(payload, compressed)
+	}
+	return h.exportHTTP(payload, contentType, compressed)
+}
+
+func (h *OTLPHandler) exportHTTP(payload []byte, contentType string, compressed bool) error {
+	req, err := http.NewRequest(http.MethodPost, h.Endpoint, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", contentType)
+	if compressed {
+		req.Header.Set("Content-Encoding", h.Codec.Name())
+	}
+	for k, v := range h.Headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := h.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+	if resp.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("slog: otlp http export failed, status: %s, body: %s", resp.Status, body)
+	}
+	return nil
+}
+
+// export by gRPC over HTTP/2. the message is prefixed by the compressed flag(1) and length(4)
+func (h *OTLPHandler) exportGRPC(payload []byte, compressed bool) error {
+	if !strings.HasPrefix(h.Endpoint, "https://") {
+		return ErrOTLPGRPCInsecure
+	}
+
+	msg := make([]byte, 5, 5+len(payload))
+	if compressed {
+		msg[0] = 1
+	}
+	binary.BigEndian.PutUint32(msg[1:], uint32(len(payload)))
+	msg = append(msg, payload...)
+
+	url := strings.TrimSuffix(h.Endpoint, "/") + otlpGRPCPath
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(msg))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/grpc")
+	req.Header.Set("TE", "trailers")
+	if compressed {
+		req.Header.Set("Grpc-Encoding", h.Codec.Name())
+	}
+	for k, v := range h.Headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := h.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	// must read the body before reading the trailers
+	_, _ = io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("slog: otlp grpc export failed, status: %s", resp.Status)
+	}
+
+	// the status in headers on trailers-only response
+	status, message := resp.Trailer.Get("Grpc-Status"), resp.Trailer.Get("Grpc-Message")
+	if status == "" {
+		status, message = resp.Header.Get("Grpc-Status"), resp.Header.Get("Grpc-Message")
+	}
+	if status != "0" {
+		return fmt.Errorf("slog: otlp grpc export failed, grpc status: %s, message: %s", status, message)
+	}
+	return nil
+}
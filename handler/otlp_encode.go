@@ -0,0 +1,315 @@
+package handler
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/gookit/goutil/strutil"
+	"github.com/gookit/slog"
+)
+
+// the minimal OTLP logs data model and encoders for the OTLPHandler.
+// see https://github.com/open-telemetry/opentelemetry-proto/blob/main/opentelemetry/proto/logs/v1/logs.proto
+
+// the AnyValue kinds
+const (
+	otlpString = iota
+	otlpBool
+	otlpInt
+	otlpDouble
+	otlpArray
+	otlpKvList
+	otlpBytes
+)
+
+// otlpValue the AnyValue
+type otlpValue struct {
+	kind int
+	str  string
+	b    bool
+	i    int64
+	f    float64
+	bts  []byte
+	arr  []otlpValue
+	kvs  []otlpKV
+}
+
+// otlpKV the KeyValue
+type otlpKV struct {
+	key string
+	val otlpValue
+}
+
+// otlpLogRecord the LogRecord
+type otlpLogRecord struct {
+	time     time.Time
+	observed time.Time
+	sevNum   int
+	sevText  string
+	body     string
+	attrs    []otlpKV
+	traceID  []byte
+	spanID   []byte
+}
+
+// convert a go value to the AnyValue
+func otlpValueOf(v any) otlpValue {
+	switch tv := v.(type) {
+	case nil:
+		return otlpValue{}
+	case string:
+		return otlpValue{str: tv}
+	case bool:
+		return otlpValue{kind: otlpBool, b: tv}
+	case int:
+		return otlpValue{kind: otlpInt, i: int64(tv)}
+	case int8:
+		return otlpValue{kind: otlpInt, i: int64(tv)}
+	case int16:
+		return otlpValue{kind: otlpInt, i: int64(tv)}
+	case int32:
+		return otlpValue{kind: otlpInt, i: int64(tv)}
+	case int64:
+		return otlpValue{kind: otlpInt, i: tv}
+	case uint:
+		return otlpUintValue(uint64(tv))
+	case uint8:
+		return otlpValue{kind: otlpInt, i: int64(tv)}
+	case uint16:
+		return otlpValue{kind: otlpInt, i: int64(tv)}
+	case uint32:
+		return otlpValue{kind: otlpInt, i: int64(tv)}
+	case uint64:
+		return otlpUintValue(tv)
+	case float32:
+		return otlpValue{kind: otlpDouble, f: float64(tv)}
+	case float64:
+		return otlpValue{kind: otlpDouble, f: tv}
+	case []byte:
+		return otlpValue{kind: otlpBytes, bts: tv}
+	case time.Time:
+		return otlpValue{str: tv.Format(time.RFC3339Nano)}
+	case map[string]any:
+		return otlpValue{kind: otlpKvList, kvs: otlpKVsOf(tv)}
+	case slog.M:
+		return otlpValue{kind: otlpKvList, kvs: otlpKVsOf(tv)}
+	case error:
+		return otlpValue{str: tv.Error()}
+	case fmt.Stringer:
+		return otlpValue{str: tv.String()}
+	}
+
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Array:
+		arr := make([]otlpValue, rv.Len())
+		for i := range arr {
+			arr[i] = otlpValueOf(rv.Index(i).Interface())
+		}
+		return otlpValue{kind: otlpArray, arr: arr}
+	case reflect.Map:
+		if rv.Type().Key().Kind() == reflect.String {
+			mp := make(map[string]any, rv.Len())
+			for it := rv.MapRange(); it.Next(); {
+				mp[it.Key().String()] = it.Value().Interface()
+			}
+			return otlpValue{kind: otlpKvList, kvs: otlpKVsOf(mp)}
+		}
+	}
+	return otlpValue{str: strutil.SafeString(v)}
+}
+
+// the int value is int64, will use string on overflow.
+func otlpUintValue(u uint64) otlpValue {
+	if u > math.MaxInt64 {
+		return otlpValue{str: strconv.FormatUint(u, 10)}
+	}
+	return otlpValue{kind: otlpInt, i: int64(u)}
+}
+
+// convert the map to KeyValue list, sorted by the keys.
+func otlpKVsOf(mp map[string]any) []otlpKV {
+	keys := make([]string, 0, len(mp))
+	for k := range mp {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	kvs := make([]otlpKV, 0, len(keys))
+	for _, k := range keys {
+		kvs = append(kvs, otlpKV{key: k, val: otlpValueOf(mp[k])})
+	}
+	return kvs
+}
+
+//
+// --------- protobuf encoding ---------
+//
+
+// protobuf wire types
+const (
+	pbVarint  = 0
+	pbFixed64 = 1
+	pbBytes   = 2
+)
+
+func pbAppendTag(b []byte, field, wireType int) []byte {
+	return binary.AppendUvarint(b, uint64(field<<3|wireType))
+}
+
+func pbAppendVarint(b []byte, field int, v uint64) []byte {
+	return binary.AppendUvarint(pbAppendTag(b, field, pbVarint), v)
+}
+
+func pbAppendFixed64(b []byte, field int, v uint64) []byte {
+	return binary.LittleEndian.AppendUint64(pbAppendTag(b, field, pbFixed64), v)
+}
+
+func pbAppendBytes(b []byte, field int, v []byte) []byte {
+	b = binary.AppendUvarint(pbAppendTag(b, field, pbBytes), uint64(len(v)))
+	return append(b, v...)
+}
+
+func pbAppendString(b []byte, field int, s string) []byte {
+	b = binary.AppendUvarint(pbAppendTag(b, field, pbBytes), uint64(len(s)))
+	return append(b, s...)
+}
+
+func pbAppendValue(b []byte, v otlpValue) []byte {
+	switch v.kind {
+	case otlpBool:
+		var u uint64
+		if v.b {
+			u = 1
+		}
+		return pbAppendVarint(b, 2, u)
+	case otlpInt:
+		return pbAppendVarint(b, 3, uint64(v.i))
+	case otlpDouble:
+		return pbAppendFixed64(b, 4, math.Float64bits(v.f))
+	case otlpArray:
+		var sub []byte
+		for _, item := range v.arr {
+			sub = pbAppendBytes(sub, 1, pbAppendValue(nil, item))
+		}
+		return pbAppendBytes(b, 5, sub)
+	case otlpKvList:
+		return pbAppendBytes(b, 6, pbAppendKVs(nil, 1, v.kvs))
+	case otlpBytes:
+		return pbAppendBytes(b, 7, v.bts)
+	}
+	return pbAppendString(b, 1, v.str)
+}
+
+// append the KeyValue list as the repeated field
+func pbAppendKVs(b []byte, field int, kvs []otlpKV) []byte {
+	for _, kv := range kvs {
+		sub := pbAppendString(nil, 1, kv.key)
+		sub = pbAppendBytes(sub, 2, pbAppendValue(nil, kv.val))
+		b = pbAppendBytes(b, field, sub)
+	}
+	return b
+}
+
+func pbAppendLogRecord(b []byte, r *otlpLogRecord) []byte {
+	b = pbAppendFixed64(b, 1, uint64(r.time.UnixNano()))
+	b = pbAppendVarint(b, 2, uint64(r.sevNum))
+	b = pbAppendString(b, 3, r.sevText)
+	b = pbAppendBytes(b, 5, pbAppendValue(nil, otlpValue{str: r.body}))
+	b = pbAppendKVs(b, 6, r.attrs)
+	if len(r.traceID) > 0 {
+		b = pbAppendBytes(b, 9, r.traceID)
+	}
+	if len(r.spanID) > 0 {
+		b = pbAppendBytes(b, 10, r.spanID)
+	}
+	return pbAppendFixed64(b, 11, uint64(r.observed.UnixNano()))
+}
+
+// encode the ExportLogsServiceRequest by protobuf
+func otlpEncodeProto(resource []otlpKV, scope string, logs []*otlpLogRecord) []byte {
+	var scopeLogs []byte
+	scopeLogs = pbAppendBytes(scopeLogs, 1, pbAppendString(nil, 1, scope))
+	for _, r := range logs {
+		scopeLogs = pbAppendBytes(scopeLogs, 2, pbAppendLogRecord(nil, r))
+	}
+
+	resLogs := pbAppendBytes(nil, 1, pbAppendKVs(nil, 1, resource))
+	resLogs = pbAppendBytes(resLogs, 2, scopeLogs)
+	return pbAppendBytes(nil, 1, resLogs)
+}
+
+//
+// --------- JSON encoding ---------
+//
+
+// the OTLP/JSON value. the int64 is encoded as string, the bytes is base64 encoded.
+// see https://opentelemetry.io/docs/specs/otlp/#json-protobuf-encoding
+func otlpJSONValue(v otlpValue) map[string]any {
+	switch v.kind {
+	case otlpBool:
+		return map[string]any{"boolValue": v.b}
+	case otlpInt:
+		return map[string]any{"intValue": strconv.FormatInt(v.i, 10)}
+	case otlpDouble:
+		return map[string]any{"doubleValue": v.f}
+	case otlpArray:
+		arr := make([]any, 0, len(v.arr))
+		for _, item := range v.arr {
+			arr = append(arr, otlpJSONValue(item))
+		}
+		return map[string]any{"arrayValue": map[string]any{"values": arr}}
+	case otlpKvList:
+		return map[string]any{"kvlistValue": map[string]any{"values": otlpJSONKVs(v.kvs)}}
+	case otlpBytes:
+		return map[string]any{"bytesValue": base64.StdEncoding.EncodeToString(v.bts)}
+	}
+	return map[string]any{"stringValue": v.str}
+}
+
+func otlpJSONKVs(kvs []otlpKV) []any {
+	ls := make([]any, 0, len(kvs))
+	for _, kv := range kvs {
+		ls = append(ls, map[string]any{"key": kv.key, "value": otlpJSONValue(kv.val)})
+	}
+	return ls
+}
+
+// build the ExportLogsServiceRequest for JSON encoding. the trace id, span id are hex encoded.
+func otlpJSONRequest(resource []otlpKV, scope string, logs []*otlpLogRecord) map[string]any {
+	records := make([]any, 0, len(logs))
+	for _, r := range logs {
+		mp := map[string]any{
+			"timeUnixNano":         strconv.FormatInt(r.time.UnixNano(), 10),
+			"observedTimeUnixNano": strconv.FormatInt(r.observed.UnixNano(), 10),
+			"severityNumber":       r.sevNum,
+			"severityText":         r.sevText,
+			"body":                 otlpJSONValue(otlpValue{str: r.body}),
+			"attributes":           otlpJSONKVs(r.attrs),
+		}
+		if len(r.traceID) > 0 {
+			mp["traceId"] = hex.EncodeToString(r.traceID)
+		}
+		if len(r.spanID) > 0 {
+			mp["spanId"] = hex.EncodeToString(r.spanID)
+		}
+		records = append(records, mp)
+	}
+
+	return map[string]any{
+		"resourceLogs": []any{map[string]any{
+			"resource": map[string]any{"attributes": otlpJSONKVs(resource)},
+			"scopeLogs": []any{map[string]any{
+				"scope":      map[string]any{"name": scope},
+				"logRecords": records,
+			}},
+		}},
+	}
+}
@@ -0,0 +1,267 @@
+package handler_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gookit/goutil/testutil/assert"
+	"github.com/gookit/slog"
+	"github.com/gookit/slog/handler"
+)
+
+// parse the protobuf message to fields, the value is uint64 or []byte
+func pbFields(t *testing.T, b []byte) map[int][]any {
+	fields := make(map[int][]any)
+	for len(b) > 0 {
+		tag, n := binary.Uvarint(b)
+		assert.Gt(t, n, 0)
+		b = b[n:]
+
+		var val any
+		switch tag & 7 {
+		case 0:
+			v, n := binary.Uvarint(b)
+			val, b = v, b[n:]
+		case 1:
+			val, b = binary.LittleEndian.Uint64(b), b[8:]
+		case 2:
+			ln, n := binary.Uvarint(b)
+			val, b = b[n:n+int(ln)], b[n+int(ln):]
+		default:
+			t.Fatalf("unexpected wire type %d", tag&7)
+		}
+		fields[int(tag>>3)] = append(fields[int(tag>>3)], val)
+	}
+	return fields
+}
+
+func pbMsg(t *testing.T, b []byte, path ...int) map[int][]any {
+	fs := pbFields(t, b)
+	for _, field := range path {
+		fs = pbFields(t, fs[field][0].([]byte))
+	}
+	return fs
+}
+
+func newOTLPRecord() *slog.Record {
+	r := newLogRecord("otlp message")
+	r.Level = slog.ErrorLevel
+	r.Time = time.Unix(1700000000, 123)
+	r.Fields = slog.M{
+		"trace_id": "4bf92f3577b34da6a3ce929d0e0e4736",
+		"span_id":  "00f067aa0ba902b7",
+		"user_id":  23,
+	}
+	return r
+}
+
+func TestOTLPHandler_protobuf(t *testing.T) {
+	reqs := make(chan *http.Request, 2)
+	bodies := make(chan []byte, 2)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		bs, _ := io.ReadAll(req.Body)
+		reqs <- req
+		bodies <- bs
+	}))
+	defer srv.Close()
+
+	h := handler.NewOTLPHandler(srv.URL+"/v1/logs", func(h *handler.OTLPHandler) {
+		h.Resource["service.name"] = "order-api"
+		h.ScheduledDelay = 0
+	})
+	assert.NoErr(t, h.Handle(newOTLPRecord()))
+	assert.Len(t, reqs, 0)
+	assert.NoErr(t, h.Flush())
+
+	req := <-reqs
+	assert.Eq(t, "/v1/logs", req.URL.Path)
+	assert.Eq(t, "application/x-protobuf", req.Header.Get("Content-Type"))
+
+	body := <-bodies
+	// ExportLogsServiceRequest.resource_logs.resource.attributes
+	attr := pbMsg(t, body, 1, 1, 1)
+	assert.Eq(t, "service.name", string(attr[1][0].([]byte)))
+	assert.Eq(t, "order-api", string(pbMsg(t, attr[2][0].([]byte))[1][0].([]byte)))
+
+	// ResourceLogs.scope_logs.log_records
+	scope := pbMsg(t, body, 1, 2)
+	assert.Eq(t, "github.com/gookit/slog", string(pbMsg(t, scope[1][0].([]byte))[1][0].([]byte)))
+
+	lr := pbFields(t, scope[2][0].([]byte))
+	assert.Eq(t, uint64(1700000000000000123), lr[1][0])
+	assert.Eq(t, uint64(17), lr[2][0])
+	assert.Eq(t, "ERROR", string(lr[3][0].([]byte)))
+	assert.Eq(t, "otlp message", string(pbFields(t, lr[5][0].([]byte))[1][0].([]byte)))
+	assert.Len(t, lr[9][0], 16)
+	assert.Len(t, lr[10][0], 8)
+
+	keys := make(map[string]map[int][]any)
+	for _, kv := range lr[6] {
+		fs := pbFields(t, kv.([]byte))
+		keys[string(fs[1][0].([]byte))] = pbFields(t, fs[2][0].([]byte))
+	}
+	assert.Eq(t, uint64(23), keys["user_id"][3][0])
+	assert.Eq(t, "handler_test", string(keys["channel"][1][0].([]byte)))
+	assert.NotContains(t, keys, "trace_id")
+	// nested map to kvlist
+	assert.NotEmpty(t, keys["sub"][6])
+}
+
+func TestOTLPHandler_json(t *testing.T) {
+	bodies := make(chan []byte, 2)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		assert.Eq(t, "application/json", req.Header.Get("Content-Type"))
+		assert.Eq(t, "gzip", req.Header.Get("Content-Encoding"))
+		zr, err := gzip.NewReader(req.Body)
+		assert.NoErr(t, err)
+		bs, _ := io.ReadAll(zr)
+		bodies <- bs
+	}))
+	defer srv.Close()
+
+	h := handler.NewOTLPHandler(srv.URL, func(h *handler.OTLPHandler) {
+		h.Protocol = handler.OTLPHTTPJSON
+		h.Codec, _ = handler.GetCodec(handler.CodecGzip)
+		h.MaxExportBatchSize = 2
+	})
+
+	// export on the batch is full
+	assert.NoErr(t, h.Handle(newOTLPRecord()))
+	assert.NoErr(t, h.Handle(newLogRecord("second message")))
+
+	var req struct {
+		ResourceLogs []struct {
+			ScopeLogs []struct {
+				LogRecords []map[string]any `json:"logRecords"`
+			} `json:"scopeLogs"`
+		} `json:"resourceLogs"`
+	}
+	assert.NoErr(t, json.Unmarshal(<-bodies, &req))
+
+	lrs := req.ResourceLogs[0].ScopeLogs[0].LogRecords
+	assert.Len(t, lrs, 2)
+	assert.Eq(t, "1700000000000000123", lrs[0]["timeUnixNano"])
+	assert.Eq(t, float64(17), lrs[0]["severityNumber"])
+	assert.Eq(t, "4bf92f3577b34da6a3ce929d0e0e4736", lrs[0]["traceId"])
+	assert.Eq(t, map[string]any{"stringValue": "second message"}, lrs[1]["body"])
+	assert.NoErr(t, h.Close())
+}
+
+func TestOTLPHandler_scheduled(t *testing.T) {
+	bodies := make(chan []byte, 2)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		bs, _ := io.ReadAll(req.Body)
+		bodies <- bs
+	}))
+	defer srv.Close()
+
+	h := handler.NewOTLPHandler(srv.URL, func(h *handler.OTLPHandler) {
+		h.ScheduledDelay = 20 * time.Millisecond
+	})
+	assert.NoErr(t, h.Handle(newLogRecord("scheduled message")))
+
+	select {
+	case bs := <-bodies:
+		assert.True(t, bytes.Contains(bs, []byte("scheduled message")))
+	case <-time.After(2 * time.Second):
+		t.Fatal("timeout for the scheduled export")
+	}
+	assert.NoErr(t, h.Close())
+}
+
+func TestOTLPHandler_queue(t *testing.T) {
+	got := make(chan int, 8)
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		bs, _ := io.ReadAll(req.Body)
+		got <- len(bs)
+		<-release
+	}))
+	defer srv.Close()
+
+	h := handler.NewOTLPHandler(srv.URL, func(h *handler.OTLPHandler) {
+		h.ScheduledDelay = 0
+		h.MaxExportBatchSize = 1
+		h.MaxQueueSize = 2
+	})
+
+	// the first record is exporting, blocked by the server
+	assert.NoErr(t, h.Handle(newLogRecord("first message")))
+	<-got
+
+	// not blocked by the export, the new record is dropped on the queue is full
+	done := make(chan struct{})
+	go func() {
+		for i := 0; i < 3; i++ {
+			assert.NoErr(t, h.Handle(newLogRecord("queued message")))
+		}
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("the Handle is blocked by the export")
+	}
+	assert.Eq(t, uint64(1), h.Dropped())
+
+	close(release)
+	assert.NoErr(t, h.Close())
+	assert.Len(t, got, 2)
+	assert.ErrIs(t, h.Handle(newLogRecord("closed")), handler.ErrOTLPClosed)
+	assert.NoErr(t, h.Flush())
+}
+
+func TestOTLPHandler_grpc(t *testing.T) {
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		assert.Eq(t, "/opentelemetry.proto.collector.logs.v1.LogsService/Export", req.URL.Path)
+		assert.Eq(t, "application/grpc", req.Header.Get("Content-Type"))
+		assert.Eq(t, 2, req.ProtoMajor)
+
+		bs, _ := io.ReadAll(req.Body)
+		assert.Eq(t, byte(0), bs[0])
+		assert.Eq(t, uint32(len(bs)-5), binary.BigEndian.Uint32(bs[1:5]))
+
+		status := "0"
+		if !bytes.Contains(bs, []byte("otlp message")) {
+			status = "3"
+		}
+		w.Header().Set("Trailer", "Grpc-Status, Grpc-Message")
+		w.Header().Set("Content-Type", "application/grpc")
+		_, _ = w.Write([]byte{0, 0, 0, 0, 0})
+		w.Header().Set("Grpc-Status", status)
+		w.Header().Set("Grpc-Message", "invalid argument")
+	}))
+	srv.EnableHTTP2 = true
+	srv.StartTLS()
+	defer srv.Close()
+
+	h := handler.NewOTLPHandler(srv.URL, func(h *handler.OTLPHandler) {
+		h.Protocol = handler.OTLPGRPC
+		h.Client = srv.Client()
+	})
+	assert.NoErr(t, h.Handle(newOTLPRecord()))
+	assert.NoErr(t, h.Flush())
+
+	assert.NoErr(t, h.Handle(newLogRecord("other message")))
+	assert.ErrMsg(t, h.Flush(), "slog: otlp grpc export failed, grpc status: 3, message: invalid argument")
+
+	// insecure endpoint
+	h.Endpoint = "http://127.0.0.1:4317"
+	assert.NoErr(t, h.Handle(newOTLPRecord()))
+	assert.ErrIs(t, h.Flush(), handler.ErrOTLPGRPCInsecure)
+}
+
+func TestOTLPSeverity(t *testing.T) {
+	assert.Eq(t, 1, handler.OTLPSeverity(slog.TraceLevel))
+	assert.Eq(t, 9, handler.OTLPSeverity(slog.InfoLevel))
+	assert.Eq(t, 13, handler.OTLPSeverity(slog.WarnLevel))
+	assert.Eq(t, 22, handler.OTLPSeverity(slog.PanicLevel))
+}
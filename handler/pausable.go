@@ -0,0 +1,153 @@
+package handler
+
+import (
+	"sync"
+
+	"github.com/gookit/slog"
+)
+
+// PauseMode the records handling mode on the handler is paused
+type PauseMode uint8
+
+const (
+	// PauseBuffer buffer the records on paused, and write them on resumed.
+	PauseBuffer PauseMode = iota
+	// PauseDrop drop the records on paused.
+	PauseDrop
+)
+
+// DefaultPauseBufferSize default max buffered records for the PausableHandler
+var DefaultPauseBufferSize = 1000
+
+// PausableHandler wrap a handler, can pause and resume the handling at runtime.
+//
+// Useful for the maintenance operations, eg: remount the log volume, rotate the log file by external tools.
+// While paused, the records are buffered or dropped by the Mode, and the Flush will not call the wrapped handler.
+type PausableHandler struct {
+	// the wrapped handler
+	slog.Handler
+	// Mode on paused. default is PauseBuffer
+	Mode PauseMode
+	// MaxBuffer max buffered records on paused, the oldest records are dropped on full.
+	MaxBuffer int
+
+	mu     sync.Mutex
+	paused bool
+	buf    []*slog.Record
+	// the dropped records count on paused
+	dropped uint64
+}
+
+// NewPausable create new PausableHandler
+//
+// Usage:
+//
+//	h := handler.NewPausable(fileHandler)
+//	logger.AddHandler(h)
+//
+//	h.Pause()
+//	// ... remount the log volume
+//	err := h.Resume()
+func NewPausable(h slog.Handler, fns ...func(h *PausableHandler)) *PausableHandler {
+	ph := &PausableHandler{
+		Handler:   h,
+		MaxBuffer: DefaultPauseBufferSize,
+	}
+
+	for _, fn := range fns {
+		fn(ph)
+	}
+	return ph
+}
+
+// Pause the handling, the records will be buffered or dropped by the Mode.
+func (h *PausableHandler) Pause() {
+	h.mu.Lock()
+	h.paused = true
+	h.mu.Unlock()
+}
+
+// Resume the handling, will write the buffered records to the wrapped handler.
+func (h *PausableHandler) Resume() (err error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.paused = false
+	for _, r := range h.buf {
+		if herr := h.Handler.Handle(r); herr != nil && err == nil {
+			err = herr
+		}
+	}
+
+	h.buf = nil
+	return err
+}
+
+// Paused check the handler is paused
+func (h *PausableHandler) Paused() bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.paused
+}
+
+// Buffered get the buffered records count
+func (h *PausableHandler) Buffered() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return len(h.buf)
+}
+
+// Dropped get the dropped records count on paused
+func (h *PausableHandler) Dropped() uint64 {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.dropped
+}
+
+// Handle a log record, will buffer or drop it on paused.
+func (h *PausableHandler) Handle(r *slog.Record) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if !h.paused {
+		return h.Handler.Handle(r)
+	}
+
+	if h.Mode == PauseDrop {
+		h.dropped++
+		return nil
+	}
+
+	if h.MaxBuffer > 0 && len(h.buf) >= h.MaxBuffer {
+		h.buf[0] = nil
+		h.buf = h.buf[1:]
+		h.dropped++
+	}
+	// clone the record, it will be reused by the logger
+	h.buf = append(h.buf, r.Clone())
+	return nil
+}
+
+// Flush the wrapped handler, do nothing on paused.
+func (h *PausableHandler) Flush() error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.paused {
+		return nil
+	}
+	return h.Handler.Flush()
+}
+
+// Close the wrapped handler. the buffered records will be dropped on paused.
+func (h *PausableHandler) Close() error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if n := len(h.buf); n > 0 {
+		h.dropped += uint64(n)
+		h.buf = nil
+		slog.ReportInternal(slog.WarnLevel, "slog: pausable handler closed on paused, dropped buffered records:", n)
+	}
+	return h.Handler.Close()
+}
@@ -0,0 +1,67 @@
+package handler_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/gookit/goutil/testutil/assert"
+	"github.com/gookit/slog"
+	"github.com/gookit/slog/handler"
+)
+
+func TestPausableHandler_buffer(t *testing.T) {
+	buf := new(bytes.Buffer)
+	h := handler.NewPausable(handler.NewSimple(buf, slog.DebugLevel), func(h *handler.PausableHandler) {
+		h.MaxBuffer = 2
+	})
+	l := slog.NewWithHandlers(h)
+
+	l.Info("message1")
+	h.Pause()
+	assert.True(t, h.Paused())
+
+	l.Info("message2")
+	l.Error("message3")
+	l.Info("message4")
+	assert.Eq(t, 2, h.Buffered())
+	assert.Eq(t, uint64(1), h.Dropped())
+	assert.NotContains(t, buf.String(), "message3")
+
+	// write the buffered records on resume
+	assert.NoErr(t, h.Resume())
+	assert.False(t, h.Paused())
+	assert.Eq(t, 0, h.Buffered())
+
+	s := buf.String()
+	assert.NotContains(t, s, "message2")
+	assert.True(t, strings.Index(s, "message3") < strings.Index(s, "message4"))
+
+	l.Info("message5")
+	assert.StrContains(t, buf.String(), "message5")
+}
+
+func TestPausableHandler_drop(t *testing.T) {
+	buf := new(bytes.Buffer)
+	h := handler.NewPausable(handler.NewSimple(buf, slog.DebugLevel), func(h *handler.PausableHandler) {
+		h.Mode = handler.PauseDrop
+	})
+	l := slog.NewWithHandlers(h)
+
+	h.Pause()
+	l.Info("message1")
+	l.Info("message2")
+	assert.NoErr(t, h.Flush())
+	assert.Eq(t, 0, h.Buffered())
+	assert.Eq(t, uint64(2), h.Dropped())
+
+	assert.NoErr(t, h.Resume())
+	assert.Empty(t, buf.String())
+
+	// the buffered records are dropped on close
+	h.Mode = handler.PauseBuffer
+	h.Pause()
+	l.Info("message3")
+	assert.NoErr(t, l.Close())
+	assert.Eq(t, uint64(3), h.Dropped())
+}
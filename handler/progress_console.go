@@ -0,0 +1,139 @@
+package handler
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"sync"
+
+	"github.com/gookit/slog"
+)
+
+// the ANSI sequence for move to the line start and clear the line
+const clearLineSeq = "\r\x1b[2K"
+
+// ProgressConsoleHandler a console handler that cooperates with the terminal progress bars, spinners.
+//
+// It keeps the active progress line, clears it before print a record, then redraws it after.
+// so the logs and the progress UI are not mixed on the same line.
+type ProgressConsoleHandler struct {
+	NameTrait
+	NopFlushClose
+	slog.LevelFormattable
+
+	mu  sync.Mutex
+	out io.Writer
+	// the active progress line, not end with newline
+	line []byte
+}
+
+// NewProgressConsoleHandler create new ProgressConsoleHandler, output to the os.Stdout
+//
+// Usage:
+//
+//	h := handler.NewProgressConsoleHandler(slog.AllLevels)
+//	logger.AddHandler(h)
+//
+//	// set the progress line directly
+//	h.SetProgress("downloading 23%")
+//	h.ClearProgress()
+//
+//	// or use it as the output of the progress bar library
+//	bar := progressbar.NewOptions(100, progressbar.OptionSetWriter(h.ProgressWriter()))
+func NewProgressConsoleHandler(levels []slog.Level, fns ...func(h *ProgressConsoleHandler)) *ProgressConsoleHandler {
+	f := slog.NewTextFormatter()
+	f.WithEnableColor(slog.ColorEnabled(os.Stdout))
+
+	h := &ProgressConsoleHandler{
+		out:              os.Stdout,
+		LevelFormattable: slog.NewLvsFormatter(levels),
+	}
+	h.SetFormatter(f)
+
+	for _, fn := range fns {
+		fn(h)
+	}
+	return h
+}
+
+// SetOutput set the output writer. default is os.Stdout
+func (h *ProgressConsoleHandler) SetOutput(w io.Writer) {
+	h.mu.Lock()
+	h.out = w
+	h.mu.Unlock()
+}
+
+// Handle a log record, clear the progress line before write, then redraw it.
+func (h *ProgressConsoleHandler) Handle(r *slog.Record) error {
+	bts, err := h.Formatter().Format(r)
+	if err != nil {
+		return err
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if len(h.line) == 0 {
+		_, err = h.out.Write(bts)
+		return err
+	}
+
+	buf := make([]byte, 0, len(clearLineSeq)+len(bts)+len(h.line))
+	buf = append(buf, clearLineSeq...)
+	buf = append(buf, bts...)
+	buf = append(buf, h.line...)
+	_, err = h.out.Write(buf)
+	return err
+}
+
+// Progress get the active progress line
+func (h *ProgressConsoleHandler) Progress() string {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return string(h.line)
+}
+
+// SetProgress set and redraw the active progress line
+func (h *ProgressConsoleHandler) SetProgress(line string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.line = append(h.line[:0], line...)
+	_, _ = io.WriteString(h.out, clearLineSeq+line)
+}
+
+// ClearProgress clear the active progress line. eg: on the progress is done
+func (h *ProgressConsoleHandler) ClearProgress() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if len(h.line) > 0 {
+		h.line = h.line[:0]
+		_, _ = h.out.Write([]byte(clearLineSeq))
+	}
+}
+
+// ProgressWriter returns a writer for the progress bar libraries.
+//
+// The written data is passed to the output, and the text after the last "\n", "\r"
+// is kept as the active progress line.
+func (h *ProgressConsoleHandler) ProgressWriter() io.Writer {
+	return progressWriter{h}
+}
+
+type progressWriter struct {
+	h *ProgressConsoleHandler
+}
+
+func (w progressWriter) Write(p []byte) (int, error) {
+	h := w.h
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if i := bytes.LastIndexAny(p, "\r\n"); i >= 0 {
+		h.line = append(h.line[:0], p[i+1:]...)
+	} else {
+		h.line = append(h.line, p...)
+	}
+	return h.out.Write(p)
+}
@@ -0,0 +1,69 @@
+package handler_test
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/gookit/goutil/testutil/assert"
+	"github.com/gookit/slog"
+	"github.com/gookit/slog/handler"
+	"github.com/gookit/slog/slogtest"
+)
+
+func TestProgressConsoleHandler(t *testing.T) {
+	buf := new(bytes.Buffer)
+	h := handler.NewProgressConsoleHandler(slog.AllLevels, func(h *handler.ProgressConsoleHandler) {
+		h.SetOutput(buf)
+		h.SetFormatter(slog.NewTextFormatter("{{level}} {{message}}\n"))
+	})
+
+	// no progress line
+	assert.NoErr(t, h.Handle(newLogRecord("message1")))
+	assert.Eq(t, "INFO message1\n", buf.String())
+
+	buf.Reset()
+	h.SetProgress("downloading 10%")
+	assert.Eq(t, "downloading 10%", h.Progress())
+	assert.NoErr(t, h.Handle(newLogRecord("message2")))
+	assert.Eq(t, "\r\x1b[2Kdownloading 10%\r\x1b[2KINFO message2\ndownloading 10%", buf.String())
+
+	buf.Reset()
+	h.ClearProgress()
+	assert.Eq(t, "", h.Progress())
+	assert.NoErr(t, h.Handle(newLogRecord("message3")))
+	assert.Eq(t, "\r\x1b[2KINFO message3\n", buf.String())
+}
+
+func TestProgressConsoleHandler_ProgressWriter(t *testing.T) {
+	buf := new(bytes.Buffer)
+	h := handler.NewProgressConsoleHandler(slog.AllLevels, func(h *handler.ProgressConsoleHandler) {
+		h.SetOutput(buf)
+		h.SetFormatter(slog.NewTextFormatter("{{message}}\n"))
+	})
+
+	w := h.ProgressWriter()
+	_, _ = fmt.Fprint(w, "\r[=>  ] 30%")
+	_, _ = fmt.Fprint(w, "\r[==> ] 60%")
+	assert.Eq(t, "[==> ] 60%", h.Progress())
+
+	buf.Reset()
+	assert.NoErr(t, h.Handle(newLogRecord("message")))
+	assert.Eq(t, "\r\x1b[2Kmessage\n[==> ] 60%", buf.String())
+
+	// appended without line break
+	_, _ = fmt.Fprint(w, " 12MB/s")
+	assert.Eq(t, "[==> ] 60% 12MB/s", h.Progress())
+
+	// progress done
+	_, _ = fmt.Fprint(w, "\r[====] 100%\n")
+	assert.Eq(t, "", h.Progress())
+}
+
+func TestProgressConsoleHandler_conformance(t *testing.T) {
+	h := handler.NewProgressConsoleHandler(slog.AllLevels, func(h *handler.ProgressConsoleHandler) {
+		h.SetOutput(new(bytes.Buffer))
+	})
+	h.SetProgress("running")
+	slogtest.TestHandler(t, h)
+}
@@ -0,0 +1,179 @@
+package handler
+
+import (
+	"io"
+	"net/http"
+	"sync"
+
+	"github.com/gookit/slog"
+)
+
+// DefaultRingBufferSize default max records of the RingBufferHandler
+var DefaultRingBufferSize = 500
+
+// RingBufferHandler keep the last N records in memory, and dump them to
+// another handler or writer on demand. like a flight recorder.
+//
+// Useful for only output the detailed logs when something goes wrong,
+// eg: on first error, on receive a signal, or by a HTTP debug endpoint.
+type RingBufferHandler struct {
+	NameTrait
+	NopFlushClose
+	// for limit the buffered levels, and format records on DumpTo()
+	slog.LevelFormattable
+	// DumpLevel auto dump the buffered records to the DumpTarget, on a record level <= DumpLevel.
+	// 0 is disable. eg: slog.ErrorLevel
+	DumpLevel slog.Level
+	// DumpTarget the handler for auto dump
+	DumpTarget slog.Handler
+
+	mu   sync.Mutex
+	size int
+	recs []*slog.Record
+	// the index for write next record
+	next int
+}
+
+// NewRingBufferHandler create new RingBufferHandler, keep the last n records of all levels.
+//
+// Usage:
+//
+//	rb := handler.NewRingBufferHandler(1000, func(h *handler.RingBufferHandler) {
+//		h.DumpLevel = slog.ErrorLevel
+//		h.DumpTarget = fileHandler
+//	})
+//	logger.AddHandler(rb)
+//
+//	// dump by signal
+//	ch := make(chan os.Signal, 1)
+//	signal.Notify(ch, syscall.SIGUSR1)
+//	go func() {
+//		for range ch {
+//			_ = rb.DumpTo(os.Stderr)
+//		}
+//	}()
+//
+//	// dump by HTTP
+//	http.Handle("/debug/logs", rb)
+func NewRingBufferHandler(n int, fns ...func(h *RingBufferHandler)) *RingBufferHandler {
+	if n <= 0 {
+		n = DefaultRingBufferSize
+	}
+
+	h := &RingBufferHandler{
+		size: n,
+		recs: make([]*slog.Record, 0, n),
+		// init formatter and level handle
+		LevelFormattable: slog.NewLvsFormatter(slog.AllLevels),
+	}
+
+	for _, fn := range fns {
+		fn(h)
+	}
+	return h
+}
+
+// Handle a log record, keep a clone of it in the buffer.
+func (h *RingBufferHandler) Handle(r *slog.Record) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	// clone the record, it will be reused by the logger
+	if len(h.recs) < h.size {
+		h.recs = append(h.recs, r.Clone())
+	} else {
+		h.recs[h.next] = r.Clone()
+	}
+	h.next = (h.next + 1) % h.size
+
+	if h.DumpLevel > 0 && r.Level <= h.DumpLevel && h.DumpTarget != nil {
+		return h.dump(h.DumpTarget, true)
+	}
+	return nil
+}
+
+// Len get the buffered records count
+func (h *RingBufferHandler) Len() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return len(h.recs)
+}
+
+// Records get the buffered records, the oldest first.
+func (h *RingBufferHandler) Records() []*slog.Record {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.ordered()
+}
+
+// Reset clear the buffered records
+func (h *RingBufferHandler) Reset() {
+	h.mu.Lock()
+	h.reset()
+	h.mu.Unlock()
+}
+
+// Dump the buffered records to the handler, the oldest first.
+// The records are not filtered by the levels of the handler, and will be kept after dump.
+func (h *RingBufferHandler) Dump(to slog.Handler) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.dump(to, false)
+}
+
+// DumpTo format and write the buffered records to the writer, the oldest first.
+func (h *RingBufferHandler) DumpTo(w io.Writer) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for _, r := range h.ordered() {
+		bts, err := h.Formatter().Format(r)
+		if err != nil {
+			return err
+		}
+		if _, err = w.Write(bts); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ServeHTTP dump the buffered records to the response, implements the http.Handler
+func (h *RingBufferHandler) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	if err := h.DumpTo(w); err != nil {
+		reportError("slog: ring buffer handler dump to http response error:", err)
+	}
+}
+
+// dump records to the handler, and clear the buffer on reset=true
+func (h *RingBufferHandler) dump(to slog.Handler, reset bool) (err error) {
+	for _, r := range h.ordered() {
+		if herr := to.Handle(r); herr != nil && err == nil {
+			err = herr
+		}
+	}
+
+	if reset {
+		h.reset()
+	}
+	if ferr := to.Flush(); ferr != nil && err == nil {
+		err = ferr
+	}
+	return err
+}
+
+func (h *RingBufferHandler) ordered() []*slog.Record {
+	if len(h.recs) < h.size {
+		return append([]*slog.Record(nil), h.recs...)
+	}
+	return append(append([]*slog.Record(nil), h.recs[h.next:]...), h.recs[:h.next]...)
+}
+
+func (h *RingBufferHandler) reset() {
+	for i := range h.recs {
+		h.recs[i] = nil
+	}
+	h.recs = h.recs[:0]
+	h.next = 0
+}
@@ -0,0 +1,72 @@
+package handler_test
+
+import (
+	"bytes"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gookit/goutil/testutil/assert"
+	"github.com/gookit/slog"
+	"github.com/gookit/slog/handler"
+)
+
+func TestRingBufferHandler(t *testing.T) {
+	h := handler.NewRingBufferHandler(3)
+	h.SetFormatter(slog.NewTextFormatter("{{level}} {{message}}\n"))
+	l := slog.NewWithHandlers(h)
+
+	l.Info("message1")
+	l.Debug("message2")
+	assert.Eq(t, 2, h.Len())
+
+	l.Warn("message3")
+	l.Info("message4")
+	assert.Eq(t, 3, h.Len())
+
+	rs := h.Records()
+	assert.Len(t, rs, 3)
+	assert.Eq(t, "message2", rs[0].Message)
+	assert.Eq(t, "message4", rs[2].Message)
+
+	buf := new(bytes.Buffer)
+	assert.NoErr(t, h.DumpTo(buf))
+	assert.Eq(t, "DEBUG message2\nWARN message3\nINFO message4\n", buf.String())
+
+	// dump to a handler
+	buf.Reset()
+	assert.NoErr(t, h.Dump(handler.NewSimple(buf, slog.InfoLevel)))
+	s := buf.String()
+	assert.StrContains(t, s, "message2")
+	assert.StrContains(t, s, "message4")
+	assert.Eq(t, 3, h.Len())
+
+	// dump by HTTP
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("GET", "/debug/logs", nil))
+	assert.Eq(t, 200, w.Code)
+	assert.Eq(t, "DEBUG message2\nWARN message3\nINFO message4\n", w.Body.String())
+
+	h.Reset()
+	assert.Eq(t, 0, h.Len())
+	assert.Empty(t, h.Records())
+}
+
+func TestRingBufferHandler_DumpLevel(t *testing.T) {
+	buf := new(bytes.Buffer)
+	h := handler.NewRingBufferHandler(10, func(h *handler.RingBufferHandler) {
+		h.DumpLevel = slog.ErrorLevel
+		h.DumpTarget = handler.NewSimple(buf, slog.InfoLevel)
+	})
+	l := slog.NewWithHandlers(h)
+
+	l.Debug("detail message1")
+	l.Warn("warn message")
+	assert.Empty(t, buf.String())
+
+	l.Error("error message")
+	s := buf.String()
+	assert.Eq(t, 3, strings.Count(s, "\n"))
+	assert.True(t, strings.Index(s, "detail message1") < strings.Index(s, "error message"))
+	assert.Eq(t, 0, h.Len())
+}
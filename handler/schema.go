@@ -0,0 +1,180 @@
+package handler
+
+import (
+	"fmt"
+	"reflect"
+	"sync/atomic"
+
+	"github.com/gookit/slog"
+)
+
+// SchemaMode how to handle the nonconforming records
+type SchemaMode uint8
+
+// built-in schema modes
+const (
+	// SchemaReject drop the nonconforming records
+	SchemaReject SchemaMode = iota
+	// SchemaFix fix the records: fill default value for missing fields, convert to string on Type="string".
+	// the unfixable problems will be annotated.
+	SchemaFix
+	// SchemaAnnotate add the problems to the field "schema_errors", then handle it.
+	SchemaAnnotate
+)
+
+// FieldKeySchemaErrors field name for annotate the schema problems
+const FieldKeySchemaErrors = "schema_errors"
+
+// FieldRule schema rule for a field
+type FieldRule struct {
+	Name     string
+	Required bool
+	// Type expected value type: string, int, uint, float, bool, map, slice. empty is any type.
+	Type string
+	// Default value for fix the missing field on SchemaFix
+	Default any
+}
+
+// Schema declared for the records
+type Schema struct {
+	// Fields rules. will check the field on Record.Fields, then Record.Data
+	Fields []FieldRule
+	// Levels the allowed levels. empty is allow all
+	Levels []slog.Level
+}
+
+// SchemaHandler wrap a handler, validates records against the schema,
+// then rejects, fixes or annotates the nonconforming records.
+//
+// Useful when downstream pipelines are strict.
+type SchemaHandler struct {
+	slog.Handler
+	Schema *Schema
+	Mode   SchemaMode
+	// rejected records count
+	rejected uint64
+}
+
+// NewSchemaHandler create new SchemaHandler
+//
+// Usage:
+//
+//	h := handler.NewSchemaHandler(jsonHandler, &handler.Schema{
+//		Fields: []handler.FieldRule{
+//			{Name: "service", Required: true, Type: "string", Default: "unknown"},
+//			{Name: "latency_ms", Type: "int"},
+//		},
+//	}, handler.SchemaFix)
+func NewSchemaHandler(h slog.Handler, schema *Schema, mode SchemaMode) *SchemaHandler {
+	return &SchemaHandler{Handler: h, Schema: schema, Mode: mode}
+}
+
+// Rejected get the rejected records count
+func (h *SchemaHandler) Rejected() uint64 {
+	return atomic.LoadUint64(&h.rejected)
+}
+
+// Validate the record, returns the problems
+func (h *SchemaHandler) Validate(r *slog.Record) []string {
+	var errs []string
+	if len(h.Schema.Levels) > 0 && !slog.Levels(h.Schema.Levels).Contains(r.Level) {
+		errs = append(errs, fmt.Sprintf("level %s is not allowed", r.LevelName()))
+	}
+
+	for _, rule := range h.Schema.Fields {
+		val, ok := fieldValue(r, rule.Name)
+		if !ok {
+			if rule.Required {
+				errs = append(errs, fmt.Sprintf("field %q is required", rule.Name))
+			}
+			continue
+		}
+
+		if !matchType(val, rule.Type) {
+			errs = append(errs, fmt.Sprintf("field %q must be %s, got %T", rule.Name, rule.Type, val))
+		}
+	}
+	return errs
+}
+
+// Handle a log record
+func (h *SchemaHandler) Handle(r *slog.Record) error {
+	errs := h.Validate(r)
+	if len(errs) == 0 {
+		return h.Handler.Handle(r)
+	}
+
+	if h.Mode == SchemaReject {
+		atomic.AddUint64(&h.rejected, 1)
+		return nil
+	}
+
+	// copy the record, don't affect other handlers
+	nr := *r
+	nr.Fields = make(slog.M, len(r.Fields)+1)
+	for k, v := range r.Fields {
+		nr.Fields[k] = v
+	}
+
+	if h.Mode == SchemaFix {
+		h.fix(&nr)
+		errs = h.Validate(&nr)
+	}
+
+	if len(errs) > 0 {
+		nr.Fields[FieldKeySchemaErrors] = errs
+	}
+	return h.Handler.Handle(&nr)
+}
+
+func (h *SchemaHandler) fix(r *slog.Record) {
+	for _, rule := range h.Schema.Fields {
+		val, ok := fieldValue(r, rule.Name)
+		if !ok {
+			if rule.Required && rule.Default != nil {
+				r.Fields[rule.Name] = rule.Default
+			}
+			continue
+		}
+
+		if rule.Type == "string" && !matchType(val, rule.Type) {
+			r.Fields[rule.Name] = fmt.Sprint(val)
+		}
+	}
+}
+
+// get field value from Record.Fields, then Record.Data
+func fieldValue(r *slog.Record, name string) (any, bool) {
+	if val, ok := r.Fields[name]; ok {
+		return val, true
+	}
+	val, ok := r.Data[name]
+	return val, ok
+}
+
+func matchType(val any, typ string) bool {
+	if typ == "" {
+		return true
+	}
+	if val == nil {
+		return false
+	}
+
+	switch reflect.TypeOf(val).Kind() {
+	case reflect.String:
+		return typ == "string"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return typ == "int"
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return typ == "uint" || typ == "int"
+	case reflect.Float32, reflect.Float64:
+		return typ == "float"
+	case reflect.Bool:
+		return typ == "bool"
+	case reflect.Map, reflect.Struct:
+		return typ == "map"
+	case reflect.Slice, reflect.Array:
+		return typ == "slice"
+	}
+	return false
+}
@@ -0,0 +1,60 @@
+package handler_test
+
+import (
+	"testing"
+
+	"github.com/gookit/goutil/byteutil"
+	"github.com/gookit/goutil/testutil/assert"
+	"github.com/gookit/slog"
+	"github.com/gookit/slog/handler"
+)
+
+func TestSchemaHandler_Handle(t *testing.T) {
+	buf := byteutil.NewBuffer()
+	wh := handler.NewIOWriter(buf, slog.AllLevels)
+	wh.SetFormatter(slog.NewJSONFormatter())
+
+	schema := &handler.Schema{
+		Fields: []handler.FieldRule{
+			{Name: "service", Required: true, Type: "string", Default: "unknown"},
+			{Name: "latency", Type: "int"},
+		},
+		Levels: []slog.Level{slog.InfoLevel, slog.ErrorLevel},
+	}
+
+	h := handler.NewSchemaHandler(wh, schema, handler.SchemaReject)
+	l := slog.NewWithHandlers(h)
+
+	r := newLogRecord("test")
+	r.Fields = slog.M{"latency": "12ms"}
+	assert.Eq(t, []string{
+		`field "service" is required`,
+		`field "latency" must be int, got string`,
+	}, h.Validate(r))
+
+	// reject
+	l.WithField("service", "api").Info("valid message")
+	l.WithField("latency", 12).Info("missing service")
+	l.WithField("service", "api").Warn("level not allowed")
+	assert.Eq(t, uint64(2), h.Rejected())
+	assert.StrContains(t, buf.String(), "valid message")
+	assert.NotContains(t, buf.String(), "missing service")
+
+	// fix
+	buf.Reset()
+	h.Mode = handler.SchemaFix
+	l.WithFields(slog.M{"latency": 12}).Info("fixed message")
+	l.WithFields(slog.M{"service": 23, "latency": "12ms"}).Info("fix partial")
+	str := buf.String()
+	assert.StrContains(t, str, `"service":"unknown"`)
+	assert.StrContains(t, str, `"service":"23"`)
+	assert.StrContains(t, str, `"schema_errors":["field \"latency\" must be int, got string"]`)
+
+	// annotate
+	buf.Reset()
+	h.Mode = handler.SchemaAnnotate
+	l.Warn("annotate message")
+	str = buf.String()
+	assert.StrContains(t, str, "annotate message")
+	assert.StrContains(t, str, `"schema_errors":["level WARN is not allowed","field \"service\" is required"]`)
+}
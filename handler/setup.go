@@ -0,0 +1,43 @@
+package handler
+
+import (
+	"github.com/gookit/slog"
+	"github.com/gookit/slog/rotatefile"
+)
+
+//
+// ---------------------------------------------------------------------------
+// setup funcs for quick configure the std logger. see slog.MustConfigure()
+// ---------------------------------------------------------------------------
+//
+
+// FileSetup create a file handler and add to the std logger
+func FileSetup(logfile string, fns ...ConfigFn) slog.SetupFn {
+	return func(sl *slog.SugaredLogger) error {
+		h, err := NewFileHandler(logfile, fns...)
+		if err != nil {
+			return err
+		}
+
+		sl.AddHandler(h)
+		return nil
+	}
+}
+
+// JSONFileSetup create a file handler with JSON formatter and add to the std logger
+func JSONFileSetup(logfile string, fns ...ConfigFn) slog.SetupFn {
+	return FileSetup(logfile, append(fns, WithUseJSON(true))...)
+}
+
+// RotateFileSetup create a rotate file handler and add to the std logger
+func RotateFileSetup(logfile string, rt rotatefile.RotateTime, fns ...ConfigFn) slog.SetupFn {
+	return func(sl *slog.SugaredLogger) error {
+		h, err := NewRotateFileHandler(logfile, rt, fns...)
+		if err != nil {
+			return err
+		}
+
+		sl.AddHandler(h)
+		return nil
+	}
+}
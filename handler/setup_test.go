@@ -0,0 +1,41 @@
+package handler_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/gookit/goutil/fsutil"
+	"github.com/gookit/goutil/testutil/assert"
+	"github.com/gookit/slog"
+	"github.com/gookit/slog/handler"
+	"github.com/gookit/slog/rotatefile"
+)
+
+func TestMustConfigure_setupFns(t *testing.T) {
+	defer slog.Reset()
+	dir := t.TempDir()
+	logfile := filepath.Join(dir, "setup-file.log")
+	jsonFile := filepath.Join(dir, "setup-file.json")
+	rotateFile := filepath.Join(dir, "setup-rotate-file.log")
+
+	slog.MustConfigure(
+		slog.WithConsoleMaxLevel(slog.InfoLevel),
+		handler.FileSetup(logfile),
+		handler.JSONFileSetup(jsonFile, handler.WithLogLevels(slog.DangerLevels)),
+		handler.RotateFileSetup(rotateFile, rotatefile.EveryDay, handler.WithBuffSize(0)),
+	)
+
+	// std self + 3 handlers
+	assert.Eq(t, 4, slog.Std().HandlersNum())
+	slog.Debug("debug message")
+	slog.Warn("warn message")
+	assert.NoErr(t, slog.Close())
+
+	assert.StrContains(t, fsutil.ReadString(logfile), "[WARN] ")
+	assert.StrContains(t, fsutil.ReadString(jsonFile), `"message":"warn message"`)
+	assert.StrContains(t, fsutil.ReadString(rotateFile), "debug message")
+
+	assert.Panics(t, func() {
+		slog.MustConfigure(handler.FileSetup(""))
+	})
+}
@@ -0,0 +1,77 @@
+package handler
+
+import (
+	"context"
+
+	"github.com/gookit/slog"
+)
+
+// SpanRecorder the span for record log events. eg: wrap the OpenTelemetry trace.Span
+type SpanRecorder interface {
+	// IsRecording returns true if the span is active and recording events.
+	IsRecording() bool
+	// AddEvent add an event with attributes to the span.
+	AddEvent(name string, attrs map[string]any)
+}
+
+// SpanEventHandler when the record context has an active span, record the log as a span event.
+// so traces carry inline log context.
+//
+// Usage with OpenTelemetry:
+//
+//	type otelSpan struct{ trace.Span }
+//
+//	func (s otelSpan) AddEvent(name string, attrs map[string]any) {
+//		kvs := make([]attribute.KeyValue, 0, len(attrs))
+//		for k, v := range attrs {
+//			kvs = append(kvs, attribute.String(k, fmt.Sprint(v)))
+//		}
+//		s.Span.AddEvent(name, trace.WithAttributes(kvs...))
+//	}
+//
+//	h := handler.NewSpanEventHandler(slog.WarnLevel, func(ctx context.Context) handler.SpanRecorder {
+//		return otelSpan{trace.SpanFromContext(ctx)}
+//	})
+type SpanEventHandler struct {
+	NopFlushClose
+	slog.LevelWithFormatter
+	// SpanOf get the span from context.
+	SpanOf func(ctx context.Context) SpanRecorder
+	// EventName for the span event. default is "log"
+	EventName string
+}
+
+// NewSpanEventHandler create a SpanEventHandler, will handle the records level <= maxLevel
+func NewSpanEventHandler(maxLevel slog.Level, spanOf func(ctx context.Context) SpanRecorder) *SpanEventHandler {
+	h := &SpanEventHandler{
+		SpanOf:    spanOf,
+		EventName: "log",
+	}
+	h.Level = maxLevel
+	return h
+}
+
+// Handle log record
+func (h *SpanEventHandler) Handle(r *slog.Record) error {
+	if r.Ctx == nil || h.SpanOf == nil {
+		return nil
+	}
+
+	span := h.SpanOf(r.Ctx)
+	if span == nil || !span.IsRecording() {
+		return nil
+	}
+
+	attrs := make(map[string]any, len(r.Fields)+len(r.Data)+2)
+	for k, v := range r.Data {
+		attrs[k] = v
+	}
+	for k, v := range r.Fields {
+		attrs[k] = v
+	}
+	attrs["log.severity"] = r.LevelName()
+	attrs["log.message"] = r.Message
+
+	span.AddEvent(h.EventName, attrs)
+	return nil
+}
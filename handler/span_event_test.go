@@ -0,0 +1,55 @@
+package handler_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gookit/goutil/testutil/assert"
+	"github.com/gookit/slog"
+	"github.com/gookit/slog/handler"
+)
+
+type testSpanKey struct{}
+
+type testSpan struct {
+	recording bool
+	names     []string
+	events    []map[string]any
+}
+
+func (s *testSpan) IsRecording() bool { return s.recording }
+
+func (s *testSpan) AddEvent(name string, attrs map[string]any) {
+	s.names = append(s.names, name)
+	s.events = append(s.events, attrs)
+}
+
+func TestSpanEventHandler_Handle(t *testing.T) {
+	h := handler.NewSpanEventHandler(slog.WarnLevel, func(ctx context.Context) handler.SpanRecorder {
+		if span, ok := ctx.Value(testSpanKey{}).(*testSpan); ok {
+			return span
+		}
+		return nil
+	})
+	l := slog.NewWithHandlers(h)
+
+	span := &testSpan{recording: true}
+	ctx := context.WithValue(context.Background(), testSpanKey{}, span)
+
+	l.WithContext(ctx).WithField("user", "inhere").Error("error message")
+	l.WithContext(ctx).Info("info message")
+	// no span in context
+	l.WithContext(context.Background()).Warn("warn message")
+	l.Error("no context")
+
+	assert.Len(t, span.events, 1)
+	assert.Eq(t, "log", span.names[0])
+	assert.Eq(t, "ERROR", span.events[0]["log.severity"])
+	assert.Eq(t, "error message", span.events[0]["log.message"])
+	assert.Eq(t, "inhere", span.events[0]["user"])
+
+	// span not recording
+	span.recording = false
+	l.WithContext(ctx).Error("error message2")
+	assert.Len(t, span.events, 1)
+}
@@ -0,0 +1,280 @@
+package handler
+
+import (
+	"context"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gookit/goutil/strutil"
+	"github.com/gookit/slog"
+)
+
+// SyslogFormat the syslog message format
+type SyslogFormat uint8
+
+// syslog message formats
+const (
+	// RFC5424 the modern syslog format, record fields are written as structured data.
+	RFC5424 SyslogFormat = iota
+	// RFC3164 the BSD syslog format, record fields are appended to message as "key=value".
+	RFC3164
+)
+
+// some commonly syslog facilities
+const (
+	FacilityKern   = 0
+	FacilityUser   = 1
+	FacilityDaemon = 3
+	FacilityAuth   = 4
+	FacilityLocal0 = 16
+	FacilityLocal1 = 17
+	FacilityLocal2 = 18
+	FacilityLocal3 = 19
+	FacilityLocal4 = 20
+	FacilityLocal5 = 21
+	FacilityLocal6 = 22
+	FacilityLocal7 = 23
+)
+
+// DefaultSyslogSDID the default SD-ID for the record fields in RFC5424 structured data.
+//
+// 32473 is the private enterprise number reserved for documentation use.
+var DefaultSyslogSDID = "fields@32473"
+
+// local syslog socket paths
+var syslogSocketPaths = []string{"/dev/log", "/var/run/syslog", "/var/run/log"}
+
+// SyslogHandler write the log records to local or remote syslog server, in RFC5424 or RFC3164 format.
+//
+// Unlike SysLogHandler(based on log/syslog), it is pure go implements, and will keep the record fields.
+// The connection is managed by the NetHandler, so it supports reconnect, TLS and re-resolve.
+type SyslogHandler struct {
+	slog.LevelHandling
+	net *NetHandler
+	// Format the message format. default is RFC5424
+	Format SyslogFormat
+	// Facility the syslog facility. default is FacilityUser
+	Facility int
+	// Hostname default is os.Hostname()
+	Hostname string
+	// AppName default is the base name of os.Args[0]
+	AppName string
+	// SDID the SD-ID for the record fields in structured data. default is DefaultSyslogSDID
+	SDID string
+	// OctetCounting use octet counting framing(RFC6587) on stream network. eg: tcp.
+	// default is false, use the newline as trailer.
+	OctetCounting bool
+}
+
+// NewSyslogHandler create new SyslogHandler.
+//
+// If network is empty, will write to the local syslog socket. eg: /dev/log
+//
+// Usage:
+//
+//	// local syslog
+//	h := handler.NewSyslogHandler("", "")
+//	// remote syslog server
+//	h := handler.NewSyslogHandler("udp", "logs.example.com:514", func(h *handler.SyslogHandler) {
+//		h.Facility = handler.FacilityLocal0
+//	})
+func NewSyslogHandler(network, addr string, fns ...func(h *SyslogHandler)) *SyslogHandler {
+	hostname, _ := os.Hostname()
+	h := &SyslogHandler{
+		net:      NewNetHandler(network, addr),
+		Facility: FacilityUser,
+		Hostname: hostname,
+		AppName:  filepath.Base(os.Args[0]),
+		SDID:     DefaultSyslogSDID,
+	}
+	h.SetMaxLevel(slog.InfoLevel)
+
+	if network == "" {
+		h.net.Dial = dialLocalSyslog
+	}
+
+	for _, fn := range fns {
+		fn(h)
+	}
+	return h
+}
+
+// ConfigNet config the underlying NetHandler. eg: set TLSConfig, ResolveInterval
+func (h *SyslogHandler) ConfigNet(fn func(nh *NetHandler)) *SyslogHandler {
+	fn(h.net)
+	return h
+}
+
+// try to connect the local syslog socket
+func dialLocalSyslog(ctx context.Context, _, _ string) (conn net.Conn, err error) {
+	var d net.Dialer
+	for _, network := range []string{"unixgram", "unix"} {
+		for _, path := range syslogSocketPaths {
+			if conn, err = d.DialContext(ctx, network, path); err == nil {
+				return conn, nil
+			}
+		}
+	}
+	return nil, err
+}
+
+// SyslogSeverity get the syslog severity of the level
+func SyslogSeverity(level slog.Level) int {
+	switch level {
+	case slog.PanicLevel:
+		return 0 // emerg
+	case slog.FatalLevel:
+		return 2 // crit
+	case slog.ErrorLevel:
+		return 3
+	case slog.WarnLevel:
+		return 4
+	case slog.NoticeLevel:
+		return 5
+	case slog.InfoLevel:
+		return 6
+	default: // debug, trace
+		return 7
+	}
+}
+
+// Handle a log record
+func (h *SyslogHandler) Handle(r *slog.Record) error {
+	return h.net.send(h.Build(r))
+}
+
+// Build the syslog message for the record, contains the framing.
+func (h *SyslogHandler) Build(r *slog.Record) []byte {
+	var msg []byte
+	if h.Format == RFC3164 {
+		msg = h.build3164(r)
+	} else {
+		msg = h.build5424(r)
+	}
+
+	// framing on stream network
+	switch h.net.Network {
+	case "tcp", "tcp4", "tcp6":
+		if h.OctetCounting {
+			return append([]byte(strconv.Itoa(len(msg))+" "), msg...)
+		}
+		return append(msg, '\n')
+	}
+	return msg
+}
+
+// format: <PRI>1 TIMESTAMP HOSTNAME APP-NAME PROCID MSGID [SD-ID PARAM="VALUE"...] MSG
+func (h *SyslogHandler) build5424(r *slog.Record) []byte {
+	var sb strings.Builder
+	sb.WriteString(h.priority(r.Level))
+	sb.WriteString("1 ")
+	sb.WriteString(r.Time.Format("2006-01-02T15:04:05.000000Z07:00"))
+	sb.WriteByte(' ')
+	sb.WriteString(syslogNil(h.Hostname))
+	sb.WriteByte(' ')
+	sb.WriteString(syslogNil(h.AppName))
+	sb.WriteByte(' ')
+	sb.WriteString(strconv.Itoa(os.Getpid()))
+	sb.WriteByte(' ')
+	sb.WriteString(syslogNil(strings.ReplaceAll(r.Channel, " ", "_")))
+	sb.WriteByte(' ')
+
+	fields := r.MergedFields()
+	if len(fields) == 0 {
+		sb.WriteByte('-')
+	} else {
+		sb.WriteByte('[')
+		sb.WriteString(h.SDID)
+		for _, key := range sortedKeys(fields) {
+			sb.WriteByte(' ')
+			sb.WriteString(sdParamName(key))
+			sb.WriteString(`="`)
+			sb.WriteString(sdParamEscaper.Replace(strutil.SafeString(fields[key])))
+			sb.WriteByte('"')
+		}
+		sb.WriteByte(']')
+	}
+
+	if r.Message != "" {
+		sb.WriteByte(' ')
+		sb.WriteString(r.Message)
+	}
+	return []byte(sb.String())
+}
+
+// format: <PRI>Mmm dd hh:mm:ss HOSTNAME TAG[PID]: MSG key=value ...
+func (h *SyslogHandler) build3164(r *slog.Record) []byte {
+	var sb strings.Builder
+	sb.WriteString(h.priority(r.Level))
+	sb.WriteString(r.Time.Format("Jan _2 15:04:05"))
+	sb.WriteByte(' ')
+	// the local syslog daemon will add the hostname
+	if h.net.Network != "" {
+		sb.WriteString(syslogNil(h.Hostname))
+		sb.WriteByte(' ')
+	}
+	sb.WriteString(h.AppName)
+	sb.WriteString("[" + strconv.Itoa(os.Getpid()) + "]: ")
+	sb.WriteString(r.Message)
+
+	fields := r.MergedFields()
+	for _, key := range sortedKeys(fields) {
+		sb.WriteByte(' ')
+		sb.WriteString(key)
+		sb.WriteByte('=')
+		sb.WriteString(strutil.SafeString(fields[key]))
+	}
+	return []byte(sb.String())
+}
+
+func (h *SyslogHandler) priority(level slog.Level) string {
+	return "<" + strconv.Itoa(h.Facility*8+SyslogSeverity(level)) + ">"
+}
+
+// Flush logs. the records are written directly, do nothing.
+func (h *SyslogHandler) Flush() error {
+	return nil
+}
+
+// Close the connection
+func (h *SyslogHandler) Close() error {
+	return h.net.Close()
+}
+
+// the NILVALUE "-" for empty header field
+func syslogNil(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
+var sdParamEscaper = strings.NewReplacer(`"`, `\"`, `\`, `\\`, `]`, `\]`)
+
+// SD-NAME: max 32 printable US-ASCII, except '=', SP, ']', '"'
+func sdParamName(key string) string {
+	bs := []byte(key)
+	for i, c := range bs {
+		if c <= ' ' || c >= 127 || c == '=' || c == ']' || c == '"' {
+			bs[i] = '_'
+		}
+	}
+
+	if len(bs) > 32 {
+		bs = bs[:32]
+	}
+	return string(bs)
+}
+
+func sortedKeys(m slog.M) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
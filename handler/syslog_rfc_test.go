@@ -0,0 +1,68 @@
+package handler_test
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gookit/goutil/testutil/assert"
+	"github.com/gookit/slog"
+	"github.com/gookit/slog/handler"
+)
+
+func TestSyslogHandler_RFC5424(t *testing.T) {
+	addr, lines := newTCPServer(t)
+
+	h := handler.NewSyslogHandler("tcp", addr, func(h *handler.SyslogHandler) {
+		h.Hostname = "host1"
+		h.AppName = "app1"
+		h.Facility = handler.FacilityLocal0
+	})
+	assert.False(t, h.IsHandling(slog.DebugLevel))
+
+	l := slog.NewWithHandlers(h)
+	l.WithFields(slog.M{"user": "inhere", "bad key": `a"b]`}).Warn("syslog message")
+
+	line := <-lines
+	assert.StrContains(t, line, "<132>1 ")
+	assert.StrContains(t, line, " host1 app1 ")
+	assert.StrContains(t, line, ` application [fields@32473 bad_key="a\"b\]" user="inhere"] syslog message`)
+	assert.NoErr(t, h.Flush())
+	assert.NoErr(t, h.Close())
+}
+
+func TestSyslogHandler_Build(t *testing.T) {
+	r := &slog.Record{
+		Time:    time.Now(),
+		Level:   slog.ErrorLevel,
+		Channel: "app",
+		Message: "syslog message",
+		Fields:  slog.M{"key": "value"},
+	}
+
+	h := handler.NewSyslogHandler("udp", "127.0.0.1:514", func(h *handler.SyslogHandler) {
+		h.Format = handler.RFC3164
+		h.Hostname = "host1"
+		h.AppName = "app1"
+	})
+
+	msg := string(h.Build(r))
+	assert.True(t, strings.HasPrefix(msg, "<11>"))
+	assert.StrContains(t, msg, " host1 app1[")
+	assert.True(t, strings.HasSuffix(msg, "]: syslog message key=value"))
+
+	// octet counting framing
+	h = handler.NewSyslogHandler("tcp", "127.0.0.1:514", func(h *handler.SyslogHandler) {
+		h.OctetCounting = true
+	})
+	r.Fields = nil
+	r.Channel = ""
+	msg = string(h.Build(r))
+	n, body, _ := strings.Cut(msg, " ")
+	assert.Eq(t, n, strconv.Itoa(len(body)))
+	assert.StrContains(t, body, " - - syslog message")
+
+	assert.Eq(t, 0, handler.SyslogSeverity(slog.PanicLevel))
+	assert.Eq(t, 7, handler.SyslogSeverity(slog.TraceLevel))
+}
@@ -0,0 +1,44 @@
+package handler
+
+import "github.com/gookit/slog"
+
+// TagFilterHandler wrap a handler, filter the records by Record.Tags
+type TagFilterHandler struct {
+	slog.Handler
+	// Tags for match the records
+	Tags []string
+	// Exclude the records has any of the tags. default is only handle the records has any of the tags.
+	Exclude bool
+}
+
+// NewTagFilter create a handler, only handle the records has any of the tags.
+//
+// Usage:
+//
+//	h := handler.NewTagFilter(securityFileHandler, "security", "audit")
+//	logger.WithTags("security").Warn("login failed")
+func NewTagFilter(h slog.Handler, tags ...string) *TagFilterHandler {
+	return &TagFilterHandler{Handler: h, Tags: tags}
+}
+
+// ExcludeTags create a handler, will skip the records has any of the tags.
+func ExcludeTags(h slog.Handler, tags ...string) *TagFilterHandler {
+	return &TagFilterHandler{Handler: h, Tags: tags, Exclude: true}
+}
+
+// Handle a log record
+func (h *TagFilterHandler) Handle(r *slog.Record) error {
+	if h.matchTags(r) == h.Exclude {
+		return nil
+	}
+	return h.Handler.Handle(r)
+}
+
+func (h *TagFilterHandler) matchTags(r *slog.Record) bool {
+	for _, tag := range h.Tags {
+		if r.HasTag(tag) {
+			return true
+		}
+	}
+	return false
+}
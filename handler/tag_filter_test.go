@@ -0,0 +1,32 @@
+package handler_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/gookit/goutil/testutil/assert"
+	"github.com/gookit/slog"
+	"github.com/gookit/slog/handler"
+)
+
+func TestNewTagFilter(t *testing.T) {
+	secBuf := new(bytes.Buffer)
+	appBuf := new(bytes.Buffer)
+
+	l := slog.NewWithHandlers(
+		handler.NewTagFilter(handler.NewIOWriter(secBuf, slog.AllLevels), "security", "audit"),
+		handler.ExcludeTags(handler.NewIOWriter(appBuf, slog.AllLevels), "security"),
+	)
+
+	l.WithTags("security").Warn("login failed")
+	l.WithTags("audit", "billing").Info("invoice changed")
+	l.Info("normal message")
+
+	assert.StrContains(t, secBuf.String(), "login failed")
+	assert.StrContains(t, secBuf.String(), "invoice changed")
+	assert.NotContains(t, secBuf.String(), "normal message")
+
+	assert.NotContains(t, appBuf.String(), "login failed")
+	assert.StrContains(t, appBuf.String(), "invoice changed")
+	assert.StrContains(t, appBuf.String(), "normal message")
+}
@@ -0,0 +1,247 @@
+package handler
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/gookit/slog"
+)
+
+const (
+	// TenantMaxKeys max tenants of the dropped stats, avoid unbounded memory growth.
+	// on reached, keep the top half tenants by count, the others are merged to TenantOverflowKey.
+	TenantMaxKeys = slog.SampleMaxKeys
+	// TenantOverflowKey the tenant key for the merged dropped count. see TenantMaxKeys
+	TenantOverflowKey = slog.SampleOverflowKey
+)
+
+// TenantHandler limit the log records by tenant, the tenant ID is read from the record field.
+//
+//   - per-tenant rate/volume quotas in a time window, so one noisy tenant can't crowd out others' logs.
+//   - optional per-tenant routing to a dedicated handler.
+type TenantHandler struct {
+	mu sync.Mutex
+	// Key the field name for get tenant ID from Record.Fields, Record.Data
+	Key string
+	// Window duration for quota counting. default is 1s
+	Window time.Duration
+	// MaxRecords max records of a tenant in a window. 0 is not limit
+	MaxRecords int
+	// MaxBytes max message bytes of a tenant in a window. 0 is not limit
+	MaxBytes int
+	// Handler the default handler for all tenants
+	Handler slog.Handler
+
+	routeMu sync.RWMutex
+	routes  map[string]slog.Handler
+	quotas  map[string]*tenantQuota
+	dropped map[string]uint64
+	// last time for evict the idle tenant quotas
+	evicted time.Time
+}
+
+type tenantQuota struct {
+	start   time.Time
+	records int
+	bytes   int
+}
+
+// NewTenantHandler create new TenantHandler
+//
+// Usage:
+//
+//	h := handler.NewTenantHandler("tenant_id", fileHandler, func(h *handler.TenantHandler) {
+//		h.MaxRecords = 100
+//	})
+//	h.Route("vip", vipHandler)
+func NewTenantHandler(key string, h slog.Handler, fns ...func(h *TenantHandler)) *TenantHandler {
+	th := &TenantHandler{
+		Key:     key,
+		Window:  time.Second,
+		Handler: h,
+		routes:  make(map[string]slog.Handler),
+		quotas:  make(map[string]*tenantQuota),
+		dropped: make(map[string]uint64),
+	}
+
+	for _, fn := range fns {
+		fn(th)
+	}
+	return th
+}
+
+// Route records of the tenant to a dedicated handler
+func (h *TenantHandler) Route(tenant string, th slog.Handler) *TenantHandler {
+	h.routeMu.Lock()
+	h.routes[tenant] = th
+	h.routeMu.Unlock()
+	return h
+}
+
+// Dropped get the dropped count by tenant.
+//
+// The tenants are limited by TenantMaxKeys, the less dropped tenants are merged to TenantOverflowKey.
+func (h *TenantHandler) Dropped() map[string]uint64 {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	mp := make(map[string]uint64, len(h.dropped))
+	for k, n := range h.dropped {
+		mp[k] = n
+	}
+	return mp
+}
+
+// Active get the number of tenants that have a quota in the current window
+func (h *TenantHandler) Active() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return len(h.quotas)
+}
+
+// TenantOf get tenant ID from the record
+func (h *TenantHandler) TenantOf(r *slog.Record) string {
+	val := r.Field(h.Key)
+	if val == nil {
+		val = r.Value(h.Key)
+	}
+
+	if val == nil {
+		return ""
+	}
+	return fmt.Sprint(val)
+}
+
+// IsHandling Check if the current level can be handling
+func (h *TenantHandler) IsHandling(level slog.Level) bool {
+	if h.Handler != nil && h.Handler.IsHandling(level) {
+		return true
+	}
+
+	h.routeMu.RLock()
+	defer h.routeMu.RUnlock()
+	for _, rh := range h.routes {
+		if rh.IsHandling(level) {
+			return true
+		}
+	}
+	return false
+}
+
+// Handle a log record
+func (h *TenantHandler) Handle(r *slog.Record) error {
+	tenant := h.TenantOf(r)
+
+	h.routeMu.RLock()
+	dst, ok := h.routes[tenant]
+	h.routeMu.RUnlock()
+	if !ok {
+		dst = h.Handler
+	}
+
+	// check the level first, the discarded records should not use up the quota
+	if dst == nil || !dst.IsHandling(r.Level) {
+		return nil
+	}
+
+	if !h.allow(tenant, len(r.Message), r.Time) {
+		return nil
+	}
+	return dst.Handle(r)
+}
+
+// check the tenant quota
+func (h *TenantHandler) allow(tenant string, size int, now time.Time) bool {
+	if h.MaxRecords <= 0 && h.MaxBytes <= 0 {
+		return true
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	// evict the idle tenants on the window rolls, avoid the quotas growing without bound
+	if now.Sub(h.evicted) >= h.Window {
+		for id, q := range h.quotas {
+			if now.Sub(q.start) >= h.Window {
+				delete(h.quotas, id)
+			}
+		}
+		h.evicted = now
+	}
+
+	q, ok := h.quotas[tenant]
+	if !ok || now.Sub(q.start) >= h.Window {
+		q = &tenantQuota{start: now}
+		h.quotas[tenant] = q
+	}
+
+	if (h.MaxRecords > 0 && q.records >= h.MaxRecords) || (h.MaxBytes > 0 && q.bytes+size > h.MaxBytes) {
+		h.drop(tenant)
+		return false
+	}
+
+	q.records++
+	q.bytes += size
+	return true
+}
+
+// count the dropped record, compact the tenants on reached TenantMaxKeys.
+func (h *TenantHandler) drop(tenant string) {
+	if _, ok := h.dropped[tenant]; !ok && len(h.dropped) >= TenantMaxKeys {
+		// keep the top half tenants by count, merge the others to TenantOverflowKey.
+		keys := make([]string, 0, len(h.dropped))
+		for key := range h.dropped {
+			if key != TenantOverflowKey {
+				keys = append(keys, key)
+			}
+		}
+
+		sort.Slice(keys, func(i, j int) bool {
+			return h.dropped[keys[i]] > h.dropped[keys[j]]
+		})
+
+		for _, key := range keys[TenantMaxKeys/2:] {
+			h.dropped[TenantOverflowKey] += h.dropped[key]
+			delete(h.dropped, key)
+		}
+	}
+	h.dropped[tenant]++
+}
+
+// Flush all handlers
+func (h *TenantHandler) Flush() error {
+	return h.visit(func(sh slog.Handler) error {
+		return sh.Flush()
+	})
+}
+
+// Close all handlers
+func (h *TenantHandler) Close() error {
+	return h.visit(func(sh slog.Handler) error {
+		return sh.Close()
+	})
+}
+
+func (h *TenantHandler) visit(fn func(sh slog.Handler) error) error {
+	if h.Handler != nil {
+		if err := fn(h.Handler); err != nil {
+			return err
+		}
+	}
+
+	h.routeMu.RLock()
+	routes := make([]slog.Handler, 0, len(h.routes))
+	for _, rh := range h.routes {
+		routes = append(routes, rh)
+	}
+	h.routeMu.RUnlock()
+
+	for _, rh := range routes {
+		if err := fn(rh); err != nil {
+			return err
+		}
+	}
+	return nil
+}
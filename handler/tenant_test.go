@@ -0,0 +1,157 @@
+package handler_test
+
+import (
+	"bytes"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gookit/goutil/testutil/assert"
+	"github.com/gookit/slog"
+	"github.com/gookit/slog/handler"
+)
+
+func TestTenantHandler_quota(t *testing.T) {
+	buf := new(bytes.Buffer)
+	vipBuf := new(bytes.Buffer)
+
+	h := handler.NewTenantHandler("tenant", handler.NewIOWriter(buf, slog.AllLevels), func(h *handler.TenantHandler) {
+		h.MaxRecords = 2
+		h.Window = time.Minute
+	})
+	h.Route("vip", handler.NewIOWriter(vipBuf, slog.AllLevels))
+	assert.True(t, h.IsHandling(slog.InfoLevel))
+
+	l := slog.NewWithHandlers(h)
+	for i := 0; i < 5; i++ {
+		l.WithField("tenant", "noisy").Info("noisy message")
+		l.WithField("tenant", "vip").Info("vip message")
+	}
+	l.WithData(slog.M{"tenant": "other"}).Info("other message")
+
+	assert.Eq(t, 2, bytes.Count(buf.Bytes(), []byte("noisy message")))
+	assert.Eq(t, 1, bytes.Count(buf.Bytes(), []byte("other message")))
+	assert.Eq(t, 2, bytes.Count(vipBuf.Bytes(), []byte("vip message")))
+	assert.Eq(t, map[string]uint64{"noisy": 3, "vip": 3}, h.Dropped())
+
+	assert.NoErr(t, h.Flush())
+	assert.NoErr(t, h.Close())
+}
+
+func TestTenantHandler_maxBytes(t *testing.T) {
+	buf := new(bytes.Buffer)
+	h := handler.NewTenantHandler("tenant", handler.NewIOWriter(buf, slog.AllLevels), func(h *handler.TenantHandler) {
+		h.MaxBytes = 10
+	})
+
+	r := newLogRecord("0123456789")
+	r.AddField("tenant", "t1")
+	assert.Eq(t, "t1", h.TenantOf(r))
+	assert.NoErr(t, h.Handle(r))
+	assert.NoErr(t, h.Handle(r))
+	assert.Eq(t, 1, bytes.Count(buf.Bytes(), []byte("0123456789")))
+	assert.Eq(t, uint64(1), h.Dropped()["t1"])
+
+	// no tenant
+	assert.Eq(t, "", h.TenantOf(newLogRecord("hi")))
+}
+
+func TestTenantHandler_evictIdle(t *testing.T) {
+	buf := new(bytes.Buffer)
+	h := handler.NewTenantHandler("tenant", handler.NewIOWriter(buf, slog.AllLevels), func(h *handler.TenantHandler) {
+		h.MaxRecords = 1
+		h.Window = time.Minute
+	})
+
+	now := time.Now()
+	for _, tenant := range []string{"t1", "t2", "t3"} {
+		r := newLogRecord("message")
+		r.Time = now
+		r.AddField("tenant", tenant)
+		assert.NoErr(t, h.Handle(r))
+	}
+	assert.Eq(t, 3, h.Active())
+
+	// the window rolls, the idle tenants are evicted
+	r := newLogRecord("message")
+	r.Time = now.Add(time.Minute)
+	r.AddField("tenant", "t1")
+	assert.NoErr(t, h.Handle(r))
+	assert.Eq(t, 1, h.Active())
+	assert.Eq(t, 4, bytes.Count(buf.Bytes(), []byte("message")))
+}
+
+func TestTenantHandler_droppedMaxKeys(t *testing.T) {
+	h := handler.NewTenantHandler("tenant", handler.NewIOWriter(new(bytes.Buffer), slog.AllLevels), func(h *handler.TenantHandler) {
+		h.MaxRecords = 1
+		h.Window = time.Minute
+	})
+
+	now := time.Now()
+	for i := 0; i < handler.TenantMaxKeys+10; i++ {
+		r := newLogRecord("message")
+		r.Time = now
+		r.AddField("tenant", "t"+strconv.Itoa(i))
+		// the second record is dropped
+		assert.NoErr(t, h.Handle(r))
+		assert.NoErr(t, h.Handle(r))
+	}
+
+	dropped := h.Dropped()
+	assert.True(t, len(dropped) <= handler.TenantMaxKeys)
+	assert.True(t, dropped[handler.TenantOverflowKey] > 0)
+
+	var total uint64
+	for _, n := range dropped {
+		total += n
+	}
+	assert.Eq(t, uint64(handler.TenantMaxKeys+10), total)
+}
+
+func TestTenantHandler_levelBeforeQuota(t *testing.T) {
+	buf := new(bytes.Buffer)
+	h := handler.NewTenantHandler("tenant", handler.NewIOWriter(buf, []slog.Level{slog.ErrorLevel}), func(h *handler.TenantHandler) {
+		h.MaxRecords = 1
+		h.Window = time.Minute
+	})
+
+	// the discarded debug records not use up the quota
+	for i := 0; i < 3; i++ {
+		r := newLogRecord("debug message")
+		r.Level = slog.DebugLevel
+		r.AddField("tenant", "t1")
+		assert.NoErr(t, h.Handle(r))
+	}
+
+	r := newLogRecord("error message")
+	r.Level = slog.ErrorLevel
+	r.AddField("tenant", "t1")
+	assert.NoErr(t, h.Handle(r))
+	assert.StrContains(t, buf.String(), "error message")
+	assert.Empty(t, h.Dropped())
+}
+
+func TestTenantHandler_routeConcurrent(t *testing.T) {
+	h := handler.NewTenantHandler("tenant", handler.NewIOWriter(new(bytes.Buffer), slog.AllLevels))
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 100; i++ {
+			h.Route("t"+strconv.Itoa(i), handler.NewIOWriter(new(bytes.Buffer), slog.AllLevels))
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 100; i++ {
+			r := newLogRecord("message")
+			r.AddField("tenant", "t"+strconv.Itoa(i))
+			assert.NoErr(t, h.Handle(r))
+			h.IsHandling(slog.InfoLevel)
+		}
+	}()
+	wg.Wait()
+	assert.NoErr(t, h.Flush())
+}
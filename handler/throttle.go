@@ -0,0 +1,94 @@
+package handler
+
+import (
+	"io"
+	"sync"
+	"time"
+)
+
+// Throttle limit the bandwidth by bytes per second. it is a token bucket.
+//
+// Use for the shipping handlers, so bulk log flushing after an outage doesn't saturate
+// a constrained uplink. see NetHandler.Throttle, HTTPHandler.Throttle
+type Throttle struct {
+	mu sync.Mutex
+	// rate bytes per second
+	rate  float64
+	burst float64
+	// available bytes, will be negative on waiting
+	tokens float64
+	last   time.Time
+}
+
+// NewThrottle create new Throttle. burst is the max bytes can be sent at once, default is equals rate.
+//
+// Usage:
+//
+//	// limit to 512 KB/s
+//	h.Throttle = handler.NewThrottle(512*1024, 0)
+func NewThrottle(bytesPerSec, burst int) *Throttle {
+	if burst <= 0 {
+		burst = bytesPerSec
+	}
+
+	return &Throttle{
+		rate:   float64(bytesPerSec),
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+	}
+}
+
+// Burst get the max bytes can be sent at once
+func (t *Throttle) Burst() int {
+	return int(t.burst)
+}
+
+// Wait until n bytes can be sent.
+func (t *Throttle) Wait(n int) {
+	if d := t.reserve(n); d > 0 {
+		time.Sleep(d)
+	}
+}
+
+// reserve n bytes, returns the wait duration
+func (t *Throttle) reserve(n int) time.Duration {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := time.Now()
+	t.tokens += now.Sub(t.last).Seconds() * t.rate
+	if t.tokens > t.burst {
+		t.tokens = t.burst
+	}
+	t.last = now
+
+	t.tokens -= float64(n)
+	if t.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-t.tokens / t.rate * float64(time.Second))
+}
+
+// Reader wrap the reader, the read speed will be limited by the throttle.
+func (t *Throttle) Reader(r io.Reader) io.Reader {
+	return &throttledReader{r: r, t: t}
+}
+
+type throttledReader struct {
+	r io.Reader
+	t *Throttle
+}
+
+func (tr *throttledReader) Read(p []byte) (int, error) {
+	// read at most burst bytes at once
+	if burst := tr.t.Burst(); burst > 0 && len(p) > burst {
+		p = p[:burst]
+	}
+
+	n, err := tr.r.Read(p)
+	if n > 0 {
+		tr.t.Wait(n)
+	}
+	return n, err
+}
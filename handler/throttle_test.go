@@ -0,0 +1,62 @@
+package handler_test
+
+import (
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gookit/goutil/testutil/assert"
+	"github.com/gookit/slog/handler"
+)
+
+func TestThrottle_Wait(t *testing.T) {
+	th := handler.NewThrottle(1000, 100)
+	assert.Eq(t, 100, th.Burst())
+
+	start := time.Now()
+	th.Wait(100)
+	assert.True(t, time.Since(start) < 20*time.Millisecond)
+
+	// no tokens, wait 50ms for 50 bytes
+	th.Wait(50)
+	assert.True(t, time.Since(start) >= 40*time.Millisecond)
+
+	// burst default equals rate
+	assert.Eq(t, 1000, handler.NewThrottle(1000, 0).Burst())
+}
+
+func TestThrottle_Reader(t *testing.T) {
+	th := handler.NewThrottle(2000, 100)
+	data := strings.Repeat("a", 300)
+
+	start := time.Now()
+	bs, err := io.ReadAll(th.Reader(strings.NewReader(data)))
+	assert.NoErr(t, err)
+	assert.Eq(t, data, string(bs))
+	// 100 bytes burst, then 200 bytes on 2000 B/s
+	assert.True(t, time.Since(start) >= 80*time.Millisecond)
+}
+
+func TestHTTPHandler_Throttle(t *testing.T) {
+	tc, srv := newTestCollector()
+	defer srv.Close()
+
+	h := handler.NewHTTPHandler(srv.URL, func(h *handler.HTTPHandler) {
+		h.Throttle = handler.NewThrottle(100*1024, 64)
+	})
+
+	assert.NoErr(t, h.Handle(newLogRecord("throttle message")))
+	assert.StrContains(t, tc.bodies[0], "throttle message")
+}
+
+func TestNetHandler_Throttle(t *testing.T) {
+	addr, lines := newTCPServer(t)
+	h := handler.NewNetHandler("tcp", addr, func(h *handler.NetHandler) {
+		h.Throttle = handler.NewThrottle(100*1024, 0)
+	})
+
+	assert.NoErr(t, h.Handle(newLogRecord("throttle message")))
+	assert.StrContains(t, <-lines, "throttle message")
+	assert.NoErr(t, h.Close())
+}
@@ -0,0 +1,118 @@
+package handler
+
+import (
+	"crypto/tls"
+	"os"
+	"sync"
+	"time"
+)
+
+// CertReloader load the client certificate from files, and reload it on the files changed.
+//
+// The certificate is checked on each TLS handshake, so the new connections will use the
+// rotated identity, and the existing connections are not dropped.
+// useful for the short-lived certs. eg: SPIFFE, cert-manager
+type CertReloader struct {
+	mu sync.Mutex
+	// CertFile and KeyFile the PEM encoded cert and key file path
+	CertFile, KeyFile string
+	// CheckInterval min interval for check the files changed. 0 is check on each handshake.
+	CheckInterval time.Duration
+	// OnError on reload failed, will keep use the old certificate. default will print to stderr.
+	OnError func(err error)
+
+	cert    *tls.Certificate
+	modTime time.Time
+	checked time.Time
+}
+
+// NewCertReloader create new CertReloader and load the certificate.
+//
+// Usage:
+//
+//	cr, err := handler.NewCertReloader("client.crt", "client.key")
+//	h := handler.NewNetHandler("tcp", "logs.example.com:6514", func(h *handler.NetHandler) {
+//		h.TLSConfig = cr.TLSConfig(&tls.Config{RootCAs: pool})
+//	})
+func NewCertReloader(certFile, keyFile string, fns ...func(cr *CertReloader)) (*CertReloader, error) {
+	cr := &CertReloader{CertFile: certFile, KeyFile: keyFile}
+	for _, fn := range fns {
+		fn(cr)
+	}
+
+	if err := cr.Reload(); err != nil {
+		return nil, err
+	}
+	return cr, nil
+}
+
+// Reload the certificate from files
+func (cr *CertReloader) Reload() error {
+	cr.mu.Lock()
+	defer cr.mu.Unlock()
+
+	return cr.load(cr.lastModTime())
+}
+
+func (cr *CertReloader) load(modTime time.Time) error {
+	cert, err := tls.LoadX509KeyPair(cr.CertFile, cr.KeyFile)
+	if err != nil {
+		return err
+	}
+
+	cr.cert = &cert
+	cr.modTime = modTime
+	cr.checked = time.Now()
+	return nil
+}
+
+// get the latest mod time of the cert and key files
+func (cr *CertReloader) lastModTime() time.Time {
+	var mt time.Time
+	for _, fpath := range []string{cr.CertFile, cr.KeyFile} {
+		if fi, err := os.Stat(fpath); err == nil && fi.ModTime().After(mt) {
+			mt = fi.ModTime()
+		}
+	}
+	return mt
+}
+
+// Certificate get the current certificate, will reload it on the files changed.
+func (cr *CertReloader) Certificate() *tls.Certificate {
+	cr.mu.Lock()
+	defer cr.mu.Unlock()
+
+	if time.Since(cr.checked) < cr.CheckInterval {
+		return cr.cert
+	}
+
+	cr.checked = time.Now()
+	if mt := cr.lastModTime(); !mt.Equal(cr.modTime) {
+		if err := cr.load(mt); err != nil {
+			if cr.OnError != nil {
+				cr.OnError(err)
+			} else {
+				reportError("slog: reload the tls certificate error:", err)
+			}
+		}
+	}
+	return cr.cert
+}
+
+// GetClientCertificate implements the tls.Config.GetClientCertificate
+func (cr *CertReloader) GetClientCertificate(_ *tls.CertificateRequestInfo) (*tls.Certificate, error) {
+	return cr.Certificate(), nil
+}
+
+// TLSConfig clone the base config(can be nil), and set the GetClientCertificate by the reloader.
+func (cr *CertReloader) TLSConfig(base *tls.Config) *tls.Config {
+	var cfg *tls.Config
+	if base != nil {
+		cfg = base.Clone()
+	} else {
+		cfg = &tls.Config{MinVersion: tls.VersionTLS12}
+	}
+
+	cfg.GetClientCertificate = cr.GetClientCertificate
+	return cfg
+}
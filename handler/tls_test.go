@@ -0,0 +1,141 @@
+package handler_test
+
+import (
+	"bufio"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gookit/goutil/testutil/assert"
+	"github.com/gookit/slog/handler"
+)
+
+// create a cert signed by the parent. if parent is nil, create a self-signed CA.
+func newTestCert(t *testing.T, cn string, parent *tls.Certificate) tls.Certificate {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoErr(t, err)
+
+	tpl := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: cn},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+
+	parentTpl, parentKey := tpl, any(key)
+	if parent == nil {
+		tpl.IsCA = true
+		tpl.BasicConstraintsValid = true
+		tpl.KeyUsage = x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature
+	} else {
+		parentTpl = parent.Leaf
+		parentKey = parent.PrivateKey
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, tpl, parentTpl, &key.PublicKey, parentKey)
+	assert.NoErr(t, err)
+	leaf, err := x509.ParseCertificate(der)
+	assert.NoErr(t, err)
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key, Leaf: leaf}
+}
+
+func writeTestCert(t *testing.T, cert tls.Certificate, certFile, keyFile string, modTime time.Time) {
+	keyDer, err := x509.MarshalECPrivateKey(cert.PrivateKey.(*ecdsa.PrivateKey))
+	assert.NoErr(t, err)
+
+	certPem := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Certificate[0]})
+	keyPem := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer})
+	assert.NoErr(t, os.WriteFile(certFile, certPem, 0600))
+	assert.NoErr(t, os.WriteFile(keyFile, keyPem, 0600))
+	assert.NoErr(t, os.Chtimes(certFile, modTime, modTime))
+	assert.NoErr(t, os.Chtimes(keyFile, modTime, modTime))
+}
+
+func TestNetHandler_TLSCertReload(t *testing.T) {
+	ca := newTestCert(t, "test-ca", nil)
+	pool := x509.NewCertPool()
+	pool.AddCert(ca.Leaf)
+
+	// start a mTLS server, received lines are prefixed with the client CN
+	ln, err := tls.Listen("tcp", "127.0.0.1:0", &tls.Config{
+		Certificates: []tls.Certificate{newTestCert(t, "server", &ca)},
+		ClientCAs:    pool,
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+	})
+	assert.NoErr(t, err)
+	defer ln.Close()
+
+	lines := make(chan string, 10)
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+
+			go func() {
+				defer conn.Close()
+				tc := conn.(*tls.Conn)
+				if tc.Handshake() != nil {
+					return
+				}
+
+				cn := tc.ConnectionState().PeerCertificates[0].Subject.CommonName
+				sc := bufio.NewScanner(conn)
+				for sc.Scan() {
+					lines <- cn + ": " + sc.Text()
+				}
+			}()
+		}
+	}()
+
+	dir := t.TempDir()
+	certFile, keyFile := filepath.Join(dir, "client.crt"), filepath.Join(dir, "client.key")
+	writeTestCert(t, newTestCert(t, "client1", &ca), certFile, keyFile, time.Now().Add(-time.Minute))
+
+	cr, err := handler.NewCertReloader(certFile, keyFile)
+	assert.NoErr(t, err)
+
+	h := handler.NewNetHandler("tcp", ln.Addr().String(), func(h *handler.NetHandler) {
+		h.TLSConfig = cr.TLSConfig(&tls.Config{RootCAs: pool})
+	})
+
+	assert.NoErr(t, h.Handle(newLogRecord("tls message1")))
+	assert.StrContains(t, <-lines, "client1: ")
+
+	// rotate the client certificate, then reconnect
+	writeTestCert(t, newTestCert(t, "client2", &ca), certFile, keyFile, time.Now())
+	assert.NoErr(t, h.Close())
+	assert.NoErr(t, h.Handle(newLogRecord("tls message2")))
+	assert.StrContains(t, <-lines, "client2: ")
+
+	// bad files, keep the old certificate
+	var reloadErr error
+	cr.OnError = func(err error) { reloadErr = err }
+	assert.NoErr(t, os.WriteFile(keyFile, []byte("invalid"), 0600))
+	assert.NoErr(t, os.Chtimes(keyFile, time.Now().Add(time.Minute), time.Now().Add(time.Minute)))
+	assert.Eq(t, "client2", mustParseLeaf(t, cr.Certificate()).Subject.CommonName)
+	assert.Err(t, reloadErr)
+	assert.NoErr(t, h.Close())
+
+	_, err = handler.NewCertReloader(certFile, keyFile)
+	assert.Err(t, err)
+}
+
+func mustParseLeaf(t *testing.T, cert *tls.Certificate) *x509.Certificate {
+	leaf, err := x509.ParseCertificate(cert.Certificate[0])
+	assert.NoErr(t, err)
+	return leaf
+}
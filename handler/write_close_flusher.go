@@ -6,8 +6,12 @@ import (
 
 // FlushCloseHandler definition
 type FlushCloseHandler struct {
+	NameTrait
 	slog.LevelFormattable
 	Output FlushCloseWriter
+	// FlushOnLevel flush the buffered output immediately on handle a record level <= FlushOnLevel.
+	// 0 is disable. eg: slog.ErrorLevel
+	FlushOnLevel slog.Level
 }
 
 // NewFlushCloserWithLF create new FlushCloseHandler, with custom slog.LevelFormattable
@@ -19,6 +23,11 @@ func NewFlushCloserWithLF(out FlushCloseWriter, lf slog.LevelFormattable) *Flush
 	}
 }
 
+// SetMaxLevel set max level for log message, can be changed at runtime. see slog.NewLevelServer()
+func (h *FlushCloseHandler) SetMaxLevel(maxLv slog.Level) {
+	setMaxLevel(&h.LevelFormattable, maxLv)
+}
+
 //
 // ------------- Use max log level -------------
 //
@@ -75,6 +84,12 @@ func (h *FlushCloseHandler) Handle(record *slog.Record) error {
 		return err
 	}
 
-	_, err = h.Output.Write(bts)
-	return err
+	if _, err = h.Output.Write(bts); err != nil {
+		return err
+	}
+
+	if h.FlushOnLevel > 0 && record.Level <= h.FlushOnLevel {
+		return h.Flush()
+	}
+	return nil
 }
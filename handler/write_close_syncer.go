@@ -8,8 +8,12 @@ import (
 
 // SyncCloseHandler definition
 type SyncCloseHandler struct {
+	NameTrait
 	slog.LevelFormattable
 	Output SyncCloseWriter
+	// FlushOnLevel flush the buffered output immediately on handle a record level <= FlushOnLevel.
+	// 0 is disable. eg: slog.ErrorLevel
+	FlushOnLevel slog.Level
 }
 
 // NewSyncCloserWithLF create new SyncCloseHandler, with custom slog.LevelFormattable
@@ -21,6 +25,11 @@ func NewSyncCloserWithLF(out SyncCloseWriter, lf slog.LevelFormattable) *SyncClo
 	}
 }
 
+// SetMaxLevel set max level for log message, can be changed at runtime. see slog.NewLevelServer()
+func (h *SyncCloseHandler) SetMaxLevel(maxLv slog.Level) {
+	setMaxLevel(&h.LevelFormattable, maxLv)
+}
+
 //
 // ------------- Use max log level -------------
 //
@@ -67,6 +76,14 @@ func (h *SyncCloseHandler) Flush() error {
 	return h.Output.Sync()
 }
 
+// Reopen the output file, on the output implements slog.Reopener. see slog.Logger.ReopenAll()
+func (h *SyncCloseHandler) Reopen() error {
+	if ro, ok := h.Output.(slog.Reopener); ok {
+		return ro.Reopen()
+	}
+	return nil
+}
+
 // Writer of the handler
 func (h *SyncCloseHandler) Writer() io.Writer {
 	return h.Output
@@ -79,6 +96,12 @@ func (h *SyncCloseHandler) Handle(record *slog.Record) error {
 		return err
 	}
 
-	_, err = h.Output.Write(bts)
-	return err
+	if _, err = h.Output.Write(bts); err != nil {
+		return err
+	}
+
+	if h.FlushOnLevel > 0 && record.Level <= h.FlushOnLevel {
+		return h.Flush()
+	}
+	return nil
 }
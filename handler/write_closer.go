@@ -8,6 +8,7 @@ import (
 
 // WriteCloserHandler definition
 type WriteCloserHandler struct {
+	NameTrait
 	slog.LevelFormattable
 	Output io.WriteCloser
 }
@@ -26,6 +27,11 @@ func WriteCloserWithMaxLevel(out io.WriteCloser, maxLevel slog.Level) *WriteClos
 	return NewWriteCloserWithLF(out, slog.NewLvFormatter(maxLevel))
 }
 
+// SetMaxLevel set max level for log message, can be changed at runtime. see slog.NewLevelServer()
+func (h *WriteCloserHandler) SetMaxLevel(maxLv slog.Level) {
+	setMaxLevel(&h.LevelFormattable, maxLv)
+}
+
 //
 // ------------- Use multi log levels -------------
 //
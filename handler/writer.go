@@ -8,14 +8,15 @@ import (
 
 // IOWriterHandler definition
 type IOWriterHandler struct {
+	NameTrait
 	NopFlushClose
 	slog.LevelFormattable
 	Output io.Writer
 }
 
-// TextFormatter get the formatter
+// TextFormatter get the formatter. will unwrap it on the prefix, suffix is set. see slog.AsTextFormatter()
 func (h *IOWriterHandler) TextFormatter() *slog.TextFormatter {
-	return h.Formatter().(*slog.TextFormatter)
+	return slog.AsTextFormatter(h.Formatter())
 }
 
 // Handle log record
@@ -38,6 +39,11 @@ func NewIOWriterWithLF(out io.Writer, lf slog.LevelFormattable) *IOWriterHandler
 	}
 }
 
+// SetMaxLevel set max level for log message, can be changed at runtime. see slog.NewLevelServer()
+func (h *IOWriterHandler) SetMaxLevel(maxLv slog.Level) {
+	setMaxLevel(&h.LevelFormattable, maxLv)
+}
+
 //
 // ------------- Use max log level -------------
 //
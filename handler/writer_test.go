@@ -28,6 +28,23 @@ func TestNewIOWriter(t *testing.T) {
 	assert.NoErr(t, h.Close())
 }
 
+func TestIOWriterHandler_SetMaxLevel(t *testing.T) {
+	jf := slog.NewJSONFormatter()
+	h := handler.NewIOWriter(new(bytes.Buffer), slog.NormalLevels)
+	h.SetFormatter(jf)
+	assert.False(t, h.IsHandling(slog.WarnLevel))
+
+	// replace the levels list, keep the formatter
+	h.SetMaxLevel(slog.WarnLevel)
+	assert.True(t, h.IsHandling(slog.WarnLevel))
+	assert.False(t, h.IsHandling(slog.InfoLevel))
+	assert.Eq(t, jf, h.Formatter())
+
+	h.SetMaxLevel(slog.DebugLevel)
+	assert.True(t, h.IsHandling(slog.DebugLevel))
+	assert.False(t, h.IsHandling(slog.TraceLevel))
+}
+
 func TestNewSyncCloser(t *testing.T) {
 	logfile := "./testdata/sync_closer.log"
 
@@ -0,0 +1,70 @@
+// Package echolog provide the request logging middleware for Echo, by the httplog.RequestLogger.
+//
+// It is a separate module, so the slog module does not depend on Echo.
+//
+// Usage:
+//
+//	e := echo.New()
+//	// add before the middleware.Recover(), the panicked request is logged with status 500.
+//	e.Use(echolog.Middleware(logger), middleware.Recover())
+//
+//	e.GET("/users/:id", func(c echo.Context) error {
+//		echolog.FromContext(c).Info("get user")
+//		return c.String(http.StatusOK, "user")
+//	})
+package echolog
+
+import (
+	"time"
+
+	"github.com/gookit/slog"
+	"github.com/gookit/slog/httplog"
+	"github.com/labstack/echo/v4"
+)
+
+// ContextKey the key of the request-scoped record in the echo.Context
+const ContextKey = "slog.record"
+
+// Middleware create a request logging middleware for Echo.
+//
+// The request-scoped record is injected to the echo.Context and the request context,
+// and the record is logged with the matched route on the handlers finished. see httplog.Middleware()
+func Middleware(l *slog.Logger, fns ...httplog.OptionFn) echo.MiddlewareFunc {
+	rl := httplog.NewRequestLogger(l, fns...)
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) (err error) {
+			res := &httplog.Result{Start: time.Now(), Panicked: true}
+
+			req := c.Request()
+			r := rl.Begin(req)
+			c.SetRequest(req.WithContext(httplog.NewContext(req.Context(), r)))
+			c.Set(ContextKey, r)
+
+			// not recover the panic, keep the stack for the outer recovery.
+			defer func() {
+				resp := c.Response()
+				res.Route, res.Err = c.Path(), err
+				res.Status, res.Size = resp.Status, int(resp.Size)
+				rl.Log(r, c.Request(), res)
+				r.Release()
+			}()
+
+			// call the error handler to write the response, then can get the real status.
+			if err = next(c); err != nil {
+				c.Error(err)
+			}
+			res.Panicked = false
+			return err
+		}
+	}
+}
+
+// FromContext get the request-scoped record from echo.Context.
+// if not exists, will return a new record from slog.Std(). see httplog.FromRequest()
+func FromContext(c echo.Context) *slog.Record {
+	if r, ok := c.Get(ContextKey).(*slog.Record); ok {
+		return r
+	}
+	return httplog.FromRequest(c.Request())
+}
@@ -0,0 +1,85 @@
+package echolog_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gookit/goutil/byteutil"
+	"github.com/gookit/goutil/testutil/assert"
+	"github.com/gookit/slog"
+	"github.com/gookit/slog/handler"
+	"github.com/gookit/slog/httplog/echolog"
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+)
+
+func newTestEcho() (*echo.Echo, *byteutil.Buffer) {
+	buf := byteutil.NewBuffer()
+	h := handler.NewIOWriter(buf, slog.AllLevels)
+	h.SetFormatter(slog.NewJSONFormatter())
+
+	e := echo.New()
+	e.Use(echolog.Middleware(slog.NewWithHandlers(h)), middleware.Recover())
+	return e, buf
+}
+
+func TestMiddleware(t *testing.T) {
+	e, buf := newTestEcho()
+	e.GET("/users/:id", func(c echo.Context) error {
+		echolog.FromContext(c).Info("get user")
+		return c.String(http.StatusOK, "user "+c.Param("id"))
+	})
+	e.GET("/fail", func(c echo.Context) error {
+		return echo.NewHTTPError(http.StatusBadGateway, "bad gateway")
+	})
+	e.GET("/err", func(c echo.Context) error {
+		return errors.New("internal")
+	})
+
+	req := httptest.NewRequest("GET", "/users/23", nil)
+	req.Header.Set("X-Request-ID", "abc123")
+	e.ServeHTTP(httptest.NewRecorder(), req)
+
+	lines := strings.Split(strings.TrimSpace(buf.ResetGet()), "\n")
+	assert.Len(t, lines, 2)
+	assert.StrContains(t, lines[0], `"message":"get user"`)
+	assert.StrContains(t, lines[0], `"request_id":"abc123"`)
+	assert.StrContains(t, lines[1], `"message":"http request"`)
+	assert.StrContains(t, lines[1], `"route":"/users/:id"`)
+	assert.StrContains(t, lines[1], `"path":"/users/23"`)
+	assert.StrContains(t, lines[1], `"status":200`)
+	assert.StrContains(t, lines[1], `"bytes":7`)
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest("GET", "/fail", nil))
+	assert.Eq(t, http.StatusBadGateway, rec.Code)
+	str := buf.ResetGet()
+	assert.StrContains(t, str, `"level":"ERROR"`)
+	assert.StrContains(t, str, `"status":502`)
+	assert.StrContains(t, str, `bad gateway`)
+
+	e.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/err", nil))
+	str = buf.ResetGet()
+	assert.StrContains(t, str, `"status":500`)
+	assert.StrContains(t, str, `"error":"internal"`)
+}
+
+func TestMiddleware_panic(t *testing.T) {
+	e, buf := newTestEcho()
+	e.GET("/panic", func(c echo.Context) error {
+		panic("oops")
+	})
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest("GET", "/panic", nil))
+	assert.Eq(t, http.StatusInternalServerError, rec.Code)
+
+	// the echo recover is after the middleware, the panic is recovered in the next()
+	str := buf.ResetGet()
+	assert.StrContains(t, str, `"route":"/panic"`)
+	assert.StrContains(t, str, `"status":500`)
+	assert.StrContains(t, str, `"level":"ERROR"`)
+}
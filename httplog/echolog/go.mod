@@ -0,0 +1,29 @@
+module github.com/gookit/slog/httplog/echolog
+
+go 1.19
+
+require (
+	github.com/gookit/goutil v0.6.18
+	github.com/gookit/slog v0.5.0
+	github.com/labstack/echo/v4 v4.11.4
+)
+
+require (
+	github.com/golang-jwt/jwt v3.2.2+incompatible // indirect
+	github.com/gookit/color v1.5.4 // indirect
+	github.com/gookit/gsr v0.1.0 // indirect
+	github.com/labstack/gommon v0.4.2 // indirect
+	github.com/mattn/go-colorable v0.1.13 // indirect
+	github.com/mattn/go-isatty v0.0.20 // indirect
+	github.com/valyala/bytebufferpool v1.0.0 // indirect
+	github.com/valyala/fasttemplate v1.2.2 // indirect
+	github.com/xo/terminfo v0.0.0-20220910002029-abceb7e1c41e // indirect
+	golang.org/x/crypto v0.17.0 // indirect
+	golang.org/x/net v0.19.0 // indirect
+	golang.org/x/sync v0.10.0 // indirect
+	golang.org/x/sys v0.28.0 // indirect
+	golang.org/x/text v0.21.0 // indirect
+	golang.org/x/time v0.5.0 // indirect
+)
+
+replace github.com/gookit/slog => ../..
@@ -0,0 +1,69 @@
+// Package ginlog provide the request logging middleware for Gin, by the httplog.RequestLogger.
+//
+// It is a separate module, so the slog module does not depend on Gin.
+//
+// Usage:
+//
+//	r := gin.New()
+//	// add before the gin.Recovery(), the panicked request is logged with status 500.
+//	r.Use(ginlog.Middleware(logger), gin.Recovery())
+//
+//	r.GET("/users/:id", func(c *gin.Context) {
+//		ginlog.FromContext(c).Info("get user")
+//	})
+package ginlog
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gookit/slog"
+	"github.com/gookit/slog/httplog"
+)
+
+// ContextKey the key of the request-scoped record in the gin.Context
+const ContextKey = "slog.record"
+
+// Middleware create a request logging middleware for Gin.
+//
+// The request-scoped record is injected to the gin.Context and the request context,
+// and the record is logged with the matched route on the handlers finished. see httplog.Middleware()
+func Middleware(l *slog.Logger, fns ...httplog.OptionFn) gin.HandlerFunc {
+	rl := httplog.NewRequestLogger(l, fns...)
+
+	return func(c *gin.Context) {
+		res := &httplog.Result{Start: time.Now(), Panicked: true}
+
+		r := rl.Begin(c.Request)
+		c.Request = c.Request.WithContext(httplog.NewContext(c.Request.Context(), r))
+		c.Set(ContextKey, r)
+
+		// not recover the panic, keep the stack for the outer recovery.
+		defer func() {
+			res.Route = c.FullPath()
+			res.Status, res.Size = c.Writer.Status(), c.Writer.Size()
+			if res.Size < 0 {
+				res.Size = 0
+			}
+			if err := c.Errors.Last(); err != nil {
+				res.Err = err.Err
+			}
+			rl.Log(r, c.Request, res)
+			r.Release()
+		}()
+
+		c.Next()
+		res.Panicked = false
+	}
+}
+
+// FromContext get the request-scoped record from gin.Context.
+// if not exists, will return a new record from slog.Std(). see httplog.FromRequest()
+func FromContext(c *gin.Context) *slog.Record {
+	if v, ok := c.Get(ContextKey); ok {
+		if r, ok := v.(*slog.Record); ok {
+			return r
+		}
+	}
+	return httplog.FromRequest(c.Request)
+}
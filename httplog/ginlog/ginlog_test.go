@@ -0,0 +1,77 @@
+package ginlog_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gookit/goutil/byteutil"
+	"github.com/gookit/goutil/testutil/assert"
+	"github.com/gookit/slog"
+	"github.com/gookit/slog/handler"
+	"github.com/gookit/slog/httplog/ginlog"
+)
+
+func newTestEngine() (*gin.Engine, *byteutil.Buffer) {
+	gin.SetMode(gin.TestMode)
+
+	buf := byteutil.NewBuffer()
+	h := handler.NewIOWriter(buf, slog.AllLevels)
+	h.SetFormatter(slog.NewJSONFormatter())
+
+	r := gin.New()
+	r.Use(ginlog.Middleware(slog.NewWithHandlers(h)), gin.RecoveryWithWriter(byteutil.NewBuffer()))
+	return r, buf
+}
+
+func TestMiddleware(t *testing.T) {
+	r, buf := newTestEngine()
+	r.GET("/users/:id", func(c *gin.Context) {
+		ginlog.FromContext(c).Info("get user")
+		c.String(http.StatusOK, "user "+c.Param("id"))
+	})
+	r.GET("/fail", func(c *gin.Context) {
+		_ = c.Error(errors.New("bad gateway"))
+		c.Status(http.StatusBadGateway)
+	})
+
+	req := httptest.NewRequest("GET", "/users/23", nil)
+	req.Header.Set("X-Request-ID", "abc123")
+	r.ServeHTTP(httptest.NewRecorder(), req)
+
+	lines := strings.Split(strings.TrimSpace(buf.ResetGet()), "\n")
+	assert.Len(t, lines, 2)
+	assert.StrContains(t, lines[0], `"message":"get user"`)
+	assert.StrContains(t, lines[0], `"request_id":"abc123"`)
+	assert.StrContains(t, lines[1], `"message":"http request"`)
+	assert.StrContains(t, lines[1], `"route":"/users/:id"`)
+	assert.StrContains(t, lines[1], `"path":"/users/23"`)
+	assert.StrContains(t, lines[1], `"status":200`)
+	assert.StrContains(t, lines[1], `"bytes":7`)
+
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/fail", nil))
+	str := buf.ResetGet()
+	assert.StrContains(t, str, `"level":"ERROR"`)
+	assert.StrContains(t, str, `"status":502`)
+	assert.StrContains(t, str, `"error":"bad gateway"`)
+}
+
+func TestMiddleware_panic(t *testing.T) {
+	r, buf := newTestEngine()
+	r.GET("/panic", func(c *gin.Context) {
+		panic("oops")
+	})
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest("GET", "/panic", nil))
+	assert.Eq(t, http.StatusInternalServerError, rec.Code)
+
+	// the gin recovery is after the middleware, the panic is recovered in the c.Next()
+	str := buf.ResetGet()
+	assert.StrContains(t, str, `"route":"/panic"`)
+	assert.StrContains(t, str, `"status":500`)
+	assert.StrContains(t, str, `"level":"ERROR"`)
+}
@@ -0,0 +1,243 @@
+// Package httplog provide request logging and recovery middlewares for net/http,
+// log structured request records through slog, and inject a request-scoped logger
+// into the request context.
+//
+// The middlewares use the standard signature func(http.Handler) http.Handler, can be used on net/http, chi and others.
+//
+// For Gin and Echo, please use the middlewares in the separate modules, they log the matched route
+// and inject the request-scoped record into the framework context:
+//
+//   - github.com/gookit/slog/httplog/ginlog
+//   - github.com/gookit/slog/httplog/echolog
+package httplog
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/gookit/slog"
+)
+
+// Options for the request logging middleware
+type Options struct {
+	// Level for log the request record. default is slog.InfoLevel
+	Level slog.Level
+	// ErrorLevel for log the request record on status >= 500. default is slog.ErrorLevel
+	ErrorLevel slog.Level
+	// RequestIDHeader name of the request id header. default is "X-Request-ID"
+	RequestIDHeader string
+	// SkipPaths will not log request on the path
+	SkipPaths []string
+	// Message for the request record. default is "http request"
+	Message string
+}
+
+// OptionFn option func
+type OptionFn func(opt *Options)
+
+// NewOptions create default options
+func NewOptions(fns ...OptionFn) *Options {
+	opt := &Options{
+		Level:           slog.InfoLevel,
+		ErrorLevel:      slog.ErrorLevel,
+		RequestIDHeader: "X-Request-ID",
+		Message:         "http request",
+	}
+
+	for _, fn := range fns {
+		fn(opt)
+	}
+	return opt
+}
+
+type ctxKey struct{}
+
+// NewContext returns a new context with the request-scoped log record.
+func NewContext(ctx context.Context, r *slog.Record) context.Context {
+	return context.WithValue(ctx, ctxKey{}, r)
+}
+
+// FromContext get the request-scoped log record from context. return nil if not exists.
+func FromContext(ctx context.Context) *slog.Record {
+	if r, ok := ctx.Value(ctxKey{}).(*slog.Record); ok {
+		return r
+	}
+	return nil
+}
+
+// FromRequest get the request-scoped log record from request.
+// if not exists, will return a new record from slog.Std().
+func FromRequest(req *http.Request) *slog.Record {
+	if r := FromContext(req.Context()); r != nil {
+		return r
+	}
+	return slog.Std().WithContext(req.Context())
+}
+
+// Result of a handled request, for log the request record.
+type Result struct {
+	// Route the matched route pattern. eg: "/users/:id"
+	Route string
+	// Status and Size of the response
+	Status int
+	Size   int
+	// Start time of the request
+	Start time.Time
+	// Err returned by the handler. optional
+	Err error
+	// Panicked the handler is panicked, the status will be logged as 500.
+	Panicked bool
+}
+
+// RequestLogger log the request records. it is used by Middleware() and the framework middlewares.
+type RequestLogger struct {
+	*Options
+	l     *slog.Logger
+	skips map[string]bool
+}
+
+// NewRequestLogger create a RequestLogger
+func NewRequestLogger(l *slog.Logger, fns ...OptionFn) *RequestLogger {
+	opt := NewOptions(fns...)
+	skips := make(map[string]bool, len(opt.SkipPaths))
+	for _, path := range opt.SkipPaths {
+		skips[path] = true
+	}
+
+	return &RequestLogger{Options: opt, l: l, skips: skips}
+}
+
+// Begin create the request-scoped record with fields: method, path, request_id.
+// please inject it to the request context by NewContext().
+//
+// The record is marked as reused, so it can be logged multiple times in the request,
+// please call Record.Release() after the request record is logged.
+func (rl *RequestLogger) Begin(req *http.Request) *slog.Record {
+	fields := slog.M{
+		"method": req.Method,
+		"path":   req.URL.Path,
+	}
+	if reqID := req.Header.Get(rl.RequestIDHeader); reqID != "" {
+		fields["request_id"] = reqID
+	}
+	return rl.l.WithFields(fields).WithContext(req.Context()).Reused()
+}
+
+// Log the request record by the request-scoped record, will add fields:
+// status, bytes, duration, remote_addr, and route, error, panicked on them are not empty.
+func (rl *RequestLogger) Log(r *slog.Record, req *http.Request, res *Result) {
+	if rl.skips[req.URL.Path] {
+		return
+	}
+
+	status := res.Status
+	if res.Panicked && status < http.StatusInternalServerError {
+		status = http.StatusInternalServerError
+	}
+
+	level := rl.Level
+	if status >= http.StatusInternalServerError {
+		level = rl.ErrorLevel
+	}
+
+	fields := slog.M{
+		"status":      status,
+		"bytes":       res.Size,
+		"duration":    slog.Since(res.Start).String(),
+		"remote_addr": req.RemoteAddr,
+	}
+	if res.Route != "" {
+		fields["route"] = res.Route
+	}
+	if res.Err != nil {
+		fields["error"] = res.Err.Error()
+	}
+	if res.Panicked {
+		fields["panicked"] = true
+	}
+	r.Copy().AddFields(fields).Log(level, rl.Message)
+}
+
+// Middleware create a request logging middleware.
+//
+// Each request will log a record with fields: method, path, status, bytes, duration, remote_addr, request_id.
+// The record is logged in defer, so the request is logged even the handler panicked,
+// and the panic is not recovered, please use it with Recovery().
+func Middleware(l *slog.Logger, fns ...OptionFn) func(http.Handler) http.Handler {
+	rl := NewRequestLogger(l, fns...)
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			res := &Result{Start: time.Now(), Panicked: true}
+
+			// inject request-scoped record
+			r := rl.Begin(req)
+			req = req.WithContext(NewContext(req.Context(), r))
+
+			rw := &respWriter{ResponseWriter: w, status: http.StatusOK}
+			// not recover the panic, keep the stack for the outer recovery.
+			defer func() {
+				res.Status, res.Size = rw.status, rw.size
+				rl.Log(r, req, res)
+				r.Release()
+			}()
+
+			next.ServeHTTP(rw, req)
+			res.Panicked = false
+		})
+	}
+}
+
+// respWriter record the response status and size
+type respWriter struct {
+	http.ResponseWriter
+	status int
+	size   int
+	wrote  bool
+}
+
+// WriteHeader implements http.ResponseWriter
+func (w *respWriter) WriteHeader(code int) {
+	if !w.wrote {
+		w.status = code
+		w.wrote = true
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+// Write implements http.ResponseWriter
+func (w *respWriter) Write(p []byte) (int, error) {
+	w.wrote = true
+	n, err := w.ResponseWriter.Write(p)
+	w.size += n
+	return n, err
+}
+
+// Flush implements http.Flusher
+func (w *respWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Unwrap returns the original http.ResponseWriter. for http.ResponseController
+func (w *respWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// redact the password in userinfo and the query values, don't log the credentials and tokens.
+func redactURL(u *url.URL) string {
+	if u.RawQuery == "" {
+		return u.Redacted()
+	}
+
+	ru := *u
+	query := ru.Query()
+	for key := range query {
+		query[key] = []string{"xxxxx"}
+	}
+	ru.RawQuery = query.Encode()
+	return ru.Redacted()
+}
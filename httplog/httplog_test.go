@@ -0,0 +1,178 @@
+package httplog_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/gookit/goutil/byteutil"
+	"github.com/gookit/goutil/testutil/assert"
+	"github.com/gookit/slog"
+	"github.com/gookit/slog/handler"
+	"github.com/gookit/slog/httplog"
+)
+
+func newTestLogger() (*slog.Logger, *byteutil.Buffer) {
+	buf := byteutil.NewBuffer()
+	h := handler.NewIOWriter(buf, slog.AllLevels)
+	h.SetFormatter(slog.NewJSONFormatter())
+	return slog.NewWithHandlers(h), buf
+}
+
+func TestMiddleware(t *testing.T) {
+	l, buf := newTestLogger()
+
+	mw := httplog.Middleware(l, func(opt *httplog.Options) {
+		opt.SkipPaths = []string{"/health"}
+	})
+	srv := mw(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		httplog.FromRequest(req).Info("in handler")
+		if req.URL.Path == "/fail" {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		_, _ = w.Write([]byte("hello"))
+	}))
+
+	req := httptest.NewRequest("GET", "/hello", nil)
+	req.Header.Set("X-Request-ID", "abc123")
+	srv.ServeHTTP(httptest.NewRecorder(), req)
+
+	str := buf.String()
+	assert.StrContains(t, str, `"message":"in handler"`)
+	assert.StrContains(t, str, `"request_id":"abc123"`)
+	assert.StrContains(t, str, `"message":"http request"`)
+	assert.StrContains(t, str, `"status":200`)
+	assert.StrContains(t, str, `"bytes":5`)
+
+	buf.Reset()
+	srv.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/fail", nil))
+	assert.StrContains(t, buf.String(), `"level":"ERROR"`)
+	assert.StrContains(t, buf.String(), `"status":502`)
+
+	// skip path
+	buf.Reset()
+	srv.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/health", nil))
+	assert.NotContains(t, buf.String(), "http request")
+}
+
+// go test -race -run TestMiddleware_concurrent
+func TestMiddleware_concurrent(t *testing.T) {
+	l, buf := newTestLogger()
+
+	srv := httplog.Middleware(l)(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		for i := 0; i < 3; i++ {
+			httplog.FromRequest(req).Info("in handler", req.URL.Path)
+			l.Info("other record")
+		}
+	}))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			req := httptest.NewRequest("GET", fmt.Sprintf("/req/%d", i), nil)
+			req.Header.Set("X-Request-ID", fmt.Sprintf("id-%d", i))
+			srv.ServeHTTP(httptest.NewRecorder(), req)
+		}(i)
+	}
+	wg.Wait()
+
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		if strings.Contains(line, "other record") {
+			assert.NotContains(t, line, "request_id")
+		} else if strings.Contains(line, "in handler") {
+			var i int
+			_, err := fmt.Sscanf(line[strings.Index(line, "/req/"):], "/req/%d", &i)
+			assert.NoErr(t, err)
+			assert.StrContains(t, line, fmt.Sprintf(`"request_id":"id-%d"`, i))
+		}
+	}
+}
+
+func TestMiddleware_panic(t *testing.T) {
+	l, buf := newTestLogger()
+
+	// the recovery is outside, the panic is passed through the Middleware
+	srv := httplog.Recovery(l, func(rc *httplog.Recoverer) {
+		rc.PrintStack = false
+	})(httplog.Middleware(l)(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		panic("oops")
+	})))
+
+	rec := httptest.NewRecorder()
+	srv.ServeHTTP(rec, httptest.NewRequest("GET", "/panic", nil))
+	assert.Eq(t, http.StatusInternalServerError, rec.Code)
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	assert.Len(t, lines, 2)
+	assert.StrContains(t, lines[0], `"message":"http request"`)
+	assert.StrContains(t, lines[0], `"level":"ERROR"`)
+	assert.StrContains(t, lines[0], `"status":500`)
+	assert.StrContains(t, lines[0], `"panicked":true`)
+	assert.StrContains(t, lines[1], "http panic recovered")
+
+	// no recovery
+	buf.Reset()
+	srv = httplog.Middleware(l)(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		panic("oops")
+	}))
+	assert.Panics(t, func() {
+		srv.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/panic", nil))
+	})
+	assert.StrContains(t, buf.String(), `"panicked":true`)
+}
+
+func TestRecovery(t *testing.T) {
+	l, buf := newTestLogger()
+
+	srv := httplog.Recovery(l)(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		panic("oops")
+	}))
+
+	rec := httptest.NewRecorder()
+	srv.ServeHTTP(rec, httptest.NewRequest("GET", "/panic", nil))
+	assert.Eq(t, http.StatusInternalServerError, rec.Code)
+	assert.StrContains(t, buf.String(), `"panic":"oops"`)
+	assert.StrContains(t, buf.String(), "http panic recovered")
+}
+
+func TestRecoverer(t *testing.T) {
+	l, buf := newTestLogger()
+
+	// with request-scoped record
+	srv := httplog.Middleware(l)(httplog.Recovery(l, func(rc *httplog.Recoverer) {
+		rc.PrintStack = false
+	})(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		panic("oops")
+	})))
+
+	req := httptest.NewRequest("GET", "/panic", nil)
+	req.Header.Set("X-Request-ID", "req-1")
+	rec := httptest.NewRecorder()
+	srv.ServeHTTP(rec, req)
+
+	assert.Eq(t, http.StatusInternalServerError, rec.Code)
+	str := buf.String()
+	assert.StrContains(t, str, `"request_id":"req-1"`)
+	assert.StrContains(t, str, `"panic":"oops"`)
+	assert.NotContains(t, str, `"stack"`)
+
+	// negroni style, custom response
+	buf.Reset()
+	rc := httplog.NewRecoverer(l, func(rc *httplog.Recoverer) {
+		rc.OnPanic = func(w http.ResponseWriter, req *http.Request, err any) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	})
+	rec = httptest.NewRecorder()
+	rc.ServeHTTP(rec, httptest.NewRequest("GET", "/panic", nil), func(w http.ResponseWriter, req *http.Request) {
+		panic("oops2")
+	})
+	assert.Eq(t, http.StatusServiceUnavailable, rec.Code)
+	assert.StrContains(t, buf.String(), `"stack"`)
+}
@@ -0,0 +1,89 @@
+package httplog
+
+import (
+	"net/http"
+	"runtime/debug"
+
+	"github.com/gookit/slog"
+)
+
+// Recoverer a panic recovery handler. will log the panic with stack and
+// request context at ErrorLevel, and response status 500.
+//
+// It implements the negroni.Handler interface, can be used on negroni:
+//
+//	n := negroni.New(httplog.NewRecoverer(logger))
+type Recoverer struct {
+	l *slog.Logger
+	// Level for log the panic. default is slog.ErrorLevel
+	Level slog.Level
+	// PrintStack log the panic stack. default is true
+	PrintStack bool
+	// OnPanic custom handle the response on panic. default is response status 500
+	OnPanic func(w http.ResponseWriter, req *http.Request, err any)
+}
+
+// NewRecoverer create a Recoverer
+func NewRecoverer(l *slog.Logger, fns ...func(rc *Recoverer)) *Recoverer {
+	rc := &Recoverer{
+		l:          l,
+		Level:      slog.ErrorLevel,
+		PrintStack: true,
+	}
+
+	for _, fn := range fns {
+		fn(rc)
+	}
+	return rc
+}
+
+// ServeHTTP implements the negroni.Handler
+func (rc *Recoverer) ServeHTTP(w http.ResponseWriter, req *http.Request, next http.HandlerFunc) {
+	defer func() {
+		if err := recover(); err != nil {
+			if err == http.ErrAbortHandler {
+				panic(err)
+			}
+
+			rc.logPanic(req, err)
+			if rc.OnPanic != nil {
+				rc.OnPanic(w, req, err)
+			} else {
+				w.WriteHeader(http.StatusInternalServerError)
+			}
+		}
+	}()
+
+	next(w, req)
+}
+
+// Handler returns a middleware for net/http, chi and others.
+func (rc *Recoverer) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		rc.ServeHTTP(w, req, next.ServeHTTP)
+	})
+}
+
+func (rc *Recoverer) logPanic(req *http.Request, err any) {
+	fields := slog.M{
+		"method":      req.Method,
+		"url":         redactURL(req.URL),
+		"remote_addr": req.RemoteAddr,
+		"panic":       err,
+	}
+	if rc.PrintStack {
+		fields["stack"] = string(debug.Stack())
+	}
+
+	// use the request-scoped record, contains the request context fields. eg: request_id
+	if r := FromContext(req.Context()); r != nil {
+		r.Copy().AddFields(fields).Log(rc.Level, "http panic recovered")
+		return
+	}
+	rc.l.WithFields(fields).WithContext(req.Context()).Log(rc.Level, "http panic recovered")
+}
+
+// Recovery create a recovery middleware. see Recoverer
+func Recovery(l *slog.Logger, fns ...func(rc *Recoverer)) func(http.Handler) http.Handler {
+	return NewRecoverer(l, fns...).Handler
+}
@@ -0,0 +1,131 @@
+package httplog
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gookit/slog"
+)
+
+// DefaultMaxBodySize default max body size for log on Transport.LogBody=true
+const DefaultMaxBodySize = 4096
+
+// Transport an http.RoundTripper wrapper, log the outbound request and response metadata.
+//
+// Usage:
+//
+//	client := &http.Client{Transport: httplog.NewTransport(logger, nil)}
+type Transport struct {
+	l *slog.Logger
+	// Base the wrapped RoundTripper. default is http.DefaultTransport
+	Base http.RoundTripper
+	// Level for log the request. default is slog.DebugLevel
+	Level slog.Level
+	// ErrLevel for log on request error or status >= 500. default is slog.ErrorLevel
+	ErrLevel slog.Level
+	// LogBody log the request and response body
+	LogBody bool
+	// MaxBodySize max body size for log, the body will be truncated. default is DefaultMaxBodySize
+	MaxBodySize int
+}
+
+// NewTransport create a new logging Transport
+func NewTransport(l *slog.Logger, base http.RoundTripper, fns ...func(t *Transport)) *Transport {
+	t := &Transport{
+		l:           l,
+		Base:        base,
+		Level:       slog.DebugLevel,
+		ErrLevel:    slog.ErrorLevel,
+		MaxBodySize: DefaultMaxBodySize,
+	}
+
+	for _, fn := range fns {
+		fn(t)
+	}
+	return t
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	fields := slog.M{
+		"method": req.Method,
+		"url":    redactURL(req.URL),
+	}
+
+	if t.LogBody && req.Body != nil && req.Body != http.NoBody {
+		body, rc, err := t.peekBody(req.Body)
+		if err != nil {
+			return nil, err
+		}
+		// copy request, should not modify the original request
+		req = req.Clone(req.Context())
+		req.Body = rc
+		fields["request_body"] = body
+	}
+
+	start := time.Now()
+	resp, err := base.RoundTrip(req)
+	fields["duration"] = slog.Since(start).String()
+
+	level := t.Level
+	if err != nil {
+		level = t.ErrLevel
+		fields["error"] = err.Error()
+	} else {
+		fields["status"] = resp.StatusCode
+		fields["content_length"] = resp.ContentLength
+		if resp.StatusCode >= http.StatusInternalServerError {
+			level = t.ErrLevel
+		}
+
+		if t.LogBody && resp.Body != nil {
+			body, rc, rerr := t.peekBody(resp.Body)
+			if rerr != nil {
+				// the body has been closed, can't return the response
+				resp, err = nil, rerr
+				level = t.ErrLevel
+				fields["error"] = rerr.Error()
+			} else {
+				resp.Body = rc
+				fields["response_body"] = body
+			}
+		}
+	}
+
+	t.l.WithFields(fields).WithContext(req.Context()).Log(level, "http client request")
+	return resp, err
+}
+
+// peek body contents up to MaxBodySize, returns a new body for read all contents.
+//
+// NOTICE: the original body will be closed on read error.
+func (t *Transport) peekBody(body io.ReadCloser) (string, io.ReadCloser, error) {
+	buf, err := io.ReadAll(io.LimitReader(body, int64(t.MaxBodySize)))
+	if err != nil {
+		_ = body.Close()
+		return "", nil, err
+	}
+
+	rc := &multiReadCloser{
+		Reader: io.MultiReader(bytes.NewReader(buf), body),
+		closer: body,
+	}
+	return string(buf), rc, nil
+}
+
+type multiReadCloser struct {
+	io.Reader
+	closer io.Closer
+}
+
+// Close the original body
+func (m *multiReadCloser) Close() error {
+	return m.closer.Close()
+}
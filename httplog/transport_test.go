@@ -0,0 +1,101 @@
+package httplog_test
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gookit/goutil/testutil/assert"
+	"github.com/gookit/slog/httplog"
+)
+
+func TestTransport_RoundTrip(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		bs, _ := io.ReadAll(req.Body)
+		if string(bs) == "fail" {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+		_, _ = w.Write([]byte("resp:" + string(bs)))
+	}))
+	defer srv.Close()
+
+	l, buf := newTestLogger()
+	client := &http.Client{Transport: httplog.NewTransport(l, nil, func(t *httplog.Transport) {
+		t.LogBody = true
+		t.MaxBodySize = 8
+	})}
+
+	resp, err := client.Post(srv.URL+"/api", "text/plain", strings.NewReader("hello world"))
+	assert.NoErr(t, err)
+	bs, err := io.ReadAll(resp.Body)
+	assert.NoErr(t, err)
+	assert.NoErr(t, resp.Body.Close())
+	// body is not changed
+	assert.Eq(t, "resp:hello world", string(bs))
+
+	str := buf.String()
+	assert.StrContains(t, str, `"level":"DEBUG"`)
+	assert.StrContains(t, str, `"method":"POST"`)
+	assert.StrContains(t, str, `"status":200`)
+	assert.StrContains(t, str, `"request_body":"hello wo"`)
+	assert.StrContains(t, str, `"response_body":"resp:hel"`)
+
+	buf.Reset()
+	resp, err = client.Post(srv.URL+"/api", "text/plain", strings.NewReader("fail"))
+	assert.NoErr(t, err)
+	assert.NoErr(t, resp.Body.Close())
+	assert.StrContains(t, buf.String(), `"level":"ERROR"`)
+
+	// request error
+	buf.Reset()
+	_, err = client.Get("http://127.0.0.1:1/not-exists")
+	assert.Err(t, err)
+	assert.StrContains(t, buf.String(), `"error":`)
+}
+
+func TestTransport_redactURL(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {}))
+	defer srv.Close()
+
+	l, buf := newTestLogger()
+	client := &http.Client{Transport: httplog.NewTransport(l, nil)}
+
+	u := strings.Replace(srv.URL, "http://", "http://user:pass@", 1) + "/api?token=abc123&page=2"
+	resp, err := client.Get(u)
+	assert.NoErr(t, err)
+	assert.NoErr(t, resp.Body.Close())
+
+	str := buf.String()
+	assert.StrContains(t, str, "user:xxxxx@")
+	assert.StrContains(t, str, "/api?page=xxxxx")
+	assert.StrContains(t, str, "token=xxxxx")
+	assert.NotContains(t, str, "pass@")
+	assert.NotContains(t, str, "abc123")
+}
+
+type errReader struct {
+	closed bool
+}
+
+func (r *errReader) Read([]byte) (int, error) { return 0, errors.New("read error") }
+
+func (r *errReader) Close() error {
+	r.closed = true
+	return nil
+}
+
+func TestTransport_peekBodyError(t *testing.T) {
+	l, _ := newTestLogger()
+	tp := httplog.NewTransport(l, nil, func(t *httplog.Transport) {
+		t.LogBody = true
+	})
+
+	body := &errReader{}
+	req := httptest.NewRequest(http.MethodPost, "http://127.0.0.1:1/api", body)
+	_, err := tp.RoundTrip(req)
+	assert.ErrMsg(t, err, "read error")
+	assert.True(t, body.closed)
+}
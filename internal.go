@@ -0,0 +1,176 @@
+package slog
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// InternalChannel the channel name for the slog self-diagnostics records
+var InternalChannel = "slog.internal"
+
+// the handler for slog internal diagnostics, nil is print to stderr.
+var internalHandler atomic.Value
+
+type internalHolder struct{ h Handler }
+
+// SetInternalHandler route the slog internal diagnostics(eg: handler failures, drops, reconnects)
+// as records to the handler, the record channel is InternalChannel. set nil to print them to stderr.
+//
+// NOTICE: the handler must be safe for concurrent use, and should not write to a logger
+// that reports diagnostics to itself.
+//
+// Usage:
+//
+//	slog.SetInternalHandler(handler.NewIOWriter(os.Stderr, slog.AllLevels))
+func SetInternalHandler(h Handler) {
+	internalHandler.Store(internalHolder{h: h})
+}
+
+// InternalHandler get the handler for slog internal diagnostics
+func InternalHandler() Handler {
+	if hd, ok := internalHandler.Load().(internalHolder); ok {
+		return hd.h
+	}
+	return nil
+}
+
+// ReportInternal report a slog internal diagnostic message. args are same as fmt.Println()
+//
+// Will write to the internal handler if set, otherwise print to stderr.
+// NOTICE: on print to stderr, the level > WarnLevel(eg: notice for reconnect) will be ignored.
+// The error in args will be added to the record data with key FieldKeyError.
+func ReportInternal(level Level, args ...any) {
+	msg := strings.TrimSuffix(fmt.Sprintln(args...), "\n")
+
+	h := InternalHandler()
+	if h == nil {
+		if level <= WarnLevel {
+			_, _ = fmt.Fprintln(os.Stderr, msg)
+		}
+		return
+	}
+	if !h.IsHandling(level) {
+		return
+	}
+
+	r := &Record{
+		Time:    time.Now(),
+		Level:   level,
+		Channel: InternalChannel,
+		Message: msg,
+		Data:    M{},
+	}
+	for _, arg := range args {
+		if err, ok := arg.(error); ok {
+			r.Data[FieldKeyError] = err.Error()
+		}
+	}
+
+	r.Init(false)
+	if err := h.Handle(r); err != nil {
+		_, _ = fmt.Fprintln(os.Stderr, msg)
+		_, _ = fmt.Fprintln(os.Stderr, "slog: internal handler handle record error:", err)
+	}
+}
+
+// report an internal error. see ReportInternal()
+func reportError(args ...any) {
+	ReportInternal(ErrorLevel, args...)
+}
+
+// RecentErrorsSize the max number of recent errors kept by the logger. see Logger.RecentErrors()
+const RecentErrorsSize = 16
+
+// ErrorRecord an internal error of the logger, with the time and handler identity.
+type ErrorRecord struct {
+	Time time.Time
+	Err  error
+	// Op the failed operation. eg: "handle", "flush", "close", "format"
+	Op string
+	// Handler the identity of the failed handler. see HandlerName()
+	Handler string
+}
+
+// String get the error record string
+func (er ErrorRecord) String() string {
+	return er.Time.Format(time.RFC3339) + " " + er.Op + " " + er.Handler + ": " + er.Err.Error()
+}
+
+// errorRing keep the recent internal errors, the oldest one will be overwritten on full.
+type errorRing struct {
+	mu   sync.Mutex
+	errs []ErrorRecord
+	// next write position
+	next int
+	// the latest error, will be cleared on LastErr()
+	last error
+}
+
+func (er *errorRing) add(op string, h Handler, err error) {
+	rec := ErrorRecord{Time: time.Now(), Err: err, Op: op, Handler: HandlerName(h)}
+
+	er.mu.Lock()
+	if len(er.errs) < RecentErrorsSize {
+		er.errs = append(er.errs, rec)
+	} else {
+		er.errs[er.next] = rec
+	}
+	er.next = (er.next + 1) % RecentErrorsSize
+	er.last = err
+	er.mu.Unlock()
+}
+
+// get the latest error, and clear it
+func (er *errorRing) takeLast() error {
+	er.mu.Lock()
+	defer er.mu.Unlock()
+
+	err := er.last
+	er.last = nil
+	return err
+}
+
+func (er *errorRing) lastErr() error {
+	er.mu.Lock()
+	defer er.mu.Unlock()
+	return er.last
+}
+
+// get the recent errors, from oldest to newest
+func (er *errorRing) recent() []ErrorRecord {
+	er.mu.Lock()
+	defer er.mu.Unlock()
+
+	ln := len(er.errs)
+	list := make([]ErrorRecord, 0, ln)
+	if ln < RecentErrorsSize {
+		return append(list, er.errs...)
+	}
+	list = append(list, er.errs[er.next:]...)
+	return append(list, er.errs[:er.next]...)
+}
+
+// HandlerNamer the handler can provide a name, use for diagnostics. see HandlerName()
+type HandlerNamer interface {
+	Name() string
+}
+
+// HandlerName get the handler identity for diagnostics(eg: error reporting, RecentErrors).
+//
+// Returns the Name() on the handler implements HandlerNamer and the name is not empty,
+// otherwise is the type name. eg: "*handler.IOWriterHandler"
+func HandlerName(h Handler) string {
+	if h == nil {
+		return ""
+	}
+	if hn, ok := h.(HandlerNamer); ok {
+		if name := hn.Name(); name != "" {
+			return name
+		}
+	}
+	return fmt.Sprintf("%T", h)
+}
@@ -0,0 +1,46 @@
+package slog_test
+
+import (
+	"testing"
+
+	"github.com/gookit/goutil/byteutil"
+	"github.com/gookit/goutil/testutil/assert"
+	"github.com/gookit/slog"
+	"github.com/gookit/slog/handler"
+)
+
+func TestSetInternalHandler(t *testing.T) {
+	buf := new(byteutil.Buffer)
+	ih := handler.NewIOWriter(buf, slog.AllLevels)
+	ih.SetFormatter(slog.NewJSONFormatter())
+
+	slog.SetInternalHandler(ih)
+	defer slog.SetInternalHandler(nil)
+	assert.Eq(t, ih, slog.InternalHandler())
+
+	// handler failure is routed to the internal handler
+	h := newTestHandler()
+	h.errOnHandle = true
+	l := slog.NewWithHandlers(h)
+	l.Info("a message")
+
+	str := buf.ResetAndGet()
+	assert.StrContains(t, str, `"channel":"slog.internal"`)
+	assert.StrContains(t, str, `"level":"ERROR"`)
+	assert.StrContains(t, str, `"message":"slog: failed to handle log, handler: *slog_test.testHandler error: handle error"`)
+	assert.StrContains(t, str, `"error":"handle error"`)
+
+	slog.ReportInternal(slog.NoticeLevel, "slog: a notice", 23)
+	assert.StrContains(t, buf.ResetAndGet(), `"message":"slog: a notice 23"`)
+
+	// not handled level
+	slog.SetInternalHandler(handler.NewIOWriter(buf, slog.DangerLevels))
+	slog.ReportInternal(slog.NoticeLevel, "slog: a notice")
+	assert.Empty(t, buf.ResetAndGet())
+
+	// print to stderr
+	slog.SetInternalHandler(nil)
+	assert.Nil(t, slog.InternalHandler())
+	slog.ReportInternal(slog.WarnLevel, "slog: a warning")
+	assert.Empty(t, buf.ResetAndGet())
+}
@@ -0,0 +1,202 @@
+package slog
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+)
+
+// MaxLevelSetter the handler that support change max level at runtime.
+// eg: LevelWithFormatter, LevelHandling
+type MaxLevelSetter interface {
+	SetMaxLevel(maxLv Level)
+}
+
+// HandlerLevel the handling level info of a handler
+type HandlerLevel struct {
+	Index int    `json:"index"`
+	Type  string `json:"type"`
+	// Name the handler name. see HandlerName()
+	Name string `json:"name"`
+	// Level the least severe level that handled. empty on the handler not handle any level.
+	Level string `json:"level"`
+	// Settable the handler implements MaxLevelSetter
+	Settable bool `json:"settable"`
+}
+
+// LevelState the response data of the LevelServer
+type LevelState struct {
+	// Level the least severe level that handled by any handler
+	Level    string         `json:"level"`
+	Handlers []HandlerLevel `json:"handlers"`
+}
+
+// LevelServer an http.Handler for report and change the handlers max level at runtime.
+//
+//   - GET: report the levels of the logger and handlers
+//   - PUT: change the max level of the handlers, that implement MaxLevelSetter.
+//
+// The new level can be set by query/form value or JSON body, option "handler" for change one handler by index.
+//
+//	curl -X PUT "localhost:8080/loglevel?level=debug"
+//	curl -X PUT -d '{"level": "debug", "handler": 0}' localhost:8080/loglevel
+type LevelServer struct {
+	logger *Logger
+}
+
+// NewLevelServer create a LevelServer for the logger
+//
+// Usage:
+//
+//	http.Handle("/loglevel", slog.NewLevelServer(logger))
+func NewLevelServer(l *Logger) *LevelServer {
+	return &LevelServer{logger: l.rootLogger()}
+}
+
+// ServeHTTP implements the http.Handler
+func (s *LevelServer) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	switch req.Method {
+	case http.MethodGet:
+		s.writeJSON(w, http.StatusOK, s.State())
+	case http.MethodPut:
+		level, index, err := parseLevelRequest(req)
+		if err != nil {
+			s.writeError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		if err = s.SetLevel(level, index); err != nil {
+			s.writeError(w, http.StatusBadRequest, err)
+			return
+		}
+		s.writeJSON(w, http.StatusOK, s.State())
+	default:
+		w.Header().Set("Allow", "GET, PUT")
+		s.writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("method %s not allowed", req.Method))
+	}
+}
+
+// State get the current levels of the logger and handlers
+func (s *LevelServer) State() *LevelState {
+	st := &LevelState{}
+	var maxLv Level
+
+	// lock for the handler levels may be changed in Config()
+	s.logger.Config(func(l *Logger) {
+		for i, h := range l.loadHandlers() {
+			lv := maxHandlingLevel(h)
+			if lv > maxLv {
+				maxLv = lv
+			}
+
+			_, ok := h.(MaxLevelSetter)
+			st.Handlers = append(st.Handlers, HandlerLevel{
+				Index:    i,
+				Type:     fmt.Sprintf("%T", h),
+				Name:     HandlerName(h),
+				Level:    levelStateName(lv),
+				Settable: ok,
+			})
+		}
+	})
+
+	st.Level = levelStateName(maxLv)
+	return st
+}
+
+// SetLevel set the max level for the handlers that implement MaxLevelSetter.
+// index < 0 for set all handlers, otherwise only set the handler at index.
+func (s *LevelServer) SetLevel(level Level, index int) (err error) {
+	var n int
+	s.logger.Config(func(l *Logger) {
+		hs := l.loadHandlers()
+		if index >= len(hs) {
+			err = fmt.Errorf("handler index %d out of range", index)
+			return
+		}
+
+		for i, h := range hs {
+			if index >= 0 && i != index {
+				continue
+			}
+			if ls, ok := h.(MaxLevelSetter); ok {
+				ls.SetMaxLevel(level)
+				n++
+			}
+		}
+	})
+
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return fmt.Errorf("no handler support change the max level")
+	}
+
+	ReportInternal(NoticeLevel, "slog: level server change the max level to", level.LowerName())
+	return nil
+}
+
+// get the least severe level that handled by the handler. 0 on not handle any level.
+func maxHandlingLevel(h Handler) (maxLv Level) {
+	for _, lv := range AllLevels {
+		if lv > maxLv && h.IsHandling(lv) {
+			maxLv = lv
+		}
+	}
+	return
+}
+
+func levelStateName(lv Level) string {
+	if lv == 0 {
+		return ""
+	}
+	return lv.LowerName()
+}
+
+// parse the level and handler index from the query/form values or JSON body
+func parseLevelRequest(req *http.Request) (level Level, index int, err error) {
+	var body struct {
+		Level   string `json:"level"`
+		Handler *int   `json:"handler"`
+	}
+
+	index = -1
+	if name := req.FormValue("level"); name != "" {
+		body.Level = name
+		if idx := req.FormValue("handler"); idx != "" {
+			if index, err = strconv.Atoi(idx); err != nil {
+				return 0, 0, fmt.Errorf("invalid handler index %q", idx)
+			}
+		}
+	} else {
+		if err = json.NewDecoder(req.Body).Decode(&body); err != nil {
+			return 0, 0, fmt.Errorf("invalid request body: %w", err)
+		}
+		if body.Handler != nil {
+			index = *body.Handler
+		}
+	}
+
+	if body.Level == "" {
+		return 0, 0, fmt.Errorf("the level is required")
+	}
+	if level, err = Name2Level(body.Level); err != nil {
+		return 0, 0, err
+	}
+	if index < -1 {
+		return 0, 0, fmt.Errorf("invalid handler index %d", index)
+	}
+	return level, index, nil
+}
+
+func (s *LevelServer) writeError(w http.ResponseWriter, status int, err error) {
+	s.writeJSON(w, status, map[string]string{"error": err.Error()})
+}
+
+func (s *LevelServer) writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,133 @@
+package slog_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/gookit/goutil/testutil/assert"
+	"github.com/gookit/slog"
+	"github.com/gookit/slog/handler"
+)
+
+func doLevelRequest(s http.Handler, method, uri, body string) (*httptest.ResponseRecorder, *slog.LevelState) {
+	req := httptest.NewRequest(method, uri, strings.NewReader(body))
+	if body != "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	w := httptest.NewRecorder()
+	s.ServeHTTP(w, req)
+
+	st := &slog.LevelState{}
+	_ = json.Unmarshal(w.Body.Bytes(), st)
+	return w, st
+}
+
+func TestLevelServer(t *testing.T) {
+	buf := new(bytes.Buffer)
+	h1 := handler.NewIOWriter(buf, slog.NormalLevels)
+	h2 := handler.WithMaxLevel(handler.NewIOWriter(buf, slog.AllLevels), slog.WarnLevel)
+	l := slog.NewWithHandlers(h1, h2)
+	s := slog.NewLevelServer(l)
+
+	w, st := doLevelRequest(s, http.MethodGet, "/loglevel", "")
+	assert.Eq(t, http.StatusOK, w.Code)
+	assert.Eq(t, "trace", st.Level)
+	assert.Len(t, st.Handlers, 2)
+	assert.Eq(t, "warn", st.Handlers[1].Level)
+	assert.True(t, st.Handlers[1].Settable)
+
+	// change all handlers by query
+	w, st = doLevelRequest(s, http.MethodPut, "/loglevel?level=error", "")
+	assert.Eq(t, http.StatusOK, w.Code)
+	assert.Eq(t, "error", st.Level)
+	assert.False(t, l.IsHandling(slog.WarnLevel))
+
+	l.Warn("warn message")
+	l.Error("error message")
+	assert.StrNotContains(t, buf.String(), "warn message")
+	assert.StrContains(t, buf.String(), "error message")
+
+	// change one handler by JSON body
+	w, st = doLevelRequest(s, http.MethodPut, "/loglevel", `{"level": "debug", "handler": 1}`)
+	assert.Eq(t, http.StatusOK, w.Code)
+	assert.Eq(t, "debug", st.Level)
+	assert.Eq(t, "error", st.Handlers[0].Level)
+	assert.Eq(t, "debug", st.Handlers[1].Level)
+	assert.True(t, l.IsHandling(slog.DebugLevel))
+}
+
+func TestLevelServer_error(t *testing.T) {
+	l := slog.NewWithHandlers(handler.WithMaxLevel(handler.NewIOWriter(new(bytes.Buffer), slog.AllLevels), slog.InfoLevel))
+	s := slog.NewLevelServer(l)
+
+	w, _ := doLevelRequest(s, http.MethodPost, "/loglevel", "")
+	assert.Eq(t, http.StatusMethodNotAllowed, w.Code)
+	assert.Eq(t, "GET, PUT", w.Header().Get("Allow"))
+
+	w, _ = doLevelRequest(s, http.MethodPut, "/loglevel?level=invalid", "")
+	assert.Eq(t, http.StatusBadRequest, w.Code)
+	assert.StrContains(t, w.Body.String(), "invalid")
+
+	w, _ = doLevelRequest(s, http.MethodPut, "/loglevel", `{"handler": 0}`)
+	assert.Eq(t, http.StatusBadRequest, w.Code)
+	assert.StrContains(t, w.Body.String(), "level is required")
+
+	w, _ = doLevelRequest(s, http.MethodPut, "/loglevel?level=debug&handler=3", "")
+	assert.Eq(t, http.StatusBadRequest, w.Code)
+	assert.StrContains(t, w.Body.String(), "out of range")
+
+	// handler not support change level
+	l = slog.NewWithHandlers(newTestHandler())
+	w, _ = doLevelRequest(slog.NewLevelServer(l), http.MethodPut, "/loglevel?level=debug", "")
+	assert.Eq(t, http.StatusBadRequest, w.Code)
+	assert.StrContains(t, w.Body.String(), "no handler support")
+}
+
+// go test -race -run TestLevelServer_concurrent
+func TestLevelServer_concurrent(t *testing.T) {
+	buf := new(bytes.Buffer)
+	h := handler.NewIOWriter(buf, slog.AllLevels)
+	l := slog.NewWithHandlers(h)
+	s := slog.NewLevelServer(l)
+
+	var wg, started sync.WaitGroup
+	stop := make(chan struct{})
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		started.Add(1)
+		go func() {
+			defer wg.Done()
+			_ = l.IsHandling(slog.InfoLevel)
+			started.Done()
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+				}
+				l.Info("info message")
+				_ = l.IsHandling(slog.DebugLevel)
+			}
+		}()
+	}
+
+	started.Wait()
+	for i := 0; i < 50; i++ {
+		if i%2 == 0 {
+			assert.NoErr(t, s.SetLevel(slog.ErrorLevel, -1))
+		} else {
+			h.SetMaxLevel(slog.DebugLevel)
+		}
+	}
+	close(stop)
+	wg.Wait()
+
+	assert.False(t, l.IsHandling(slog.TraceLevel))
+	assert.True(t, l.IsHandling(slog.DebugLevel))
+}
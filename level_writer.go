@@ -0,0 +1,108 @@
+package slog
+
+import (
+	"bytes"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// LevelRule match the line by regex pattern, and log it with the Level.
+type LevelRule struct {
+	Pattern *regexp.Regexp
+	Level   Level
+}
+
+// LevelWriter an io.Writer, split the written contents to lines,
+// then classifies each line by rules into different log levels.
+//
+// Useful on capture the stdout/stderr of subprocesses through slog. eg:
+//
+//	w := slog.NewLevelWriter(logger, slog.InfoLevel).
+//		AddRule(`^(ERROR|FATAL)`, slog.ErrorLevel).
+//		AddRule(`^WARN`, slog.WarnLevel)
+//	cmd.Stdout = w
+//	cmd.Stderr = w
+type LevelWriter struct {
+	mu  sync.Mutex
+	buf []byte
+	// logger for write log lines
+	logger *Logger
+	// Rules matched in order, the first matched rule will be used.
+	Rules []LevelRule
+	// Default level on no rule matched.
+	Default Level
+	// Fields append to each log record. eg: identifying the subprocess
+	Fields M
+}
+
+// NewLevelWriter create a new LevelWriter
+func NewLevelWriter(l *Logger, defLevel Level) *LevelWriter {
+	return &LevelWriter{logger: l, Default: defLevel}
+}
+
+// AddRule add a line match rule. will panic on pattern is invalid.
+func (w *LevelWriter) AddRule(pattern string, level Level) *LevelWriter {
+	w.Rules = append(w.Rules, LevelRule{
+		Pattern: regexp.MustCompile(pattern),
+		Level:   level,
+	})
+	return w
+}
+
+// LevelOf get log level of the line
+func (w *LevelWriter) LevelOf(line string) Level {
+	for _, rule := range w.Rules {
+		if rule.Pattern.MatchString(line) {
+			return rule.Level
+		}
+	}
+	return w.Default
+}
+
+// Write contents, the incomplete last line will be buffered until next newline or Flush.
+func (w *LevelWriter) Write(p []byte) (n int, err error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.buf = append(w.buf, p...)
+	for {
+		idx := bytes.IndexByte(w.buf, '\n')
+		if idx < 0 {
+			break
+		}
+
+		w.writeLine(string(w.buf[:idx]))
+		w.buf = w.buf[idx+1:]
+	}
+	return len(p), nil
+}
+
+// Flush write the buffered incomplete line.
+func (w *LevelWriter) Flush() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if len(w.buf) > 0 {
+		w.writeLine(string(w.buf))
+		w.buf = w.buf[:0]
+	}
+	return nil
+}
+
+// Close the writer, will flush buffered line.
+func (w *LevelWriter) Close() error {
+	return w.Flush()
+}
+
+func (w *LevelWriter) writeLine(line string) {
+	line = strings.TrimRight(line, "\r")
+	if line == "" {
+		return
+	}
+	if len(w.Fields) > 0 {
+		w.logger.WithFields(w.Fields).Log(w.LevelOf(line), line)
+	} else {
+		w.logger.Log(w.LevelOf(line), line)
+	}
+}
@@ -0,0 +1,36 @@
+package slog_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/gookit/goutil/byteutil"
+	"github.com/gookit/goutil/testutil/assert"
+	"github.com/gookit/slog"
+	"github.com/gookit/slog/handler"
+)
+
+func TestLevelWriter_Write(t *testing.T) {
+	buf := byteutil.NewBuffer()
+	h := handler.NewIOWriter(buf, slog.AllLevels)
+	h.SetFormatter(slog.NewTextFormatter("{{level}}|{{message}}\n"))
+	l := slog.NewWithHandlers(h)
+
+	w := slog.NewLevelWriter(l, slog.InfoLevel).
+		AddRule(`^ERROR`, slog.ErrorLevel).
+		AddRule(`(?i)^warn`, slog.WarnLevel)
+	assert.Eq(t, slog.ErrorLevel, w.LevelOf("ERROR: failed"))
+	assert.Eq(t, slog.InfoLevel, w.LevelOf("hello"))
+
+	_, err := fmt.Fprint(w, "line one\nERROR: some error\r\nwarning: a ")
+	assert.NoErr(t, err)
+	assert.Eq(t, "INFO|line one\nERROR|ERROR: some error\n", buf.String())
+
+	_, err = fmt.Fprint(w, "message\n\nlast line")
+	assert.NoErr(t, err)
+	assert.StrContains(t, buf.String(), "WARN|warning: a message\n")
+	assert.NotContains(t, buf.String(), "last line")
+
+	assert.NoErr(t, w.Close())
+	assert.StrContains(t, buf.String(), "INFO|last line\n")
+}
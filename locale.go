@@ -0,0 +1,104 @@
+package slog
+
+import (
+	"strings"
+	"time"
+)
+
+// DateLocale the localized names for render the date in formatters.
+// see TextFormatter.DateLocale, JSONFormatter.DateLocale
+//
+// The names in the time layout will be replaced: "January", "Jan", "Monday", "Mon", "PM", "pm".
+// if a name is not set, the English name will be used.
+//
+// Usage:
+//
+//	f := slog.NewTextFormatter()
+//	f.TimeFormat = "2006年1月2日 Monday 15:04:05"
+//	f.DateLocale = &slog.DateLocale{
+//		Days: []string{"星期日", "星期一", "星期二", "星期三", "星期四", "星期五", "星期六"},
+//	}
+type DateLocale struct {
+	// Months the month names, from January
+	Months []string
+	// ShortMonths the short month names, from Jan
+	ShortMonths []string
+	// Days the weekday names, from Sunday
+	Days []string
+	// ShortDays the short weekday names, from Sun
+	ShortDays []string
+	// AM, PM the names of the AM, PM
+	AM, PM string
+}
+
+// the layout tokens can be localized, the longer one must be first.
+var localeTokens = []string{"January", "Jan", "Monday", "Mon", "PM", "pm"}
+
+// Format the time by the layout, with the localized names
+func (dl *DateLocale) Format(t time.Time, layout string) string {
+	return string(dl.AppendFormat(nil, t, layout))
+}
+
+// AppendFormat like the time.Time.AppendFormat(), with the localized names
+func (dl *DateLocale) AppendFormat(b []byte, t time.Time, layout string) []byte {
+	for layout != "" {
+		i, tok := indexLocaleToken(layout)
+		if i < 0 {
+			break
+		}
+
+		b = t.AppendFormat(b, layout[:i])
+		if name := dl.name(t, tok); name != "" {
+			b = append(b, name...)
+		} else {
+			b = t.AppendFormat(b, tok)
+		}
+		layout = layout[i+len(tok):]
+	}
+	return t.AppendFormat(b, layout)
+}
+
+func (dl *DateLocale) name(t time.Time, tok string) string {
+	switch tok {
+	case "January":
+		return indexName(dl.Months, int(t.Month())-1)
+	case "Jan":
+		return indexName(dl.ShortMonths, int(t.Month())-1)
+	case "Monday":
+		return indexName(dl.Days, int(t.Weekday()))
+	case "Mon":
+		return indexName(dl.ShortDays, int(t.Weekday()))
+	}
+
+	if t.Hour() >= 12 {
+		return dl.PM
+	}
+	return dl.AM
+}
+
+func indexName(names []string, i int) string {
+	if i < len(names) {
+		return names[i]
+	}
+	return ""
+}
+
+// find the first localized token in the layout
+func indexLocaleToken(layout string) (int, string) {
+	for i := 0; i < len(layout); i++ {
+		for _, tok := range localeTokens {
+			if strings.HasPrefix(layout[i:], tok) {
+				return i, tok
+			}
+		}
+	}
+	return -1, ""
+}
+
+// get the display name of the record level, use the custom names on it is set.
+func displayLevelName(names map[Level]string, r *Record) string {
+	if name, ok := names[r.Level]; ok {
+		return name
+	}
+	return r.LevelName()
+}
@@ -0,0 +1,62 @@
+package slog_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gookit/goutil/testutil/assert"
+	"github.com/gookit/slog"
+)
+
+func TestDateLocale_Format(t *testing.T) {
+	dl := &slog.DateLocale{
+		Months:    []string{"janvier", "février", "mars"},
+		ShortDays: []string{"dim.", "lun.", "mar.", "mer.", "jeu.", "ven.", "sam."},
+		AM:        "上午",
+		PM:        "下午",
+	}
+
+	tt := time.Date(2023, 2, 8, 15, 4, 5, 0, time.UTC)
+	assert.Eq(t, "mer. 8 février 2023 03:04 下午", dl.Format(tt, "Mon 2 January 2006 03:04 PM"))
+	// not set names, use the English names
+	assert.Eq(t, "Wednesday, Feb 08", dl.Format(tt, "Monday, Jan 02"))
+	assert.Eq(t, "2023-02-08 15:04:05", dl.Format(tt, "2006-01-02 15:04:05"))
+
+	tt = time.Date(2023, 3, 5, 9, 0, 0, 0, time.UTC)
+	assert.Eq(t, "dim. mars 上午", dl.Format(tt, "Mon January pm"))
+}
+
+func TestFormatter_LevelNames(t *testing.T) {
+	r := newLogRecord("level names")
+	r.Level = slog.WarnLevel
+	r.Time = time.Date(2023, 2, 8, 15, 4, 5, 0, time.UTC)
+
+	tf := slog.NewTextFormatter("{{datetime}} {{level}} {{message}}\n")
+	tf.TimeFormat = "2006年1月2日 Monday"
+	tf.LevelNames = map[slog.Level]string{slog.WarnLevel: "SEVERE"}
+	tf.DateLocale = &slog.DateLocale{
+		Days: []string{"星期日", "星期一", "星期二", "星期三", "星期四", "星期五", "星期六"},
+	}
+
+	bs, err := tf.Format(r)
+	assert.NoErr(t, err)
+	assert.Eq(t, "2023年2月8日 星期三 SEVERE level names\n", string(bs))
+
+	// not set the level name
+	r.Level = slog.InfoLevel
+	r.Init(false)
+	bs, err = tf.Format(r)
+	assert.NoErr(t, err)
+	assert.StrContains(t, string(bs), " INFO level names")
+
+	jf := slog.NewJSONFormatter(func(f *slog.JSONFormatter) {
+		f.Fields = []string{slog.FieldKeyDatetime, slog.FieldKeyLevel}
+		f.TimeFormat = "Jan 2"
+		f.LevelNames = map[slog.Level]string{slog.InfoLevel: "信息"}
+		f.DateLocale = &slog.DateLocale{ShortMonths: []string{"1月", "2月"}}
+	})
+	bs, err = jf.Format(r)
+	assert.NoErr(t, err)
+	assert.StrContains(t, string(bs), `"datetime":"2月 8"`)
+	assert.StrContains(t, string(bs), `"level":"信息"`)
+}
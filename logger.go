@@ -3,32 +3,66 @@ package slog
 import (
 	"context"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/gookit/goutil"
+	"github.com/gookit/goutil/strutil"
 )
 
 // Logger log dispatcher definition.
 //
 // The logger implements the `github.com/gookit/gsr.Logger`
+//
+// Concurrency: the logger is safe for concurrent logging. The logging reads an immutable snapshot
+// of the options, the snapshot is taken on first use and replaced by Config(). so on logging,
+// please change the options by Config(), handlers and processors can be added by the setters directly.
 type Logger struct {
 	name string
 	// lock for write logs
 	mu sync.Mutex
-	// logger latest error
-	err error
+	// lock for change options, and the snapshot of options for logging. see Config()
+	cfgMu sync.Mutex
+	opts  atomic.Pointer[loggerOptions]
+	// logger latest error and the recent errors. see RecentErrors()
+	errs errorRing
 	// mark logger is closed
 	closed bool
-
-	// log handlers for logger
-	handlers   []Handler
-	processors []Processor
+	// dry-run mode, records are processed and formatted but not written. see SetDryRun()
+	dryRun atomic.Bool
+
+	// log handlers and processors for logger. them are copy-on-write,
+	// so can be safely updated on logging. lock by setMu for update.
+	setMu      sync.Mutex
+	handlers   atomic.Pointer[[]Handler]
+	processors atomic.Pointer[[]Processor]
+	// extract values from the record context. see AddContextExtractor()
+	ctxExtractors atomic.Pointer[[]ContextExtractor]
+	// sampler for drop records, and the dropped stats
+	sampler  atomic.Pointer[Sampler]
+	sampling atomic.Pointer[samplingStats]
+
+	// the root logger and the inherited fields for child logger. see With()
+	root   *Logger
+	fields M
 
 	// reusable empty record
 	recordPool sync.Pool
 	// handlers on exit.
 	exitHandlers []func()
-	quitDaemon   chan struct{}
+	// cleanup funcs on exit, added by FatalDefer()
+	cleanups []func()
+	// stop the FlushDaemon(). lock by setMu
+	quitDaemon chan struct{}
+	// logger created time, and the written records count by level
+	startAt     time.Time
+	levelCounts map[Level]uint64
+	// the sequence for generate the top operation id. see Begin()
+	opSeq atomic.Uint64
+	// last time from TimeClock, and the clock warnings has been reported
+	lastTime   time.Time
+	skewWarned bool
+	jumpWarned bool
 
 	//
 	// logger options
@@ -44,15 +78,91 @@ type Logger struct {
 	ReportCaller bool
 	CallerSkip   int
 	CallerFlag   uint8
+	// CallerSkipPackages the frames in the packages will be skipped on report caller,
+	// so the caller is right on wrap the logger. eg: []string{"github.com/my/pkg/logx"}
+	CallerSkipPackages []string
+	// StackDepth max frames of the stack trace on Record.EnableStack=true. default is DefaultStackDepth
+	StackDepth int
+	// StackSkip skip more frames from the caller on capture the stack trace. default is 0
+	StackSkip int
 	// BackupArgs backup log input args to Record.Args
 	BackupArgs bool
+	// ExitSummary emit a final "process exiting" record on Exit, contains exit code,
+	// uptime and the written records count by level, then flush all handlers.
+	ExitSummary bool
+	// SampleReportInterval emit a meta-record at WarnLevel on the sampler dropped records,
+	// at most once per interval. 0 is disable.
+	SampleReportInterval time.Duration
 	// TimeClock custom time clock, timezone
 	TimeClock ClockFn
+	// ClockSkewThreshold warn once on the TimeClock time is far from the system clock
+	// over the threshold, and warn once on the TimeClock time goes backwards. 0 is disable.
+	//
+	// Helping diagnose records that sort incorrectly downstream. see ReportInternal()
+	ClockSkewThreshold time.Duration
+	// ExitTimeout max time for run the exit handlers and cleanups before call ExitFunc.
+	// 0 is not limit.
+	ExitTimeout time.Duration
+	// FatalAsPanic call PanicFunc instead of exit on fatal level.
+	//
+	// Useful for libraries: avoid killing the host process, the caller can recover it.
+	FatalAsPanic bool
+	// PanicAsFatal call exit(ExitFunc) instead of PanicFunc on panic level.
+	PanicAsFatal bool
 	// custom exit, panic handler.
 	ExitFunc  func(code int)
 	PanicFunc func(v any)
 }
 
+// loggerOptions an immutable snapshot of the logger options, it is read on logging.
+// see the same name fields of the Logger.
+type loggerOptions struct {
+	channelName          string
+	flushInterval        time.Duration
+	lowerLevelName       bool
+	reportCaller         bool
+	callerSkip           int
+	callerFlag           uint8
+	callerSkipPackages   []string
+	stackDepth           int
+	stackSkip            int
+	backupArgs           bool
+	exitSummary          bool
+	sampleReportInterval time.Duration
+	timeClock            ClockFn
+	clockSkewThreshold   time.Duration
+	exitTimeout          time.Duration
+	fatalAsPanic         bool
+	panicAsFatal         bool
+	exitFunc             func(code int)
+	panicFunc            func(v any)
+}
+
+// take a snapshot of the option fields. l.cfgMu is held.
+func (l *Logger) newOptions() *loggerOptions {
+	return &loggerOptions{
+		channelName:          strutil.OrElse(l.ChannelName, DefaultChannelName),
+		flushInterval:        l.FlushInterval,
+		lowerLevelName:       l.LowerLevelName,
+		reportCaller:         l.ReportCaller,
+		callerSkip:           l.CallerSkip,
+		callerFlag:           l.CallerFlag,
+		callerSkipPackages:   append([]string(nil), l.CallerSkipPackages...),
+		stackDepth:           l.StackDepth,
+		stackSkip:            l.StackSkip,
+		backupArgs:           l.BackupArgs,
+		exitSummary:          l.ExitSummary,
+		sampleReportInterval: l.SampleReportInterval,
+		timeClock:            l.TimeClock,
+		clockSkewThreshold:   l.ClockSkewThreshold,
+		exitTimeout:          l.ExitTimeout,
+		fatalAsPanic:         l.FatalAsPanic,
+		panicAsFatal:         l.PanicAsFatal,
+		exitFunc:             l.ExitFunc,
+		panicFunc:            l.PanicFunc,
+	}
+}
+
 // New create a new logger
 func New(fns ...LoggerFn) *Logger {
 	return NewWithName("logger", fns...)
@@ -85,24 +195,56 @@ func NewWithName(name string, fns ...LoggerFn) *Logger {
 		TimeClock:    DefaultClockFn,
 		// flush interval time
 		FlushInterval: defaultFlushInterval,
+		startAt:       time.Now(),
+		levelCounts:   make(map[Level]uint64, len(AllLevels)),
 	}
 
 	logger.recordPool.New = func() any {
 		return newRecord(logger)
 	}
-	return logger.Config(fns...)
+
+	// the options snapshot is taken on first use, so the options can be set directly after create.
+	for _, fn := range fns {
+		fn(logger)
+	}
+	return logger
 }
 
 // NewRecord get new logger record
 func (l *Logger) newRecord() *Record {
+	if l.root != nil {
+		r := l.root.newRecord()
+		r.Channel = l.loadOpts().channelName
+
+		if len(l.fields) > 0 {
+			r.Fields = mergeMap(nil, l.fields, true)
+		}
+		return r
+	}
+
+	opts := l.loadOpts()
 	r := l.recordPool.Get().(*Record)
 	r.reuse = false
 	r.freed = false
+	r.Channel = opts.channelName
+	r.CallerFlag = opts.callerFlag
+	r.CallerSkip = opts.callerSkip
+	r.EnableStack = false
+	r.recovered = false
+	r.injected = false
+	r.Ctx = nil
+	r.Caller = nil
 	r.Fields = nil
+	r.Tags = nil
 	return r
 }
 
 func (l *Logger) releaseRecord(r *Record) {
+	// the records are allocated by the root logger
+	if l.root != nil {
+		l.root.releaseRecord(r)
+		return
+	}
 	if r.reuse || r.freed {
 		return
 	}
@@ -111,13 +253,16 @@ func (l *Logger) releaseRecord(r *Record) {
 	r.Time = emptyTime
 	r.Data = map[string]any{}
 	r.Extra = nil
+	r.Attrs = r.Attrs[:0]
+	r.Ctx = nil
+	r.Caller = nil
+	r.callerFixed = false
 	// reset flags
 	r.inited = false
 	r.reuse = false
 	r.freed = true
 
 	r.Message = ""
-	r.CallerSkip = l.CallerSkip
 	l.recordPool.Put(r)
 }
 
@@ -127,14 +272,66 @@ func (l *Logger) releaseRecord(r *Record) {
 // ---------------------------------------------------------------------------
 //
 
-// Config current logger
+// Config current logger.
+//
+// The fns are applied with the logger lock held, then a new snapshot of the options will be used by the logging.
+// so it is safe to change the options concurrently with logging. don't log by the logger in the fns.
+//
+// NOTICE: after the logger is used, change the option fields directly will not take effect.
+//
+// Usage:
+//
+//	// on running, safe to change the options
+//	logger.Config(func(l *slog.Logger) {
+//		l.ReportCaller = false
+//	})
+//
+// On a child logger, the fns are applied to the root logger. see With()
 func (l *Logger) Config(fns ...LoggerFn) *Logger {
-	for _, fn := range fns {
-		fn(l)
+	if l.root != nil {
+		l.root.Config(fns...)
+		return l
 	}
+
+	l.update(func() {
+		for _, fn := range fns {
+			fn(l)
+		}
+	})
 	return l
 }
 
+// update the option fields by fn, then replace the options snapshot.
+func (l *Logger) update(fn func()) {
+	// make sure the snapshot exists, the fn can use it. eg: call With() in the fn
+	l.loadOpts()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.cfgMu.Lock()
+	defer l.cfgMu.Unlock()
+
+	fn()
+	l.opts.Store(l.newOptions())
+}
+
+// load the options snapshot, take it from the option fields on first use.
+func (l *Logger) loadOpts() *loggerOptions {
+	if opts := l.opts.Load(); opts != nil {
+		return opts
+	}
+
+	l.cfgMu.Lock()
+	defer l.cfgMu.Unlock()
+
+	opts := l.opts.Load()
+	if opts == nil {
+		opts = l.newOptions()
+		l.opts.Store(opts)
+	}
+	return opts
+}
+
 // Configure current logger. alias of Config()
 func (l *Logger) Configure(fn LoggerFn) *Logger { return l.Config(fn) }
 
@@ -176,22 +373,27 @@ const defaultFlushInterval = 30 * time.Second
 //
 // Usage please refer to the FlushDaemon() on package.
 func (l *Logger) FlushDaemon(onStops ...func()) {
-	l.quitDaemon = make(chan struct{})
-	if l.FlushInterval <= 0 {
-		l.FlushInterval = defaultFlushInterval
+	l.setMu.Lock()
+	quit := make(chan struct{})
+	l.quitDaemon = quit
+	l.setMu.Unlock()
+
+	interval := l.loadOpts().flushInterval
+	if interval <= 0 {
+		interval = defaultFlushInterval
 	}
 
 	// create a ticker
-	tk := time.NewTicker(l.FlushInterval)
+	tk := time.NewTicker(interval)
 	defer tk.Stop()
 
 	for {
 		select {
 		case <-tk.C:
 			if err := l.lockAndFlushAll(); err != nil {
-				printlnStderr("slog.FlushDaemon: daemon flush logs error: ", err)
+				reportError("slog.FlushDaemon: daemon flush logs error: ", err)
 			}
-		case <-l.quitDaemon:
+		case <-quit:
 			for _, fn := range onStops {
 				fn()
 			}
@@ -202,10 +404,14 @@ func (l *Logger) FlushDaemon(onStops ...func()) {
 
 // StopDaemon stop flush daemon
 func (l *Logger) StopDaemon() {
+	l.setMu.Lock()
+	defer l.setMu.Unlock()
+
 	if l.quitDaemon == nil {
 		panic("cannot quit daemon, please call FlushDaemon() first")
 	}
 	close(l.quitDaemon)
+	l.quitDaemon = nil
 }
 
 // FlushTimeout flush logs on limit time.
@@ -215,7 +421,7 @@ func (l *Logger) FlushTimeout(timeout time.Duration) {
 	done := make(chan bool, 1)
 	go func() {
 		if err := l.lockAndFlushAll(); err != nil {
-			printlnStderr("slog.FlushTimeout: flush logs error: ", err)
+			reportError("slog.FlushTimeout: flush logs error: ", err)
 		}
 		done <- true
 	}()
@@ -223,7 +429,7 @@ func (l *Logger) FlushTimeout(timeout time.Duration) {
 	select {
 	case <-done:
 	case <-time.After(timeout):
-		printlnStderr("slog.FlushTimeout: flush took longer than timeout:", timeout)
+		ReportInternal(WarnLevel, "slog.FlushTimeout: flush took longer than timeout:", timeout)
 	}
 }
 
@@ -246,11 +452,15 @@ func (l *Logger) FlushAll() error { return l.lockAndFlushAll() }
 
 // lockAndFlushAll is like flushAll but locks l.mu first.
 func (l *Logger) lockAndFlushAll() error {
+	if l.root != nil {
+		return l.root.lockAndFlushAll()
+	}
+
 	l.mu.Lock()
 	l.flushAll()
 	l.mu.Unlock()
 
-	return l.err
+	return l.errs.lastErr()
 }
 
 // flush all without lock
@@ -258,8 +468,8 @@ func (l *Logger) flushAll() {
 	// flush from fatal down, in case there's trouble flushing.
 	_ = l.VisitAll(func(handler Handler) error {
 		if err := handler.Flush(); err != nil {
-			l.err = err
-			printlnStderr("slog: call handler.Flush() error:", err)
+			l.errs.add("flush", handler, err)
+			reportError("slog: call handler.Flush() error, handler:", HandlerName(handler), "error:", err)
 		}
 		return nil
 	})
@@ -276,25 +486,28 @@ func (l *Logger) MustClose() {
 //
 //	if enable async/buffer mode, please call the Close() before exit.
 func (l *Logger) Close() error {
+	if l.root != nil {
+		return l.root.Close()
+	}
 	if l.closed {
 		return nil
 	}
 
 	_ = l.VisitAll(func(handler Handler) error {
 		if err := handler.Close(); err != nil {
-			l.err = err
-			printlnStderr("slog: call handler.Close() error:", err)
+			l.errs.add("close", handler, err)
+			reportError("slog: call handler.Close() error, handler:", HandlerName(handler), "error:", err)
 		}
 		return nil
 	})
 
 	l.closed = true
-	return l.err
+	return l.errs.lastErr()
 }
 
 // VisitAll logger handlers
 func (l *Logger) VisitAll(fn func(handler Handler) error) error {
-	for _, handler := range l.handlers {
+	for _, handler := range l.rootLogger().loadHandlers() {
 		// TIP: you can return nil for ignore error
 		if err := fn(handler); err != nil {
 			return err
@@ -311,55 +524,243 @@ func (l *Logger) Reset() {
 }
 
 // ResetProcessors for the logger
-func (l *Logger) ResetProcessors() {
-	l.processors = make([]Processor, 0)
-}
+func (l *Logger) ResetProcessors() { l.SetProcessors(make([]Processor, 0)) }
 
 // ResetHandlers for the logger
-func (l *Logger) ResetHandlers() {
-	l.handlers = make([]Handler, 0)
-}
+func (l *Logger) ResetHandlers() { l.SetHandlers(make([]Handler, 0)) }
 
 // Exit logger handle
-func (l *Logger) Exit(code int) {
-	l.runExitHandlers()
+func (l *Logger) Exit(code int) { l.rootLogger().exit(code, false) }
+
+// exit logger handle. locked - whether the l.mu is held.
+func (l *Logger) exit(code int, locked bool) {
+	if !locked {
+		l.mu.Lock()
+	}
+
+	opts := l.loadOpts()
+	if opts.exitSummary {
+		l.exitSummary(code)
+		l.flushAll()
+	}
 
-	// global exit handlers
-	runExitHandlers()
+	// take the cleanup funcs from FatalDefer(), and the exit handlers.
+	// them may be still running in the background after the ExitTimeout.
+	cleanups, handlers, globals := l.cleanups, l.exitHandlers, exitHandlers
+	l.cleanups = nil
 
-	if l.ExitFunc != nil {
-		l.ExitFunc(code)
+	// release the lock, the cleanups and exit handlers can use the logger.
+	l.mu.Unlock()
+	if locked {
+		defer l.mu.Lock()
+	}
+
+	l.runWithTimeout(opts.exitTimeout, func() {
+		for _, fn := range cleanups {
+			runSafely("cleanup", fn)
+		}
+
+		l.runExitHandlers(handlers)
+
+		// global exit handlers
+		runExitHandlers(globals)
+	})
+
+	if opts.exitFunc != nil {
+		opts.exitFunc(code)
+	}
+}
+
+// run exit handlers with the ExitTimeout.
+func (l *Logger) runWithTimeout(timeout time.Duration, fn func()) {
+	if timeout <= 0 {
+		fn()
+		return
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		fn()
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(timeout):
+		ReportInternal(WarnLevel, "slog: run exit handlers took longer than timeout:", timeout)
+	}
+}
+
+// FatalDefer logs a message at level fatal, will run the cleanup func before exit.
+//
+// Unlike the defers, the cleanup func will be run even if the process is exited by ExitFunc.
+// it is run without the logger lock, so it can log by the logger. see Logger.ExitTimeout for limit the cleanup time.
+func (l *Logger) FatalDefer(msg string, cleanup func()) {
+	if cleanup != nil {
+		rl := l.rootLogger()
+		rl.mu.Lock()
+		rl.cleanups = append(rl.cleanups, cleanup)
+		rl.mu.Unlock()
+	}
+	l.log(FatalLevel, []any{msg})
+}
+
+// emit the exit summary record. l.mu is held.
+func (l *Logger) exitSummary(code int) {
+	counts := make(M, len(l.levelCounts))
+	for level, n := range l.levelCounts {
+		counts[level.Name()] = n
+	}
+
+	r := l.newRecord()
+	r.Level = InfoLevel
+	r.Message = "process exiting"
+	r.Data = M{
+		"exit_code":    code,
+		"uptime":       time.Since(l.startAt).String(),
+		"level_counts": counts,
+	}
+
+	l.dispatch(r)
+	l.releaseRecord(r)
+}
+
+// LevelCounts get the written records count by level
+func (l *Logger) LevelCounts() map[Level]uint64 {
+	if l.root != nil {
+		return l.root.LevelCounts()
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	mp := make(map[Level]uint64, len(l.levelCounts))
+	for level, n := range l.levelCounts {
+		mp[level] = n
 	}
+	return mp
 }
 
-func (l *Logger) runExitHandlers() {
+func (l *Logger) runExitHandlers(handlers []func()) {
 	defer func() {
 		if err := recover(); err != nil {
-			printlnStderr("slog: run exit handler recovered, error:", err)
+			reportError("slog: run exit handler recovered, error:", err)
 		}
 	}()
 
-	for _, handler := range l.exitHandlers {
+	for _, handler := range handlers {
 		handler()
 	}
 }
 
 // DoNothingOnPanicFatal do nothing on panic or fatal level. useful on testing.
+//
+// NOTICE: it changes the option fields, please call it in the Config() fns after the logger is used.
 func (l *Logger) DoNothingOnPanicFatal() {
 	l.PanicFunc = DoNothingOnPanic
 	l.ExitFunc = DoNothingOnExit
 }
 
+// SetDryRun set the dry-run mode for the logger.
+//
+// On dry-run, the records are processed and formatted by the handler formatters,
+// but not written by handlers. useful for benchmarks and validate config before production.
+//
+// NOTICE: the handlers that not implement Formattable will be skipped.
+func (l *Logger) SetDryRun(dryRun bool) {
+	l.rootLogger().dryRun.Store(dryRun)
+}
+
+// IsDryRun check the logger is in dry-run mode. see SetDryRun()
+func (l *Logger) IsDryRun() bool {
+	return l.rootLogger().dryRun.Load()
+}
+
+//
+// ---------------------------------------------------------------------------
+// Child logger
+// ---------------------------------------------------------------------------
+//
+
+// With create a child logger with the fields, the fields will be added to every record.
+//
+// The child logger shares the handlers, processors and options with the root logger,
+// so please config them on the root logger. Config, Flush, Close and Exit are delegated to it.
+//
+// Usage:
+//
+//	reqLogger := logger.With(slog.M{"request_id": reqID})
+//	reqLogger.Info("request started")
+func (l *Logger) With(fields M) *Logger {
+	nl := l.child()
+	nl.fields = mergeMap(nl.fields, fields, true)
+	return nl
+}
+
+// WithChannel create a child logger with the channel name. see With()
+//
+// Usage:
+//
+//	orderLogger := logger.WithChannel("order")
+func (l *Logger) WithChannel(name string) *Logger {
+	nl := l.child()
+	nl.ChannelName = name
+	return nl
+}
+
+// create a child logger, inherit the fields and channel from current logger.
+func (l *Logger) child() *Logger {
+	return &Logger{
+		name:        l.name,
+		root:        l.rootLogger(),
+		fields:      mergeMap(nil, l.fields, true),
+		ChannelName: l.loadOpts().channelName,
+	}
+}
+
+// get the root logger, returns self on it is not a child logger.
+func (l *Logger) rootLogger() *Logger {
+	if l.root != nil {
+		return l.root
+	}
+	return l
+}
+
+// Fields get the inherited fields of the child logger. see With()
+func (l *Logger) Fields() M {
+	return l.fields
+}
+
+// IsHandling check the level is handled by any handler
+func (l *Logger) IsHandling(level Level) bool {
+	for _, handler := range l.rootLogger().loadHandlers() {
+		if handler.IsHandling(level) {
+			return true
+		}
+	}
+	return false
+}
+
 // HandlersNum returns the number of handlers
 func (l *Logger) HandlersNum() int {
-	return len(l.handlers)
+	return len(l.rootLogger().loadHandlers())
 }
 
 // LastErr fetch, will clear it after read.
 func (l *Logger) LastErr() error {
-	err := l.err
-	l.err = nil
-	return err
+	return l.rootLogger().errs.takeLast()
+}
+
+// RecentErrors get the recent internal errors of the logger, from oldest to newest.
+// keep at most RecentErrorsSize errors, and not be cleared by LastErr().
+//
+// Usage:
+//
+//	for _, er := range logger.RecentErrors() {
+//		fmt.Println(er.Time, er.Op, er.Handler, er.Err)
+//	}
+func (l *Logger) RecentErrors() []ErrorRecord {
+	return l.rootLogger().errs.recent()
 }
 
 //
@@ -377,27 +778,150 @@ func (l *Logger) AddHandlers(hs ...Handler) { l.PushHandlers(hs...) }
 // PushHandler to the l. alias of AddHandler()
 func (l *Logger) PushHandler(h Handler) { l.PushHandlers(h) }
 
-// PushHandlers to the logger
+// PushHandlers to the logger. it is safe to call concurrently with logging.
 func (l *Logger) PushHandlers(hs ...Handler) {
 	if len(hs) > 0 {
-		l.handlers = append(l.handlers, hs...)
+		rl := l.rootLogger()
+		rl.setMu.Lock()
+		rl.handlers.Store(appendCopy(rl.loadHandlers(), hs))
+		rl.setMu.Unlock()
 	}
 }
 
 // SetHandlers for the logger
-func (l *Logger) SetHandlers(hs []Handler) { l.handlers = hs }
+func (l *Logger) SetHandlers(hs []Handler) {
+	rl := l.rootLogger()
+	rl.setMu.Lock()
+	rl.handlers.Store(&hs)
+	rl.setMu.Unlock()
+}
+
+// RemoveHandler remove the handler from the logger, returns false on not found.
+//
+// After returns, the handler will not receive new records, so it can be flushed and closed safely.
+// NOTICE: the removed handler will not be closed. don't call it in the handlers or processors.
+//
+// Usage:
+//
+//	if l.RemoveHandler(netHandler) {
+//		netHandler.Close()
+//	}
+func (l *Logger) RemoveHandler(h Handler) bool {
+	return l.updateHandler(func(hs []Handler) (int, Handler) {
+		return indexHandler(hs, func(item Handler) bool { return item == h }), nil
+	})
+}
+
+// RemoveHandlerByName remove the first handler that matched the name, returns the removed handler.
+// returns nil on not found. see HandlerName()
+func (l *Logger) RemoveHandlerByName(name string) Handler {
+	var removed Handler
+	l.updateHandler(func(hs []Handler) (int, Handler) {
+		i := indexHandler(hs, func(item Handler) bool { return HandlerName(item) == name })
+		if i >= 0 {
+			removed = hs[i]
+		}
+		return i, nil
+	})
+	return removed
+}
+
+// ReplaceHandler replace the old handler with the new handler at same position, returns false on old not found.
+//
+// After returns, the old handler will not receive new records, so it can be flushed and closed safely.
+// NOTICE: don't call it in the handlers or processors.
+//
+// Usage:
+//
+//	// re-point the network handler
+//	newHandler := handler.NewNetHandler("tcp", "new-collector:5140")
+//	if l.ReplaceHandler(oldHandler, newHandler) {
+//		oldHandler.Close()
+//	}
+func (l *Logger) ReplaceHandler(old, new Handler) bool {
+	return l.updateHandler(func(hs []Handler) (int, Handler) {
+		return indexHandler(hs, func(item Handler) bool { return item == old }), new
+	})
+}
+
+// update the handler at the index returned by fn. will remove it on the returned handler is nil.
+//
+// Will lock l.mu, so the running dispatch is done, and the old handler will not be used after returns.
+func (l *Logger) updateHandler(fn func(hs []Handler) (int, Handler)) bool {
+	rl := l.rootLogger()
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+	rl.setMu.Lock()
+	defer rl.setMu.Unlock()
+
+	hs := rl.loadHandlers()
+	i, nh := fn(hs)
+	if i < 0 {
+		return false
+	}
+
+	ns := make([]Handler, 0, len(hs))
+	ns = append(ns, hs[:i]...)
+	if nh != nil {
+		ns = append(ns, nh)
+	}
+	ns = append(ns, hs[i+1:]...)
+
+	rl.handlers.Store(&ns)
+	return true
+}
+
+func indexHandler(hs []Handler, match func(h Handler) bool) int {
+	for i, h := range hs {
+		if match(h) {
+			return i
+		}
+	}
+	return -1
+}
 
 // AddProcessor to the logger
-func (l *Logger) AddProcessor(p Processor) { l.processors = append(l.processors, p) }
+func (l *Logger) AddProcessor(p Processor) { l.AddProcessors(p) }
 
 // PushProcessor to the logger, alias of AddProcessor()
-func (l *Logger) PushProcessor(p Processor) { l.processors = append(l.processors, p) }
-
-// AddProcessors to the logger. alias of AddProcessor()
-func (l *Logger) AddProcessors(ps ...Processor) { l.processors = append(l.processors, ps...) }
+func (l *Logger) PushProcessor(p Processor) { l.AddProcessors(p) }
+
+// AddProcessors to the logger. it is safe to call concurrently with logging.
+func (l *Logger) AddProcessors(ps ...Processor) {
+	rl := l.rootLogger()
+	rl.setMu.Lock()
+	rl.processors.Store(appendCopy(rl.loadProcessors(), ps))
+	rl.setMu.Unlock()
+}
 
 // SetProcessors for the logger
-func (l *Logger) SetProcessors(ps []Processor) { l.processors = ps }
+func (l *Logger) SetProcessors(ps []Processor) {
+	rl := l.rootLogger()
+	rl.setMu.Lock()
+	rl.processors.Store(&ps)
+	rl.setMu.Unlock()
+}
+
+func (l *Logger) loadHandlers() []Handler {
+	if hs := l.handlers.Load(); hs != nil {
+		return *hs
+	}
+	return nil
+}
+
+func (l *Logger) loadProcessors() []Processor {
+	if ps := l.processors.Load(); ps != nil {
+		return *ps
+	}
+	return nil
+}
+
+// append to a new slice, the old slice may be in use by logging.
+func appendCopy[T any](old, items []T) *[]T {
+	ns := make([]T, 0, len(old)+len(items))
+	ns = append(append(ns, old...), items...)
+	return &ns
+}
 
 //
 // ---------------------------------------------------------------------------
@@ -425,6 +949,42 @@ func (l *Logger) Reused() *Record {
 	return l.newRecord().Reused()
 }
 
+// NewRecordAt new a fully-formed record with the original time, for inject it by WriteRecord().
+// Useful for the log-forwarding agents and replay tools.
+//
+// Usage:
+//
+//	r := logger.NewRecordAt(ts, slog.WarnLevel, "disk is almost full", slog.M{"host": "web1"})
+//	r.SetCaller(frame) // optional, the caller will not be reported for the injected record
+//	logger.WriteRecord(r)
+func (l *Logger) NewRecordAt(t time.Time, level Level, msg string, fields M) *Record {
+	r := l.newRecord()
+	r.Time = t
+	r.Level = level
+	r.Message = msg
+	if len(fields) > 0 {
+		r.Fields = mergeMap(r.Fields, fields, true)
+	}
+	return r
+}
+
+// WriteRecord write a fully-formed record to the handlers, then release it.
+// see NewRecordAt()
+//
+// The record is passed through the sampler, processors and handlers as normal, but:
+//
+//   - the Time of record is kept, will use now on it is zero.
+//   - the caller is not reported, only use the caller set by Record.SetCaller().
+//   - will not call PanicFunc or exit on the Panic, Fatal level.
+func (l *Logger) WriteRecord(r *Record) {
+	lr := l.rootLogger()
+	r.logger = lr
+	r.injected = true
+	r.callerFixed = true
+
+	lr.writeRecord(r.Level, r, r.Fmt, r.Message, r.Args)
+}
+
 // WithField new record with field
 //
 // TIP: add field need config Formatter template fields.
@@ -443,6 +1003,20 @@ func (l *Logger) WithFields(fields M) *Record {
 	return r.WithFields(fields)
 }
 
+// WithAttrs new record with typed fields. see String(), Int(), Err() ...
+func (l *Logger) WithAttrs(fields ...Field) *Record {
+	r := l.newRecord()
+	defer l.releaseRecord(r)
+	return r.WithAttrs(fields...)
+}
+
+// WithTags new record with tags
+func (l *Logger) WithTags(tags ...string) *Record {
+	r := l.newRecord()
+	defer l.releaseRecord(r)
+	return r.WithTags(tags...)
+}
+
 // WithData new record with data
 func (l *Logger) WithData(data M) *Record {
 	r := l.newRecord()
@@ -549,8 +1123,21 @@ func (l *Logger) ErrorT(err error) {
 	}
 }
 
-// Stack logs a error message and with call stack. TODO
-// func EStack(args ...any) { std.log(ErrorLevel, args) }
+// ErrorStack logs a error at level Error, with the stack trace and the unwrapped error causes.
+//
+// The stack trace is in the field FieldKeyStack, the causes in the field FieldKeyErrorCauses.
+func (l *Logger) ErrorStack(err error) {
+	if err == nil {
+		return
+	}
+
+	r := l.newRecord()
+	r.EnableStack = true
+	if causes := errorCauses(err); len(causes) > 0 {
+		r.AddField(FieldKeyErrorCauses, causes)
+	}
+	r.log(ErrorLevel, []any{err})
+}
 
 // Notice logs a message at level notice
 func (l *Logger) Notice(args ...any) { l.log(NoticeLevel, args) }
@@ -3,7 +3,12 @@ package slog_test
 import (
 	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -72,6 +77,30 @@ func TestLogger_ReportCaller(t *testing.T) {
 	assert.Contains(t, str, `"caller":"logger_test.go`)
 }
 
+// a logger wrapper, eg: in the package logx
+func wrapInfo(l *slog.Logger, msg string) { l.Info(msg) }
+
+func TestLogger_CallerSkipPackages(t *testing.T) {
+	buf := new(bytes.Buffer)
+	h := handler.NewIOWriterHandler(buf, slog.AllLevels)
+	h.SetFormatter(slog.NewJSONFormatter(func(f *slog.JSONFormatter) {
+		f.Fields = []string{slog.FieldKeyCaller, slog.FieldKeyMessage}
+	}))
+
+	l := slog.NewWithHandlers(h)
+	l.CallerFlag = slog.CallerFlagFcName
+	wrapInfo(l, "message1")
+	assert.StrContains(t, buf.String(), `"caller":"wrapInfo"`)
+
+	// skip the wrapper package. the test package is the wrapper here, so the caller is the testing.tRunner
+	buf.Reset()
+	l.Config(func(l *slog.Logger) {
+		l.CallerSkipPackages = []string{"github.com/gookit/slog_test"}
+	})
+	wrapInfo(l, "message2")
+	assert.StrContains(t, buf.String(), `"caller":"tRunner"`)
+}
+
 func TestLogger_Log(t *testing.T) {
 	l := slog.NewWithConfig(func(l *slog.Logger) {
 		l.ReportCaller = true
@@ -200,6 +229,185 @@ func TestLogger_write_error(t *testing.T) {
 	assert.Eq(t, "handle error", err.Error())
 }
 
+type multiErr []error
+
+func (es multiErr) Error() string   { return fmt.Sprint([]error(es)) }
+func (es multiErr) Unwrap() []error { return es }
+
+func TestLogger_ErrorStack(t *testing.T) {
+	buf := new(bytes.Buffer)
+	h := handler.NewIOWriterHandler(buf, slog.AllLevels)
+	h.SetFormatter(slog.NewJSONFormatter(func(f *slog.JSONFormatter) {
+		f.Fields = []string{slog.FieldKeyCaller, slog.FieldKeyMessage}
+	}))
+
+	l := slog.NewWithHandlers(h)
+	l.CallerFlag = slog.CallerFlagFcName
+	l.ErrorStack(nil)
+	assert.Empty(t, buf.String())
+
+	root := errors.New("connection refused")
+	err := fmt.Errorf("get user: %w", fmt.Errorf("query db: %w", root))
+	l.ErrorStack(err)
+
+	var data map[string]any
+	assert.NoErr(t, json.Unmarshal(buf.Bytes(), &data))
+	assert.Eq(t, "TestLogger_ErrorStack", data["caller"])
+	assert.Eq(t, "get user: query db: connection refused", data["message"])
+	assert.Eq(t, []any{"query db: connection refused", "connection refused"}, data["error.causes"])
+	assert.StrContains(t, data["stack"].(string), "github.com/gookit/slog_test.TestLogger_ErrorStack\n\t")
+
+	// multi errors
+	buf.Reset()
+	l.ErrorStack(fmt.Errorf("batch: %w", multiErr{errors.New("err1"), fmt.Errorf("err2: %w", root)}))
+	data = nil
+	assert.NoErr(t, json.Unmarshal(buf.Bytes(), &data))
+	assert.Len(t, data["error.causes"], 4)
+	assert.Eq(t, "err1", data["error.causes"].([]any)[1])
+	assert.Eq(t, "connection refused", data["error.causes"].([]any)[3])
+
+	// no causes
+	buf.Reset()
+	l.ErrorStack(root)
+	assert.NotContains(t, buf.String(), "error.causes")
+	assert.StrContains(t, buf.String(), `"stack":`)
+}
+
+func TestLogger_WriteRecord(t *testing.T) {
+	buf := new(bytes.Buffer)
+	h := handler.NewIOWriterHandler(buf, slog.AllLevels)
+	h.SetFormatter(slog.NewJSONFormatter(func(f *slog.JSONFormatter) {
+		f.Fields = []string{slog.FieldKeyDatetime, slog.FieldKeyChannel, slog.FieldKeyLevel, slog.FieldKeyCaller, slog.FieldKeyMessage}
+		f.TimeFormat = time.RFC3339
+	}))
+
+	var exited bool
+	l := slog.NewWithHandlers(h)
+	l.ReportCaller = true
+	l.ExitFunc = func(code int) { exited = true }
+
+	ts := time.Date(2023, 5, 6, 7, 8, 9, 0, time.UTC)
+	r := l.NewRecordAt(ts, slog.WarnLevel, "replayed message", slog.M{"host": "web1"})
+	l.WriteRecord(r)
+
+	var data map[string]any
+	assert.NoErr(t, json.Unmarshal(buf.Bytes(), &data))
+	assert.Eq(t, "2023-05-06T07:08:09Z", data["datetime"])
+	assert.Eq(t, "WARN", data["level"])
+	assert.Eq(t, "replayed message", data["message"])
+	assert.Eq(t, "web1", data["host"])
+	assert.NotContains(t, data, "caller")
+
+	// sub logger, with the fields of it
+	buf.Reset()
+	sub := l.With(slog.M{"app": "agent"})
+	sub.WriteRecord(sub.NewRecordAt(ts, slog.InfoLevel, "from sub", nil))
+	assert.StrContains(t, buf.String(), `"app":"agent"`)
+	assert.StrContains(t, buf.String(), `"message":"from sub"`)
+
+	// will not exit on fatal level
+	buf.Reset()
+	l.WriteRecord(l.NewRecordAt(time.Time{}, slog.FatalLevel, "fatal message", nil))
+	assert.False(t, exited)
+	assert.StrContains(t, buf.String(), `"level":"FATAL"`)
+
+	// normal log is not affected
+	buf.Reset()
+	l.Fatal("real fatal")
+	assert.True(t, exited)
+	assert.StrContains(t, buf.String(), `"caller":"logger_test.go`)
+}
+
+func TestLogger_RecentErrors(t *testing.T) {
+	h := newTestHandler()
+	h.errOnHandle = true
+
+	l := slog.NewWithHandlers(h)
+	assert.Empty(t, l.RecentErrors())
+
+	l.Info("a message")
+	h.errOnFlush = true
+	assert.Err(t, l.Flush())
+
+	ers := l.RecentErrors()
+	assert.Len(t, ers, 2)
+	assert.Eq(t, "handle", ers[0].Op)
+	assert.Eq(t, "*slog_test.testHandler", ers[0].Handler)
+	assert.Eq(t, "flush", ers[1].Op)
+	assert.Eq(t, "flush error", ers[1].Err.Error())
+	assert.False(t, ers[1].Time.IsZero())
+	assert.StrContains(t, ers[1].String(), "flush *slog_test.testHandler: flush error")
+
+	// LastErr will not clear the recent errors
+	assert.Eq(t, "flush error", l.LastErr().Error())
+	assert.Nil(t, l.LastErr())
+	assert.Len(t, l.RecentErrors(), 2)
+
+	// the oldest errors are overwritten on full
+	h.errOnFlush = false
+	for i := 0; i < slog.RecentErrorsSize; i++ {
+		l.Info("a message")
+	}
+	ers = l.RecentErrors()
+	assert.Len(t, ers, slog.RecentErrorsSize)
+	for _, er := range ers {
+		assert.Eq(t, "handle", er.Op)
+	}
+}
+
+func TestLogger_RemoveHandler(t *testing.T) {
+	h1, h2, h3 := newTestHandler(), newTestHandler(), newTestHandler()
+	l := slog.NewWithHandlers(h1, handler.Named(h2, "h2"), h3)
+	assert.Eq(t, 3, l.HandlersNum())
+
+	assert.True(t, l.RemoveHandler(h1))
+	assert.False(t, l.RemoveHandler(h1))
+	assert.Eq(t, 2, l.HandlersNum())
+
+	assert.Nil(t, l.RemoveHandlerByName("not-exists"))
+	assert.NotNil(t, l.RemoveHandlerByName("h2"))
+	assert.Eq(t, 1, l.HandlersNum())
+
+	l.Info("a message")
+	assert.Empty(t, h1.String())
+	assert.StrContains(t, h3.String(), "a message")
+
+	// replace at same position
+	h4 := newTestHandler()
+	assert.False(t, l.ReplaceHandler(h1, h4))
+	assert.True(t, l.ReplaceHandler(h3, h4))
+	assert.Eq(t, 1, l.HandlersNum())
+
+	l.Info("new message")
+	assert.NotContains(t, h3.String(), "new message")
+	assert.StrContains(t, h4.String(), "new message")
+}
+
+func TestLogger_ReplaceHandler_concurrent(t *testing.T) {
+	h1 := newTestHandler()
+	l := slog.NewWithHandlers(h1)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				l.Info("message")
+			}
+		}()
+	}
+
+	var old slog.Handler = h1
+	for i := 0; i < 20; i++ {
+		nh := newTestHandler()
+		assert.True(t, l.ReplaceHandler(old, nh))
+		old = nh
+	}
+	wg.Wait()
+	assert.Eq(t, 1, l.HandlersNum())
+}
+
 func TestLogger_option_BackupArgs(t *testing.T) {
 	l := slog.New(func(l *slog.Logger) {
 		l.BackupArgs = true
@@ -279,3 +487,342 @@ func TestLogger_rewrite_record(t *testing.T) {
 		dump.P(h.ResetGet())
 	})
 }
+
+func TestLogger_ExitSummary(t *testing.T) {
+	buf := new(bytes.Buffer)
+	l := slog.NewWithHandlers(handler.NewIOWriter(buf, slog.AllLevels))
+	l.ExitSummary = true
+
+	var exitCode int
+	l.ExitFunc = func(code int) {
+		exitCode = code
+	}
+
+	l.Info("info message")
+	l.Warn("warn message")
+	l.Info("info message2")
+	assert.Eq(t, map[slog.Level]uint64{slog.InfoLevel: 2, slog.WarnLevel: 1}, l.LevelCounts())
+
+	l.Exit(3)
+	assert.Eq(t, 3, exitCode)
+	str := buf.String()
+	assert.Contains(t, str, "process exiting")
+	assert.Contains(t, str, "exit_code:3")
+	assert.Contains(t, str, "INFO:2")
+
+	// exit on fatal, the lock is held
+	buf.Reset()
+	l.Fatal("fatal message")
+	assert.Eq(t, 1, exitCode)
+	assert.Contains(t, buf.String(), "exit_code:1")
+	assert.Contains(t, buf.String(), "FATAL:1")
+}
+
+func TestLogger_FatalDefer(t *testing.T) {
+	buf := new(bytes.Buffer)
+	l := slog.NewWithHandlers(handler.NewIOWriter(buf, slog.AllLevels))
+	l.ExitFunc = func(code int) {
+		buf.WriteString("Exited")
+	}
+
+	l.FatalDefer("fatal message", func() {
+		buf.WriteString("Cleanup,")
+	})
+
+	str := buf.String()
+	assert.Contains(t, str, "[FATAL] [logger_test.go")
+	assert.Contains(t, str, "fatal message")
+	assert.StrContains(t, str, "Cleanup,Exited")
+
+	// cleanup is only run once
+	buf.Reset()
+	l.Exit(0)
+	assert.Eq(t, "Exited", buf.String())
+}
+
+func TestLogger_FatalDefer_logOnCleanup(t *testing.T) {
+	buf := new(bytes.Buffer)
+	l := slog.NewWithHandlers(handler.NewIOWriter(buf, slog.AllLevels))
+	l.ExitTimeout = time.Second
+
+	var exited bool
+	l.ExitFunc = func(code int) {
+		exited = true
+	}
+	l.RegisterExitHandler(func() {
+		l.Info("exit handler message")
+	})
+
+	l.FatalDefer("fatal message", func() {
+		l.Warn("cleanup message")
+	})
+
+	str := buf.String()
+	assert.True(t, exited)
+	assert.StrContains(t, str, "fatal message")
+	assert.StrContains(t, str, "cleanup message")
+	assert.StrContains(t, str, "exit handler message")
+
+	// can log after exit
+	l.Info("after exit")
+	assert.StrContains(t, buf.String(), "after exit")
+}
+
+func TestLogger_ExitTimeout(t *testing.T) {
+	l := slog.New()
+	l.ExitTimeout = 20 * time.Millisecond
+
+	var exited bool
+	l.ExitFunc = func(code int) {
+		exited = true
+	}
+
+	done := make(chan struct{})
+	l.RegisterExitHandler(func() {
+		<-done
+	})
+	l.FatalDefer("fatal message", func() {
+		panic("cleanup panic")
+	})
+
+	close(done)
+	assert.True(t, exited)
+}
+
+func TestLogger_FatalAsPanic(t *testing.T) {
+	buf := new(bytes.Buffer)
+	l := slog.NewWithHandlers(handler.NewIOWriter(buf, slog.AllLevels))
+	l.FatalAsPanic = true
+
+	var exited bool
+	l.ExitFunc = func(code int) {
+		exited = true
+	}
+
+	assert.Panics(t, func() {
+		l.Fatal("fatal message")
+	})
+	assert.False(t, exited)
+	assert.Contains(t, buf.String(), "[FATAL]")
+	assert.Contains(t, buf.String(), "fatal message")
+
+	// panic as fatal
+	l.Config(func(l *slog.Logger) {
+		l.FatalAsPanic = false
+		l.PanicAsFatal = true
+	})
+	buf.Reset()
+	l.Panic("panic message")
+	assert.True(t, exited)
+	assert.Contains(t, buf.String(), "[PANIC]")
+}
+
+func TestLogger_ClockSkewThreshold(t *testing.T) {
+	buf := new(bytes.Buffer)
+	slog.SetInternalHandler(handler.NewIOWriter(buf, slog.AllLevels))
+	defer slog.SetInternalHandler(nil)
+
+	now := time.Now()
+	l := slog.NewWithHandlers(handler.NewIOWriter(new(bytes.Buffer), slog.AllLevels))
+	l.ClockSkewThreshold = time.Minute
+	l.TimeClock = func() time.Time {
+		now = now.Add(-time.Second)
+		return now
+	}
+
+	l.Info("message1")
+	assert.Empty(t, buf.String())
+
+	// goes backwards
+	l.Info("message2")
+	assert.StrContains(t, buf.String(), "slog: the TimeClock goes backwards by 1s")
+
+	// far from system clock, only warn once
+	buf.Reset()
+	now = now.Add(time.Hour)
+	l.Info("message3")
+	l.Info("message4")
+	assert.Eq(t, 1, strings.Count(buf.String(), "slog: the TimeClock is skewed from the system clock by"))
+	assert.NotContains(t, buf.String(), "goes backwards")
+}
+
+func TestLogger_With(t *testing.T) {
+	buf := new(bytes.Buffer)
+	h := handler.NewIOWriter(buf, slog.AllLevels)
+	h.SetFormatter(slog.NewJSONFormatter())
+	l := slog.NewWithHandlers(h)
+	l.DoNothingOnPanicFatal()
+
+	reqLog := l.With(slog.M{"request_id": "abc"})
+	assert.Eq(t, slog.M{"request_id": "abc"}, reqLog.Fields())
+	assert.Eq(t, 1, reqLog.HandlersNum())
+	assert.True(t, reqLog.IsHandling(slog.DebugLevel))
+
+	reqLog.Info("child message")
+	str := buf.String()
+	assert.StrContains(t, str, `"request_id":"abc"`)
+	assert.StrContains(t, str, `"channel":"application"`)
+	assert.StrContains(t, str, `"caller":"logger_test.go`)
+
+	// nested child, with channel
+	buf.Reset()
+	orderLog := reqLog.WithChannel("order").With(slog.M{"order_id": 23})
+	orderLog.WithField("key", "value").Warn("order message")
+	str = buf.String()
+	assert.StrContains(t, str, `"request_id":"abc"`)
+	assert.StrContains(t, str, `"order_id":23`)
+	assert.StrContains(t, str, `"key":"value"`)
+	assert.StrContains(t, str, `"channel":"order"`)
+
+	// the root logger is not affected
+	buf.Reset()
+	l.Info("root message")
+	str = buf.String()
+	assert.StrContains(t, str, `"channel":"application"`)
+	assert.NotContains(t, str, "request_id")
+	assert.Nil(t, l.Fields())
+
+	// config on the child logger, is applied to the root logger
+	buf.Reset()
+	orderLog.Config(func(l *slog.Logger) {
+		l.ReportCaller = false
+	})
+	orderLog.Info("order message2")
+	l.Info("root message2")
+	str = buf.String()
+	assert.StrContains(t, str, `"channel":"order"`)
+	assert.NotContains(t, str, `"caller"`)
+
+	// shared the level counts, flush and close
+	assert.Eq(t, uint64(4), orderLog.LevelCounts()[slog.InfoLevel])
+	assert.NoErr(t, orderLog.Flush())
+	assert.NoErr(t, orderLog.Close())
+}
+
+func TestLogger_SetDryRun(t *testing.T) {
+	buf := new(bytes.Buffer)
+	h := handler.NewIOWriter(buf, slog.AllLevels)
+
+	var formatted int
+	h.SetFormatter(slog.FormatterFunc(func(r *slog.Record) ([]byte, error) {
+		formatted++
+		if r.Message == "bad" {
+			return nil, errorx.Raw("format error")
+		}
+		return []byte(r.Message + "\n"), nil
+	}))
+
+	l := slog.NewWithHandlers(h)
+	l.AddProcessor(slog.ProcessorFunc(func(r *slog.Record) {
+		r.AddField("processed", true)
+	}))
+
+	l.SetDryRun(true)
+	assert.True(t, l.IsDryRun())
+	assert.True(t, l.With(slog.M{"a": 1}).IsDryRun())
+
+	l.Info("dry-run message")
+	assert.Eq(t, 1, formatted)
+	assert.Empty(t, buf.String())
+
+	l.Warn("bad")
+	assert.Eq(t, 2, formatted)
+	assert.Err(t, l.LastErr())
+
+	l.SetDryRun(false)
+	l.Info("real message")
+	assert.Eq(t, 3, formatted)
+	assert.Eq(t, "real message\n", buf.String())
+}
+
+func TestLogger_changeFieldsAfterUse(t *testing.T) {
+	buf := new(bytes.Buffer)
+	h := handler.NewIOWriter(buf, slog.AllLevels)
+	h.SetFormatter(slog.NewJSONFormatter())
+	l := slog.NewWithHandlers(h)
+	l.Info("first message")
+	assert.StrContains(t, buf.String(), `"channel":"application"`)
+
+	// change the fields directly, not take effect
+	buf.Reset()
+	l.ChannelName = "order"
+	l.Info("second message")
+	assert.StrContains(t, buf.String(), `"channel":"application"`)
+
+	buf.Reset()
+	l.Config(func(l *slog.Logger) {
+		l.ChannelName = "order"
+		l.ReportCaller = false
+	})
+	l.Info("third message")
+	str := buf.String()
+	assert.StrContains(t, str, `"channel":"order"`)
+	assert.NotContains(t, str, `"caller"`)
+
+	var code int
+	l.Config(func(l *slog.Logger) {
+		l.ExitFunc = func(c int) { code = c }
+	})
+	l.Exit(23)
+	assert.Eq(t, 23, code)
+}
+
+// go test -race -run TestLogger_concurrentConfig
+func TestLogger_concurrentConfig(t *testing.T) {
+	buf := new(bytes.Buffer)
+	h := handler.WithMaxLevel(handler.NewSimple(buf, slog.TraceLevel), slog.InfoLevel)
+	l := slog.NewWithHandlers(h)
+
+	var wg sync.WaitGroup
+	stop := make(chan struct{})
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			child := l.With(slog.M{"worker": i})
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+				}
+
+				l.Info("root message", i)
+				l.Debugf("debug message %d", i)
+				child.WithContext(context.Background()).Warn("child message")
+				_ = l.IsHandling(slog.DebugLevel)
+			}
+		}(i)
+	}
+
+	for i := 0; i < 20; i++ {
+		l.Config(func(l *slog.Logger) {
+			l.ReportCaller = i%2 == 0
+			l.CallerFlag = uint8(i % 3)
+			l.BackupArgs = i%2 == 1
+			l.ChannelName = fmt.Sprint("ch", i)
+			h.SetMaxLevel(slog.Level(slog.InfoLevel + slog.Level(i%2*100)))
+			// not deadlock on use the logger in the fn
+			_ = l.With(slog.M{"config": i}).IsHandling(slog.InfoLevel)
+		})
+		if i%4 == 0 {
+			l.SetSampler(slog.EveryN(2))
+		} else if i%4 == 2 {
+			l.SetSampler(nil)
+		}
+		l.AddProcessor(slog.ProcessorFunc(func(r *slog.Record) {}))
+		l.AddContextExtractor(slog.CtxValueExtractor("key", "key"))
+		l.SetDryRun(i%5 == 0)
+		if i%10 == 0 {
+			l.AddHandler(handler.NewSimple(io.Discard, slog.WarnLevel))
+		}
+	}
+
+	close(stop)
+	wg.Wait()
+	assert.Eq(t, 3, l.HandlersNum())
+	assert.False(t, l.IsDryRun())
+
+	l.Info("final message")
+	assert.StrContains(t, buf.String(), "final message")
+}
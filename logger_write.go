@@ -1,5 +1,10 @@
 package slog
 
+import (
+	"runtime"
+	"time"
+)
+
 //
 // ---------------------------------------------------------------------------
 // Do write log message
@@ -34,62 +39,163 @@ func (r *Record) Init(lowerLevelName bool) {
 
 	// init log time
 	if r.Time.IsZero() {
-		r.Time = r.logger.TimeClock.Now()
+		r.Time = r.logger.loadOpts().timeClock.Now()
+		r.logger.checkClock(r.Time)
 	}
 
 	// r.microSecond = r.Time.Nanosecond() / 1000
 }
 
+// check the time from TimeClock, warn once on skew or goes backwards. l.mu is held.
+func (l *Logger) checkClock(t time.Time) {
+	threshold := l.loadOpts().clockSkewThreshold
+	if threshold <= 0 {
+		return
+	}
+
+	if !l.skewWarned {
+		skew := t.Sub(time.Now())
+		if skew > threshold || skew < -threshold {
+			l.skewWarned = true
+			ReportInternal(WarnLevel, "slog: the TimeClock is skewed from the system clock by", skew, "threshold:", threshold)
+		}
+	}
+
+	if !l.jumpWarned && t.Before(l.lastTime) {
+		l.jumpWarned = true
+		ReportInternal(WarnLevel, "slog: the TimeClock goes backwards by", l.lastTime.Sub(t), "last:", l.lastTime.Format(time.RFC3339Nano))
+	}
+	l.lastTime = t
+}
+
 // Init something for record.
 func (r *Record) beforeHandle(l *Logger) {
+	opts := l.loadOpts()
 	// log caller. will alloc 3 times
-	if l.ReportCaller {
-		caller, ok := getCaller(r.CallerSkip)
+	if opts.reportCaller && !r.callerFixed {
+		var caller runtime.Frame
+		var ok bool
+		// +1 for the Logger.dispatch() frame
+		if len(opts.callerSkipPackages) > 0 {
+			caller, ok = getCallerSkipPkgs(r.CallerSkip+1, opts.callerSkipPackages)
+		} else {
+			caller, ok = getCaller(r.CallerSkip + 1)
+		}
 		if ok {
 			r.Caller = &caller
 		}
 	}
 
+	// +1 for the Logger.dispatch() frame
+	if r.EnableStack {
+		if _, ok := r.Fields[FieldKeyStack]; !ok {
+			r.AddField(FieldKeyStack, getCallStack(r.CallerSkip+1+opts.stackSkip, opts.stackDepth, opts.callerSkipPackages))
+		}
+	}
+
+	l.extractContext(r)
+
 	// processing log record
-	for i := range l.processors {
-		l.processors[i].Process(r)
+	for _, p := range l.loadProcessors() {
+		p.Process(r)
 	}
 }
 
-// do write record to handlers, will add lock.
-func (l *Logger) writeRecord(level Level, r *Record) {
+// do write record to handlers, then release the record. will add lock.
+//
+// The message and args are set to the record in the lock, so a record can be shared by goroutines.
+func (l *Logger) writeRecord(level Level, r *Record, format, message string, args []any) {
 	l.mu.Lock()
 	defer l.mu.Unlock()
+
+	opts := l.loadOpts()
+	r.Level, r.Message = level, message
+	// will be cleared on Logger.BackupArgs=false
+	if opts.backupArgs {
+		r.Fmt, r.Args = format, args
+	} else {
+		r.Fmt, r.Args = "", nil
+	}
+
+	// drop by sampler. report on both paths, the drops will be reported even if all later records are dropped.
+	dropped := l.sampleDropped(r)
+	l.reportSampling(r)
+	if dropped {
+		l.releaseRecord(r)
+		return
+	}
+	l.dispatch(r)
+	l.levelCounts[level]++
+
+	// ---- after write log ----
+	r.Time = emptyTime
+	r.callerFixed = false
+
+	// flush logs on level <= error level.
+	if level <= ErrorLevel {
+		l.flushAll() // has been in lock
+	}
+
+	// the panic has been recovered, see Recover(). or injected by WriteRecord()
+	if r.recovered || r.injected {
+		l.releaseRecord(r)
+		return
+	}
+
+	if level <= PanicLevel {
+		if opts.panicAsFatal {
+			l.exit(1, true)
+		} else {
+			opts.panicFunc(r)
+		}
+	} else if level <= FatalLevel {
+		if opts.fatalAsPanic {
+			opts.panicFunc(r)
+		} else {
+			l.exit(1, true)
+		}
+	}
+	l.releaseRecord(r)
+}
+
+// dispatch record to handlers. l.mu is held.
+func (l *Logger) dispatch(r *Record) {
+	level := r.Level
 	// reset init flag, useful for repeat use Record
 	r.inited = false
 
-	for _, handler := range l.handlers {
+	dryRun := l.dryRun.Load()
+	for _, handler := range l.loadHandlers() {
 		if handler.IsHandling(level) {
 			// init record, call processors
 			if !r.inited {
-				r.Init(l.LowerLevelName)
+				r.Init(l.loadOpts().lowerLevelName)
 				r.beforeHandle(l)
 			}
 
+			if dryRun {
+				l.dryRunFormat(handler, r)
+				continue
+			}
+
 			// do write log message by handler
 			if err := handler.Handle(r); err != nil {
-				l.err = err
-				printlnStderr("slog: failed to handle log, error:", err)
+				l.errs.add("handle", handler, err)
+				reportError("slog: failed to handle log, handler:", HandlerName(handler), "error:", err)
 			}
 		}
 	}
+}
 
-	// ---- after write log ----
-	r.Time = emptyTime
-
-	// flush logs on level <= error level.
-	if level <= ErrorLevel {
-		l.flushAll() // has been in lock
+// format the record by the handler formatter, but not write it. l.mu is held.
+func (l *Logger) dryRunFormat(h Handler, r *Record) {
+	fh, ok := h.(Formattable)
+	if !ok {
+		return
 	}
 
-	if level <= PanicLevel {
-		l.PanicFunc(r)
-	} else if level <= FatalLevel {
-		l.Exit(1)
+	if _, err := fh.Formatter().Format(r); err != nil {
+		l.errs.add("format", h, err)
+		reportError("slog: failed to format log on dry-run, handler:", HandlerName(h), "error:", err)
 	}
 }
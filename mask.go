@@ -0,0 +1,240 @@
+package slog
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/gookit/goutil/strutil"
+)
+
+// MaskRule a rule for mask the sensitive values on records.
+//
+//   - only Keys: mask the whole value of the fields that key matched.
+//   - only Pattern: mask the matched parts in the message and all string values.
+//   - both: mask the matched parts in the string values of the fields that key matched.
+type MaskRule struct {
+	// Keys match the field key, case-insensitive contains. eg: "password" will match "DB_Password"
+	//
+	// NOTICE: the keys should be lower case, see MaskKeys()
+	Keys []string
+	// Pattern match the sensitive parts in string values.
+	Pattern *regexp.Regexp
+	// Mask func for the matched value. default is FullMask
+	Mask func(s string) string
+}
+
+func (mr *MaskRule) matchKey(key string) bool {
+	if len(mr.Keys) == 0 {
+		return true
+	}
+	if key == "" {
+		return false
+	}
+
+	key = strings.ToLower(key)
+	for _, k := range mr.Keys {
+		if strings.Contains(key, k) {
+			return true
+		}
+	}
+	return false
+}
+
+func (mr *MaskRule) mask(s string) string {
+	if mr.Mask != nil {
+		return mr.Mask(s)
+	}
+	return FullMask(s)
+}
+
+// FullMask mask the whole value as SecretMask
+func FullMask(string) string { return SecretMask }
+
+// KeepLast create a mask func, keep the last n chars and replace others with '*'.
+//
+// eg: KeepLast(4)("4111111111111111") => "************1111"
+func KeepLast(n int) func(s string) string {
+	return func(s string) string {
+		rs := []rune(s)
+		if len(rs) <= n {
+			return strings.Repeat("*", len(rs))
+		}
+		return strings.Repeat("*", len(rs)-n) + string(rs[len(rs)-n:])
+	}
+}
+
+// MaskKeys create a rule, mask the whole value of fields that key contains any of the keys.
+func MaskKeys(keys ...string) MaskRule {
+	lowers := make([]string, len(keys))
+	for i, key := range keys {
+		lowers[i] = strings.ToLower(key)
+	}
+	return MaskRule{Keys: lowers}
+}
+
+// MaskPattern create a rule, mask the parts matched the regex pattern. mask can be nil.
+func MaskPattern(pattern string, mask func(s string) string) MaskRule {
+	return MaskRule{Pattern: regexp.MustCompile(pattern), Mask: mask}
+}
+
+// built-in mask rules
+var (
+	// MaskSecretKeys mask the values of fields like password, token, secret
+	MaskSecretKeys = MaskKeys("password", "passwd", "token", "secret")
+	// MaskCreditCard mask the credit card numbers, keep the last 4 digits.
+	MaskCreditCard = MaskPattern(`\b(?:\d[ -]?){12,18}\d\b`, KeepLast(4))
+	// MaskEmail mask the email addresses, keep the first char and the domain. eg: "i***@example.com"
+	MaskEmail = MaskPattern(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`, func(s string) string {
+		at := strings.IndexByte(s, '@')
+		return s[:1] + "***" + s[at:]
+	})
+)
+
+// DefaultMaskRules the default rules for NewMaskProcessor()
+func DefaultMaskRules() []MaskRule {
+	return []MaskRule{MaskSecretKeys, MaskCreditCard, MaskEmail}
+}
+
+// MaskProcessor mask the sensitive values on the record Message, Fields, Attrs, Data and Extra,
+// so PII never reaches the handlers.
+//
+// The maps and attrs passed by user will not be modified, the masked values are set on copies.
+//
+// The pattern rules are applied to the values of type: string, []string, error, fmt.Stringer,
+// and the nested M, map[string]any. the error and fmt.Stringer are replaced with the masked string.
+// NOTICE: the other types are not covered by the pattern rules. eg: structs, pointers, other slices and maps.
+type MaskProcessor struct {
+	rules []MaskRule
+}
+
+// NewMaskProcessor create a MaskProcessor. will use DefaultMaskRules() on rules is empty.
+//
+// Usage:
+//
+//	logger.AddProcessor(slog.NewMaskProcessor())
+//
+//	// custom rules
+//	logger.AddProcessor(slog.NewMaskProcessor(
+//		slog.MaskKeys("api_key", "cookie"),
+//		slog.MaskCreditCard,
+//		slog.MaskPattern(`\d{3}-\d{2}-\d{4}`, slog.KeepLast(4)),
+//	))
+func NewMaskProcessor(rules ...MaskRule) *MaskProcessor {
+	if len(rules) == 0 {
+		rules = DefaultMaskRules()
+	}
+	return &MaskProcessor{rules: rules}
+}
+
+// Process mask the record
+func (p *MaskProcessor) Process(r *Record) {
+	r.Message = p.maskString("", r.Message)
+	r.Fields, _ = p.maskMap(r.Fields)
+	r.Data, _ = p.maskMap(r.Data)
+	r.Extra, _ = p.maskMap(r.Extra)
+
+	// copy attrs, don't modify the slice shared with other records
+	var attrs []Field
+	for i, attr := range r.Attrs {
+		if nv, ok := p.maskValue(attr.Key, attr.Value()); ok {
+			if attrs == nil {
+				attrs = append([]Field(nil), r.Attrs...)
+			}
+			attrs[i] = Any(attr.Key, nv)
+		}
+	}
+	if attrs != nil {
+		r.Attrs = attrs
+	}
+}
+
+// maskMap returns a new map and true on any value has been masked.
+func (p *MaskProcessor) maskMap(m M) (M, bool) {
+	var nm M
+	for key, val := range m {
+		if nv, ok := p.maskValue(key, val); ok {
+			// copy map, don't modify the map passed by user
+			if nm == nil {
+				nm = mergeMap(nil, m, true)
+			}
+			nm[key] = nv
+		}
+	}
+
+	if nm == nil {
+		return m, false
+	}
+	return nm, true
+}
+
+// maskValue returns the masked value and true on the value has been masked.
+func (p *MaskProcessor) maskValue(key string, val any) (any, bool) {
+	if _, ok := val.(SecretValue); ok || val == nil {
+		return val, false
+	}
+
+	// mask the whole value on the key matched
+	for i := range p.rules {
+		rule := &p.rules[i]
+		if rule.Pattern == nil && len(rule.Keys) > 0 && rule.matchKey(key) {
+			return rule.mask(strutil.SafeString(val)), true
+		}
+	}
+
+	switch tv := val.(type) {
+	case string:
+		ns := p.maskString(key, tv)
+		return ns, ns != tv
+	case M:
+		return p.maskMap(tv)
+	case map[string]any:
+		nm, ok := p.maskMap(tv)
+		return map[string]any(nm), ok
+	case []string:
+		return p.maskStrings(key, tv)
+	case error:
+		return p.maskText(key, tv.Error())
+	case fmt.Stringer:
+		return p.maskText(key, tv.String())
+	}
+	return val, false
+}
+
+// mask the text of error, fmt.Stringer. returns the masked string and true on it has been masked.
+func (p *MaskProcessor) maskText(key, s string) (any, bool) {
+	if ns := p.maskString(key, s); ns != s {
+		return ns, true
+	}
+	return nil, false
+}
+
+// returns a new slice and true on any value has been masked.
+func (p *MaskProcessor) maskStrings(key string, ss []string) (any, bool) {
+	var ns []string
+	for i, s := range ss {
+		if ms := p.maskString(key, s); ms != s {
+			// copy slice, don't modify the slice passed by user
+			if ns == nil {
+				ns = append([]string(nil), ss...)
+			}
+			ns[i] = ms
+		}
+	}
+
+	if ns == nil {
+		return ss, false
+	}
+	return ns, true
+}
+
+// mask the parts matched the Pattern rules
+func (p *MaskProcessor) maskString(key, s string) string {
+	for i := range p.rules {
+		rule := &p.rules[i]
+		if rule.Pattern != nil && rule.matchKey(key) {
+			s = rule.Pattern.ReplaceAllStringFunc(s, rule.mask)
+		}
+	}
+	return s
+}
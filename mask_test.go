@@ -0,0 +1,88 @@
+package slog_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/gookit/goutil/byteutil"
+	"github.com/gookit/goutil/testutil/assert"
+	"github.com/gookit/slog"
+)
+
+func TestNewMaskProcessor(t *testing.T) {
+	buf := new(byteutil.Buffer)
+	l := slog.NewJSONSugared(buf, slog.InfoLevel)
+	l.AddProcessor(slog.NewMaskProcessor())
+
+	data := slog.M{
+		"DB_Password": "123456",
+		"card":        "4111 1111 1111 1111",
+		"user":        slog.M{"email": "inhere@example.com", "token": 123},
+		"secret":      slog.Secret("abc"),
+	}
+	l.WithData(data).
+		WithAttrs(slog.Int("api_token", 23), slog.String("note", "mail to bob@example.com")).
+		Info("paid by card 4111-1111-1111-1234")
+
+	str := buf.String()
+	assert.StrContains(t, str, `"DB_Password":"****"`)
+	assert.StrContains(t, str, `"card":"***************1111"`)
+	assert.StrContains(t, str, `"email":"i***@example.com"`)
+	assert.StrContains(t, str, `"token":"****"`)
+	assert.StrContains(t, str, `"secret":"****"`)
+	assert.StrContains(t, str, `"api_token":"****"`)
+	assert.StrContains(t, str, `"note":"mail to b***@example.com"`)
+	assert.StrContains(t, str, `paid by card ***************1234`)
+
+	// the user data is not modified
+	assert.Eq(t, "123456", data["DB_Password"])
+	assert.Eq(t, "inhere@example.com", data["user"].(slog.M)["email"])
+}
+
+func TestNewMaskProcessor_customRules(t *testing.T) {
+	p := slog.NewMaskProcessor(
+		slog.MaskKeys("Cookie"),
+		slog.MaskPattern(`\d{3}-\d{2}-\d{4}`, slog.KeepLast(4)),
+		slog.MaskRule{Keys: []string{"phone"}, Pattern: slog.MaskCreditCard.Pattern, Mask: slog.FullMask},
+	)
+
+	r := &slog.Record{
+		Message: "ssn 123-45-6789",
+		Fields:  slog.M{"set_cookie": "sid=abc", "phone": "call 1234567890123", "other": "1234567890123"},
+	}
+	p.Process(r)
+
+	assert.Eq(t, "ssn *******6789", r.Message)
+	assert.Eq(t, "****", r.Fields["set_cookie"])
+	assert.Eq(t, "call ****", r.Fields["phone"])
+	assert.Eq(t, "1234567890123", r.Fields["other"])
+	assert.Eq(t, "**34", slog.KeepLast(2)("1234"))
+	assert.Eq(t, "**", slog.KeepLast(4)("12"))
+}
+
+type maskStringer struct{ s string }
+
+func (ms maskStringer) String() string { return ms.s }
+
+func TestMaskProcessor_textValues(t *testing.T) {
+	p := slog.NewMaskProcessor()
+	users := []string{"bob@example.com", "alice"}
+	r := &slog.Record{
+		Fields: slog.M{
+			"error": errors.New("user bob@example.com not found"),
+			"user":  maskStringer{"bob@example.com"},
+			"users": users,
+			"ok":    errors.New("not found"),
+		},
+		Attrs: []slog.Field{slog.Err(errors.New("user bob@example.com not found"))},
+	}
+	p.Process(r)
+
+	assert.Eq(t, "user b***@example.com not found", r.Fields["error"])
+	assert.Eq(t, "b***@example.com", r.Fields["user"])
+	assert.Eq(t, []string{"b***@example.com", "alice"}, r.Fields["users"])
+	assert.Err(t, r.Fields["ok"].(error))
+	assert.Eq(t, "user b***@example.com not found", r.Attrs[0].Value())
+	// the user slice is not modified
+	assert.Eq(t, "bob@example.com", users[0])
+}
@@ -0,0 +1,157 @@
+package slog
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// FieldKeyOpID the field name of the operation id. can be used in the formatter template: {{op_id}}
+const FieldKeyOpID = "op_id"
+
+// OpIndent the message indent for each level of the nested operations. set to "" for disable it.
+var OpIndent = "  "
+
+// Operation a hierarchical operation, the records in it are indented by the depth,
+// and have the operation id field. eg: "1", "1.2", "1.2.1"
+//
+// Useful for the CLI tools want readable nested progress output.
+type Operation struct {
+	l      *Logger
+	parent *Operation
+	// ID of the operation, the child id is prefixed with the parent id.
+	ID string
+	// Name of the operation
+	Name string
+	// Depth of the operation, the top operation is 0.
+	Depth int
+	// Start time of the operation
+	Start time.Time
+
+	mu       sync.Mutex
+	children int
+	ended    bool
+}
+
+// Begin a top operation, will log the begin message at InfoLevel.
+//
+// Usage:
+//
+//	op := logger.Begin("build")
+//	defer op.End()
+//
+//	sub := op.Begin("compile")
+//	sub.Info("compile 23 files")
+//	sub.End(err)
+//
+// Output:
+//
+//	[INFO] begin build
+//	[INFO]   begin compile
+//	[INFO]     compile 23 files
+//	[INFO]   end compile, elapsed: 1.2s
+//	[INFO] end build, elapsed: 1.5s
+func (l *Logger) Begin(name string) *Operation {
+	id := strconv.FormatUint(l.rootLogger().opSeq.Add(1), 10)
+	return newOperation(l, nil, id, name)
+}
+
+func newOperation(l *Logger, parent *Operation, id, name string) *Operation {
+	op := &Operation{l: l, parent: parent, ID: id, Name: name, Start: time.Now()}
+	if parent != nil {
+		op.Depth = parent.Depth + 1
+	}
+
+	r := op.record(op.Depth)
+	r.CallerSkip += 2
+	r.log(InfoLevel, []any{"begin " + name})
+	return op
+}
+
+// Begin a child operation
+func (op *Operation) Begin(name string) *Operation {
+	op.mu.Lock()
+	op.children++
+	id := op.ID + "." + strconv.Itoa(op.children)
+	op.mu.Unlock()
+
+	return newOperation(op.l, op, id, name)
+}
+
+// Parent get the parent operation, returns nil on it is top operation.
+func (op *Operation) Parent() *Operation { return op.parent }
+
+// End the operation, will log the end message with elapsed time.
+// Log at ErrorLevel on the err is not nil, and only the first call is logged.
+func (op *Operation) End(err ...error) time.Duration {
+	elapsed := time.Since(op.Start)
+
+	op.mu.Lock()
+	ended := op.ended
+	op.ended = true
+	op.mu.Unlock()
+	if ended {
+		return elapsed
+	}
+
+	r := op.record(op.Depth)
+	r.CallerSkip++
+	r.Data = M{"elapsed": elapsed.String()}
+
+	msg := "end " + op.Name + ", elapsed: " + elapsed.String()
+	if len(err) > 0 && err[0] != nil {
+		r.log(ErrorLevel, []any{msg + ", error: " + err[0].Error()})
+	} else {
+		r.log(InfoLevel, []any{msg})
+	}
+	return elapsed
+}
+
+// new record with the operation id, and the message will be indented by the depth.
+func (op *Operation) record(depth int) *opRecord {
+	r := op.l.newRecord()
+	r.AddField(FieldKeyOpID, op.ID)
+	return &opRecord{Record: r, indent: strings.Repeat(OpIndent, depth)}
+}
+
+// opRecord indent the message for the operation
+type opRecord struct {
+	*Record
+	indent string
+}
+
+func (r *opRecord) log(level Level, args []any) {
+	if r.indent == "" {
+		r.Record.log(level, args)
+		return
+	}
+	r.Record.logf(level, "%s%s", []any{r.indent, formatArgsWithSpaces(args)})
+}
+
+func (op *Operation) log(level Level, args []any) {
+	r := op.record(op.Depth + 1)
+	r.CallerSkip += 2
+	r.log(level, args)
+}
+
+// Log a message in the operation
+func (op *Operation) Log(level Level, args ...any) { op.log(level, args) }
+
+// Logf a format message in the operation
+func (op *Operation) Logf(level Level, format string, args ...any) {
+	op.log(level, []any{fmt.Sprintf(format, args...)})
+}
+
+// Debug logs a message in the operation at DebugLevel
+func (op *Operation) Debug(args ...any) { op.log(DebugLevel, args) }
+
+// Info logs a message in the operation at InfoLevel
+func (op *Operation) Info(args ...any) { op.log(InfoLevel, args) }
+
+// Warn logs a message in the operation at WarnLevel
+func (op *Operation) Warn(args ...any) { op.log(WarnLevel, args) }
+
+// Error logs a message in the operation at ErrorLevel
+func (op *Operation) Error(args ...any) { op.log(ErrorLevel, args) }
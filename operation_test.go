@@ -0,0 +1,58 @@
+package slog_test
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/gookit/goutil/testutil/assert"
+	"github.com/gookit/slog"
+	"github.com/gookit/slog/handler"
+)
+
+func TestLogger_Begin(t *testing.T) {
+	buf := new(bytes.Buffer)
+	h := handler.NewIOWriterHandler(buf, slog.AllLevels)
+	h.SetFormatter(slog.NewTextFormatter("{{level}} [{{op_id}}] {{message}} {{caller}}\n"))
+
+	l := slog.NewWithHandlers(h)
+	l.ReportCaller = true
+	l.CallerFlag = slog.CallerFlagFnLine
+
+	op := l.Begin("build")
+	sub := op.Begin("compile")
+	sub.Logf(slog.InfoLevel, "compile %d files", 23)
+	sub.End(errors.New("syntax error"))
+	op.Warn("has", "warnings")
+	assert.Gt(t, op.End(), 0)
+	// ended, not log again
+	op.End()
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	assert.Len(t, lines, 6)
+	assert.StrContains(t, lines[0], "INFO [1] begin build")
+	assert.StrContains(t, lines[1], "INFO [1.1]   begin compile")
+	assert.StrContains(t, lines[2], "INFO [1.1]     compile 23 files")
+	assert.StrContains(t, lines[3], "ERROR [1.1]   end compile, elapsed: ")
+	assert.StrContains(t, lines[3], ", error: syntax error")
+	assert.StrContains(t, lines[4], "WARN [1]   has warnings")
+	assert.StrContains(t, lines[5], "INFO [1] end build, elapsed: ")
+	for _, line := range lines {
+		assert.StrContains(t, line, "operation_test.go")
+	}
+
+	assert.Eq(t, op, sub.Parent())
+	assert.Nil(t, op.Parent())
+	assert.Eq(t, 1, sub.Depth)
+
+	// the top id is unique for the child loggers
+	buf.Reset()
+	slog.OpIndent = ""
+	defer func() { slog.OpIndent = "  " }()
+
+	op = l.With(slog.M{"k": "v"}).Begin("deploy")
+	op.Begin("upload").Info("done")
+	assert.Eq(t, "2", op.ID)
+	assert.StrContains(t, buf.String(), "INFO [2.1] done")
+}
@@ -1,10 +1,13 @@
 package slog
 
 import (
+	"crypto/hmac"
 	"crypto/md5"
+	"crypto/sha256"
 	"encoding/hex"
 	"os"
 	"runtime"
+	"time"
 
 	"github.com/gookit/goutil/strutil"
 )
@@ -100,3 +103,90 @@ func AppendCtxKeys(keys ...string) Processor {
 		}
 	})
 }
+
+// field names for the AddCtxErr processor
+const (
+	FieldKeyCtxErr      = "ctx_err"
+	FieldKeyCtxDeadline = "ctx_deadline_remain"
+)
+
+// AddCtxErr on the record context is done(canceled or timeout), add the field "ctx_err",
+// and the field "ctx_deadline_remain" on the context has deadline. it is negative on exceeded.
+//
+// Usage:
+//
+//	logger.AddProcessor(slog.AddCtxErr())
+func AddCtxErr() Processor {
+	return ProcessorFunc(func(record *Record) {
+		if record.Ctx == nil {
+			return
+		}
+
+		err := record.Ctx.Err()
+		if err == nil {
+			return
+		}
+
+		record.AddField(FieldKeyCtxErr, err.Error())
+		if deadline, ok := record.Ctx.Deadline(); ok {
+			record.AddField(FieldKeyCtxDeadline, time.Until(deadline).String())
+		}
+	})
+}
+
+// HashFields replace the field values with salted hashes(HMAC-SHA256, hex encoded),
+// so logs remain correlatable but not personally identifiable. for GDPR pseudonymization.
+//
+// Will check the keys on Record.Fields, Attrs, Data and Extra.
+//
+// Usage:
+//
+//	logger.AddProcessor(slog.HashFields(salt, "user_id", "email"))
+func HashFields(salt string, keys ...string) Processor {
+	hashMap := func(m M) M {
+		var nm M
+		for _, key := range keys {
+			if val, ok := m[key]; ok && val != nil {
+				// copy map, don't modify the map passed by user
+				if nm == nil {
+					nm = mergeMap(nil, m, true)
+				}
+				nm[key] = HashValue(salt, val)
+			}
+		}
+
+		if nm == nil {
+			return m
+		}
+		return nm
+	}
+
+	return ProcessorFunc(func(record *Record) {
+		record.Fields = hashMap(record.Fields)
+		record.Data = hashMap(record.Data)
+		record.Extra = hashMap(record.Extra)
+
+		// copy attrs, don't modify the slice shared with other records
+		var attrs []Field
+		for i, attr := range record.Attrs {
+			for _, key := range keys {
+				if attr.Key == key && !(attr.Kind == KindAny && attr.any == nil) {
+					if attrs == nil {
+						attrs = append([]Field(nil), record.Attrs...)
+					}
+					attrs[i] = String(key, HashValue(salt, attr.Value()))
+				}
+			}
+		}
+		if attrs != nil {
+			record.Attrs = attrs
+		}
+	})
+}
+
+// HashValue returns the salted hash(HMAC-SHA256, hex encoded) of the value.
+func HashValue(salt string, val any) string {
+	mac := hmac.New(sha256.New, []byte(salt))
+	mac.Write([]byte(strutil.SafeString(val)))
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -5,10 +5,12 @@ import (
 	"fmt"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/gookit/goutil/byteutil"
 	"github.com/gookit/goutil/testutil/assert"
 	"github.com/gookit/slog"
+	"github.com/gookit/slog/handler"
 )
 
 func TestLogger_AddProcessor(t *testing.T) {
@@ -72,3 +74,58 @@ func TestProcessable_AddProcessor(t *testing.T) {
 	assert.NotEmpty(t, r.Extra)
 	assert.Contains(t, r.Extra, "memoryUsage")
 }
+
+func TestHashFields(t *testing.T) {
+	buf := new(byteutil.Buffer)
+	h := handler.NewIOWriter(buf, slog.AllLevels)
+	h.SetFormatter(slog.NewJSONFormatter())
+
+	l := slog.NewWithHandlers(h)
+	l.AddProcessor(slog.HashFields("salt", "user_id", "email"))
+
+	data := slog.M{"email": "inhere@example.com"}
+	l.WithFields(slog.M{"user_id": 23, "name": "inhere"}).
+		SetData(data).
+		Info("user login")
+	// should not modify the input map
+	assert.Eq(t, "inhere@example.com", data["email"])
+
+	str := buf.String()
+	assert.StrContains(t, str, `"user_id":"`+slog.HashValue("salt", 23)+`"`)
+	assert.StrContains(t, str, `"email":"`+slog.HashValue("salt", "inhere@example.com")+`"`)
+	assert.StrContains(t, str, `"name":"inhere"`)
+	assert.NotContains(t, str, "inhere@example.com")
+
+	// same value has same hash, different salt has different hash
+	assert.Eq(t, slog.HashValue("salt", "abc"), slog.HashValue("salt", "abc"))
+	assert.NotEq(t, slog.HashValue("salt", "abc"), slog.HashValue("salt2", "abc"))
+}
+
+func TestAddCtxErr(t *testing.T) {
+	buf := new(byteutil.Buffer)
+	l := slog.NewJSONSugared(buf, slog.InfoLevel)
+	l.AddProcessor(slog.AddCtxErr())
+
+	// not done
+	ctx, cancel := context.WithCancel(context.Background())
+	l.WithContext(ctx).Info("not done")
+	assert.NotContains(t, buf.String(), slog.FieldKeyCtxErr)
+
+	// canceled
+	buf.Reset()
+	cancel()
+	l.WithContext(ctx).Info("canceled")
+	str := buf.String()
+	assert.StrContains(t, str, `"ctx_err":"context canceled"`)
+	assert.NotContains(t, str, slog.FieldKeyCtxDeadline)
+
+	// timeout
+	buf.Reset()
+	ctx, cancel = context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+	<-ctx.Done()
+	l.WithContext(ctx).Info("timeout")
+	str = buf.String()
+	assert.StrContains(t, str, `"ctx_err":"context deadline exceeded"`)
+	assert.StrContains(t, str, `"ctx_deadline_remain":"-`)
+}
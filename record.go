@@ -4,10 +4,9 @@ import (
 	"context"
 	"fmt"
 	"runtime"
+	"sort"
 	"strconv"
 	"time"
-
-	"github.com/gookit/goutil/strutil"
 )
 
 // Record a log record definition
@@ -21,6 +20,12 @@ type Record struct {
 	freed bool
 	// inited flag for record
 	inited bool
+	// the caller is set by SetCaller(), will not be overwritten on report caller.
+	callerFixed bool
+	// the panic has been recovered by Recover(), will not call PanicFunc after write.
+	recovered bool
+	// the record is injected by Logger.WriteRecord(), will not call PanicFunc or exit after write.
+	injected bool
 
 	// Time for record log, if is empty will use now.
 	//
@@ -38,6 +43,14 @@ type Record struct {
 	// Ctx context.Context
 	Ctx context.Context
 
+	// The custom fields of a record has three maps and the typed Attrs, the precedence on merge
+	// them(see MergedFields): Fields > Attrs > Data > Extra
+	//
+	//  - Fields: the top level fields set by the user. eg: WithField(), WithFields()
+	//  - Attrs: the typed fields set by the user, same level as Fields. eg: WithAttrs()
+	//  - Data: log context data set by the user. eg: WithData(), AddValue()
+	//  - Extra: extra data, mostly added by processors. eg: AddHostname()
+
 	// Fields custom fields data.
 	// Contains all the fields set by the user.
 	Fields M
@@ -45,6 +58,11 @@ type Record struct {
 	Data M
 	// Extra log extra data
 	Extra M
+	// Attrs typed fields, store them without interface boxing and map allocation.
+	// on same key, the value in Fields will be used.
+	Attrs []Field
+	// Tags for classify the record. eg: "security", "billing"
+	Tags []string
 
 	// Caller information
 	Caller *runtime.Frame
@@ -52,7 +70,8 @@ type Record struct {
 	CallerFlag uint8
 	// CallerSkip value. default is equals to Logger.CallerSkip
 	CallerSkip int
-	// EnableStack enable stack info, default is false. TODO
+	// EnableStack capture the stack trace to the field FieldKeyStack, default is false.
+	// see Logger.StackDepth, Logger.StackSkip
 	EnableStack bool
 
 	// Buffer Can use Buffer on formatter
@@ -64,12 +83,13 @@ type Record struct {
 }
 
 func newRecord(logger *Logger) *Record {
+	opts := logger.loadOpts()
 	return &Record{
 		logger:  logger,
-		Channel: strutil.OrElse(logger.ChannelName, DefaultChannelName),
+		Channel: opts.channelName,
 		// with some options
-		CallerFlag: logger.CallerFlag,
-		CallerSkip: logger.CallerSkip,
+		CallerFlag: opts.callerFlag,
+		CallerSkip: opts.callerSkip,
 		// init map data field
 		// Data:   make(M, 2),
 		// Extra:  make(M, 0),
@@ -119,6 +139,24 @@ func (r *Record) WithError(err error) *Record {
 	return r.WithFields(M{FieldKeyError: err})
 }
 
+// WithStack capture the stack trace to the field FieldKeyStack on write the record.
+//
+// Usage:
+//
+//	slog.WithFields(slog.M{"uid": 23}).WithStack().Error("get user failed")
+func (r *Record) WithStack() *Record {
+	nr := r.Copy()
+	nr.EnableStack = true
+	return nr
+}
+
+// WithTags with new tags to record
+func (r *Record) WithTags(tags ...string) *Record {
+	nr := r.Copy()
+	nr.Tags = append(nr.Tags, tags...)
+	return nr
+}
+
 // WithData on record
 func (r *Record) WithData(data M) *Record {
 	nr := r.Copy()
@@ -148,6 +186,17 @@ func (r *Record) WithFields(fields M) *Record {
 	return nr
 }
 
+// WithAttrs with new typed fields to record. see String(), Int(), Err() ...
+//
+// Usage:
+//
+//	l.Record().WithAttrs(slog.String("user", "inhere"), slog.Duration("cost", cost)).Info("message")
+func (r *Record) WithAttrs(fields ...Field) *Record {
+	nr := r.Copy()
+	nr.Attrs = append(nr.Attrs, fields...)
+	return nr
+}
+
 // Copy new record from old record
 func (r *Record) Copy() *Record {
 	dataCopy := make(M, len(r.Data))
@@ -168,19 +217,41 @@ func (r *Record) Copy() *Record {
 	return &Record{
 		// reuse: true, // copy record is reused
 		logger:  r.logger,
+		Ctx:     r.Ctx,
 		Channel: r.Channel,
 		// Time:       r.Time,
 		Level:      r.Level,
 		levelName:  r.levelName,
 		CallerFlag: r.CallerFlag,
 		CallerSkip: r.CallerSkip,
-		Message:    r.Message,
-		Data:       dataCopy,
-		Extra:      extraCopy,
-		Fields:     fieldsCopy,
+		// keep the stack flag on chain. eg: WithStack().WithField()
+		EnableStack: r.EnableStack,
+		Message:     r.Message,
+		Data:        dataCopy,
+		Extra:       extraCopy,
+		Fields:      fieldsCopy,
+		Tags:        append([]string(nil), r.Tags...),
+		Attrs:       append([]Field(nil), r.Attrs...),
 	}
 }
 
+// Clone full copy the record, contains the Time, Caller and other fields.
+//
+// Useful on handle the record asynchronously, the original record will be reused by the logger.
+func (r *Record) Clone() *Record {
+	nr := r.Copy()
+	nr.Time = r.Time
+	nr.inited = r.inited
+	nr.Fmt = r.Fmt
+	nr.Args = r.Args
+
+	if r.Caller != nil {
+		caller := *r.Caller
+		nr.Caller = &caller
+	}
+	return nr
+}
+
 //
 // ---------------------------------------------------------------------------
 // Direct set value to record
@@ -202,16 +273,15 @@ func (r *Record) SetData(data M) *Record {
 	return r
 }
 
-// AddData on record
+// AddData on record, will override the exists keys.
 func (r *Record) AddData(data M) *Record {
-	if r.Data == nil {
-		r.Data = data
-		return r
-	}
+	r.Data = mergeMap(r.Data, data, true)
+	return r
+}
 
-	for k, v := range data {
-		r.Data[k] = v
-	}
+// MergeData on record, only add the not exists keys.
+func (r *Record) MergeData(data M) *Record {
+	r.Data = mergeMap(r.Data, data, false)
 	return r
 }
 
@@ -239,16 +309,17 @@ func (r *Record) SetExtra(data M) *Record {
 	return r
 }
 
-// AddExtra information on record
+// AddExtra information on record, will override the exists keys.
 func (r *Record) AddExtra(data M) *Record {
-	if r.Extra == nil {
-		r.Extra = data
-		return r
-	}
+	r.Extra = mergeMap(r.Extra, data, true)
+	return r
+}
 
-	for k, v := range data {
-		r.Extra[k] = v
-	}
+// MergeExtra information on record, only add the not exists keys.
+//
+// Useful for processors, don't override the values set by the user.
+func (r *Record) MergeExtra(data M) *Record {
+	r.Extra = mergeMap(r.Extra, data, false)
 	return r
 }
 
@@ -276,16 +347,25 @@ func (r *Record) AddField(name string, val any) *Record {
 	return r
 }
 
-// AddFields add new fields to the record
-func (r *Record) AddFields(fields M) *Record {
-	if r.Fields == nil {
-		r.Fields = fields
-		return r
-	}
+// AddAttrs add new typed fields to the record
+func (r *Record) AddAttrs(fields ...Field) *Record {
+	r.Attrs = append(r.Attrs, fields...)
+	return r
+}
 
-	for n, v := range fields {
-		r.Fields[n] = v
+// Attr get the typed field by key
+func (r *Record) Attr(key string) (Field, bool) {
+	for i := len(r.Attrs) - 1; i >= 0; i-- {
+		if r.Attrs[i].Key == key {
+			return r.Attrs[i], true
+		}
 	}
+	return Field{}, false
+}
+
+// AddFields add new fields to the record
+func (r *Record) AddFields(fields M) *Record {
+	r.Fields = mergeMap(r.Fields, fields, true)
 	return r
 }
 
@@ -295,12 +375,49 @@ func (r *Record) SetFields(fields M) *Record {
 	return r
 }
 
-// Field value get from record
+// SetCaller set the caller of the record, it will not be overwritten on Logger.ReportCaller=true.
+//
+// Useful for the bridges, the caller is reported by other log library.
+func (r *Record) SetCaller(frame *runtime.Frame) *Record {
+	r.Caller = frame
+	r.callerFixed = frame != nil
+	return r
+}
+
+// CallerString format the caller by the CallerFlag. returns empty on the caller is not reported.
+func (r *Record) CallerString() string {
+	if r.Caller == nil {
+		return ""
+	}
+	return formatCaller(r.Caller, r.CallerFlag)
+}
+
+// AddTags add new tags to the record
+func (r *Record) AddTags(tags ...string) *Record {
+	r.Tags = append(r.Tags, tags...)
+	return r
+}
+
+// HasTag check the record has the tag
+func (r *Record) HasTag(tag string) bool {
+	for _, t := range r.Tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// Field value get from record. will also check the typed fields in Attrs.
 func (r *Record) Field(key string) any {
-	if r.Fields == nil {
-		return nil
+	if val, ok := r.Fields[key]; ok {
+		return val
 	}
-	return r.Fields[key]
+
+	if f, ok := r.Attr(key); ok {
+		return f.Value()
+	}
+	return nil
 }
 
 //
@@ -337,28 +454,14 @@ func (r *Record) Field(key string) any {
 //
 
 func (r *Record) log(level Level, args []any) {
-	r.Level = level
-	if r.logger.BackupArgs {
-		r.Args = args
-	}
-
 	// r.Message = strutil.Byte2str(formatArgsWithSpaces(args)) // will reduce memory allocation once
-	r.Message = formatArgsWithSpaces(args)
 	// do write log, then release record
-	r.logger.writeRecord(level, r)
-	r.logger.releaseRecord(r)
+	r.logger.writeRecord(level, r, "", formatArgsWithSpaces(args), args)
 }
 
 func (r *Record) logf(level Level, format string, args []any) {
-	if r.logger.BackupArgs {
-		r.Fmt, r.Args = format, args
-	}
-
-	r.Level = level
-	r.Message = fmt.Sprintf(format, args...)
 	// do write log, then release record
-	r.logger.writeRecord(level, r)
-	r.logger.releaseRecord(r)
+	r.logger.writeRecord(level, r, format, fmt.Sprintf(format, args...), args)
 }
 
 // Log a message with level
@@ -457,6 +560,94 @@ func (r *Record) Panicf(format string, args ...any) {
 // LevelName get
 func (r *Record) LevelName() string { return r.levelName }
 
+// MergedFields merge the Fields, Attrs, Data and Extra to a new map.
+//
+// precedence on same key: Fields > Attrs > Data > Extra
+func (r *Record) MergedFields() M {
+	merged := make(M, len(r.Fields)+len(r.Attrs)+len(r.Data)+len(r.Extra))
+	for _, m := range []M{r.Extra, r.Data} {
+		for k, v := range m {
+			merged[k] = v
+		}
+	}
+	for _, f := range r.Attrs {
+		merged[f.Key] = f.Value()
+	}
+	for k, v := range r.Fields {
+		merged[k] = v
+	}
+	return merged
+}
+
+// EachField visit all custom fields of the record in stable order.
+//
+// Order: Fields, Attrs, Data, Extra. the keys of each map are visited in sorted order,
+// the Attrs are visited in added order.
+// Useful for third-party formatters, don't need to reach into the three maps.
+//
+// Each key is visited once, the precedence on same key is same as MergedFields().
+func (r *Record) EachField(fn func(key string, val any)) {
+	seen := make(map[string]bool, len(r.Fields)+len(r.Attrs))
+	visit := func(key string, val any) {
+		if !seen[key] {
+			seen[key] = true
+			fn(key, val)
+		}
+	}
+
+	eachSorted(r.Fields, visit)
+	for i, f := range r.Attrs {
+		// the last one wins on the same key in Attrs
+		if !hasAttrKey(r.Attrs[i+1:], f.Key) {
+			visit(f.Key, f.Value())
+		}
+	}
+
+	for _, m := range []M{r.Data, r.Extra} {
+		eachSorted(m, visit)
+	}
+}
+
+func hasAttrKey(attrs []Field, key string) bool {
+	for _, f := range attrs {
+		if f.Key == key {
+			return true
+		}
+	}
+	return false
+}
+
+// merge src to dst, will init dst on it is nil. don't use the src as dst, avoid share the map.
+func mergeMap(dst, src M, override bool) M {
+	if dst == nil {
+		dst = make(M, len(src))
+	}
+
+	for k, v := range src {
+		if _, ok := dst[k]; ok && !override {
+			continue
+		}
+		dst[k] = v
+	}
+	return dst
+}
+
+func eachSorted(m M, fn func(key string, val any)) {
+	if len(m) == 0 {
+		return
+	}
+
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		fn(k, m[k])
+	}
+}
+
 // GoString of the record
 func (r *Record) GoString() string {
 	return "slog: " + r.Message
@@ -4,6 +4,8 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"runtime"
+	"strings"
 	"sync"
 	"testing"
 	"time"
@@ -132,6 +134,40 @@ func TestRecord_WithError(t *testing.T) {
 	fmt.Print(s)
 }
 
+func TestRecord_WithStack(t *testing.T) {
+	w := newBuffer()
+	l := slog.NewWithConfig(func(l *slog.Logger) {
+		l.StackDepth = 2
+		l.DoNothingOnPanicFatal()
+	})
+	h := handler.NewIOWriter(w, slog.AllLevels)
+	h.SetFormatter(slog.NewTextFormatter("msg={{message}}\n{{stack}}\n"))
+	l.SetHandlers([]slog.Handler{h})
+
+	l.Record().WithStack().Warn("record with stack")
+	s := w.StringReset()
+	lines := strings.Split(strings.TrimSpace(s), "\n")
+	assert.Len(t, lines, 5)
+	assert.Eq(t, "msg=record with stack", lines[0])
+	assert.Eq(t, "github.com/gookit/slog_test.TestRecord_WithStack", lines[1])
+	assert.StrContains(t, lines[2], "record_test.go:")
+	assert.Eq(t, "testing.tRunner", lines[3])
+
+	// the pooled record not keep the flag
+	l.Warn("without stack")
+	assert.Eq(t, "msg=without stack\nstack\n", w.StringReset())
+
+	// keep the flag on chain
+	l.Record().WithStack().WithField("k", "v").Error("chain with stack")
+	s = w.StringReset()
+	assert.StrContains(t, s, "msg=chain with stack\ngithub.com/gookit/slog_test.TestRecord_WithStack\n")
+
+	// skip more frames
+	l.StackSkip = 1
+	l.Record().WithStack().Warn("record with stack")
+	assert.StrContains(t, w.StringReset(), "\ntesting.tRunner\n")
+}
+
 func TestRecord_WithTime(t *testing.T) {
 	w := newBuffer()
 	l := slog.NewWithConfig(func(l *slog.Logger) {
@@ -244,3 +280,100 @@ func TestRecord_useMultiTimes(t *testing.T) {
 		wg.Wait()
 	})
 }
+
+func TestRecord_WithTags(t *testing.T) {
+	l := slog.New()
+	r := l.WithTags("security")
+	assert.True(t, r.HasTag("security"))
+	assert.False(t, r.HasTag("billing"))
+
+	r2 := r.WithTags("billing")
+	assert.Eq(t, []string{"security", "billing"}, r2.Tags)
+	assert.Eq(t, []string{"security"}, r.Tags)
+	r.AddTags("audit")
+	assert.True(t, r.HasTag("audit"))
+
+	r2.Message = "tags message"
+	f := slog.NewTextFormatter("[{{tags}}] {{message}}\n")
+	bts, err := f.Format(r2)
+	assert.NoErr(t, err)
+	assert.Eq(t, "[security,billing] tags message\n", string(bts))
+
+	jf := slog.NewJSONFormatter().AddField(slog.FieldKeyTags)
+	bts, err = jf.Format(r2)
+	assert.NoErr(t, err)
+	assert.StrContains(t, string(bts), `"tags":["security","billing"]`)
+	assert.Eq(t, []string{"audit"}, slog.WithTags("audit").Tags)
+}
+
+func TestRecord_EachField(t *testing.T) {
+	r := newLogRecord("test message")
+	r.Fields = slog.M{"b": 2, "a": 1}
+	r.Data = slog.M{"d": 4, "c": 3}
+	r.Extra = slog.M{"e": 5}
+
+	var keys []string
+	var vals []any
+	r.EachField(func(key string, val any) {
+		keys = append(keys, key)
+		vals = append(vals, val)
+	})
+
+	assert.Eq(t, []string{"a", "b", "c", "d", "e"}, keys)
+	assert.Eq(t, []any{1, 2, 3, 4, 5}, vals)
+
+	// overlapping keys, same precedence as MergedFields
+	r.Attrs = []slog.Field{slog.Int("a", 10), slog.Int("f", 6), slog.Int("f", 60), slog.Int("c", 30)}
+	r.Extra = slog.M{"a": 100, "c": 300, "e": 5}
+
+	keys, vals = nil, nil
+	r.EachField(func(key string, val any) {
+		keys = append(keys, key)
+		vals = append(vals, val)
+	})
+
+	assert.Eq(t, []string{"a", "b", "f", "c", "d", "e"}, keys)
+	assert.Eq(t, []any{1, 2, int64(60), int64(30), 4, 5}, vals)
+
+	merged := r.MergedFields()
+	assert.Len(t, merged, len(keys))
+	for i, key := range keys {
+		assert.Eq(t, merged[key], vals[i])
+	}
+}
+
+func TestRecord_MergeExtra(t *testing.T) {
+	r := newLogRecord("test message")
+	r.Extra = nil
+
+	ext := slog.M{"key0": "val0"}
+	r.AddExtra(ext)
+	r.SetExtraValue("key1", "val1")
+	// should not modify the input map
+	assert.Len(t, ext, 1)
+
+	r.MergeExtra(slog.M{"key0": "new", "key2": "val2"})
+	assert.Eq(t, slog.M{"key0": "val0", "key1": "val1", "key2": "val2"}, r.Extra)
+	r.AddExtra(slog.M{"key0": "new"})
+	assert.Eq(t, "new", r.Extra["key0"])
+
+	r.SetData(nil)
+	data := slog.M{"key0": "val0"}
+	r.AddData(data).AddValue("key1", "val1")
+	assert.Len(t, data, 1)
+	r.MergeData(slog.M{"key1": "new", "key2": "val2"})
+	assert.Eq(t, slog.M{"key0": "val0", "key1": "val1", "key2": "val2"}, r.Data)
+}
+
+func TestRecord_Clone(t *testing.T) {
+	r := newLogRecord("test message")
+	r.Caller = &runtime.Frame{File: "test.go", Line: 23}
+
+	nr := r.Clone()
+	assert.Eq(t, r.Time, nr.Time)
+	assert.Eq(t, r.Message, nr.Message)
+	assert.Eq(t, 23, nr.Caller.Line)
+
+	nr.Caller.Line = 45
+	assert.Eq(t, 23, r.Caller.Line)
+}
@@ -0,0 +1,153 @@
+package slog
+
+import (
+	"net/http"
+	"runtime"
+	"strings"
+)
+
+// FieldKeyPanic key name for the recovered panic value. see Recover()
+const FieldKeyPanic = "panic"
+
+// RecoverOptions for the Recover(), Logger.RecoverFunc()
+type RecoverOptions struct {
+	// RePanic re-panic with the recovered value after logged. default is false, swallow the panic.
+	RePanic bool
+	// OnPanic custom callback after logged, before re-panic.
+	OnPanic func(v any)
+}
+
+func newRecoverOptions(fns []func(opt *RecoverOptions)) *RecoverOptions {
+	opt := &RecoverOptions{}
+	for _, fn := range fns {
+		fn(opt)
+	}
+	return opt
+}
+
+// Recover the panic and log it at PanicLevel with the stack trace. must be called directly by defer.
+//
+// The caller of the record is the function that panicked, the Logger.PanicFunc will not be called.
+//
+// Usage:
+//
+//	func worker() {
+//		defer slog.Recover(logger)
+//		// ...
+//	}
+//
+//	// re-panic after logged
+//	defer slog.Recover(logger, func(opt *slog.RecoverOptions) {
+//		opt.RePanic = true
+//	})
+func Recover(l *Logger, fns ...func(opt *RecoverOptions)) {
+	if v := recover(); v != nil {
+		l.logRecovered(v, newRecoverOptions(fns))
+	}
+}
+
+// RecoverFunc returns a func for recover the panic by defer. see Recover()
+//
+// Usage:
+//
+//	defer logger.RecoverFunc()()
+func (l *Logger) RecoverFunc(fns ...func(opt *RecoverOptions)) func() {
+	opt := newRecoverOptions(fns)
+	return func() {
+		if v := recover(); v != nil {
+			l.logRecovered(v, opt)
+		}
+	}
+}
+
+// RecoverHandler wrap the http.Handler, will recover the panic and log it,
+// then response status 500. the http.ErrAbortHandler will be re-panic.
+//
+// Usage:
+//
+//	http.ListenAndServe(":8080", slog.RecoverHandler(logger, mux))
+func RecoverHandler(l *Logger, next http.Handler, fns ...func(opt *RecoverOptions)) http.Handler {
+	opt := newRecoverOptions(fns)
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		defer func() {
+			if v := recover(); v != nil {
+				if v == http.ErrAbortHandler {
+					panic(v)
+				}
+
+				if !opt.RePanic {
+					w.WriteHeader(http.StatusInternalServerError)
+				}
+				l.WithContext(req.Context()).
+					AddFields(M{"method": req.Method, "url": req.URL.String()}).
+					logRecovered(v, opt)
+			}
+		}()
+
+		next.ServeHTTP(w, req)
+	})
+}
+
+func (l *Logger) logRecovered(v any, opt *RecoverOptions) {
+	l.newRecord().logRecovered(v, opt)
+}
+
+func (r *Record) logRecovered(v any, opt *RecoverOptions) {
+	caller, stack := getPanicStack(r.logger.loadOpts().stackDepth)
+	if caller.PC != 0 {
+		r.SetCaller(&caller)
+	}
+
+	r.recovered = true
+	r.AddField(FieldKeyStack, stack)
+	if err, ok := v.(error); ok {
+		r.AddField(FieldKeyPanic, err.Error())
+		if causes := errorCauses(err); len(causes) > 0 {
+			r.AddField(FieldKeyErrorCauses, causes)
+		}
+	} else {
+		r.AddField(FieldKeyPanic, v)
+	}
+	r.log(PanicLevel, []any{"panic recovered:", v})
+
+	if opt.OnPanic != nil {
+		opt.OnPanic(v)
+	}
+	if opt.RePanic {
+		panic(v)
+	}
+}
+
+// getPanicStack get the stack trace from the function that panicked, must be called on recover.
+//
+// returns the first frame as the caller.
+func getPanicStack(depth int) (caller runtime.Frame, stack string) {
+	if depth <= 0 {
+		depth = DefaultStackDepth
+	}
+
+	pcs := make([]uintptr, depth+maxCallerDepth)
+	num := runtime.Callers(2, pcs)
+	frames := runtime.CallersFrames(pcs[:num])
+
+	// skip the frames before the panic, and the runtime frames. eg: runtime.gopanic, runtime.sigpanic
+	var sb strings.Builder
+	var panicked bool
+	for n := 0; n < depth; {
+		f, more := frames.Next()
+		if panicked && !strings.HasPrefix(f.Function, "runtime.") {
+			if n == 0 {
+				caller = f
+			}
+			writeStackFrame(&sb, &f)
+			n++
+		} else if f.Function == "runtime.gopanic" {
+			panicked = true
+		}
+
+		if !more {
+			break
+		}
+	}
+	return caller, sb.String()
+}
@@ -0,0 +1,126 @@
+package slog_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gookit/goutil/testutil/assert"
+	"github.com/gookit/slog"
+	"github.com/gookit/slog/handler"
+)
+
+func newRecoverLogger() (*slog.Logger, *bytes.Buffer) {
+	buf := new(bytes.Buffer)
+	h := handler.NewIOWriterHandler(buf, slog.AllLevels)
+	h.SetFormatter(slog.NewJSONFormatter(func(f *slog.JSONFormatter) {
+		f.Fields = []string{slog.FieldKeyLevel, slog.FieldKeyCaller, slog.FieldKeyMessage}
+	}))
+
+	l := slog.NewWithHandlers(h)
+	l.CallerFlag = slog.CallerFlagFcName
+	return l, buf
+}
+
+func decodeRecord(t *testing.T, buf *bytes.Buffer) map[string]any {
+	var data map[string]any
+	assert.NoErr(t, json.Unmarshal(buf.Bytes(), &data))
+	buf.Reset()
+	return data
+}
+
+func panicWorker(v any) {
+	panic(v)
+}
+
+func TestRecover(t *testing.T) {
+	l, buf := newRecoverLogger()
+
+	func() {
+		defer slog.Recover(l)
+		panicWorker("boom")
+	}()
+
+	data := decodeRecord(t, buf)
+	assert.Eq(t, "PANIC", data["level"])
+	assert.Eq(t, "panicWorker", data["caller"])
+	assert.Eq(t, "panic recovered: boom", data["message"])
+	assert.Eq(t, "boom", data["panic"])
+	assert.StrContains(t, data["stack"].(string), "github.com/gookit/slog_test.panicWorker\n\t")
+
+	// runtime error
+	func() {
+		defer slog.Recover(l)
+		var mp map[string]int
+		mp["key"] = 1
+	}()
+
+	data = decodeRecord(t, buf)
+	assert.Eq(t, "func2", data["caller"])
+	assert.Eq(t, "assignment to entry in nil map", data["panic"])
+
+	// re-panic
+	var called bool
+	err := fmt.Errorf("wrap: %w", errors.New("root error"))
+	assert.PanicsErrMsg(t, func() {
+		defer slog.Recover(l, func(opt *slog.RecoverOptions) {
+			opt.RePanic = true
+			opt.OnPanic = func(v any) { called = true }
+		})
+		panicWorker(err)
+	}, "wrap: root error")
+
+	assert.True(t, called)
+	data = decodeRecord(t, buf)
+	assert.Eq(t, "wrap: root error", data["panic"])
+	assert.Eq(t, []any{"root error"}, data["error.causes"])
+}
+
+func TestLogger_RecoverFunc(t *testing.T) {
+	l, buf := newRecoverLogger()
+
+	func() {
+		defer l.RecoverFunc()()
+		panicWorker(23)
+	}()
+
+	data := decodeRecord(t, buf)
+	assert.Eq(t, "panicWorker", data["caller"])
+	assert.Eq(t, float64(23), data["panic"])
+
+	// no panic
+	func() {
+		defer l.RecoverFunc()()
+	}()
+	assert.Empty(t, buf.String())
+}
+
+func TestRecoverHandler(t *testing.T) {
+	l, buf := newRecoverLogger()
+
+	h := slog.RecoverHandler(l, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panicWorker("handler panic")
+	}))
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("GET", "/users?id=23", nil))
+	assert.Eq(t, http.StatusInternalServerError, w.Code)
+
+	data := decodeRecord(t, buf)
+	assert.Eq(t, "panicWorker", data["caller"])
+	assert.Eq(t, "GET", data["method"])
+	assert.Eq(t, "/users?id=23", data["url"])
+
+	// abort handler
+	h = slog.RecoverHandler(l, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic(http.ErrAbortHandler)
+	}))
+	assert.Panics(t, func() {
+		h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+	})
+	assert.Empty(t, buf.String())
+}
@@ -0,0 +1,81 @@
+package slog
+
+import (
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+)
+
+// ReopenAll reopen the output files of the handlers, which implements the Reopener.
+//
+// Use it on the files are rotated by external tools. eg: logrotate move the file then send SIGHUP.
+// see ReopenOnSignal()
+func (l *Logger) ReopenAll() error {
+	if l.root != nil {
+		return l.root.ReopenAll()
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	var lastErr error
+	for _, h := range l.loadHandlers() {
+		ro, ok := h.(Reopener)
+		if !ok {
+			continue
+		}
+
+		if err := ro.Reopen(); err != nil {
+			lastErr = err
+			l.errs.add("reopen", h, err)
+			reportError("slog: call handler.Reopen() error, handler:", HandlerName(h), "error:", err)
+		}
+	}
+	return lastErr
+}
+
+// ReopenOnSignal reopen the output files of the handlers on receive the signals, default is SIGHUP.
+// returns a func for stop it.
+//
+// Usage:
+//
+//	stop := logger.ReopenOnSignal()
+//	defer stop()
+//
+// logrotate config:
+//
+//	/var/log/app.log {
+//		daily
+//		postrotate
+//			kill -HUP $(cat /var/run/app.pid)
+//		endscript
+//	}
+func (l *Logger) ReopenOnSignal(sigs ...os.Signal) (stop func()) {
+	if len(sigs) == 0 {
+		sigs = []os.Signal{syscall.SIGHUP}
+	}
+
+	ch := make(chan os.Signal, 1)
+	done := make(chan struct{})
+	signal.Notify(ch, sigs...)
+
+	go func() {
+		for {
+			select {
+			case <-ch:
+				_ = l.ReopenAll()
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			signal.Stop(ch)
+			close(done)
+		})
+	}
+}
@@ -0,0 +1,86 @@
+package slog_test
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/gookit/goutil/errorx"
+	"github.com/gookit/goutil/testutil/assert"
+	"github.com/gookit/slog"
+	"github.com/gookit/slog/handler"
+)
+
+type reopenHandler struct {
+	handler.NopFlushClose
+	slog.LevelWithFormatter
+	err    error
+	reopen int32
+}
+
+func (h *reopenHandler) Handle(_ *slog.Record) error { return nil }
+
+func (h *reopenHandler) Reopen() error {
+	atomic.AddInt32(&h.reopen, 1)
+	return h.err
+}
+
+func TestLogger_ReopenAll(t *testing.T) {
+	dir := t.TempDir()
+	logfile := filepath.Join(dir, "app.log")
+	fh := handler.MustFileHandler(logfile, handler.WithBuffSize(1024), handler.WithReopenable(true))
+
+	h1 := &reopenHandler{}
+	l := slog.NewWithHandlers(fh, h1)
+	defer l.MustClose()
+
+	l.Info("before rotate")
+	// move the file, like logrotate
+	assert.NoErr(t, os.Rename(logfile, logfile+".1"))
+	assert.NoErr(t, l.ReopenAll())
+	assert.Eq(t, int32(1), h1.reopen)
+
+	l.Info("after rotate")
+	assert.NoErr(t, l.Flush())
+
+	bs, err := os.ReadFile(logfile + ".1")
+	assert.NoErr(t, err)
+	assert.StrContains(t, string(bs), "before rotate")
+	assert.NotContains(t, string(bs), "after rotate")
+
+	bs, err = os.ReadFile(logfile)
+	assert.NoErr(t, err)
+	assert.StrContains(t, string(bs), "after rotate")
+	assert.NotContains(t, string(bs), "before rotate")
+
+	// reopen error
+	h2 := &reopenHandler{err: errorx.Raw("reopen error")}
+	l2 := slog.NewWithHandlers(h2)
+	assert.ErrMsg(t, l2.ReopenAll(), "reopen error")
+	assert.Eq(t, int32(1), h2.reopen)
+}
+
+func TestLogger_ReopenOnSignal(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("SIGHUP is not supported on windows")
+	}
+
+	h := &reopenHandler{}
+	l := slog.NewWithHandlers(h)
+	stop := l.ReopenOnSignal()
+	defer stop()
+
+	p, err := os.FindProcess(os.Getpid())
+	assert.NoErr(t, err)
+	assert.NoErr(t, p.Signal(syscall.SIGHUP))
+
+	for i := 0; i < 100 && atomic.LoadInt32(&h.reopen) == 0; i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+	assert.Eq(t, int32(1), atomic.LoadInt32(&h.reopen))
+	stop()
+}
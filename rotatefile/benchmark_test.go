@@ -0,0 +1,54 @@
+package rotatefile_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/gookit/slog/rotatefile"
+)
+
+var benchLine = []byte("[2023/11/16T10:20:30.000] [application] [INFO] [main.go:20] benchmark log message\n")
+
+func newBenchWriter(b *testing.B, fns ...rotatefile.ConfigFn) *rotatefile.Writer {
+	w, err := rotatefile.NewWriterWith(append([]rotatefile.ConfigFn{
+		rotatefile.WithFilepath(filepath.Join(b.TempDir(), "bench.log")),
+	}, fns...)...)
+	if err != nil {
+		b.Fatal(err)
+	}
+	return w
+}
+
+func runParallelWrite(b *testing.B, w *rotatefile.Writer) {
+	b.ReportAllocs()
+	b.SetBytes(int64(len(benchLine)))
+	b.ResetTimer()
+
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			if _, err := w.Write(benchLine); err != nil {
+				b.Error(err)
+				return
+			}
+		}
+	})
+
+	b.StopTimer()
+	if err := w.Close(); err != nil {
+		b.Fatal(err)
+	}
+}
+
+// go test -bench Writer -benchmem -run none ./rotatefile
+func BenchmarkWriter_Write(b *testing.B) {
+	runParallelWrite(b, newBenchWriter(b))
+}
+
+// rotate frequently, the rotated files are compressed and cleaned in background.
+func BenchmarkWriter_Write_rotating(b *testing.B) {
+	runParallelWrite(b, newBenchWriter(b, func(c *rotatefile.Config) {
+		c.MaxSize = 256 * 1024
+		c.BackupNum = 3
+		c.Compress = true
+	}))
+}
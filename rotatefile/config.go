@@ -27,6 +27,11 @@ const (
 //   - "error.log.20201223_1500"
 //   - "error.log.20201223_1530"
 //   - "error.log.20201223_1523"
+//
+// CalendarDay, CalendarWeek, CalendarMonth:
+//   - "error.log.20201223"
+//   - "error.log.20201221" // the Monday of the week
+//   - "error.log.202012"
 type RotateTime int
 
 // built in rotate time constants
@@ -40,16 +45,77 @@ const (
 	EverySecond RotateTime = 1 // only use for tests
 )
 
+// calendar aligned rotate time constants, will rotate exactly at the local time boundaries,
+// not drift like the fixed interval. one file per calendar day, week or month.
+const (
+	// CalendarDay rotate at the local midnight
+	CalendarDay RotateTime = -1
+	// CalendarWeek rotate at the Monday 00:00
+	CalendarWeek RotateTime = -2
+	// CalendarMonth rotate at the first day of month 00:00
+	CalendarMonth RotateTime = -3
+)
+
+// IsCalendar check is calendar aligned rotate time. see CalendarDay
+func (rt RotateTime) IsCalendar() bool {
+	return rt >= CalendarMonth && rt <= CalendarDay
+}
+
 // Interval get check interval time. unit is seconds.
+//
+// For the calendar aligned rotate time, returns the nominal seconds of the period.
 func (rt RotateTime) Interval() int64 {
+	switch rt {
+	case CalendarDay:
+		return timex.OneDaySec
+	case CalendarWeek:
+		return 7 * timex.OneDaySec
+	case CalendarMonth:
+		return 30 * timex.OneDaySec
+	}
 	return int64(rt)
 }
 
+// PeriodStart get the start time of the calendar period which contains t.
+// returns t for the not calendar aligned rotate time.
+func (rt RotateTime) PeriodStart(t time.Time) time.Time {
+	y, m, d := t.Date()
+	switch rt {
+	case CalendarDay:
+		return time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+	case CalendarWeek:
+		// days since Monday
+		offset := (int(t.Weekday()) + 6) % 7
+		return time.Date(y, m, d-offset, 0, 0, 0, 0, t.Location())
+	case CalendarMonth:
+		return time.Date(y, m, 1, 0, 0, 0, 0, t.Location())
+	}
+	return t
+}
+
+// NextPeriodStart get the start time of the next calendar period after t.
+func (rt RotateTime) NextPeriodStart(t time.Time) time.Time {
+	start := rt.PeriodStart(t)
+	switch rt {
+	case CalendarDay:
+		return start.AddDate(0, 0, 1)
+	case CalendarWeek:
+		return start.AddDate(0, 0, 7)
+	case CalendarMonth:
+		return start.AddDate(0, 1, 0)
+	}
+	return t.Add(time.Duration(rt.Interval()) * time.Second)
+}
+
 // FirstCheckTime for rotate file.
 // - will automatically align the time from the start of each hour.
+// - the calendar aligned rotate time will return the next period start.
 func (rt RotateTime) FirstCheckTime(now time.Time) time.Time {
-	interval := rt.Interval()
+	if rt.IsCalendar() {
+		return rt.NextPeriodStart(now)
+	}
 
+	interval := rt.Interval()
 	switch rt.level() {
 	case levelDay:
 		return timex.DayEnd(now)
@@ -77,7 +143,7 @@ func (rt RotateTime) FirstCheckTime(now time.Time) time.Time {
 // level for rotate time
 func (rt RotateTime) level() rotateLevel {
 	switch {
-	case rt >= timex.OneDaySec:
+	case rt.IsCalendar(), rt >= timex.OneDaySec:
 		return levelDay
 	case rt >= timex.OneHourSec:
 		return levelHour
@@ -98,6 +164,10 @@ func (rt RotateTime) level() rotateLevel {
 //   - "error.log.20201223_1530"
 //   - "error.log.20201223_1523"
 func (rt RotateTime) TimeFormat() (suffixFormat string) {
+	if rt == CalendarMonth {
+		return "200601"
+	}
+
 	suffixFormat = "20060102_1500" // default is levelHour
 	switch rt.level() {
 	case levelDay:
@@ -114,6 +184,15 @@ func (rt RotateTime) TimeFormat() (suffixFormat string) {
 
 // String rotate type to string
 func (rt RotateTime) String() string {
+	switch rt {
+	case CalendarDay:
+		return "Every Calendar Day"
+	case CalendarWeek:
+		return "Every Calendar Week"
+	case CalendarMonth:
+		return "Every Calendar Month"
+	}
+
 	switch rt.level() {
 	case levelDay:
 		return fmt.Sprintf("Every %d Day", rt.Interval()/timex.OneDaySec)
@@ -162,6 +241,8 @@ type Config struct {
 	// RotateTime the file rotate interval time, unit is seconds.
 	// If is equals zero, disable rotate file by time
 	//
+	// Use CalendarDay, CalendarWeek, CalendarMonth for rotate at the calendar boundaries.
+	//
 	// default: EveryHour
 	RotateTime RotateTime `json:"rotate_time" yaml:"rotate_time"`
 
@@ -170,9 +251,18 @@ type Config struct {
 	// default: false
 	CloseLock bool `json:"close_lock" yaml:"close_lock"`
 
+	// MultiProcess enable for multiple processes write the same logfile.
+	//
+	// Will hold an advisory file lock(flock) on write and rotating, check the rotating by the real file size,
+	// and reopen the logfile on it has been rotated by other process. so the lines are not interleaved or double-rotated.
+	// On windows, only rely on the O_APPEND writes.
+	//
+	// default: false
+	MultiProcess bool `json:"multi_process" yaml:"multi_process"`
+
 	// BackupNum max number for keep old files.
 	//
-	// 0 is not limit, default is DefaultBackNum
+	// 0 is not limit, default is DefaultBackNum. eg: BackupNum=0, BackupTime=24 only remove the backups older than 24 hours.
 	BackupNum uint `json:"backup_num" yaml:"backup_num"`
 
 	// BackupTime max time for keep old files, unit is hours.
@@ -180,10 +270,22 @@ type Config struct {
 	// 0 is not limit, default is DefaultBackTime
 	BackupTime uint `json:"backup_time" yaml:"backup_time"`
 
+	// MaxTotalSize max total size of the backup files, unit is bytes.
+	// will remove the oldest backups on the total size exceeds it, independent of BackupNum and BackupTime.
+	//
+	// 0 is not limit, default is 0.
+	MaxTotalSize uint64 `json:"max_total_size" yaml:"max_total_size"`
+
 	// Compress determines if the rotated log files should be compressed using gzip.
 	// The default is not to perform compression.
 	Compress bool `json:"compress" yaml:"compress"`
 
+	// ManifestFile write the info(name, size, sha256, time range) of the rotated files to it,
+	// one JSON per line. so the archival tools can verify the backups. see ManifestEntry, VerifyManifest()
+	//
+	// The entries of the deleted backups are removed. default is empty, not write.
+	ManifestFile string `json:"manifest_file" yaml:"manifest_file"`
+
 	// RenameFunc you can custom-build filename for rotate file by size.
 	//
 	// default see DefaultFilenameFn
@@ -196,6 +298,11 @@ type Config struct {
 	DebugMode bool
 }
 
+// check should clean old files by config
+func (c *Config) shouldClean() bool {
+	return c.BackupNum > 0 || c.BackupTime > 0 || c.MaxTotalSize > 0
+}
+
 func (c *Config) backupDuration() time.Duration {
 	if c.BackupTime < 1 {
 		return 0
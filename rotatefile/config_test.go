@@ -98,3 +98,34 @@ func TestRotateTime_FirstCheckTime_Round(t *testing.T) {
 	dur = time.Duration(nowMin + logMin)
 	assert.Eq(t, time.Duration(45), dur.Round(time.Duration(logMin)))
 }
+
+func TestRotateTime_calendar(t *testing.T) {
+	// 2023-11-16 is Thursday
+	now := time.Date(2023, 11, 16, 15, 4, 5, 0, time.Local)
+
+	rt := rotatefile.CalendarDay
+	assert.True(t, rt.IsCalendar())
+	assert.False(t, rotatefile.EveryDay.IsCalendar())
+	assert.Eq(t, "20060102", rt.TimeFormat())
+	assert.Eq(t, "Every Calendar Day", rt.String())
+	assert.Eq(t, int64(timex.OneDaySec), rt.Interval())
+	assert.Eq(t, time.Date(2023, 11, 16, 0, 0, 0, 0, time.Local), rt.PeriodStart(now))
+	assert.Eq(t, time.Date(2023, 11, 17, 0, 0, 0, 0, time.Local), rt.FirstCheckTime(now))
+
+	rt = rotatefile.CalendarWeek
+	assert.Eq(t, "20060102", rt.TimeFormat())
+	assert.Eq(t, "Every Calendar Week", rt.String())
+	assert.Eq(t, time.Date(2023, 11, 13, 0, 0, 0, 0, time.Local), rt.PeriodStart(now))
+	assert.Eq(t, time.Date(2023, 11, 20, 0, 0, 0, 0, time.Local), rt.NextPeriodStart(now))
+	// on Sunday and Monday
+	sunday := time.Date(2023, 11, 19, 23, 0, 0, 0, time.Local)
+	assert.Eq(t, time.Date(2023, 11, 13, 0, 0, 0, 0, time.Local), rt.PeriodStart(sunday))
+	monday := time.Date(2023, 11, 20, 0, 0, 0, 0, time.Local)
+	assert.Eq(t, monday, rt.PeriodStart(monday))
+
+	rt = rotatefile.CalendarMonth
+	assert.Eq(t, "200601", rt.TimeFormat())
+	assert.Eq(t, "Every Calendar Month", rt.String())
+	assert.Eq(t, time.Date(2023, 11, 1, 0, 0, 0, 0, time.Local), rt.PeriodStart(now))
+	assert.Eq(t, time.Date(2024, 1, 1, 0, 0, 0, 0, time.Local), rt.NextPeriodStart(time.Date(2023, 12, 31, 23, 59, 59, 0, time.Local)))
+}
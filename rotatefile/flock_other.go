@@ -0,0 +1,10 @@
+//go:build windows || plan9
+
+package rotatefile
+
+import "os"
+
+// flock is not supported, only rely on the O_APPEND writes.
+func lockFile(_ *os.File) error { return nil }
+
+func unlockFile(_ *os.File) error { return nil }
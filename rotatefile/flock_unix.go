@@ -0,0 +1,22 @@
+//go:build !windows && !plan9
+
+package rotatefile
+
+import (
+	"os"
+	"syscall"
+)
+
+// lock the file by flock, will block until get the exclusive lock.
+func lockFile(f *os.File) error {
+	for {
+		err := syscall.Flock(int(f.Fd()), syscall.LOCK_EX)
+		if err != syscall.EINTR {
+			return err
+		}
+	}
+}
+
+func unlockFile(f *os.File) error {
+	return syscall.Flock(int(f.Fd()), syscall.LOCK_UN)
+}
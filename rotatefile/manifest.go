@@ -0,0 +1,166 @@
+package rotatefile
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/gookit/goutil/errorx"
+	"github.com/gookit/goutil/fsutil"
+)
+
+// ManifestEntry the info of a rotated file in the manifest. see Config.ManifestFile
+type ManifestEntry struct {
+	// Name the file name of the rotated file. eg: "error.log.20231116", "error.log.20231116.gz"
+	Name string `json:"name"`
+	// Size of the file, unit is bytes.
+	Size int64 `json:"size"`
+	// SHA256 hex checksum of the file contents
+	SHA256 string `json:"sha256"`
+	// Start time of the logs in the file, it is the first write time.
+	Start time.Time `json:"start"`
+	// End time of the logs in the file, it is the rotating time.
+	End time.Time `json:"end"`
+}
+
+// ReadManifest read the manifest entries from file. the file is JSON lines, one entry per line.
+func ReadManifest(manifestFile string) ([]ManifestEntry, error) {
+	bs, err := os.ReadFile(manifestFile)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var entries []ManifestEntry
+	sc := bufio.NewScanner(bytes.NewReader(bs))
+	for sc.Scan() {
+		line := bytes.TrimSpace(sc.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+
+		var ent ManifestEntry
+		if err = json.Unmarshal(line, &ent); err != nil {
+			return nil, errorx.Wrap(err, "invalid manifest line")
+		}
+		entries = append(entries, ent)
+	}
+	return entries, sc.Err()
+}
+
+// VerifyManifest check the files in the manifest are exists, and the size and checksum are matched.
+// the files are found in the dir, default is the dir of manifest file.
+//
+// Usage:
+//
+//	err := rotatefile.VerifyManifest("/var/log/app/error.manifest.jsonl", "")
+func VerifyManifest(manifestFile, dir string) error {
+	entries, err := ReadManifest(manifestFile)
+	if err != nil {
+		return err
+	}
+
+	if dir == "" {
+		dir = filepath.Dir(manifestFile)
+	}
+
+	for _, ent := range entries {
+		size, sum, err := fileChecksum(filepath.Join(dir, ent.Name))
+		if err != nil {
+			return err
+		}
+		if size != ent.Size || sum != ent.SHA256 {
+			return fmt.Errorf("rotatefile: the file %q is not matched with the manifest", ent.Name)
+		}
+	}
+	return nil
+}
+
+// calc the size and sha256 checksum of the file
+func fileChecksum(fPath string) (int64, string, error) {
+	f, err := os.Open(fPath)
+	if err != nil {
+		return 0, "", err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	size, err := io.Copy(h, f)
+	if err != nil {
+		return 0, "", err
+	}
+	return size, hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// add the rotated file to manifest
+func (d *Writer) addManifest(cfg *Config, fPath string, start, end time.Time) error {
+	size, sum, err := fileChecksum(fPath)
+	if err != nil {
+		return err
+	}
+
+	ent := ManifestEntry{Name: filepath.Base(fPath), Size: size, SHA256: sum, Start: start, End: end}
+	return d.updateManifest(cfg, func(entries []ManifestEntry) []ManifestEntry {
+		return append(entries, ent)
+	})
+}
+
+// update the manifest entry on the file is compressed to dstPath
+func (d *Writer) renameManifest(cfg *Config, srcPath, dstPath string) error {
+	size, sum, err := fileChecksum(dstPath)
+	if err != nil {
+		return err
+	}
+
+	srcName := filepath.Base(srcPath)
+	return d.updateManifest(cfg, func(entries []ManifestEntry) []ManifestEntry {
+		for i, ent := range entries {
+			if ent.Name == srcName {
+				entries[i].Name, entries[i].Size, entries[i].SHA256 = filepath.Base(dstPath), size, sum
+			}
+		}
+		return entries
+	})
+}
+
+// update the manifest by fn, and remove the entries of the deleted files.
+// will write to a temp file then rename, avoid the broken manifest.
+func (d *Writer) updateManifest(cfg *Config, fn func(entries []ManifestEntry) []ManifestEntry) error {
+	d.manifestMu.Lock()
+	defer d.manifestMu.Unlock()
+
+	mfFile := cfg.ManifestFile
+	entries, err := ReadManifest(mfFile)
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	for _, ent := range fn(entries) {
+		if !fsutil.IsFile(filepath.Join(d.fileDir, ent.Name)) {
+			continue
+		}
+		if err = enc.Encode(ent); err != nil {
+			return err
+		}
+	}
+
+	tmpFile := mfFile + ".tmp"
+	if err = fsutil.MkParentDir(tmpFile); err != nil {
+		return err
+	}
+	if err = os.WriteFile(tmpFile, buf.Bytes(), cfg.FilePerm); err != nil {
+		return err
+	}
+	return os.Rename(tmpFile, mfFile)
+}
@@ -6,6 +6,7 @@ import (
 	"io"
 	"io/fs"
 	"os"
+	"sync"
 	"time"
 
 	"github.com/gookit/goutil"
@@ -86,8 +87,9 @@ func (fis modTimeFInfos) Len() int {
 	return len(fis)
 }
 
-// MockClocker mock clock for test
+// MockClocker mock clock for test. it is safe for concurrent use.
 type MockClocker struct {
+	mu sync.RWMutex
 	tt time.Time
 }
 
@@ -99,15 +101,19 @@ func NewMockClock(datetime string) *MockClocker {
 
 // Now get current time.
 func (mt *MockClocker) Now() time.Time {
+	mt.mu.RLock()
+	defer mt.mu.RUnlock()
 	return mt.tt
 }
 
 // Add progresses time by the given duration.
 func (mt *MockClocker) Add(d time.Duration) {
+	mt.mu.Lock()
 	mt.tt = mt.tt.Add(d)
+	mt.mu.Unlock()
 }
 
 // Datetime returns the current time in the format "2006-01-02 15:04:05".
 func (mt *MockClocker) Datetime() string {
-	return mt.tt.Format("2006-01-02 15:04:05")
+	return mt.Now().Format("2006-01-02 15:04:05")
 }
@@ -3,10 +3,10 @@ package rotatefile
 import (
 	"fmt"
 	"io/fs"
-	"math/rand"
 	"os"
 	"path"
 	"path/filepath"
+	"runtime"
 	"sort"
 	"strings"
 	"sync"
@@ -28,12 +28,16 @@ type Writer struct {
 	path string
 	// logfile dir path for the Config.Filepath
 	fileDir string
+	// the lock file on Config.MultiProcess=true
+	lockFile *os.File
+	// the open time of current logfile
+	openedAt   time.Time
+	manifestMu sync.Mutex
+	// serialize the cleans
+	cleanMu sync.Mutex
 
-	// logfile max backup time. equals Config.BackupTime * time.Hour
-	backupDur time.Duration
-	// oldFiles []string
-	cleanCh chan struct{}
-	stopCh  chan struct{}
+	// the background worker for close rotated files, compress and clean backups.
+	worker bgWorker
 
 	// context use for rotating file by size
 	written   uint64 // written size
@@ -43,6 +47,12 @@ type Writer struct {
 	suffixFormat   string    // the rotating file name suffix. eg: "20210102", "20210102_1500"
 	checkInterval  int64     // check interval seconds.
 	nextRotatingAt time.Time // next rotating time
+	// the start time of current period, on the RotateTime is calendar aligned.
+	periodStart time.Time
+	// the clock time on calc nextRotatingAt, and the wait duration from it.
+	// use Time.Sub() for check elapsed, it will use the monotonic clock if both has it.
+	scheduledAt time.Time
+	rotateWait  time.Duration
 }
 
 // NewWriter create rotate write with config and init it.
@@ -64,7 +74,6 @@ func NewWriterWith(fns ...ConfigFn) (*Writer, error) {
 func (d *Writer) init() error {
 	logfile := d.cfg.Filepath
 	d.fileDir = filepath.Dir(logfile)
-	d.backupDur = d.cfg.backupDuration()
 
 	// if d.cfg.BackupNum > 0 {
 	// 	d.oldFiles = make([]string, 0, int(float32(d.cfg.BackupNum)*1.6))
@@ -78,9 +87,23 @@ func (d *Writer) init() error {
 		now := d.cfg.TimeClock.Now()
 		// next rotating time
 		d.nextRotatingAt = d.cfg.RotateTime.FirstCheckTime(now)
+		d.schedule(now)
+		if d.cfg.RotateTime.IsCalendar() {
+			d.periodStart = d.cfg.RotateTime.PeriodStart(now)
+		}
 		if d.cfg.RotateMode == ModeCreate {
-			logfile = d.cfg.Filepath + "." + now.Format(d.suffixFormat)
+			logfile = d.cfg.Filepath + "." + d.fileSuffix(now)
+		}
+	}
+
+	// open the lock file, the name is not match the backup files. eg: "logs/.error.log.lock"
+	if d.cfg.MultiProcess {
+		lockPath := filepath.Join(d.fileDir, "."+filepath.Base(d.cfg.Filepath)+".lock")
+		lf, err := fsutil.OpenFile(lockPath, os.O_CREATE|os.O_RDWR, d.cfg.FilePerm)
+		if err != nil {
+			return err
 		}
+		d.lockFile = lf
 	}
 
 	// open the logfile
@@ -103,9 +126,16 @@ func (d *Writer) Sync() error {
 }
 
 // Close the writer. will sync data to disk, then close the file handle.
-// and will stop the async clean backups.
+// and will stop the background worker, wait for the queued jobs are finished.
 func (d *Writer) Close() error {
-	return d.close(true)
+	err := d.close(true)
+	if d.lockFile != nil {
+		if err1 := d.lockFile.Close(); err == nil {
+			err = err1
+		}
+		d.lockFile = nil
+	}
+	return err
 }
 
 // MustClose the writer. alias of Close(), but will panic if has error.
@@ -118,13 +148,13 @@ func (d *Writer) close(closeStopCh bool) error {
 		return err
 	}
 
-	// stop the async clean backups
-	if closeStopCh && d.stopCh != nil {
-		d.cfg.Debug("close stopCh for stop async clean old files")
-		close(d.stopCh)
-		d.stopCh = nil
+	err := d.file.Close()
+
+	// stop the background worker
+	if closeStopCh {
+		d.stopWorker()
 	}
-	return d.file.Close()
+	return err
 }
 
 //
@@ -146,6 +176,14 @@ func (d *Writer) Write(p []byte) (n int, err error) {
 		defer d.mu.Unlock()
 	}
 
+	if d.lockFile != nil {
+		return d.writeShared(p)
+	}
+
+	if err = d.rotateOnBoundary(); err != nil {
+		return
+	}
+
 	n, err = d.file.Write(p)
 	if err != nil {
 		return
@@ -157,10 +195,38 @@ func (d *Writer) Write(p []byte) (n int, err error) {
 	return
 }
 
+// Reopen the current logfile, use for the file is moved by external tools. eg: logrotate
+func (d *Writer) Reopen() error {
+	if !d.cfg.CloseLock {
+		d.mu.Lock()
+		defer d.mu.Unlock()
+	}
+
+	if err := d.close(false); err != nil {
+		return err
+	}
+	if err := d.openFile(d.path); err != nil {
+		return err
+	}
+
+	// the size of the reopened file
+	d.written = 0
+	if fi, err := d.file.Stat(); err == nil {
+		d.written = uint64(fi.Size())
+	}
+	return nil
+}
+
 // Rotate the file by config and async clean backups
-func (d *Writer) Rotate() error { return d.doRotate() }
+func (d *Writer) Rotate() error {
+	if !d.cfg.CloseLock {
+		d.mu.Lock()
+		defer d.mu.Unlock()
+	}
+	return d.doRotate()
+}
 
-// do rotate the logfile by config and async clean backups
+// do rotate the logfile by config. the backups are cleaned in background after rotated.
 func (d *Writer) doRotate() (err error) {
 	// do rotate file by size
 	if d.cfg.MaxSize > 0 && d.written >= d.cfg.MaxSize {
@@ -174,11 +240,6 @@ func (d *Writer) doRotate() (err error) {
 	if d.checkInterval > 0 && d.written > 0 {
 		err = d.rotatingByTime()
 	}
-
-	// async clean backup files.
-	if d.shouldClean(true) {
-		d.asyncClean()
-	}
 	return
 }
 
@@ -189,46 +250,123 @@ func (d *Writer) rotatingByTime() error {
 		return nil
 	}
 
+	// the wall clock steps forward a little(eg: NTP adjust), wait the real elapsed time, at most clockStepTolerance.
+	// on a larger jump, follow the wall clock. eg: resume from suspend, the monotonic clock is not advanced.
+	if wait := d.rotateWait - now.Sub(d.scheduledAt); wait > 0 && wait <= clockStepTolerance {
+		return nil
+	}
+
 	// generate new file path.
 	// eg: /tmp/error.log => /tmp/error.log.20220423_1600
-	file := d.cfg.Filepath + "." + d.nextRotatingAt.Format(d.suffixFormat)
-	err := d.rotatingFile(file, false)
+	file := d.cfg.Filepath + "." + d.fileSuffix(d.nextRotatingAt)
+
+	var err error
+	// has been rotated by other process, and the logfile is reopened on write.
+	if d.lockFile != nil && d.cfg.RotateMode == ModeRename && fsutil.IsFile(file) {
+		d.cfg.Debug("skip rotate, the file has been rotated by other process:", file)
+	} else {
+		err = d.rotatingFile(file, false)
+	}
 
 	// calc and storage next rotating time
-	d.nextRotatingAt = d.nextRotatingAt.Add(time.Duration(d.checkInterval) * time.Second)
+	if d.cfg.RotateTime.IsCalendar() {
+		// align to the period of now, the periods without writes are skipped.
+		d.periodStart = d.cfg.RotateTime.PeriodStart(now)
+		d.nextRotatingAt = d.cfg.RotateTime.NextPeriodStart(now)
+
+		// ModeCreate: switch to the file of new period. eg: error.log.20220424
+		if err == nil && d.cfg.RotateMode == ModeCreate {
+			old, oldPath, openedAt := d.file, d.path, d.openedAt
+			if err = d.openFile(d.cfg.Filepath + "." + d.fileSuffix(now)); err == nil {
+				d.closeAsync(rotatedFile{file: old, path: oldPath, start: openedAt, end: now, cfg: d.cfgSnapshot()})
+			}
+		}
+	} else {
+		d.nextRotatingAt = d.nextRotatingAt.Add(time.Duration(d.checkInterval) * time.Second)
+	}
+	d.schedule(now)
 	return err
 }
 
+// rotate before write on the calendar boundary is reached,
+// so the data is always written to the file of its period.
+func (d *Writer) rotateOnBoundary() error {
+	if d.periodStart.IsZero() || d.written == 0 {
+		return nil
+	}
+	return d.rotatingByTime()
+}
+
+// get the file name suffix for rotating by time.
+// the calendar aligned rotate time use the start of current period. eg: "20220423"
+func (d *Writer) fileSuffix(t time.Time) string {
+	if !d.periodStart.IsZero() {
+		return d.periodStart.Format(d.suffixFormat)
+	}
+	return t.Format(d.suffixFormat)
+}
+
+// the max wall clock step forward for wait the real elapsed time on rotating by time.
+const clockStepTolerance = time.Minute
+
+// storage the schedule time and the wait duration for next rotating.
+func (d *Writer) schedule(now time.Time) {
+	d.scheduledAt = now
+	d.rotateWait = d.nextRotatingAt.Sub(now.Round(0))
+}
+
 func (d *Writer) rotatingBySize() error {
+	bakFile := d.nextBakFile()
+
+	// don't overwrite the backup file created by other process
+	if d.lockFile != nil {
+		for i := 0; i < maxBakFileTries && fsutil.IsFile(bakFile); i++ {
+			bakFile = d.nextBakFile()
+		}
+	}
+
+	// always rename current to new file
+	return d.rotatingFile(bakFile, true)
+}
+
+// max tries for find a not exists backup file name on Config.MultiProcess=true
+const maxBakFileTries = 100
+
+func (d *Writer) nextBakFile() string {
 	d.rotateNum++
 
-	var bakFile string
 	if d.cfg.IsMode(ModeCreate) {
 		// eg: /tmp/error.log.20220423_1600 => /tmp/error.log.20220423_1600_001
-		bakFile = fmt.Sprintf("%s_%03d", d.path, d.rotateNum)
-	} else {
-		// rename current to new file
-		// eg: /tmp/error.log => /tmp/error.log.163021_001
-		bakFile = d.cfg.RenameFunc(d.cfg.Filepath, d.rotateNum)
+		return fmt.Sprintf("%s_%03d", d.path, d.rotateNum)
 	}
 
-	// always rename current to new file
-	return d.rotatingFile(bakFile, true)
+	// rename current to new file
+	// eg: /tmp/error.log => /tmp/error.log.163021_001
+	return d.cfg.RenameFunc(d.cfg.Filepath, d.rotateNum)
 }
 
-// rotateFile closes the syncBuffer's file and starts a new one.
+// rotatingFile rename the current file to bakFile, then open a new file.
+//
+// Only rename and open are in the write path, the rotated file is synced and closed
+// in background, and the backups are cleaned after it.
 func (d *Writer) rotatingFile(bakFile string, rename bool) error {
-	// close the current file
-	if err := d.close(false); err != nil {
-		return err
-	}
+	rename = rename || d.cfg.RotateMode == ModeRename
 
-	// record old files for clean.
-	// d.oldFiles = append(d.oldFiles, bakFile)
+	old, openedAt := d.file, d.openedAt
+	// cannot rename the opened file on windows, close it first.
+	if rename && runtime.GOOS == "windows" {
+		if err := old.Close(); err != nil {
+			return err
+		}
+		old = nil
+	}
 
 	// rename current to new file.
-	if rename || d.cfg.RotateMode == ModeRename {
+	if rename {
 		if err := os.Rename(d.path, bakFile); err != nil {
+			if old == nil {
+				printErrln("rotatefile: reopen file error:", d.openFile(d.path))
+			}
 			return err
 		}
 	}
@@ -239,13 +377,22 @@ func (d *Writer) rotatingFile(bakFile string, rename bool) error {
 		logfile = d.cfg.Filepath
 	}
 
-	// reopen log file
+	// open new log file. on error, continue write to the old file if it is not closed.
 	if err := d.openFile(logfile); err != nil {
 		return err
 	}
 
+	rf := rotatedFile{file: old, start: openedAt, end: d.openedAt, cfg: d.cfgSnapshot()}
+	if rename {
+		rf.path = bakFile
+	}
+	if rf.file != nil || rf.path != "" {
+		d.closeAsync(rf)
+	}
+
 	// reset written
 	d.written = 0
+	d.cleanAsync()
 	return nil
 }
 
@@ -255,67 +402,31 @@ func (d *Writer) rotatingFile(bakFile string, rename bool) error {
 // ---------------------------------------------------------------------------
 //
 
-// check should clean old files by config
-func (d *Writer) shouldClean(withRand bool) bool {
-	cfgIsYes := d.cfg.BackupNum > 0 || d.cfg.BackupTime > 0
-	if !withRand {
-		return cfgIsYes
-	}
-
-	// 20% probability trigger clean
-	return cfgIsYes && rand.Intn(100) < 20
+// Clean old files by config.
+//
+// The cleans are serialized, the background clean after rotating will wait for it.
+func (d *Writer) Clean() error {
+	return d.clean(d.cfgSnapshot())
 }
 
-// async clean old files by config. should be in lock.
-func (d *Writer) asyncClean() {
-	if !d.shouldClean(false) {
-		return
-	}
-
-	// if already running, send a signal
-	if d.cleanCh != nil {
-		select {
-		case d.cleanCh <- struct{}{}:
-			d.cfg.Debug("signal sent start clean old files ")
-		default: // skip on blocking
-			d.cfg.Debug("clean old files signal blocked, skip")
-		}
-		return
-	}
-
-	// init clean channel
-	d.cfg.Debug("init clean/stop channel for clean old files")
-	d.cleanCh = make(chan struct{})
-	d.stopCh = make(chan struct{})
-
-	// start a goroutine to clean backups
-	go func() {
-		d.cfg.Debug("START a goroutine consumer for clean old files")
-
-		// consume the signal until stop
-		for {
-			select {
-			case <-d.cleanCh:
-				d.cfg.Debug("receive signal - clean old files handling")
-				printErrln("rotatefile: clean old files error:", d.Clean())
-			case <-d.stopCh:
-				d.cleanCh = nil
-				d.cfg.Debug("STOP consumer for clean old files")
-				return // stop clean
-			}
-		}
-	}()
+// copy the config for the background jobs. should be called on the write path.
+func (d *Writer) cfgSnapshot() *Config {
+	cfg := *d.cfg
+	return &cfg
 }
 
-// Clean old files by config
-func (d *Writer) Clean() (err error) {
-	if d.cfg.BackupNum == 0 && d.cfg.BackupTime == 0 {
-		return errorx.Err("clean: backupNum and backupTime are both 0")
+// clean old files by the config snapshot. don't read the d.cfg, it may be changed on cleaning.
+func (d *Writer) clean(cfg *Config) (err error) {
+	if !cfg.shouldClean() {
+		return errorx.Err("clean: backupNum, backupTime and maxTotalSize are all 0")
 	}
 
+	d.cleanMu.Lock()
+	defer d.cleanMu.Unlock()
+
 	// oldFiles: xx.log.yy files, no gz file
 	var oldFiles, gzFiles []fileInfo
-	fileDir, fileName := filepath.Split(d.cfg.Filepath)
+	fileDir, fileName := filepath.Split(cfg.Filepath)
 	if len(fileDir) > 0 {
 		// removes the trailing separator
 		fileDir = fileDir[:len(fileDir)-1]
@@ -334,18 +445,19 @@ func (d *Writer) Clean() (err error) {
 			oldFiles = append(oldFiles, newFileInfo(fPath, fi))
 		}
 		return nil
-	}, d.buildFilterFns(fileName)...)
+	}, d.buildFilterFns(cfg, fileName)...)
 
 	gzNum := len(gzFiles)
 	oldNum := len(oldFiles)
-	remNum := gzNum + oldNum - int(d.cfg.BackupNum)
-	d.cfg.Debug("clean old files, gzNum:", gzNum, "oldNum:", oldNum, "remNum:", remNum)
+	remNum := gzNum + oldNum - int(cfg.BackupNum)
+	cfg.Debug("clean old files, gzNum:", gzNum, "oldNum:", oldNum, "remNum:", remNum)
 
-	if remNum > 0 {
+	// 0 is not limit the backup number
+	if cfg.BackupNum > 0 && remNum > 0 {
 		// remove old gz files
 		if gzNum > 0 {
 			sort.Sort(modTimeFInfos(gzFiles)) // sort by mod-time
-			d.cfg.Debug("remove old gz files ...")
+			cfg.Debug("remove old gz files ...")
 
 			for idx := 0; idx < gzNum; idx++ {
 				if err = os.Remove(gzFiles[idx].filePath); err != nil {
@@ -367,7 +479,7 @@ func (d *Writer) Clean() (err error) {
 		if remNum > 0 && oldNum > 0 {
 			// sort by mod-time, oldest at first.
 			sort.Sort(modTimeFInfos(oldFiles))
-			d.cfg.Debug("remove old normal files ...")
+			cfg.Debug("remove old normal files ...")
 
 			var idx int
 			for idx = 0; idx < oldNum; idx++ {
@@ -388,13 +500,66 @@ func (d *Writer) Clean() (err error) {
 		}
 	}
 
-	if d.cfg.Compress && len(oldFiles) > 0 {
-		d.cfg.Debug("compress old normal files to gz files")
-		err = d.compressFiles(oldFiles)
+	if cfg.Compress && len(oldFiles) > 0 {
+		cfg.Debug("compress old normal files to gz files")
+		err = d.compressFiles(cfg, oldFiles)
+	}
+
+	// remove the oldest files on the total size exceeds the quota
+	if err == nil && cfg.MaxTotalSize > 0 {
+		err = d.cleanByTotalSize(cfg, fileDir, fileName)
+	}
+
+	// remove the entries of deleted files
+	if cfg.ManifestFile != "" {
+		printErrln("rotatefile: update manifest error:", d.updateManifest(cfg, func(es []ManifestEntry) []ManifestEntry {
+			return es
+		}))
 	}
 	return
 }
 
+// remove the oldest backup files until the total size is not exceeds Config.MaxTotalSize
+func (d *Writer) cleanByTotalSize(cfg *Config, fileDir, fileName string) error {
+	var files []fileInfo
+	err := fsutil.FindInDir(fileDir, func(fPath string, ent fs.DirEntry) error {
+		fi, err := ent.Info()
+		if err != nil {
+			return err
+		}
+		files = append(files, newFileInfo(fPath, fi))
+		return nil
+	}, d.buildFilterFns(cfg, fileName)...)
+	if err != nil {
+		return err
+	}
+
+	// sort by mod-time, oldest at first.
+	sort.Sort(modTimeFInfos(files))
+	// the newest is the current logfile on ModeCreate, keep it.
+	if cfg.IsMode(ModeCreate) && len(files) > 0 {
+		files = files[:len(files)-1]
+	}
+
+	var total uint64
+	for _, fi := range files {
+		total += uint64(fi.Size())
+	}
+
+	for _, fi := range files {
+		if total <= cfg.MaxTotalSize {
+			break
+		}
+
+		cfg.Debug("remove old file on the total size exceeds quota:", fi.filePath)
+		if err = os.Remove(fi.filePath); err != nil {
+			return errorx.Wrap(err, "remove old file error")
+		}
+		total -= uint64(fi.Size())
+	}
+	return nil
+}
+
 //
 // ---------------------------------------------------------------------------
 // helper methods
@@ -410,10 +575,13 @@ func (d *Writer) openFile(logfile string) error {
 
 	d.path = logfile
 	d.file = file
+	if d.cfg.ManifestFile != "" {
+		d.openedAt = d.cfg.TimeClock.Now()
+	}
 	return nil
 }
 
-func (d *Writer) buildFilterFns(fileName string) []fsutil.FilterFunc {
+func (d *Writer) buildFilterFns(cfg *Config, fileName string) []fsutil.FilterFunc {
 	filterFns := []fsutil.FilterFunc{
 		fsutil.OnlyFindFile,
 		// filter by name. match pattern like: error.log.*
@@ -424,9 +592,17 @@ func (d *Writer) buildFilterFns(fileName string) []fsutil.FilterFunc {
 		},
 	}
 
+	// exclude the manifest file
+	if cfg.ManifestFile != "" {
+		mfName := filepath.Base(cfg.ManifestFile)
+		filterFns = append(filterFns, func(fPath string, ent fs.DirEntry) bool {
+			return ent.Name() != mfName && ent.Name() != mfName+".tmp"
+		})
+	}
+
 	// filter by mod-time, clear expired files
-	if d.cfg.BackupTime > 0 {
-		cutTime := d.cfg.TimeClock.Now().Add(-d.backupDur)
+	if cfg.BackupTime > 0 {
+		cutTime := cfg.TimeClock.Now().Add(-cfg.backupDuration())
 		filterFns = append(filterFns, func(fPath string, ent fs.DirEntry) bool {
 			fi, err := ent.Info()
 			if err != nil {
@@ -439,7 +615,7 @@ func (d *Writer) buildFilterFns(fileName string) []fsutil.FilterFunc {
 			}
 
 			// remove expired files
-			d.cfg.Debug("remove expired file:", fPath)
+			cfg.Debug("remove expired file:", fPath)
 			printErrln("rotatefile: remove expired file error:", os.Remove(fPath))
 			return false
 		})
@@ -448,7 +624,7 @@ func (d *Writer) buildFilterFns(fileName string) []fsutil.FilterFunc {
 	return filterFns
 }
 
-func (d *Writer) compressFiles(oldFiles []fileInfo) error {
+func (d *Writer) compressFiles(cfg *Config, oldFiles []fileInfo) error {
 	for _, fi := range oldFiles {
 		err := compressFile(fi.filePath, fi.filePath+compressSuffix)
 		if err != nil {
@@ -459,6 +635,10 @@ func (d *Writer) compressFiles(oldFiles []fileInfo) error {
 		if err = os.Remove(fi.filePath); err != nil {
 			return errorx.Wrap(err, "remove file error after compress")
 		}
+
+		if cfg.ManifestFile != "" {
+			printErrln("rotatefile: update manifest error:", d.renameManifest(cfg, fi.filePath, fi.filePath+compressSuffix))
+		}
 	}
 	return nil
 }
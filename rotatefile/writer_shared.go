@@ -0,0 +1,54 @@
+package rotatefile
+
+import (
+	"os"
+)
+
+// write data on Config.MultiProcess=true, hold the file lock on write and rotating.
+func (d *Writer) writeShared(p []byte) (n int, err error) {
+	if err = lockFile(d.lockFile); err != nil {
+		return
+	}
+	defer func() {
+		printErrln("rotatefile: unlock file error:", unlockFile(d.lockFile))
+	}()
+
+	if err = d.reopenIfRotated(); err != nil {
+		return
+	}
+	if err = d.rotateOnBoundary(); err != nil {
+		return
+	}
+
+	// the whole data is written by one write call on O_APPEND mode.
+	n, err = d.file.Write(p)
+	if err != nil {
+		return
+	}
+
+	// use the real file size, other processes write the file too.
+	if fi, err1 := d.file.Stat(); err1 == nil {
+		d.written = uint64(fi.Size())
+	} else {
+		d.written += uint64(n)
+	}
+	err = d.doRotate()
+	return
+}
+
+// reopen the logfile on it has been rotated(renamed or removed) by other process.
+func (d *Writer) reopenIfRotated() error {
+	fi, err := os.Stat(d.path)
+	if err == nil {
+		cur, err := d.file.Stat()
+		if err != nil || os.SameFile(fi, cur) {
+			return err
+		}
+	} else if !os.IsNotExist(err) {
+		return err
+	}
+
+	d.cfg.Debug("reopen the logfile, it has been rotated by other process:", d.path)
+	_ = d.file.Close()
+	return d.openFile(d.path)
+}
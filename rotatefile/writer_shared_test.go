@@ -0,0 +1,76 @@
+//go:build !windows && !plan9
+
+package rotatefile_test
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/gookit/goutil/testutil/assert"
+	"github.com/gookit/slog/rotatefile"
+)
+
+func TestWriter_MultiProcess(t *testing.T) {
+	logfile := filepath.Join(t.TempDir(), "multi.log")
+	newWriter := func() *rotatefile.Writer {
+		w, err := rotatefile.EmptyConfigWith(func(c *rotatefile.Config) {
+			c.Filepath = logfile
+			c.MaxSize = 2048
+			c.MultiProcess = true
+		}).Create()
+		assert.NoErr(t, err)
+		return w
+	}
+
+	// the writers has separate file handles, like in multiple processes
+	ws := []*rotatefile.Writer{newWriter(), newWriter()}
+	lineLen := len(fmt.Sprintf("writer-%d worker-%d line-%03d %s\n", 0, 0, 0, strings.Repeat("x", 30)))
+
+	var wg sync.WaitGroup
+	for wi, w := range ws {
+		for i := 0; i < 4; i++ {
+			wg.Add(1)
+			go func(wi, i int, w *rotatefile.Writer) {
+				defer wg.Done()
+				for j := 0; j < 100; j++ {
+					line := fmt.Sprintf("writer-%d worker-%d line-%03d %s\n", wi, i, j, strings.Repeat("x", 30))
+					_, err := w.WriteString(line)
+					assert.NoErr(t, err)
+				}
+			}(wi, i, w)
+		}
+	}
+	wg.Wait()
+	for _, w := range ws {
+		assert.NoErr(t, w.Close())
+	}
+
+	files, err := filepath.Glob(logfile + "*")
+	assert.NoErr(t, err)
+	assert.Gt(t, len(files), 2)
+
+	var lines int
+	for _, fpath := range files {
+		fi, err := os.Stat(fpath)
+		assert.NoErr(t, err)
+		// rotated once reach the max size, not double-rotated
+		assert.Lt(t, fi.Size(), int64(2048+lineLen))
+
+		f, err := os.Open(fpath)
+		assert.NoErr(t, err)
+		s := bufio.NewScanner(f)
+		for s.Scan() {
+			lines++
+			// no interleaved partial lines
+			assert.Len(t, s.Text(), lineLen-1)
+			assert.StrContains(t, s.Text(), "writer-")
+		}
+		assert.NoErr(t, f.Close())
+	}
+	assert.Eq(t, 2*4*100, lines)
+}
@@ -1,7 +1,10 @@
 package rotatefile_test
 
 import (
+	"fmt"
+	"os"
 	"path/filepath"
+	"sync"
 	"testing"
 	"time"
 
@@ -9,6 +12,7 @@ import (
 	"github.com/gookit/goutil/fsutil"
 	"github.com/gookit/goutil/mathutil"
 	"github.com/gookit/goutil/testutil/assert"
+	"github.com/gookit/goutil/timex"
 	"github.com/gookit/slog/rotatefile"
 )
 
@@ -141,3 +145,291 @@ func (c constantClock) Now() time.Time { return time.Time(c) }
 func (c constantClock) NewTicker(d time.Duration) *time.Ticker {
 	return &time.Ticker{}
 }
+
+func TestWriter_Reopen(t *testing.T) {
+	logfile := filepath.Join(t.TempDir(), "reopen.log")
+	w, err := rotatefile.NewConfig(logfile).Create()
+	assert.NoErr(t, err)
+
+	_, err = w.WriteString("before rotate\n")
+	assert.NoErr(t, err)
+	assert.NoErr(t, os.Rename(logfile, logfile+".1"))
+	assert.NoErr(t, w.Reopen())
+
+	_, err = w.WriteString("after rotate\n")
+	assert.NoErr(t, err)
+	assert.NoErr(t, w.Close())
+
+	assert.Eq(t, "before rotate\n", string(fsutil.MustReadFile(logfile+".1")))
+	assert.Eq(t, "after rotate\n", string(fsutil.MustReadFile(logfile)))
+}
+
+func TestWriter_rotateByCalendar(t *testing.T) {
+	logfile := filepath.Join(t.TempDir(), "calendar.log")
+
+	mt := rotatefile.NewMockClock("2023-11-16 23:59:58")
+	w, err := rotatefile.NewWriterWith(func(c *rotatefile.Config) {
+		c.TimeClock = mt
+		c.Filepath = logfile
+		c.RotateTime = rotatefile.CalendarDay
+	})
+	assert.NoErr(t, err)
+
+	_, err = w.WriteString("day 16\n")
+	assert.NoErr(t, err)
+
+	// the first write after midnight is written to the new file
+	mt.Add(3 * time.Second)
+	_, err = w.WriteString("day 17\n")
+	assert.NoErr(t, err)
+	assert.Eq(t, "day 16\n", fsutil.ReadString(logfile+".20231116"))
+
+	// the days without writes are skipped
+	mt.Add(3 * timex.OneDay)
+	_, err = w.WriteString("day 20\n")
+	assert.NoErr(t, err)
+	assert.NoErr(t, w.Close())
+
+	assert.Eq(t, "day 17\n", fsutil.ReadString(logfile+".20231117"))
+	assert.Eq(t, "day 20\n", fsutil.ReadString(logfile))
+	assert.Len(t, fsutil.Glob(logfile+".*"), 2)
+}
+
+func TestWriter_rotateByCalendar_modeCreate(t *testing.T) {
+	logfile := filepath.Join(t.TempDir(), "calendar.log")
+
+	mt := rotatefile.NewMockClock("2023-11-30 23:59:58")
+	w, err := rotatefile.NewWriterWith(func(c *rotatefile.Config) {
+		c.TimeClock = mt
+		c.Filepath = logfile
+		c.RotateMode = rotatefile.ModeCreate
+		c.RotateTime = rotatefile.CalendarMonth
+	})
+	assert.NoErr(t, err)
+
+	_, err = w.WriteString("month 11\n")
+	assert.NoErr(t, err)
+	mt.Add(3 * time.Second)
+	_, err = w.WriteString("month 12\n")
+	assert.NoErr(t, err)
+	assert.NoErr(t, w.Close())
+
+	assert.Eq(t, "month 11\n", fsutil.ReadString(logfile+".202311"))
+	assert.Eq(t, "month 12\n", fsutil.ReadString(logfile+".202312"))
+}
+
+func TestWriter_rotateConcurrent(t *testing.T) {
+	logfile := filepath.Join(t.TempDir(), "concurrent.log")
+	w, err := rotatefile.NewWriterWith(func(c *rotatefile.Config) {
+		c.Filepath = logfile
+		c.MaxSize = 512
+		c.BackupNum = 2
+		c.Compress = true
+		c.RotateTime = 0
+		c.RenameFunc = func(fPath string, rotateNum uint) string {
+			return fPath + fmt.Sprintf(".%03d", rotateNum)
+		}
+	})
+	assert.NoErr(t, err)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				_, err := w.WriteString(fmt.Sprintf("[INFO] goroutine %d, idx=%d\n", i, j))
+				assert.NoErr(t, err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	// wait for the background jobs are finished
+	assert.NoErr(t, w.Close())
+	files := fsutil.Glob(logfile + ".*")
+	assert.NotEmpty(t, files)
+	assert.Lte(t, len(files), 2)
+	for _, fPath := range files {
+		assert.StrContains(t, fPath, ".gz")
+	}
+}
+
+func TestWriter_manifest(t *testing.T) {
+	dir := t.TempDir()
+	logfile := filepath.Join(dir, "app.log")
+	mfFile := filepath.Join(dir, "app.manifest.jsonl")
+
+	mt := rotatefile.NewMockClock("2023-11-16 10:00:00")
+	w, err := rotatefile.NewWriterWith(func(c *rotatefile.Config) {
+		c.Filepath = logfile
+		c.TimeClock = mt
+		c.RotateTime = rotatefile.CalendarDay
+		c.ManifestFile = mfFile
+		c.BackupNum = 2
+	})
+	assert.NoErr(t, err)
+
+	for i := 0; i < 3; i++ {
+		_, err = w.WriteString(mt.Datetime() + " log message\n")
+		assert.NoErr(t, err)
+		mt.Add(timex.OneDay)
+	}
+	_, err = w.WriteString("current file\n")
+	assert.NoErr(t, err)
+	assert.NoErr(t, w.Close())
+
+	entries, err := rotatefile.ReadManifest(mfFile)
+	assert.NoErr(t, err)
+	assert.Len(t, entries, 2)
+	assert.Eq(t, "app.log.20231117", entries[0].Name)
+	assert.Eq(t, "app.log.20231118", entries[1].Name)
+
+	ent := entries[1]
+	assert.Eq(t, int64(len("2023-11-18 10:00:00 log message\n")), ent.Size)
+	assert.Len(t, ent.SHA256, 64)
+	assert.Eq(t, "2023-11-18 10:00:00", ent.Start.Format("2006-01-02 15:04:05"))
+	assert.Eq(t, "2023-11-19 10:00:00", ent.End.Format("2006-01-02 15:04:05"))
+	assert.NoErr(t, rotatefile.VerifyManifest(mfFile, ""))
+
+	// the file is changed
+	assert.NoErr(t, os.WriteFile(filepath.Join(dir, ent.Name), []byte("changed\n"), 0644))
+	assert.ErrSubMsg(t, rotatefile.VerifyManifest(mfFile, ""), "not matched")
+
+	// not exists manifest
+	entries, err = rotatefile.ReadManifest(filepath.Join(dir, "not-exists.jsonl"))
+	assert.NoErr(t, err)
+	assert.Empty(t, entries)
+}
+
+func TestWriter_manifest_compress(t *testing.T) {
+	dir := t.TempDir()
+	logfile := filepath.Join(dir, "app.log")
+	mfFile := filepath.Join(dir, "app.manifest.jsonl")
+
+	w, err := rotatefile.NewWriterWith(func(c *rotatefile.Config) {
+		c.Filepath = logfile
+		c.MaxSize = 64
+		c.RotateTime = 0
+		c.Compress = true
+		c.ManifestFile = mfFile
+		c.RenameFunc = func(fPath string, rotateNum uint) string {
+			return fPath + fmt.Sprintf(".%03d", rotateNum)
+		}
+	})
+	assert.NoErr(t, err)
+
+	for i := 0; i < 5; i++ {
+		_, err = w.WriteString(fmt.Sprintf("[INFO] this is a log message, idx=%d\n", i))
+		assert.NoErr(t, err)
+	}
+	assert.NoErr(t, w.Close())
+
+	entries, err := rotatefile.ReadManifest(mfFile)
+	assert.NoErr(t, err)
+	assert.NotEmpty(t, entries)
+	for _, ent := range entries {
+		assert.StrContains(t, ent.Name, ".gz")
+	}
+	assert.NoErr(t, rotatefile.VerifyManifest(mfFile, dir))
+}
+
+func TestWriter_MaxTotalSize(t *testing.T) {
+	logfile := filepath.Join(t.TempDir(), "quota.log")
+	w, err := rotatefile.NewWriterWith(func(c *rotatefile.Config) {
+		c.Filepath = logfile
+		c.MaxSize = 64
+		c.RotateTime = 0
+		c.BackupNum = 0
+		c.BackupTime = 0
+		c.MaxTotalSize = 200
+		c.RenameFunc = func(fPath string, rotateNum uint) string {
+			return fPath + fmt.Sprintf(".%03d", rotateNum)
+		}
+	})
+	assert.NoErr(t, err)
+
+	for i := 0; i < 20; i++ {
+		_, err = w.WriteString(fmt.Sprintf("[INFO] this is a log message, idx=%02d\n", i))
+		assert.NoErr(t, err)
+		// make sure the mod-time is different
+		time.Sleep(2 * time.Millisecond)
+	}
+	assert.NoErr(t, w.Close())
+
+	files := fsutil.Glob(logfile + ".*")
+	assert.NotEmpty(t, files)
+
+	var total int64
+	for _, fPath := range files {
+		fi, err := os.Stat(fPath)
+		assert.NoErr(t, err)
+		total += fi.Size()
+	}
+	assert.Lte(t, total, int64(200))
+
+	// the newest backups are kept
+	assert.StrContains(t, fsutil.ReadString(files[len(files)-1]), "idx=19")
+}
+
+func TestWriter_Clean_backupTimeOnly(t *testing.T) {
+	logfile := filepath.Join(t.TempDir(), "age.log")
+	w, err := rotatefile.NewWriterWith(func(c *rotatefile.Config) {
+		c.Filepath = logfile
+		c.RotateTime = 0
+		c.BackupNum = 0
+		c.BackupTime = 24
+	})
+	assert.NoErr(t, err)
+
+	for _, suffix := range []string{".1", ".2", ".3"} {
+		assert.NoErr(t, os.WriteFile(logfile+suffix, []byte("backup\n"), 0644))
+	}
+	expired := time.Now().Add(-48 * time.Hour)
+	assert.NoErr(t, os.Chtimes(logfile+".1", expired, expired))
+
+	// BackupNum=0 is not limit the number, only remove the expired backups
+	assert.NoErr(t, w.Clean())
+	assert.Eq(t, []string{logfile + ".2", logfile + ".3"}, fsutil.Glob(logfile+".*"))
+	assert.NoErr(t, w.Close())
+}
+
+func TestWriter_Clean_concurrent(t *testing.T) {
+	logfile := filepath.Join(t.TempDir(), "clean-concurrent.log")
+	w, err := rotatefile.NewWriterWith(func(c *rotatefile.Config) {
+		c.Filepath = logfile
+		c.MaxSize = 64
+		c.RotateTime = 0
+		c.BackupNum = 2
+		c.Compress = true
+		c.RenameFunc = func(fPath string, rotateNum uint) string {
+			return fPath + fmt.Sprintf(".%03d", rotateNum)
+		}
+	})
+	assert.NoErr(t, err)
+
+	// the user called Clean() run with the background clean after rotating
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 50; i++ {
+			_, err := w.WriteString(fmt.Sprintf("[INFO] this is a log message, idx=%02d\n", i))
+			assert.NoErr(t, err)
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 10; i++ {
+			assert.NoErr(t, w.Clean())
+		}
+	}()
+	wg.Wait()
+
+	assert.NoErr(t, w.Close())
+	files := fsutil.Glob(logfile + ".*")
+	assert.Lte(t, len(files), 2)
+	for _, fPath := range files {
+		assert.StrContains(t, fPath, ".gz")
+	}
+}
@@ -0,0 +1,169 @@
+package rotatefile
+
+import (
+	"os"
+	"sync"
+	"time"
+)
+
+// the rotated file for handle in background
+type rotatedFile struct {
+	// the opened file for sync and close, maybe nil.
+	file *os.File
+	// the path of the rotated file, add it to manifest on not empty.
+	path       string
+	start, end time.Time
+	// the config snapshot on rotating
+	cfg *Config
+}
+
+// bgWorker keep the slow jobs off the write path: sync and close the rotated files,
+// compress and clean the backup files. the jobs are handled by one goroutine.
+type bgWorker struct {
+	mu      sync.Mutex
+	started bool
+	stopped bool
+	// the rotated files for sync and close
+	closeCh chan rotatedFile
+	// signal for clean the backups with the config snapshot, the signals are merged.
+	cleanCh chan *Config
+	done    chan struct{}
+}
+
+// max queued rotated files, will close the file on the write path on the queue is full.
+const maxQueuedFiles = 16
+
+// start the worker on first call. returns false on the worker is stopped.
+func (d *Writer) startWorker() bool {
+	w := &d.worker
+	if w.stopped {
+		return false
+	}
+
+	if !w.started {
+		d.cfg.Debug("START the background worker for close rotated files and clean old files")
+		w.started = true
+		w.closeCh = make(chan rotatedFile, maxQueuedFiles)
+		w.cleanCh = make(chan *Config, 1)
+		w.done = make(chan struct{})
+		go d.runWorker(d.cfgSnapshot(), w.closeCh, w.cleanCh, w.done)
+	}
+	return true
+}
+
+func (d *Writer) runWorker(cfg *Config, closeCh <-chan rotatedFile, cleanCh <-chan *Config, done chan<- struct{}) {
+	defer close(done)
+
+	for {
+		select {
+		case rf, ok := <-closeCh:
+			if !ok {
+				// stopped, handle the pending clean
+				select {
+				case cfg := <-cleanCh:
+					d.runClean(cfg)
+				default:
+				}
+				cfg.Debug("STOP the background worker")
+				return
+			}
+			d.handleRotated(rf)
+		case cfg := <-cleanCh:
+			// close the rotated files before clean them
+			d.drainFiles(closeCh)
+			d.runClean(cfg)
+		}
+	}
+}
+
+func (d *Writer) drainFiles(closeCh <-chan rotatedFile) {
+	for {
+		select {
+		case rf, ok := <-closeCh:
+			if !ok {
+				return
+			}
+			d.handleRotated(rf)
+		default:
+			return
+		}
+	}
+}
+
+func (d *Writer) runClean(cfg *Config) {
+	cfg.Debug("background worker - clean old files handling")
+	printErrln("rotatefile: clean old files error:", d.clean(cfg))
+}
+
+// stop the worker and wait for the queued jobs are finished.
+func (d *Writer) stopWorker() {
+	w := &d.worker
+	w.mu.Lock()
+	if w.stopped {
+		w.mu.Unlock()
+		return
+	}
+
+	w.stopped = true
+	if w.started {
+		close(w.closeCh)
+	}
+	done := w.done
+	w.mu.Unlock()
+
+	if done != nil {
+		<-done
+	}
+}
+
+// sync and close the rotated file, then add it to manifest
+func (d *Writer) handleRotated(rf rotatedFile) {
+	if rf.file != nil {
+		printErrln("rotatefile: close rotated file error:", syncClose(rf.file))
+	}
+	if rf.path != "" && rf.cfg.ManifestFile != "" {
+		printErrln("rotatefile: add file to manifest error:", d.addManifest(rf.cfg, rf.path, rf.start, rf.end))
+	}
+}
+
+// handle the rotated file in background. see handleRotated()
+func (d *Writer) closeAsync(rf rotatedFile) {
+	w := &d.worker
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if d.startWorker() {
+		select {
+		case w.closeCh <- rf:
+			return
+		default: // the queue is full
+		}
+	}
+	d.handleRotated(rf)
+}
+
+// clean old files by config in background. should be called on the write path.
+func (d *Writer) cleanAsync() {
+	if !d.cfg.shouldClean() {
+		return
+	}
+
+	w := &d.worker
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if d.startWorker() {
+		select {
+		case w.cleanCh <- d.cfgSnapshot():
+		default: // has a pending clean
+		}
+	}
+}
+
+func syncClose(f *os.File) error {
+	if err := f.Sync(); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,144 @@
+// Package grpcinterceptor provide the gRPC server and client interceptors for log the rpc calls by rpclog.
+//
+// It is a separate module, so the slog module does not depend on gRPC.
+//
+// Usage:
+//
+//	rl := rpclog.New(logger)
+//	srv := grpc.NewServer(
+//		grpc.ChainUnaryInterceptor(grpcinterceptor.UnaryServerInterceptor(rl)),
+//		grpc.ChainStreamInterceptor(grpcinterceptor.StreamServerInterceptor(rl)),
+//	)
+//
+//	conn, err := grpc.Dial(target,
+//		grpc.WithChainUnaryInterceptor(grpcinterceptor.UnaryClientInterceptor(rl)),
+//		grpc.WithChainStreamInterceptor(grpcinterceptor.StreamClientInterceptor(rl)),
+//	)
+package grpcinterceptor
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/gookit/slog/rpclog"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// UnaryServerInterceptor log the unary calls on server
+func UnaryServerInterceptor(rl *rpclog.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		start := time.Now()
+		resp, err := handler(ctx, req)
+
+		rl.Log(ctx, &rpclog.CallInfo{
+			Kind:     rpclog.KindServer,
+			Method:   info.FullMethod,
+			Peer:     peerAddr(ctx),
+			Code:     status.Code(err).String(),
+			Start:    start,
+			Err:      err,
+			Request:  req,
+			Response: resp,
+		})
+		return resp, err
+	}
+}
+
+// StreamServerInterceptor log the stream calls on server, the record is written on the stream handler returns.
+func StreamServerInterceptor(rl *rpclog.Logger) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+		err := handler(srv, ss)
+
+		ctx := ss.Context()
+		rl.Log(ctx, &rpclog.CallInfo{
+			Kind:   rpclog.KindServer,
+			Method: info.FullMethod,
+			Peer:   peerAddr(ctx),
+			Code:   status.Code(err).String(),
+			Stream: true,
+			Start:  start,
+			Err:    err,
+		})
+		return err
+	}
+}
+
+// UnaryClientInterceptor log the unary calls on client
+func UnaryClientInterceptor(rl *rpclog.Logger) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		start := time.Now()
+		err := invoker(ctx, method, req, reply, cc, opts...)
+
+		rl.Log(ctx, &rpclog.CallInfo{
+			Kind:     rpclog.KindClient,
+			Method:   method,
+			Peer:     cc.Target(),
+			Code:     status.Code(err).String(),
+			Start:    start,
+			Err:      err,
+			Request:  req,
+			Response: reply,
+		})
+		return err
+	}
+}
+
+// StreamClientInterceptor log the stream calls on client.
+//
+// The record is written on the stream is failed to create, or the RecvMsg returns error(io.EOF is OK).
+func StreamClientInterceptor(rl *rpclog.Logger) grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		ci := &rpclog.CallInfo{
+			Kind:   rpclog.KindClient,
+			Method: method,
+			Peer:   cc.Target(),
+			Stream: true,
+			Start:  time.Now(),
+		}
+
+		cs, err := streamer(ctx, desc, cc, method, opts...)
+		if err != nil {
+			ci.Code, ci.Err = status.Code(err).String(), err
+			rl.Log(ctx, ci)
+			return nil, err
+		}
+		return &clientStream{ClientStream: cs, rl: rl, ci: ci}, nil
+	}
+}
+
+// clientStream wrap the grpc.ClientStream, log the call on the stream is finished.
+type clientStream struct {
+	grpc.ClientStream
+	rl   *rpclog.Logger
+	ci   *rpclog.CallInfo
+	once sync.Once
+}
+
+// RecvMsg wrap the ClientStream.RecvMsg, log the call on it returns error.
+func (s *clientStream) RecvMsg(m any) error {
+	err := s.ClientStream.RecvMsg(m)
+	if err != nil {
+		s.once.Do(func() {
+			if !errors.Is(err, io.EOF) {
+				s.ci.Err = err
+			}
+			s.ci.Code = status.Code(s.ci.Err).String()
+			s.rl.Log(s.Context(), s.ci)
+		})
+	}
+	return err
+}
+
+// get the peer address from the server context
+func peerAddr(ctx context.Context) string {
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		return p.Addr.String()
+	}
+	return ""
+}
@@ -0,0 +1,136 @@
+package grpcinterceptor_test
+
+import (
+	"bytes"
+	"context"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gookit/goutil/testutil/assert"
+	"github.com/gookit/slog"
+	"github.com/gookit/slog/handler"
+	"github.com/gookit/slog/rpclog"
+	"github.com/gookit/slog/rpclog/grpcinterceptor"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// syncBuffer the records are written by the server goroutines
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) ResetGet() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	str := b.buf.String()
+	b.buf.Reset()
+	return str
+}
+
+func newRPCLogger(buf *syncBuffer) *rpclog.Logger {
+	h := handler.NewIOWriter(buf, slog.AllLevels)
+	h.SetFormatter(slog.NewJSONFormatter())
+	return rpclog.New(slog.NewWithHandlers(h))
+}
+
+func newTestConn(t *testing.T, rl *rpclog.Logger) healthpb.HealthClient {
+	lis := bufconn.Listen(1024 * 1024)
+	srv := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(grpcinterceptor.UnaryServerInterceptor(rl)),
+		grpc.ChainStreamInterceptor(grpcinterceptor.StreamServerInterceptor(rl)),
+	)
+
+	hs := health.NewServer()
+	hs.SetServingStatus("app", healthpb.HealthCheckResponse_SERVING)
+	healthpb.RegisterHealthServer(srv, hs)
+	go func() { _ = srv.Serve(lis) }()
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpc.Dial("bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithChainUnaryInterceptor(grpcinterceptor.UnaryClientInterceptor(rl)),
+		grpc.WithChainStreamInterceptor(grpcinterceptor.StreamClientInterceptor(rl)),
+	)
+	assert.NoErr(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+	return healthpb.NewHealthClient(conn)
+}
+
+func TestUnaryInterceptors(t *testing.T) {
+	buf := new(syncBuffer)
+	client := newTestConn(t, newRPCLogger(buf))
+
+	_, err := client.Check(context.Background(), &healthpb.HealthCheckRequest{Service: "app"})
+	assert.NoErr(t, err)
+
+	// the server record is written before the client record
+	lines := strings.Split(strings.TrimSpace(buf.ResetGet()), "\n")
+	assert.Len(t, lines, 2)
+	assert.StrContains(t, lines[0], `"level":"INFO"`)
+	assert.StrContains(t, lines[0], `"rpc_kind":"server"`)
+	assert.StrContains(t, lines[0], `"rpc_method":"/grpc.health.v1.Health/Check"`)
+	assert.StrContains(t, lines[0], `"rpc_code":"OK"`)
+	assert.StrContains(t, lines[0], `"peer":"bufconn"`)
+	assert.StrContains(t, lines[1], `"rpc_kind":"client"`)
+	assert.StrContains(t, lines[1], `"peer":"bufnet"`)
+
+	// error code
+	_, err = client.Check(context.Background(), &healthpb.HealthCheckRequest{Service: "not-exists"})
+	assert.Eq(t, codes.NotFound, status.Code(err))
+
+	lines = strings.Split(strings.TrimSpace(buf.ResetGet()), "\n")
+	assert.Len(t, lines, 2)
+	assert.StrContains(t, lines[0], `"level":"WARN"`)
+	assert.StrContains(t, lines[0], `"rpc_code":"NotFound"`)
+	assert.StrContains(t, lines[1], `"rpc_code":"NotFound"`)
+}
+
+func TestStreamInterceptors(t *testing.T) {
+	buf := new(syncBuffer)
+	client := newTestConn(t, newRPCLogger(buf))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stream, err := client.Watch(ctx, &healthpb.HealthCheckRequest{Service: "app"})
+	assert.NoErr(t, err)
+
+	resp, err := stream.Recv()
+	assert.NoErr(t, err)
+	assert.Eq(t, healthpb.HealthCheckResponse_SERVING, resp.Status)
+	assert.Empty(t, buf.ResetGet())
+
+	cancel()
+	_, err = stream.Recv()
+	assert.Eq(t, codes.Canceled, status.Code(err))
+
+	// the server record is written on the stream handler returns
+	var str string
+	for i := 0; i < 100 && strings.Count(str, "\n") < 2; i++ {
+		time.Sleep(10 * time.Millisecond)
+		str += buf.ResetGet()
+	}
+
+	assert.StrContains(t, str, `"rpc_kind":"client"`)
+	assert.StrContains(t, str, `"rpc_kind":"server"`)
+	assert.StrContains(t, str, `"rpc_method":"/grpc.health.v1.Health/Watch"`)
+	assert.StrContains(t, str, `"rpc_code":"Canceled"`)
+	assert.Eq(t, 2, strings.Count(str, `"stream":true`))
+}
@@ -0,0 +1,160 @@
+// Package rpclog provide the RPC call logging for gRPC interceptors,
+// log method, peer, status code and duration per RPC, with configurable
+// payload logging and level mapping for error codes.
+//
+// The package does not depend on gRPC. the interceptors are provided by the separate module
+// github.com/gookit/slog/rpclog/grpcinterceptor, or use it in custom interceptors. eg:
+//
+//	rl := rpclog.New(logger)
+//	unary := func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+//		start := time.Now()
+//		resp, err := handler(ctx, req)
+//		p, _ := peer.FromContext(ctx)
+//		rl.Log(ctx, &rpclog.CallInfo{
+//			Method: info.FullMethod, Peer: p.Addr.String(), Code: status.Code(err).String(),
+//			Start: start, Err: err, Request: req, Response: resp,
+//		})
+//		return resp, err
+//	}
+package rpclog
+
+import (
+	"context"
+	"time"
+
+	"github.com/gookit/slog"
+)
+
+// Kind of the rpc call
+const (
+	KindServer = "server"
+	KindClient = "client"
+)
+
+// DefaultCodeLevels default level mapping for gRPC status code names.
+//
+// The not listed code will use Options.DefaultErrLevel
+var DefaultCodeLevels = map[string]slog.Level{
+	"OK":                 slog.InfoLevel,
+	"Canceled":           slog.InfoLevel,
+	"InvalidArgument":    slog.WarnLevel,
+	"NotFound":           slog.WarnLevel,
+	"AlreadyExists":      slog.WarnLevel,
+	"PermissionDenied":   slog.WarnLevel,
+	"Unauthenticated":    slog.WarnLevel,
+	"ResourceExhausted":  slog.WarnLevel,
+	"FailedPrecondition": slog.WarnLevel,
+	"Aborted":            slog.WarnLevel,
+	"OutOfRange":         slog.WarnLevel,
+}
+
+// CallInfo of a rpc call
+type CallInfo struct {
+	// Kind of call: server, client. default is server
+	Kind string
+	// Method full method name. eg: "/pkg.Service/Method"
+	Method string
+	// Peer address
+	Peer string
+	// Code status code name. eg: "OK", "NotFound"
+	Code string
+	// Stream is a streaming call
+	Stream bool
+	// Start time of the call. if Duration is zero, will use slog.Since(Start)
+	Start    time.Time
+	Duration time.Duration
+	// Err of the call
+	Err error
+	// Request and Response payload, only logged on Options.LogPayload=true
+	Request, Response any
+}
+
+// Options for the Logger
+type Options struct {
+	// CodeLevels level mapping for status code names
+	CodeLevels map[string]slog.Level
+	// DefaultErrLevel for the not mapped code. default is slog.ErrorLevel
+	DefaultErrLevel slog.Level
+	// LogPayload log the request and response payload
+	LogPayload bool
+	// Message for the call record. default is "rpc call"
+	Message string
+}
+
+// Logger for log rpc calls
+type Logger struct {
+	Options
+	l *slog.Logger
+}
+
+// New create a rpc call Logger
+func New(l *slog.Logger, fns ...func(opt *Options)) *Logger {
+	rl := &Logger{
+		l: l,
+		Options: Options{
+			CodeLevels:      DefaultCodeLevels,
+			DefaultErrLevel: slog.ErrorLevel,
+			Message:         "rpc call",
+		},
+	}
+
+	for _, fn := range fns {
+		fn(&rl.Options)
+	}
+	return rl
+}
+
+// LevelOf get log level for the status code name
+func (rl *Logger) LevelOf(code string) slog.Level {
+	if level, ok := rl.CodeLevels[code]; ok {
+		return level
+	}
+	return rl.DefaultErrLevel
+}
+
+// Fields build the log fields for the call
+func (rl *Logger) Fields(ci *CallInfo) slog.M {
+	if ci.Kind == "" {
+		ci.Kind = KindServer
+	}
+	if ci.Code == "" {
+		ci.Code = "OK"
+	}
+
+	dur := ci.Duration
+	if dur == 0 && !ci.Start.IsZero() {
+		dur = slog.Since(ci.Start)
+	}
+
+	fields := slog.M{
+		"rpc_kind":   ci.Kind,
+		"rpc_method": ci.Method,
+		"rpc_code":   ci.Code,
+		"duration":   dur.String(),
+	}
+	if ci.Peer != "" {
+		fields["peer"] = ci.Peer
+	}
+	if ci.Stream {
+		fields["stream"] = true
+	}
+	if ci.Err != nil {
+		fields["error"] = ci.Err.Error()
+	}
+
+	if rl.LogPayload {
+		if ci.Request != nil {
+			fields["request"] = ci.Request
+		}
+		if ci.Response != nil {
+			fields["response"] = ci.Response
+		}
+	}
+	return fields
+}
+
+// Log the rpc call
+func (rl *Logger) Log(ctx context.Context, ci *CallInfo) {
+	fields := rl.Fields(ci)
+	rl.l.WithFields(fields).WithContext(ctx).Log(rl.LevelOf(ci.Code), rl.Message)
+}
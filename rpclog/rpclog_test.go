@@ -0,0 +1,56 @@
+package rpclog_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/gookit/goutil/byteutil"
+	"github.com/gookit/goutil/testutil/assert"
+	"github.com/gookit/slog"
+	"github.com/gookit/slog/handler"
+	"github.com/gookit/slog/rpclog"
+)
+
+func TestLogger_Log(t *testing.T) {
+	buf := byteutil.NewBuffer()
+	h := handler.NewIOWriter(buf, slog.AllLevels)
+	h.SetFormatter(slog.NewJSONFormatter())
+
+	rl := rpclog.New(slog.NewWithHandlers(h))
+	assert.Eq(t, slog.WarnLevel, rl.LevelOf("NotFound"))
+	assert.Eq(t, slog.ErrorLevel, rl.LevelOf("Internal"))
+
+	rl.Log(context.Background(), &rpclog.CallInfo{
+		Method:   "/pkg.Service/Get",
+		Peer:     "127.0.0.1:5678",
+		Duration: 3 * time.Millisecond,
+		Request:  "req-body",
+	})
+
+	str := buf.String()
+	assert.StrContains(t, str, `"level":"INFO"`)
+	assert.StrContains(t, str, `"rpc_method":"/pkg.Service/Get"`)
+	assert.StrContains(t, str, `"rpc_code":"OK"`)
+	assert.StrContains(t, str, `"duration":"3ms"`)
+	assert.NotContains(t, str, "req-body")
+
+	// with error and payload
+	buf.Reset()
+	rl.LogPayload = true
+	rl.Log(context.Background(), &rpclog.CallInfo{
+		Kind:    rpclog.KindClient,
+		Method:  "/pkg.Service/Get",
+		Code:    "Unavailable",
+		Err:     errors.New("connection refused"),
+		Start:   time.Now(),
+		Request: "req-body",
+	})
+
+	str = buf.String()
+	assert.StrContains(t, str, `"level":"ERROR"`)
+	assert.StrContains(t, str, `"rpc_kind":"client"`)
+	assert.StrContains(t, str, `"error":"connection refused"`)
+	assert.StrContains(t, str, `"request":"req-body"`)
+}
@@ -0,0 +1,375 @@
+package slog
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// fallbackMaxDepth max depth for walk the values on EncodeOptions.MaxDepth is not set.
+const fallbackMaxDepth = 64
+
+// BytesFormat how to render the []byte field values
+type BytesFormat uint8
+
+// built-in []byte render formats
+const (
+	// BytesDefault render by the formatter default. JSON: base64 string, Text: raw string
+	BytesDefault BytesFormat = iota
+	// BytesHex render as hex string. eg: "68656c6c6f"
+	BytesHex
+	// BytesBase64 render as base64 string. eg: "aGVsbG8="
+	BytesBase64
+	// BytesLen render as length summary. eg: "[]byte(len=5)"
+	BytesLen
+)
+
+// EncodeOptions for encode the field values on formatter.
+type EncodeOptions struct {
+	// MaxDepth max depth for encode the nested values. 0 is not limit.
+	//
+	// The too deep values will be replaced with "!MAXDEPTH(type)"
+	MaxDepth int
+	// MaxElements max elements count for the slice and map values. 0 is not limit.
+	//
+	// The omitted elements will be marked as "…+N more". eg: [1,2,"…+3 more"], {"a":1,"…":"+3 more"}
+	MaxElements int
+	// BytesFormat render format for the []byte values. default is BytesDefault
+	BytesFormat BytesFormat
+	// MaxStringLen max bytes for the string values. 0 is not limit.
+	//
+	// The too long string will be truncated and marked as "…+N bytes". see TruncateString()
+	MaxStringLen int
+}
+
+func (o *EncodeOptions) enabled() bool {
+	return o.MaxDepth > 0 || o.MaxElements > 0 || o.MaxStringLen > 0 || o.BytesFormat != BytesDefault
+}
+
+func (o *EncodeOptions) newEncoder(jsonSafe bool) *safeEncoder {
+	e := newSafeEncoder(o.MaxDepth, o.MaxElements, jsonSafe)
+	e.bytesFmt = o.BytesFormat
+	e.maxStr = o.MaxStringLen
+	return e
+}
+
+// TruncateString truncate the string to max bytes, will not split a UTF-8 char,
+// and append the mark "…+N bytes". N is the omitted bytes count.
+//
+// eg: TruncateString("hello world", 5) => "hello…+6 bytes"
+func TruncateString(s string, max int) string {
+	if max <= 0 || len(s) <= max {
+		return s
+	}
+
+	n := max
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n] + fmt.Sprintf("%s+%d bytes", MoreItemsKey, len(s)-n)
+}
+
+// FormatBytes render the []byte value by the format
+func FormatBytes(bs []byte, bf BytesFormat) string {
+	switch bf {
+	case BytesHex:
+		return hex.EncodeToString(bs)
+	case BytesBase64:
+		return base64.StdEncoding.EncodeToString(bs)
+	case BytesLen:
+		return fmt.Sprintf("[]byte(len=%d)", len(bs))
+	}
+	return string(bs)
+}
+
+var jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+
+// MoreItemsKey the map key for mark the omitted elements on limit the elements count.
+const MoreItemsKey = "…"
+
+// safeEncoder walk the values, and replace the cycle references, too deep values
+// and the unsupported values with placeholder. omit the elements on exceed the maxItems.
+type safeEncoder struct {
+	maxDepth int
+	// max elements for slice and map. 0 is not limit.
+	maxItems int
+	// check the values unsupported by JSON. eg: chan, func, NaN
+	jsonSafe bool
+	bytesFmt BytesFormat
+	// max bytes for string values. 0 is not limit.
+	maxStr int
+	// the pointers on current walking path, for detect cycle
+	seen map[uintptr]struct{}
+}
+
+func newSafeEncoder(maxDepth, maxItems int, jsonSafe bool) *safeEncoder {
+	if maxDepth <= 0 {
+		maxDepth = fallbackMaxDepth
+	}
+
+	return &safeEncoder{
+		maxDepth: maxDepth,
+		maxItems: maxItems,
+		jsonSafe: jsonSafe,
+		seen:     make(map[uintptr]struct{}),
+	}
+}
+
+// safeValue returns the new value on it has been changed, otherwise returns the original value.
+func (e *safeEncoder) safeValue(v any) any {
+	if nv, changed := e.value(reflect.ValueOf(v), 1); changed {
+		return nv
+	}
+	return v
+}
+
+// safeMap returns a new map on any value has been changed.
+func (e *safeEncoder) safeMap(mp M) M {
+	var nm M
+	for k, v := range mp {
+		nv, changed := e.value(reflect.ValueOf(v), 1)
+		if !changed {
+			continue
+		}
+
+		if nm == nil {
+			nm = make(M, len(mp))
+			for k1, v1 := range mp {
+				nm[k1] = v1
+			}
+		}
+		nm[k] = nv
+	}
+
+	if nm == nil {
+		return mp
+	}
+	return nm
+}
+
+// value returns the new value and true on the value has been changed.
+func (e *safeEncoder) value(rv reflect.Value, depth int) (any, bool) {
+	if !rv.IsValid() {
+		return nil, false
+	}
+
+	typ := rv.Type()
+	if e.jsonSafe && typ.Implements(jsonMarshalerType) {
+		if rv.Kind() == reflect.Ptr && rv.IsNil() {
+			return nil, false
+		}
+		if _, err := json.Marshal(rv.Interface()); err != nil {
+			return JSONBadValue(rv.Interface(), err), true
+		}
+		return nil, false
+	}
+
+	switch rv.Kind() {
+	case reflect.Chan, reflect.Func, reflect.Complex64, reflect.Complex128, reflect.UnsafePointer:
+		if e.jsonSafe {
+			_, err := json.Marshal(rv.Interface())
+			return JSONBadValue(rv.Interface(), err), true
+		}
+	case reflect.Float32, reflect.Float64:
+		if f := rv.Float(); e.jsonSafe && (math.IsNaN(f) || math.IsInf(f, 0)) {
+			_, err := json.Marshal(rv.Interface())
+			return JSONBadValue(rv.Interface(), err), true
+		}
+	case reflect.String:
+		if e.maxStr > 0 && rv.Len() > e.maxStr {
+			return TruncateString(rv.String(), e.maxStr), true
+		}
+	case reflect.Interface:
+		if rv.IsNil() {
+			return nil, false
+		}
+		return e.value(rv.Elem(), depth)
+	case reflect.Ptr:
+		if rv.IsNil() {
+			return nil, false
+		}
+		return e.withSeen(rv, func() (any, bool) {
+			return e.value(rv.Elem(), depth)
+		})
+	case reflect.Map:
+		if rv.IsNil() {
+			return nil, false
+		}
+		return e.withSeen(rv, func() (any, bool) {
+			return e.mapValue(rv, depth)
+		})
+	case reflect.Slice:
+		if rv.IsNil() {
+			return nil, false
+		}
+		if typ.Elem().Kind() == reflect.Uint8 {
+			if e.bytesFmt == BytesDefault {
+				return nil, false
+			}
+			return FormatBytes(rv.Bytes(), e.bytesFmt), true
+		}
+		return e.withSeen(rv, func() (any, bool) {
+			return e.sliceValue(rv, depth)
+		})
+	case reflect.Array:
+		return e.sliceValue(rv, depth)
+	case reflect.Struct:
+		return e.structValue(rv, depth)
+	}
+	return nil, false
+}
+
+// check the cycle reference by pointer
+func (e *safeEncoder) withSeen(rv reflect.Value, fn func() (any, bool)) (any, bool) {
+	ptr := rv.Pointer()
+	if _, ok := e.seen[ptr]; ok {
+		return fmt.Sprintf("!CYCLE(%s)", rv.Type()), true
+	}
+
+	e.seen[ptr] = struct{}{}
+	defer delete(e.seen, ptr)
+	return fn()
+}
+
+func (e *safeEncoder) tooDeep(rv reflect.Value, depth int) (string, bool) {
+	if depth > e.maxDepth {
+		return fmt.Sprintf("!MAXDEPTH(%s)", rv.Type()), true
+	}
+	return "", false
+}
+
+func (e *safeEncoder) mapValue(rv reflect.Value, depth int) (any, bool) {
+	if s, ok := e.tooDeep(rv, depth); ok {
+		return s, true
+	}
+
+	var changed bool
+	keys := rv.MapKeys()
+	nm := make(M, len(keys))
+	if more := len(keys) - e.maxItems; e.maxItems > 0 && more > 0 {
+		// sort keys for keep the same elements
+		sort.Slice(keys, func(i, j int) bool {
+			return fmt.Sprint(keys[i].Interface()) < fmt.Sprint(keys[j].Interface())
+		})
+		keys = keys[:e.maxItems]
+		nm[MoreItemsKey] = fmt.Sprintf("+%d more", more)
+		changed = true
+	}
+
+	for _, key := range keys {
+		val := rv.MapIndex(key)
+		nv, ok := e.value(val, depth+1)
+		if ok {
+			changed = true
+		} else if val.IsValid() && val.CanInterface() {
+			nv = val.Interface()
+		}
+		nm[fmt.Sprint(key.Interface())] = nv
+	}
+
+	if !changed {
+		return nil, false
+	}
+	return nm, true
+}
+
+func (e *safeEncoder) sliceValue(rv reflect.Value, depth int) (any, bool) {
+	if s, ok := e.tooDeep(rv, depth); ok {
+		return s, true
+	}
+
+	var changed bool
+	ln := rv.Len()
+	more := ln - e.maxItems
+	if e.maxItems > 0 && more > 0 {
+		ln = e.maxItems
+		changed = true
+	}
+
+	ns := make([]any, ln, ln+1)
+	for i := 0; i < ln; i++ {
+		val := rv.Index(i)
+		nv, ok := e.value(val, depth+1)
+		if ok {
+			changed = true
+		} else if val.CanInterface() {
+			nv = val.Interface()
+		}
+		ns[i] = nv
+	}
+
+	if e.maxItems > 0 && more > 0 {
+		ns = append(ns, fmt.Sprintf("%s+%d more", MoreItemsKey, more))
+	}
+
+	if !changed {
+		return nil, false
+	}
+	return ns, true
+}
+
+// convert the struct to map on any field value has been changed.
+func (e *safeEncoder) structValue(rv reflect.Value, depth int) (any, bool) {
+	if s, ok := e.tooDeep(rv, depth); ok {
+		return s, true
+	}
+
+	var changed bool
+	typ := rv.Type()
+	nm := make(M, rv.NumField())
+	for i := 0; i < rv.NumField(); i++ {
+		sf := typ.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+
+		name, val := sf.Name, rv.Field(i)
+		if tag := sf.Tag.Get("json"); tag != "" {
+			if tag == "-" {
+				continue
+			}
+
+			tn, opts, _ := strings.Cut(tag, ",")
+			if tn != "" {
+				name = tn
+			}
+			if strings.Contains(opts, "omitempty") && isEmptyValue(val) {
+				continue
+			}
+		}
+
+		nv, ok := e.value(val, depth+1)
+		if ok {
+			changed = true
+		} else {
+			nv = val.Interface()
+		}
+		nm[name] = nv
+	}
+
+	if !changed {
+		return nil, false
+	}
+	return nm, true
+}
+
+// same as the encoding/json isEmptyValue
+func isEmptyValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64,
+		reflect.Interface, reflect.Ptr:
+		return v.IsZero()
+	}
+	return false
+}
@@ -0,0 +1,343 @@
+package slog
+
+import (
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+//
+// Sampler interface
+//
+
+// Sampler interface definition. decide whether a record should be logged.
+//
+// NOTICE: records with level <= FatalLevel will never be sampled.
+type Sampler interface {
+	// Sample returns true to keep the record, false to drop it.
+	Sample(r *Record) bool
+}
+
+// SamplerFunc wrapper definition
+type SamplerFunc func(r *Record) bool
+
+// Sample record
+func (fn SamplerFunc) Sample(r *Record) bool {
+	return fn(r)
+}
+
+// SampleKey build the sampling key for a record. format: "LEVEL:message"
+func SampleKey(r *Record) string {
+	return r.Level.Name() + ":" + r.Message
+}
+
+const (
+	// SampleMaxKeys max sample keys of the dropped stats, avoid unbounded memory growth.
+	// on reached, keep the top half keys by count, the others are merged to SampleOverflowKey.
+	SampleMaxKeys = 1024
+	// SampleOverflowKey the sample key for the merged dropped count. see SampleMaxKeys
+	SampleOverflowKey = "overflow"
+)
+
+// samplingStats collect the dropped records by the sampler
+type samplingStats struct {
+	mu sync.Mutex
+	// total dropped count by sample key
+	drops map[string]uint64
+	// dropped count since last report
+	pending    map[string]uint64
+	lastReport time.Time
+}
+
+func newSamplingStats() *samplingStats {
+	return &samplingStats{
+		drops:   make(map[string]uint64),
+		pending: make(map[string]uint64),
+	}
+}
+
+func (s *samplingStats) drop(key string) {
+	s.mu.Lock()
+	incrKey(s.drops, key)
+	incrKey(s.pending, key)
+	s.mu.Unlock()
+}
+
+// incr the count of the key, compact the keys on reached SampleMaxKeys.
+func incrKey(mp map[string]uint64, key string) {
+	if _, ok := mp[key]; !ok && len(mp) >= SampleMaxKeys {
+		compactKeys(mp)
+	}
+	mp[key]++
+}
+
+// keep the top half keys by count, merge the others to SampleOverflowKey.
+func compactKeys(mp map[string]uint64) {
+	keys := make([]string, 0, len(mp))
+	for key := range mp {
+		if key != SampleOverflowKey {
+			keys = append(keys, key)
+		}
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		return mp[keys[i]] > mp[keys[j]]
+	})
+
+	for _, key := range keys[SampleMaxKeys/2:] {
+		mp[SampleOverflowKey] += mp[key]
+		delete(mp, key)
+	}
+}
+
+// take the pending drops if it's time to report
+func (s *samplingStats) take(now time.Time, interval time.Duration) (M, uint64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.pending) == 0 || now.Sub(s.lastReport) < interval {
+		return nil, 0
+	}
+
+	var total uint64
+	drops := make(M, len(s.pending))
+	for key, n := range s.pending {
+		total += n
+		drops[key] = n
+	}
+
+	s.lastReport = now
+	s.pending = make(map[string]uint64)
+	return drops, total
+}
+
+func (s *samplingStats) snapshot() map[string]uint64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	mp := make(map[string]uint64, len(s.drops))
+	for key, n := range s.drops {
+		mp[key] = n
+	}
+	return mp
+}
+
+//
+// built-in samplers
+//
+
+type sampleCounter struct {
+	// the tick start time(unix nano) and count in the tick
+	tick  int64
+	count uint64
+}
+
+// sampleCounters counter of the records by sample key.
+//
+// The keys are limited by SampleMaxKeys, on reached, the counters of the expired tick are removed first,
+// then the less counted half. a removed key will be counted from zero again.
+type sampleCounters struct {
+	mu sync.Mutex
+	cs map[string]*sampleCounter
+}
+
+// incr the counter of the key, reset on enter new tick. returns the count in current tick.
+//
+// tick <= 0 is never reset.
+func (sc *sampleCounters) incr(key string, t time.Time, tick time.Duration) uint64 {
+	var start int64
+	if tick > 0 {
+		start = t.Truncate(tick).UnixNano()
+	}
+
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+
+	c, ok := sc.cs[key]
+	if !ok {
+		if sc.cs == nil {
+			sc.cs = make(map[string]*sampleCounter)
+		} else if len(sc.cs) >= SampleMaxKeys {
+			sc.compact(start)
+		}
+
+		c = &sampleCounter{tick: start}
+		sc.cs[key] = c
+	}
+
+	if c.tick != start {
+		c.tick = start
+		c.count = 0
+	}
+
+	c.count++
+	return c.count
+}
+
+// remove the counters of expired tick, then keep the top half counters by count if still full.
+func (sc *sampleCounters) compact(tick int64) {
+	keys := make([]string, 0, len(sc.cs))
+	for key, c := range sc.cs {
+		if c.tick != tick {
+			delete(sc.cs, key)
+		} else {
+			keys = append(keys, key)
+		}
+	}
+
+	if len(keys) < SampleMaxKeys {
+		return
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		return sc.cs[keys[i]].count > sc.cs[keys[j]].count
+	})
+	for _, key := range keys[SampleMaxKeys/2:] {
+		delete(sc.cs, key)
+	}
+}
+
+// the time for sample the record. the record time is not init on sampling, so use the logger clock.
+func sampleTime(r *Record) time.Time {
+	if !r.Time.IsZero() {
+		return r.Time
+	}
+	if r.logger != nil {
+		return r.logger.loadOpts().timeClock.Now()
+	}
+	return time.Now()
+}
+
+// EveryN keep the first record and then every n-th record for each sample key. see SampleKey()
+//
+// Usage:
+//
+//	l.SetSampler(slog.EveryN(100))
+func EveryN(n int) Sampler {
+	if n <= 1 {
+		return SamplerFunc(func(r *Record) bool { return true })
+	}
+
+	sc := &sampleCounters{}
+	return SamplerFunc(func(r *Record) bool {
+		return (sc.incr(SampleKey(r), r.Time, 0)-1)%uint64(n) == 0
+	})
+}
+
+// RateLimit keep at most N records per second for each level. the level not in limits is not limited.
+//
+// Usage:
+//
+//	l.SetSampler(slog.RateLimit(map[slog.Level]int{
+//		slog.DebugLevel: 100,
+//		slog.InfoLevel:  1000,
+//	}))
+func RateLimit(limits map[Level]int) Sampler {
+	sc := &sampleCounters{}
+	return SamplerFunc(func(r *Record) bool {
+		limit, ok := limits[r.Level]
+		if !ok {
+			return true
+		}
+		return sc.incr(r.Level.Name(), sampleTime(r), time.Second) <= uint64(limit)
+	})
+}
+
+// BurstSampler like the zap sampler. in each tick, keep the first N records for each sample key,
+// then keep every M-th record. thereafter <= 0 will drop all records after the first N.
+//
+// Usage:
+//
+//	// each second, log the first 100 same records, then every 100th.
+//	l.SetSampler(slog.BurstSampler(time.Second, 100, 100))
+func BurstSampler(tick time.Duration, first, thereafter int) Sampler {
+	sc := &sampleCounters{}
+	return SamplerFunc(func(r *Record) bool {
+		n := sc.incr(SampleKey(r), sampleTime(r), tick)
+		if n <= uint64(first) {
+			return true
+		}
+		if thereafter <= 0 {
+			return false
+		}
+		return (n-uint64(first))%uint64(thereafter) == 0
+	})
+}
+
+//
+// sampling on the logger
+//
+
+// SetSampler for the logger. set nil to disable sampling. it is safe to call concurrently with logging.
+func (l *Logger) SetSampler(s Sampler) {
+	rl := l.rootLogger()
+	rl.setMu.Lock()
+	defer rl.setMu.Unlock()
+
+	if s == nil {
+		rl.sampler.Store(nil)
+		return
+	}
+
+	if rl.sampling.Load() == nil {
+		rl.sampling.Store(newSamplingStats())
+	}
+	rl.sampler.Store(&s)
+}
+
+// Sampler get the logger sampler
+func (l *Logger) Sampler() Sampler {
+	if s := l.rootLogger().sampler.Load(); s != nil {
+		return *s
+	}
+	return nil
+}
+
+// SampledDrops get the total dropped count by sample key. see SampleKey()
+//
+// The keys are limited by SampleMaxKeys, the less dropped keys are merged to SampleOverflowKey.
+func (l *Logger) SampledDrops() map[string]uint64 {
+	if ss := l.rootLogger().sampling.Load(); ss != nil {
+		return ss.snapshot()
+	}
+	return map[string]uint64{}
+}
+
+// check the record should be dropped by sampler. l.mu is held.
+func (l *Logger) sampleDropped(r *Record) bool {
+	s := l.Sampler()
+	if s == nil || r.Level <= FatalLevel {
+		return false
+	}
+
+	if s.Sample(r) {
+		return false
+	}
+
+	l.sampling.Load().drop(SampleKey(r))
+	return true
+}
+
+// emit a meta-record on the sampler has dropped records. l.mu is held.
+func (l *Logger) reportSampling(r *Record) {
+	ss, opts := l.sampling.Load(), l.loadOpts()
+	if ss == nil || opts.sampleReportInterval <= 0 {
+		return
+	}
+
+	drops, total := ss.take(opts.timeClock.Now(), opts.sampleReportInterval)
+	if total == 0 {
+		return
+	}
+
+	mr := l.newRecord()
+	mr.Level = WarnLevel
+	// +1 for the reportSampling() frame, caller will be same as the current record.
+	mr.CallerSkip = r.CallerSkip + 1
+	mr.Message = "slog: sampler dropped " + strconv.FormatUint(total, 10) + " records"
+	mr.Data = M{"sampling_dropped": total, "sampling_drops": drops}
+
+	l.dispatch(mr)
+	l.releaseRecord(mr)
+}
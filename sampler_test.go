@@ -0,0 +1,228 @@
+package slog_test
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gookit/goutil/byteutil"
+	"github.com/gookit/goutil/testutil/assert"
+	"github.com/gookit/slog"
+	"github.com/gookit/slog/handler"
+)
+
+func TestLogger_SetSampler(t *testing.T) {
+	buf := new(byteutil.Buffer)
+	l := slog.NewWithHandlers(handler.NewIOWriter(buf, slog.AllLevels))
+	l.DoNothingOnPanicFatal()
+	assert.Nil(t, l.Sampler())
+	assert.Empty(t, l.SampledDrops())
+
+	// drop all debug records
+	l.SetSampler(slog.SamplerFunc(func(r *slog.Record) bool {
+		return r.Level != slog.DebugLevel
+	}))
+	assert.NotNil(t, l.Sampler())
+
+	l.Debug("debug message")
+	l.Debug("debug message")
+	l.Info("info message")
+	l.Fatal("fatal message")
+
+	str := buf.ResetAndGet()
+	assert.NotContains(t, str, "debug message")
+	assert.Contains(t, str, "info message")
+	assert.Contains(t, str, "fatal message")
+	assert.Eq(t, map[string]uint64{"DEBUG:debug message": 2}, l.SampledDrops())
+}
+
+func TestLogger_SampleReportInterval(t *testing.T) {
+	buf := new(byteutil.Buffer)
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	l := slog.NewWithHandlers(handler.NewIOWriter(buf, slog.AllLevels))
+	l.SampleReportInterval = time.Minute
+	l.TimeClock = func() time.Time { return now }
+	l.SetSampler(slog.SamplerFunc(func(r *slog.Record) bool {
+		return r.Message != "dropped"
+	}))
+
+	l.Info("dropped")
+	buf.Reset()
+	l.Info("dropped")
+	l.Info("dropped")
+	now = now.Add(time.Minute)
+	l.Info("kept message")
+
+	str := buf.ResetAndGet()
+	assert.Contains(t, str, "[WARN] [sampler_test.go:55,TestLogger_SampleReportInterval] slog: sampler dropped 2 records")
+	assert.Contains(t, str, "kept message")
+
+	// no pending drops, no meta-record
+	now = now.Add(time.Minute)
+	l.Info("kept message2")
+	assert.NotContains(t, buf.ResetAndGet(), "sampler dropped")
+
+	l.SetSampler(nil)
+	l.Info("dropped")
+	assert.Contains(t, buf.ResetAndGet(), "dropped")
+	assert.Eq(t, uint64(3), l.SampledDrops()["INFO:dropped"])
+}
+
+func TestBuiltinSamplers(t *testing.T) {
+	newRecord := func(level slog.Level, msg string, tm time.Time) *slog.Record {
+		return &slog.Record{Level: level, Message: msg, Time: tm}
+	}
+	now := time.Now().Truncate(time.Second)
+
+	t.Run("EveryN", func(t *testing.T) {
+		s := slog.EveryN(3)
+		var kept []bool
+		for i := 0; i < 7; i++ {
+			kept = append(kept, s.Sample(newRecord(slog.DebugLevel, "msg", now)))
+		}
+		assert.Eq(t, []bool{true, false, false, true, false, false, true}, kept)
+		// other key is counted separately
+		assert.True(t, s.Sample(newRecord(slog.DebugLevel, "other", now)))
+		assert.True(t, slog.EveryN(1).Sample(newRecord(slog.DebugLevel, "msg", now)))
+	})
+
+	t.Run("RateLimit", func(t *testing.T) {
+		s := slog.RateLimit(map[slog.Level]int{slog.DebugLevel: 2})
+		assert.True(t, s.Sample(newRecord(slog.DebugLevel, "msg1", now)))
+		assert.True(t, s.Sample(newRecord(slog.DebugLevel, "msg2", now)))
+		assert.False(t, s.Sample(newRecord(slog.DebugLevel, "msg3", now)))
+		// not limited level
+		assert.True(t, s.Sample(newRecord(slog.InfoLevel, "msg", now)))
+		// next second
+		assert.True(t, s.Sample(newRecord(slog.DebugLevel, "msg4", now.Add(time.Second))))
+	})
+
+	t.Run("BurstSampler", func(t *testing.T) {
+		s := slog.BurstSampler(time.Second, 2, 3)
+		var kept []bool
+		for i := 0; i < 8; i++ {
+			kept = append(kept, s.Sample(newRecord(slog.DebugLevel, "msg", now)))
+		}
+		assert.Eq(t, []bool{true, true, false, false, true, false, false, true}, kept)
+		// reset on next tick
+		assert.True(t, s.Sample(newRecord(slog.DebugLevel, "msg", now.Add(time.Second))))
+
+		s = slog.BurstSampler(time.Second, 1, 0)
+		assert.True(t, s.Sample(newRecord(slog.DebugLevel, "msg", now)))
+		assert.False(t, s.Sample(newRecord(slog.DebugLevel, "msg", now)))
+	})
+}
+
+func TestLogger_SetSampler_builtin(t *testing.T) {
+	buf := new(byteutil.Buffer)
+	l := slog.NewWithHandlers(handler.NewIOWriter(buf, slog.AllLevels))
+	l.SetSampler(slog.BurstSampler(time.Minute, 2, 0))
+
+	for i := 0; i < 5; i++ {
+		l.Debug("hot path message")
+	}
+	assert.Eq(t, 2, strings.Count(buf.ResetAndGet(), "hot path message"))
+	assert.Eq(t, uint64(3), l.SampledDrops()["DEBUG:hot path message"])
+}
+
+func TestLogger_SetSampler_window(t *testing.T) {
+	buf := new(byteutil.Buffer)
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	l := slog.NewWithHandlers(handler.NewIOWriter(buf, slog.AllLevels))
+	l.Config(func(l *slog.Logger) {
+		l.TimeClock = func() time.Time { return now }
+	})
+
+	// the window advance by the logger clock
+	l.SetSampler(slog.RateLimit(map[slog.Level]int{slog.InfoLevel: 2}))
+	for i := 0; i < 3; i++ {
+		l.Info("rate message")
+		l.Info("rate message")
+		now = now.Add(550 * time.Millisecond)
+	}
+	assert.Eq(t, 4, strings.Count(buf.ResetAndGet(), "rate message"))
+
+	l.SetSampler(slog.BurstSampler(100*time.Millisecond, 1, 0))
+	for i := 0; i < 3; i++ {
+		l.Info("burst message")
+		now = now.Add(150 * time.Millisecond)
+	}
+	assert.Eq(t, 3, strings.Count(buf.ResetAndGet(), "burst message"))
+}
+
+func TestLogger_SampledDrops_maxKeys(t *testing.T) {
+	l := slog.NewWithHandlers(handler.NewIOWriter(new(byteutil.Buffer), slog.AllLevels))
+	l.SetSampler(slog.SamplerFunc(func(r *slog.Record) bool {
+		return false
+	}))
+
+	for i := 0; i < 10; i++ {
+		l.Debug("hot message")
+	}
+	for i := 0; i < slog.SampleMaxKeys*3; i++ {
+		l.Debugf("unique message %d", i)
+	}
+
+	drops := l.SampledDrops()
+	assert.Lte(t, len(drops), slog.SampleMaxKeys+1)
+	assert.Eq(t, uint64(10), drops["DEBUG:hot message"])
+	assert.Gt(t, drops[slog.SampleOverflowKey], uint64(0))
+
+	var total uint64
+	for _, n := range drops {
+		total += n
+	}
+	assert.Eq(t, uint64(10+slog.SampleMaxKeys*3), total)
+}
+
+func TestBuiltinSamplers_distinctKeys(t *testing.T) {
+	now := time.Now().Truncate(time.Second)
+
+	// distinct keys never share the counter
+	s := slog.EveryN(2)
+	b := slog.BurstSampler(time.Minute, 1, 0)
+	for i := 0; i < slog.SampleMaxKeys*3; i++ {
+		r := &slog.Record{Level: slog.DebugLevel, Message: "msg" + strconv.Itoa(i), Time: now}
+		assert.True(t, s.Sample(r), r.Message)
+		assert.True(t, b.Sample(r), r.Message)
+	}
+
+	// the hot key is kept on reached SampleMaxKeys
+	b = slog.BurstSampler(time.Minute, 1, 0)
+	hot := &slog.Record{Level: slog.DebugLevel, Message: "hot", Time: now}
+	assert.True(t, b.Sample(hot))
+	assert.False(t, b.Sample(hot))
+	for i := 0; i < slog.SampleMaxKeys*3; i++ {
+		b.Sample(&slog.Record{Level: slog.DebugLevel, Message: "msg" + strconv.Itoa(i), Time: now})
+	}
+	assert.False(t, b.Sample(hot))
+}
+
+func TestLogger_SampleReportInterval_allDropped(t *testing.T) {
+	buf := new(byteutil.Buffer)
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	l := slog.NewWithHandlers(handler.NewIOWriter(buf, slog.AllLevels))
+	l.Config(func(l *slog.Logger) {
+		l.SampleReportInterval = time.Minute
+		l.TimeClock = func() time.Time { return now }
+	})
+	l.SetSampler(slog.SamplerFunc(func(r *slog.Record) bool {
+		return false
+	}))
+
+	l.Info("dropped")
+	assert.Contains(t, buf.ResetAndGet(), "slog: sampler dropped 1 records")
+
+	l.Info("dropped")
+	l.Info("dropped")
+	assert.Empty(t, buf.ResetAndGet())
+
+	// report on the next dropped record after the interval
+	now = now.Add(time.Minute)
+	l.Info("dropped")
+	str := buf.ResetAndGet()
+	assert.Contains(t, str, "slog: sampler dropped 3 records")
+	assert.NotContains(t, str, "[INFO]")
+	assert.Eq(t, uint64(4), l.SampledDrops()["INFO:dropped"])
+}
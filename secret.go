@@ -0,0 +1,66 @@
+package slog
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// SecretMask the mask text for render the secret value
+const SecretMask = "****"
+
+// SecretValue a wrapper for sensitive value. eg: password, token
+//
+// It is always rendered as "****" by every built-in formatter and fmt,
+// so credentials passed into fields can never leak even if redaction rules miss them.
+type SecretValue struct {
+	val     any
+	showLen bool
+}
+
+// Secret wrap a sensitive value, will be rendered as "****"
+//
+// Usage:
+//
+//	slog.WithField("password", slog.Secret(pwd)).Info("user login")
+func Secret(v any) SecretValue {
+	return SecretValue{val: v}
+}
+
+// SecretWithLen wrap a sensitive value, will be rendered as "****(len=N)".
+// N is the length of the value string.
+func SecretWithLen(v any) SecretValue {
+	return SecretValue{val: v, showLen: true}
+}
+
+// Value get the raw value. NOTICE: dont log it.
+func (s SecretValue) Value() any {
+	return s.val
+}
+
+// String implements fmt.Stringer
+func (s SecretValue) String() string {
+	if s.showLen {
+		return SecretMask + "(len=" + strconv.Itoa(len(fmt.Sprint(s.val))) + ")"
+	}
+	return SecretMask
+}
+
+// GoString implements fmt.GoStringer
+func (s SecretValue) GoString() string {
+	return s.String()
+}
+
+// Format implements fmt.Formatter, render mask for all verbs. eg: %v %+v %#v %s
+func (s SecretValue) Format(f fmt.State, _ rune) {
+	_, _ = f.Write([]byte(s.String()))
+}
+
+// MarshalJSON implements json.Marshaler
+func (s SecretValue) MarshalJSON() ([]byte, error) {
+	return []byte(strconv.Quote(s.String())), nil
+}
+
+// MarshalText implements encoding.TextMarshaler
+func (s SecretValue) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
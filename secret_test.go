@@ -0,0 +1,41 @@
+package slog_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/gookit/goutil/testutil/assert"
+	"github.com/gookit/slog"
+)
+
+func TestSecret(t *testing.T) {
+	s := slog.Secret("my-password")
+	assert.Eq(t, "my-password", s.Value())
+	assert.Eq(t, "****", s.String())
+	assert.NotContains(t, fmt.Sprintf("%v %+v %#v %s %q %x", s, s, s, s, s, s), "my-password")
+	assert.Eq(t, "****(len=11)", slog.SecretWithLen("my-password").String())
+
+	r := newLogRecord("user login")
+	r.Fields = slog.M{"password": s}
+	r.Data = slog.M{"token": slog.Secret("abc"), "nested": slog.M{"key": slog.SecretWithLen("abc")}}
+	r.Extra = slog.M{"cred": struct{ Token slog.SecretValue }{Token: slog.Secret("xyz")}}
+
+	fs := []slog.Formatter{
+		slog.NewJSONFormatter(),
+		slog.NewJSONFormatter(func(f *slog.JSONFormatter) {
+			f.MaxDepth = 5
+		}),
+		slog.NewTextFormatter("{{message}} {{password}} {{data}} {{extra}}"),
+	}
+	for _, f := range fs {
+		bts, err := f.Format(r)
+		assert.NoErr(t, err)
+
+		str := string(bts)
+		assert.StrContains(t, str, "****")
+		assert.StrContains(t, str, "****(len=3)")
+		assert.NotContains(t, str, "my-password")
+		assert.NotContains(t, str, "abc")
+		assert.NotContains(t, str, "xyz")
+	}
+}
@@ -65,8 +65,55 @@ func Reset() {
 // Configure the std logger
 func Configure(fn func(l *SugaredLogger)) { std.Config(fn) }
 
+// SetupFn setup func for the std logger, returns error on setup failed. see MustConfigure()
+type SetupFn func(sl *SugaredLogger) error
+
+// MustConfigure the std logger by setup funcs, will panic on error.
+//
+// Usage:
+//
+//	slog.MustConfigure(
+//		slog.WithConsoleMaxLevel(slog.InfoLevel),
+//		handler.RotateFileSetup("logs/app.log", rotatefile.EveryDay, handler.WithCompress(true)),
+//		handler.JSONFileSetup("logs/app.json"),
+//	)
+func MustConfigure(fns ...SetupFn) {
+	std.Config(func(sl *SugaredLogger) {
+		for _, fn := range fns {
+			goutil.PanicErr(fn(sl))
+		}
+	})
+}
+
+// WithConsoleMaxLevel setup func, set the max level for the std logger console output.
+// NOTICE: the added handlers are not affected.
+func WithConsoleMaxLevel(level Level) SetupFn {
+	return func(sl *SugaredLogger) error {
+		sl.Level = level
+		return nil
+	}
+}
+
+// WithJSONOutput setup func, use JSONFormatter for the std logger console output.
+func WithJSONOutput() SetupFn {
+	return func(sl *SugaredLogger) error {
+		sl.Formatter = NewJSONFormatter()
+		return nil
+	}
+}
+
+// WithHandlers setup func, add handlers to the std logger.
+func WithHandlers(hs ...Handler) SetupFn {
+	return func(sl *SugaredLogger) error {
+		sl.AddHandlers(hs...)
+		return nil
+	}
+}
+
 // SetExitFunc to the std logger
-func SetExitFunc(fn func(code int)) { std.ExitFunc = fn }
+func SetExitFunc(fn func(code int)) {
+	std.Config(func(sl *SugaredLogger) { sl.ExitFunc = fn })
+}
 
 // Exit runs all exit handlers and then terminates the program using os.Exit(code)
 func Exit(code int) { std.Exit(code) }
@@ -97,17 +144,24 @@ func FlushDaemon(onStops ...func()) {
 	std.FlushDaemon(onStops...)
 }
 
+// ReopenAll reopen the output files of the std logger handlers. see Logger.ReopenAll()
+func ReopenAll() error { return std.ReopenAll() }
+
 // StopDaemon stop flush daemon
 func StopDaemon() { std.StopDaemon() }
 
 // SetLogLevel max level for the std logger
-func SetLogLevel(l Level) { std.Level = l }
+func SetLogLevel(l Level) {
+	std.Config(func(sl *SugaredLogger) { sl.Level = l })
+}
 
 // SetFormatter to std logger
-func SetFormatter(f Formatter) { std.Formatter = f }
+func SetFormatter(f Formatter) {
+	std.Config(func(sl *SugaredLogger) { sl.Formatter = f })
+}
 
 // GetFormatter of the std logger
-func GetFormatter() Formatter { return std.Formatter }
+func GetFormatter() Formatter { return std.loadSugaredOpts().formatter }
 
 // AddHandler to the std logger
 func AddHandler(h Handler) { std.AddHandler(h) }
@@ -127,6 +181,9 @@ func AddProcessor(p Processor) { std.AddProcessor(p) }
 // AddProcessors to the logger
 func AddProcessors(ps ...Processor) { std.AddProcessors(ps...) }
 
+// AddContextExtractor to the std logger
+func AddContextExtractor(fns ...ContextExtractor) { std.AddContextExtractor(fns...) }
+
 // -------------------------- New record with log data, fields -----------------------------
 
 // WithExtra new record with extra data
@@ -158,6 +215,16 @@ func WithFields(fields M) *Record {
 	return std.WithFields(fields)
 }
 
+// WithAttrs new record with typed fields
+func WithAttrs(fields ...Field) *Record {
+	return std.WithAttrs(fields...)
+}
+
+// WithTags new record with tags
+func WithTags(tags ...string) *Record {
+	return std.WithTags(tags...)
+}
+
 // WithContext new record with context
 func WithContext(ctx context.Context) *Record {
 	return std.WithContext(ctx)
@@ -214,11 +281,19 @@ func ErrorT(err error) {
 	}
 }
 
-// EStack logs a error message and with call stack.
-// func EStack(args ...any) {
-// 	std.WithExtra(map[string]any{"stack": goinfo.GetCallerInfo(2)}).
-// 		log(ErrorLevel, args)
-// }
+// ErrorStack logs a error at level Error, with the stack trace and the unwrapped error causes.
+func ErrorStack(err error) {
+	if err == nil {
+		return
+	}
+
+	r := std.newRecord()
+	r.EnableStack = true
+	if causes := errorCauses(err); len(causes) > 0 {
+		r.AddField(FieldKeyErrorCauses, causes)
+	}
+	r.log(ErrorLevel, []any{err})
+}
 
 // Debug logs a message at level Debug
 func Debug(args ...any) { std.log(DebugLevel, args) }
@@ -3,9 +3,12 @@ package slog_test
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"os"
 	"strconv"
+	"strings"
 	"sync"
 	"testing"
 	"time"
@@ -41,10 +44,10 @@ func TestStd(t *testing.T) {
 	assert.True(t, ok)
 
 	buf := new(bytes.Buffer)
-	slog.Std().ExitFunc = func(code int) {
+	slog.SetExitFunc(func(code int) {
 		buf.WriteString("Exited,")
 		buf.WriteString(strconv.Itoa(code))
-	}
+	})
 	slog.Exit(34)
 	assert.Eq(t, "Exited,34", buf.String())
 }
@@ -126,6 +129,42 @@ func TestNewSugaredLogger(t *testing.T) {
 	l.Info("info message1")
 }
 
+// count the flush calls
+type flushCounter struct {
+	bytes.Buffer
+	flushed int
+}
+
+func (w *flushCounter) Flush() error {
+	w.flushed++
+	return nil
+}
+
+func TestSidecarMode(t *testing.T) {
+	l := slog.NewStd(slog.SidecarMode)
+	assert.Eq(t, os.Stdout, l.Output)
+	assert.False(t, l.ReportCaller)
+
+	w := new(flushCounter)
+	l.Config(func(sl *slog.SugaredLogger) {
+		sl.Output = w
+	})
+
+	l.Info("sidecar message1")
+	l.WithData(slog.M{"key": "multi\nline"}).Warn("sidecar message2")
+	assert.Eq(t, 2, w.flushed)
+
+	lines := strings.Split(strings.TrimSpace(w.String()), "\n")
+	assert.Len(t, lines, 2)
+	for _, line := range lines {
+		var mp map[string]any
+		assert.NoErr(t, json.Unmarshal([]byte(line), &mp))
+		assert.NotContains(t, mp, "caller")
+	}
+	assert.StrContains(t, lines[1], `"message":"sidecar message2"`)
+	assert.StrContains(t, lines[1], `"data":{"key":"multi\nline"}`)
+}
+
 type logTest struct {
 	*slog.SugaredLogger
 }
@@ -180,6 +219,7 @@ func printLogs(msg string) {
 	slog.PanicErr(errorx.Rawf("Panic Err: %s", msg))
 	slog.ErrorT(errors.New(msg))
 	slog.ErrorT(errorx.Newf("Traced Err: %s", msg))
+	slog.ErrorStack(fmt.Errorf("Stack Err: %w", errors.New(msg)))
 }
 
 func printfLogs(msg string, args ...any) {
@@ -457,3 +497,74 @@ func TestSugaredLogger_Handle(t *testing.T) {
 	assert.Err(t, err)
 	assert.Eq(t, "format error", err.Error())
 }
+
+func TestSugaredLogger_changeFieldsAfterUse(t *testing.T) {
+	buf := byteutil.NewBuffer()
+	l := slog.NewSugared(buf, slog.InfoLevel)
+	l.Info("info message1")
+	assert.StrContains(t, buf.ResetAndGet(), "info message1")
+
+	// change the fields directly, not take effect
+	l.Level = slog.ErrorLevel
+	l.Info("info message2")
+	assert.StrContains(t, buf.ResetAndGet(), "info message2")
+
+	l.Config(func(sl *slog.SugaredLogger) {
+		sl.Level = slog.ErrorLevel
+		sl.Formatter = slog.NewJSONFormatter()
+	})
+	l.Info("info message3")
+	assert.Empty(t, buf.ResetAndGet())
+	l.Error("error message")
+	assert.StrContains(t, buf.ResetAndGet(), `"message":"error message"`)
+}
+
+func TestSugaredLogger_concurrentConfig(t *testing.T) {
+	defer slog.Reset()
+
+	buf := byteutil.NewBuffer()
+	slog.Configure(func(sl *slog.SugaredLogger) {
+		sl.Output = buf
+	})
+
+	var wg sync.WaitGroup
+	stop := make(chan struct{})
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+				}
+
+				slog.Info("std message", i)
+				slog.Std().With(slog.M{"worker": i}).Warn("child message")
+				_ = slog.Std().IsHandling(slog.DebugLevel)
+			}
+		}(i)
+	}
+
+	for i := 0; i < 20; i++ {
+		slog.SetLogLevel(slog.Level(slog.InfoLevel + slog.Level(i%2*100)))
+		if i%2 == 0 {
+			slog.SetFormatter(slog.NewJSONFormatter())
+		} else {
+			slog.SetFormatter(slog.NewTextFormatter())
+		}
+		slog.Configure(func(sl *slog.SugaredLogger) {
+			sl.ReportCaller = i%2 == 0
+			sl.FlushOnWrite = i%2 == 1
+		})
+	}
+
+	close(stop)
+	wg.Wait()
+
+	buf.Reset()
+	slog.Info("final message")
+	assert.StrContains(t, buf.String(), "final message")
+	assert.Eq(t, slog.Level(slog.InfoLevel+100), slog.Std().Level)
+}
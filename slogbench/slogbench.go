@@ -0,0 +1,160 @@
+// Package slogbench provide reusable benchmark scenarios, so the performance regressions
+// across releases, and the comparison with other log libraries(eg: zap, zerolog) are measurable.
+//
+// Implement a Factory for the log library, then run all scenarios in a benchmark:
+//
+//	func BenchmarkGookitSlog(b *testing.B) {
+//		slogbench.Run(b, slogbench.Gookit)
+//	}
+//
+//	func BenchmarkZap(b *testing.B) {
+//		slogbench.Run(b, func(opt slogbench.Options) slogbench.Logger {
+//			return &zapLogger{...}
+//		})
+//	}
+package slogbench
+
+import (
+	"io"
+	"testing"
+	"time"
+
+	"github.com/gookit/slog"
+	"github.com/gookit/slog/handler"
+)
+
+// Message for the benchmark records
+var Message = "The quick brown fox jumps over the lazy dog"
+
+// Options for create the benchmark logger
+type Options struct {
+	// Output writer. default is io.Discard
+	Output io.Writer
+	// JSON use JSON format, otherwise use text format
+	JSON bool
+	// Caller report the caller on records
+	Caller bool
+	// Disabled the logger level is Error, so the Info records are disabled
+	Disabled bool
+}
+
+// Logger the benchmark logger interface, log the message at Info level.
+type Logger interface {
+	Info(msg string, fields []slog.Field)
+}
+
+// Factory create the benchmark logger by options
+type Factory func(opt Options) Logger
+
+// Scenario a benchmark scenario
+type Scenario struct {
+	Name    string
+	Options Options
+	// Fields for each record, can be empty
+	Fields []slog.Field
+}
+
+// TenFields get the 10 fields with different kinds
+func TenFields() []slog.Field {
+	return []slog.Field{
+		slog.String("string", "value"),
+		slog.Int("int", 23),
+		slog.Int64("int64", 1234567890),
+		slog.Uint64("uint64", 9876543210),
+		slog.Float64("float", 123.45),
+		slog.Bool("bool", true),
+		slog.Duration("duration", 3*time.Second),
+		slog.Time("time", time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)),
+		slog.Err(io.EOF),
+		slog.Any("strings", []string{"a", "b", "c"}),
+	}
+}
+
+// Scenarios get all built-in benchmark scenarios:
+//
+//	disabled, and "plain", "10fields", "caller" with text and JSON format.
+func Scenarios() []Scenario {
+	scs := []Scenario{
+		{Name: "disabled", Options: Options{Disabled: true}, Fields: TenFields()},
+	}
+
+	for _, format := range []string{"text", "json"} {
+		isJSON := format == "json"
+		scs = append(scs,
+			Scenario{Name: format + "/plain", Options: Options{JSON: isJSON}},
+			Scenario{Name: format + "/10fields", Options: Options{JSON: isJSON}, Fields: TenFields()},
+			Scenario{Name: format + "/caller", Options: Options{JSON: isJSON, Caller: true}},
+		)
+	}
+	return scs
+}
+
+// Run all built-in scenarios as sub-benchmarks
+func Run(b *testing.B, f Factory) {
+	for _, sc := range Scenarios() {
+		sc := sc
+		b.Run(sc.Name, func(b *testing.B) {
+			RunScenario(b, sc, f)
+		})
+	}
+}
+
+// RunScenario run a benchmark scenario
+func RunScenario(b *testing.B, sc Scenario, f Factory) {
+	opt := sc.Options
+	if opt.Output == nil {
+		opt.Output = io.Discard
+	}
+	l := f(opt)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		l.Info(Message, sc.Fields)
+	}
+}
+
+// Gookit the Factory for gookit/slog
+func Gookit(opt Options) Logger {
+	level := slog.InfoLevel
+	if opt.Disabled {
+		level = slog.ErrorLevel
+	}
+	if opt.Output == nil {
+		opt.Output = io.Discard
+	}
+
+	h := handler.NewSimple(opt.Output, level)
+	if opt.JSON {
+		h.SetFormatter(slog.NewJSONFormatter())
+	}
+
+	l := slog.NewWithHandlers(h)
+	l.ReportCaller = opt.Caller
+	return &gookitLogger{l: l, json: opt.JSON}
+}
+
+type gookitLogger struct {
+	l    *slog.Logger
+	json bool
+}
+
+// Info log the message with fields. the text format only output the Data by default template,
+// so the fields will be added as Data on text format, and as Attrs on JSON format.
+func (g *gookitLogger) Info(msg string, fields []slog.Field) {
+	if len(fields) == 0 {
+		g.l.Info(msg)
+		return
+	}
+
+	if g.json {
+		g.l.WithAttrs(fields...).Info(msg)
+		return
+	}
+
+	data := make(slog.M, len(fields))
+	for _, field := range fields {
+		data[field.Key] = field.Value()
+	}
+	g.l.WithData(data).Info(msg)
+}
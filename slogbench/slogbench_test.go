@@ -0,0 +1,42 @@
+package slogbench_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/gookit/goutil/testutil/assert"
+	"github.com/gookit/slog/slogbench"
+)
+
+func TestScenarios(t *testing.T) {
+	scs := slogbench.Scenarios()
+	assert.Len(t, scs, 7)
+	assert.Eq(t, "disabled", scs[0].Name)
+	assert.True(t, scs[0].Options.Disabled)
+	assert.Len(t, slogbench.TenFields(), 10)
+
+	for _, sc := range scs {
+		buf := new(bytes.Buffer)
+		opt := sc.Options
+		opt.Output = buf
+
+		slogbench.Gookit(opt).Info(slogbench.Message, sc.Fields)
+		str := buf.String()
+		if opt.Disabled {
+			assert.Empty(t, str, sc.Name)
+			continue
+		}
+
+		assert.StrContains(t, str, slogbench.Message, sc.Name)
+		if opt.JSON {
+			assert.StrContains(t, str, `"message":`, sc.Name)
+		}
+		if len(sc.Fields) > 0 {
+			assert.StrContains(t, str, "uint64", sc.Name)
+		}
+	}
+}
+
+func BenchmarkGookit(b *testing.B) {
+	slogbench.Run(b, slogbench.Gookit)
+}
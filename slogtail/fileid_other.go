@@ -0,0 +1,10 @@
+//go:build windows || plan9
+
+package slogtail
+
+import "os"
+
+// the file identity is not supported, only check the file size on restore.
+func fileID(_ os.FileInfo) (dev, ino uint64, ok bool) {
+	return 0, 0, false
+}
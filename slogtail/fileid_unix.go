@@ -0,0 +1,17 @@
+//go:build !windows && !plan9
+
+package slogtail
+
+import (
+	"os"
+	"syscall"
+)
+
+// get the device and inode of the file
+func fileID(fi os.FileInfo) (dev, ino uint64, ok bool) {
+	st, ok := fi.Sys().(*syscall.Stat_t)
+	if !ok {
+		return 0, 0, false
+	}
+	return uint64(st.Dev), uint64(st.Ino), true
+}
@@ -0,0 +1,474 @@
+// Package slogtail tail the existing log files and re-emit the lines as records into the logger handlers,
+// turn slog into a lightweight log shipping agent.
+//
+// It is aware of the file rotation(rename and re-create, copy-truncate), and can checkpoint the read offsets
+// with the file identity(device, inode and size) to a file, so the lines are not lost or duplicated on restart.
+//
+// Usage:
+//
+//	l := slog.NewWithHandlers(handler.NewFluentHandler(...))
+//	t := slogtail.New(l, func(opt *slogtail.Options) {
+//		opt.Files = []string{"/var/log/app/error.log"}
+//		opt.CheckpointFile = "/var/lib/slogtail/offsets.json"
+//		opt.ParseFunc = slogtail.ParseJSONLine
+//	})
+//
+//	// block until the ctx is done
+//	err := t.Run(ctx)
+package slogtail
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/gookit/goutil/fsutil"
+	"github.com/gookit/slog"
+)
+
+// FieldKeyFile the field key for the source file path of the lines
+const FieldKeyFile = "file"
+
+// Line the parsed log line
+type Line struct {
+	// Time of the line. if is zero, will use the time of read it.
+	Time   time.Time
+	Level  slog.Level
+	Msg    string
+	Fields slog.M
+}
+
+// ParseFunc parse a line to the record data. the line is not contains the trailing newline.
+type ParseFunc func(line []byte) (*Line, error)
+
+// Options for the Tailer
+type Options struct {
+	// Files the logfile paths for tail, support glob pattern. eg: "/var/log/app/*.log"
+	Files []string
+	// CheckpointFile storage the read offsets and the file identity, will save on each poll. default is empty, not save.
+	CheckpointFile string
+	// PollInterval the interval for check the new lines. default is 500ms
+	PollInterval time.Duration
+	// FromStart read the new found files from start, on the offset is not in checkpoint.
+	// default is false, read from end of the files.
+	FromStart bool
+	// Level for the lines on use the default parse func. default is slog.InfoLevel
+	Level slog.Level
+	// ParseFunc parse the lines. default use the whole line as message.
+	ParseFunc ParseFunc
+	// MaxLineSize max bytes for a line, the too long line will be split. default is 64KB
+	MaxLineSize int
+}
+
+// Tailer tail the log files and re-emit the lines to the logger. see New()
+type Tailer struct {
+	l   *slog.Logger
+	opt *Options
+	// the tailed files, key is the file path
+	files map[string]*tailFile
+	// the loaded checkpoints, key is the file path
+	offsets map[string]*checkpoint
+}
+
+// checkpoint of a file. the file is identified by the device and inode, and the size on saved.
+type checkpoint struct {
+	Offset int64  `json:"offset"`
+	Size   int64  `json:"size"`
+	Dev    uint64 `json:"dev,omitempty"`
+	Ino    uint64 `json:"ino,omitempty"`
+}
+
+// check the opened file is the checkpoint file, and not truncated after saved.
+func (c *checkpoint) match(fi os.FileInfo) bool {
+	if dev, ino, ok := fileID(fi); ok && c.Ino != 0 && (dev != c.Dev || ino != c.Ino) {
+		return false
+	}
+	return fi.Size() >= c.Size
+}
+
+type tailFile struct {
+	path string
+	file *os.File
+	// the read offset of complete lines
+	offset int64
+	// the incomplete line at end
+	partial []byte
+	// the loaded checkpoint, check it on open the file.
+	ckpt *checkpoint
+}
+
+// New create a Tailer for the logger, the lines will be written by slog.Logger.WriteRecord()
+func New(l *slog.Logger, fns ...func(opt *Options)) *Tailer {
+	opt := &Options{
+		PollInterval: 500 * time.Millisecond,
+		Level:        slog.InfoLevel,
+		MaxLineSize:  64 * 1024,
+	}
+	for _, fn := range fns {
+		fn(opt)
+	}
+
+	return &Tailer{
+		l:     l,
+		opt:   opt,
+		files: make(map[string]*tailFile),
+	}
+}
+
+// Run poll the files until the ctx is done, then save checkpoint and close the files.
+//
+// The poll errors will be reported by slog.ReportInternal(), not stop the running.
+func (t *Tailer) Run(ctx context.Context) error {
+	if err := t.loadCheckpoint(); err != nil {
+		return err
+	}
+
+	tk := time.NewTicker(t.opt.PollInterval)
+	defer tk.Stop()
+
+	for {
+		if err := t.Poll(); err != nil {
+			slog.ReportInternal(slog.ErrorLevel, "slogtail: poll files error:", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return t.Close()
+		case <-tk.C:
+		}
+	}
+}
+
+// Poll read the new lines from all files once, then save the checkpoint. returns the first error.
+func (t *Tailer) Poll() error {
+	if t.offsets == nil {
+		if err := t.loadCheckpoint(); err != nil {
+			return err
+		}
+	}
+
+	paths, err := t.findFiles()
+	if err != nil {
+		return err
+	}
+
+	for _, fpath := range paths {
+		if err1 := t.pollFile(fpath); err1 != nil && err == nil {
+			err = err1
+		}
+	}
+
+	if err1 := t.SaveCheckpoint(); err1 != nil && err == nil {
+		err = err1
+	}
+	return err
+}
+
+// Offsets get the read offsets of the files
+func (t *Tailer) Offsets() map[string]int64 {
+	mp := make(map[string]int64, len(t.files))
+	for fpath, tf := range t.files {
+		if tf.offset >= 0 {
+			mp[fpath] = tf.offset
+		}
+	}
+	return mp
+}
+
+// SaveCheckpoint save the read offsets and the file identity to the Options.CheckpointFile
+func (t *Tailer) SaveCheckpoint() error {
+	if t.opt.CheckpointFile == "" {
+		return nil
+	}
+
+	mp := make(map[string]*checkpoint, len(t.files))
+	for fpath, tf := range t.files {
+		if tf.offset < 0 {
+			continue
+		}
+
+		ck := &checkpoint{Offset: tf.offset}
+		if tf.file != nil {
+			if fi, err := tf.file.Stat(); err == nil {
+				ck.Size = fi.Size()
+				ck.Dev, ck.Ino, _ = fileID(fi)
+			}
+		}
+		mp[fpath] = ck
+	}
+
+	bs, err := json.Marshal(mp)
+	if err != nil {
+		return err
+	}
+
+	// write to temp file then rename, avoid the broken checkpoint
+	tmpFile := t.opt.CheckpointFile + ".tmp"
+	if err = fsutil.MkParentDir(tmpFile); err != nil {
+		return err
+	}
+	if err = os.WriteFile(tmpFile, bs, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmpFile, t.opt.CheckpointFile)
+}
+
+// Close save checkpoint and close the opened files
+func (t *Tailer) Close() error {
+	err := t.SaveCheckpoint()
+	for _, tf := range t.files {
+		if tf.file != nil {
+			_ = tf.file.Close()
+			tf.file = nil
+		}
+	}
+	return err
+}
+
+func (t *Tailer) loadCheckpoint() error {
+	t.offsets = make(map[string]*checkpoint)
+	if t.opt.CheckpointFile == "" {
+		return nil
+	}
+
+	bs, err := os.ReadFile(t.opt.CheckpointFile)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+
+	var mp map[string]json.RawMessage
+	if err = json.Unmarshal(bs, &mp); err != nil {
+		return err
+	}
+
+	for fpath, raw := range mp {
+		ck := &checkpoint{}
+		// compatible with the old checkpoint, only has the offset
+		if err = json.Unmarshal(raw, &ck.Offset); err != nil {
+			if err = json.Unmarshal(raw, ck); err != nil {
+				return err
+			}
+		}
+		t.offsets[fpath] = ck
+	}
+	return nil
+}
+
+func (t *Tailer) findFiles() ([]string, error) {
+	var paths []string
+	for _, pattern := range t.opt.Files {
+		matches, err := filepath.Glob(pattern)
+		if err != nil {
+			return nil, err
+		}
+		paths = append(paths, matches...)
+	}
+	return paths, nil
+}
+
+// read the new lines of a file, handle the file rotation.
+func (t *Tailer) pollFile(fpath string) error {
+	tf, ok := t.files[fpath]
+	if !ok {
+		tf = &tailFile{path: fpath, offset: -1}
+		if ck, ok := t.offsets[fpath]; ok {
+			tf.offset, tf.ckpt = ck.Offset, ck
+		} else if t.opt.FromStart {
+			tf.offset = 0
+		}
+		t.files[fpath] = tf
+	}
+
+	if tf.file == nil {
+		if err := t.openFile(tf); err != nil {
+			return err
+		}
+	}
+
+	// read the new lines from current file
+	if err := t.readLines(tf); err != nil {
+		return err
+	}
+
+	fi, err := os.Stat(fpath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil // rotated, wait for the new file is created
+		}
+		return err
+	}
+
+	cur, err := tf.file.Stat()
+	if err != nil {
+		return err
+	}
+
+	// rotated: the file is renamed and re-created.
+	// drain the old file to EOF, the lines may be written after the read above.
+	if !os.SameFile(fi, cur) {
+		if err := t.readLines(tf); err != nil {
+			return err
+		}
+
+		t.flushPartial(tf)
+		_ = tf.file.Close()
+		tf.file, tf.offset = nil, 0
+		if err := t.openFile(tf); err != nil {
+			return err
+		}
+		return t.readLines(tf)
+	}
+
+	// truncated: eg: by copy-truncate
+	if fi.Size() < tf.offset+int64(len(tf.partial)) {
+		tf.offset, tf.partial = 0, tf.partial[:0]
+		return t.readLines(tf)
+	}
+	return nil
+}
+
+func (t *Tailer) openFile(tf *tailFile) error {
+	f, err := os.Open(tf.path)
+	if err != nil {
+		return err
+	}
+
+	fi, err := f.Stat()
+	if err != nil {
+		_ = f.Close()
+		return err
+	}
+
+	if tf.offset < 0 {
+		// read from the end
+		tf.offset = fi.Size()
+	} else if tf.offset > fi.Size() || (tf.ckpt != nil && !tf.ckpt.match(fi)) {
+		// the file is truncated or rotated after the checkpoint
+		tf.offset = 0
+	}
+	tf.ckpt = nil
+
+	tf.file = f
+	tf.partial = tf.partial[:0]
+	return nil
+}
+
+// read the new data from offset, emit the complete lines.
+func (t *Tailer) readLines(tf *tailFile) error {
+	buf := make([]byte, 32*1024)
+	pos := tf.offset + int64(len(tf.partial))
+
+	for {
+		n, err := tf.file.ReadAt(buf, pos)
+		if n > 0 {
+			pos += int64(n)
+			t.consume(tf, buf[:n])
+		}
+
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func (t *Tailer) consume(tf *tailFile, data []byte) {
+	for len(data) > 0 {
+		i := bytes.IndexByte(data, '\n')
+		if i < 0 {
+			tf.partial = append(tf.partial, data...)
+			// split the too long line
+			if len(tf.partial) >= t.opt.MaxLineSize {
+				t.flushPartial(tf)
+			}
+			return
+		}
+
+		var line []byte
+		if len(tf.partial) > 0 {
+			line = append(tf.partial, data[:i]...)
+		} else {
+			line = data[:i]
+		}
+
+		tf.offset += int64(len(tf.partial) + i + 1)
+		tf.partial = tf.partial[:0]
+		data = data[i+1:]
+		t.emit(tf, line)
+	}
+}
+
+// emit the incomplete line, on the file is rotated or the line is too long.
+func (t *Tailer) flushPartial(tf *tailFile) {
+	if len(tf.partial) > 0 {
+		tf.offset += int64(len(tf.partial))
+		t.emit(tf, tf.partial)
+		tf.partial = tf.partial[:0]
+	}
+}
+
+func (t *Tailer) emit(tf *tailFile, line []byte) {
+	line = bytes.TrimSuffix(line, []byte{'\r'})
+	if len(line) == 0 {
+		return
+	}
+
+	var ln *Line
+	if t.opt.ParseFunc != nil {
+		var err error
+		if ln, err = t.opt.ParseFunc(line); err != nil {
+			slog.ReportInternal(slog.WarnLevel, "slogtail: parse line error:", err, "file:", tf.path)
+			ln = nil
+		}
+	}
+	if ln == nil {
+		ln = &Line{Level: t.opt.Level, Msg: string(line)}
+	}
+
+	fields := make(slog.M, len(ln.Fields)+1)
+	for k, v := range ln.Fields {
+		fields[k] = v
+	}
+	fields[FieldKeyFile] = tf.path
+
+	t.l.WriteRecord(t.l.NewRecordAt(ln.Time, ln.Level, ln.Msg, fields))
+}
+
+// ParseJSONLine parse the line output by slog.JSONFormatter with the default field names and time format.
+//
+// The "datetime", "level", "message" are parsed to the Line, the others are added to Line.Fields.
+func ParseJSONLine(line []byte) (*Line, error) {
+	var mp map[string]any
+	if err := json.Unmarshal(line, &mp); err != nil {
+		return nil, err
+	}
+
+	ln := &Line{Level: slog.InfoLevel, Fields: make(slog.M, len(mp))}
+	for k, v := range mp {
+		s, isStr := v.(string)
+		switch {
+		case k == slog.FieldKeyMessage && isStr:
+			ln.Msg = s
+		case k == slog.FieldKeyLevel && isStr:
+			ln.Level = slog.LevelByName(s)
+		case k == slog.FieldKeyDatetime && isStr:
+			if t, err := time.ParseInLocation(slog.DefaultTimeFormat, s, time.Local); err == nil {
+				ln.Time = t
+			} else {
+				ln.Fields[k] = v
+			}
+		default:
+			ln.Fields[k] = v
+		}
+	}
+	return ln, nil
+}
@@ -0,0 +1,164 @@
+package slogtail_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gookit/goutil/byteutil"
+	"github.com/gookit/goutil/testutil/assert"
+	"github.com/gookit/slog"
+	"github.com/gookit/slog/handler"
+	"github.com/gookit/slog/slogtail"
+)
+
+func newTestLogger() (*slog.Logger, *byteutil.Buffer) {
+	buf := byteutil.NewBuffer()
+	h := handler.NewIOWriter(buf, slog.AllLevels)
+	h.SetFormatter(slog.NewJSONFormatter())
+	return slog.NewWithHandlers(h), buf
+}
+
+func appendFile(t *testing.T, fpath, s string) {
+	f, err := os.OpenFile(fpath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	assert.NoErr(t, err)
+	_, err = f.WriteString(s)
+	assert.NoErr(t, err)
+	assert.NoErr(t, f.Close())
+}
+
+func TestTailer_Poll(t *testing.T) {
+	dir := t.TempDir()
+	logfile := filepath.Join(dir, "app.log")
+	ckFile := filepath.Join(dir, "offsets.json")
+	appendFile(t, logfile, "old line\n")
+
+	l, buf := newTestLogger()
+	newTailer := func() *slogtail.Tailer {
+		return slogtail.New(l, func(opt *slogtail.Options) {
+			opt.Files = []string{filepath.Join(dir, "*.log")}
+			opt.CheckpointFile = ckFile
+			opt.Level = slog.WarnLevel
+		})
+	}
+
+	// read from end by default
+	tl := newTailer()
+	assert.NoErr(t, tl.Poll())
+	assert.Empty(t, buf.String())
+
+	appendFile(t, logfile, "line 1\nline 2\nincomplete")
+	assert.NoErr(t, tl.Poll())
+	str := buf.String()
+	assert.StrContains(t, str, `"message":"line 1"`)
+	assert.StrContains(t, str, `"message":"line 2"`)
+	assert.StrContains(t, str, `"level":"WARN"`)
+	assert.StrContains(t, str, `"file":"`+logfile+`"`)
+	assert.NotContains(t, str, "incomplete")
+	assert.Eq(t, int64(len("old line\nline 1\nline 2\n")), tl.Offsets()[logfile])
+
+	// complete the line
+	buf.Reset()
+	appendFile(t, logfile, " line 3\n")
+	assert.NoErr(t, tl.Poll())
+	assert.StrContains(t, buf.String(), `"message":"incomplete line 3"`)
+
+	// rotate by rename, then write to the new file
+	buf.Reset()
+	appendFile(t, logfile, "before rotate\n")
+	assert.NoErr(t, os.Rename(logfile, filepath.Join(dir, "app.log.1")))
+	appendFile(t, logfile, "after rotate\n")
+	assert.NoErr(t, tl.Poll())
+	str = buf.String()
+	assert.StrContains(t, str, `"message":"before rotate"`)
+	assert.StrContains(t, str, `"message":"after rotate"`)
+	assert.Eq(t, int64(len("after rotate\n")), tl.Offsets()[logfile])
+
+	// truncated
+	buf.Reset()
+	assert.NoErr(t, os.Truncate(logfile, 0))
+	appendFile(t, logfile, "truncated\n")
+	assert.NoErr(t, tl.Poll())
+	assert.StrContains(t, buf.String(), `"message":"truncated"`)
+	assert.NoErr(t, tl.Close())
+
+	// restart from the checkpoint
+	buf.Reset()
+	appendFile(t, logfile, "on stopped\n")
+	tl = newTailer()
+	assert.NoErr(t, tl.Poll())
+	str = buf.String()
+	assert.Eq(t, 1, strings.Count(str, "\n"))
+	assert.StrContains(t, str, `"message":"on stopped"`)
+	assert.NoErr(t, tl.Close())
+}
+
+func TestTailer_checkpoint(t *testing.T) {
+	dir := t.TempDir()
+	logfile := filepath.Join(dir, "app.log")
+	ckFile := filepath.Join(dir, "offsets.json")
+	appendFile(t, logfile, "line 1\n")
+
+	l, buf := newTestLogger()
+	newTailer := func() *slogtail.Tailer {
+		return slogtail.New(l, func(opt *slogtail.Options) {
+			opt.Files = []string{logfile}
+			opt.CheckpointFile = ckFile
+		})
+	}
+
+	tl := newTailer()
+	assert.NoErr(t, tl.Poll())
+	assert.NoErr(t, tl.Close())
+	bs, err := os.ReadFile(ckFile)
+	assert.NoErr(t, err)
+	assert.StrContains(t, string(bs), `"offset":7,"size":7`)
+
+	// rotated on stopped, the new file is larger than the offset. read the new file from start.
+	assert.NoErr(t, os.Rename(logfile, logfile+".1"))
+	appendFile(t, logfile, "new file line 1\n")
+	tl = newTailer()
+	assert.NoErr(t, tl.Poll())
+	assert.StrContains(t, buf.ResetGet(), `"message":"new file line 1"`)
+	assert.NoErr(t, tl.Close())
+
+	// compatible with the old checkpoint format
+	assert.NoErr(t, os.WriteFile(ckFile, []byte(`{"`+logfile+`":16}`), 0644))
+	appendFile(t, logfile, "new file line 2\n")
+	tl = newTailer()
+	assert.NoErr(t, tl.Poll())
+	str := buf.ResetGet()
+	assert.Eq(t, 1, strings.Count(str, "\n"))
+	assert.StrContains(t, str, `"message":"new file line 2"`)
+	assert.NoErr(t, tl.Close())
+}
+
+func TestTailer_Run(t *testing.T) {
+	dir := t.TempDir()
+	logfile := filepath.Join(dir, "app.json")
+	appendFile(t, logfile, `{"datetime":"2023/05/06T07:08:09.000","level":"ERROR","message":"db error","uid":23}`+"\n")
+	appendFile(t, logfile, "not json\n")
+
+	l, buf := newTestLogger()
+	tl := slogtail.New(l, func(opt *slogtail.Options) {
+		opt.Files = []string{logfile}
+		opt.FromStart = true
+		opt.PollInterval = 10 * time.Millisecond
+		opt.ParseFunc = slogtail.ParseJSONLine
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	assert.NoErr(t, tl.Run(ctx))
+
+	str := buf.String()
+	assert.StrContains(t, str, `"datetime":"2023/05/06T07:08:09.000"`)
+	assert.StrContains(t, str, `"level":"ERROR"`)
+	assert.StrContains(t, str, `"message":"db error"`)
+	assert.StrContains(t, str, `"uid":23`)
+	// fallback to the raw line on parse error
+	assert.StrContains(t, str, `"message":"not json"`)
+}
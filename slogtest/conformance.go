@@ -0,0 +1,185 @@
+package slogtest
+
+import (
+	"fmt"
+	"reflect"
+	"runtime"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gookit/slog"
+)
+
+// HandlerOptions for the TestHandler()
+type HandlerOptions struct {
+	// Workers the goroutines number for the concurrency test. default is 8
+	Workers int
+	// Records the records number of each worker. default is 50
+	Records int
+	// Parallel call the Handle() in parallel without lock. default is false
+	//
+	// By default, the calls are serialized like the logger does, and Flush() is called concurrently.
+	// Enable it on the handler is shared by multi loggers.
+	Parallel bool
+}
+
+// TestHandler run the conformance tests for a slog.Handler, useful for the third-party handler authors.
+//
+// It checks:
+//
+//   - handles at least one level, and Handle() returns no error on the handled levels.
+//   - Handle() must not modify the record. the record is reused by the logger,
+//     so the handler should Clone() it if keep it after Handle() returned.
+//   - Handle() is safe for concurrent use, the calls are serialized like the logger does,
+//     unless HandlerOptions.Parallel=true. run tests with -race for detect the data races.
+//   - Flush() can be called multiple times, and Close() returns no error.
+//
+// NOTICE: the handler will be closed after the tests.
+//
+// Usage:
+//
+//	func TestMyHandler(t *testing.T) {
+//		slogtest.TestHandler(t, NewMyHandler())
+//	}
+func TestHandler(t *testing.T, h slog.Handler, fns ...func(opt *HandlerOptions)) {
+	t.Helper()
+	opt := &HandlerOptions{Workers: 8, Records: 50}
+	for _, fn := range fns {
+		fn(opt)
+	}
+
+	var levels []slog.Level
+	for _, lv := range slog.AllLevels {
+		if h.IsHandling(lv) {
+			levels = append(levels, lv)
+		}
+	}
+	if len(levels) == 0 {
+		t.Fatalf("slogtest: handler %s not handling any level", slog.HandlerName(h))
+	}
+
+	t.Run("Handle", func(t *testing.T) {
+		for _, lv := range levels {
+			r := newRecord(lv, "handle message")
+			snap := r.Clone()
+
+			if err := h.Handle(r); err != nil {
+				t.Errorf("slogtest: handle record of level %s returns error: %v", lv.Name(), err)
+			}
+			if diff := diffRecord(snap, r); diff != "" {
+				t.Errorf("slogtest: the record is modified on handle, %s", diff)
+			}
+		}
+	})
+
+	t.Run("Concurrent", func(t *testing.T) {
+		var mu sync.Mutex
+		var wg sync.WaitGroup
+		errs := make(chan error, opt.Workers+1)
+
+		// lock like the logger does
+		call := func(fn func() error) error {
+			if opt.Parallel {
+				return fn()
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			return fn()
+		}
+
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			for i := 0; i < opt.Records; i++ {
+				if err := call(h.Flush); err != nil {
+					errs <- err
+					return
+				}
+			}
+		}()
+
+		for i := 0; i < opt.Workers; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				for j := 0; j < opt.Records; j++ {
+					r := newRecord(levels[(i+j)%len(levels)], fmt.Sprintf("concurrent message %d-%d", i, j))
+					if err := call(func() error { return h.Handle(r) }); err != nil {
+						errs <- err
+						return
+					}
+				}
+			}(i)
+		}
+
+		wg.Wait()
+		<-done
+		close(errs)
+		for err := range errs {
+			t.Errorf("slogtest: concurrent handle returns error: %v", err)
+		}
+	})
+
+	t.Run("Flush", func(t *testing.T) {
+		for i := 0; i < 2; i++ {
+			if err := h.Flush(); err != nil {
+				t.Errorf("slogtest: flush returns error: %v", err)
+			}
+		}
+	})
+
+	t.Run("Close", func(t *testing.T) {
+		if err := h.Close(); err != nil {
+			t.Errorf("slogtest: close returns error: %v", err)
+		}
+	})
+}
+
+// create a record like the logger created
+func newRecord(lv slog.Level, msg string) *slog.Record {
+	r := &slog.Record{
+		Time:    time.Now(),
+		Level:   lv,
+		Channel: "slogtest",
+		Message: msg,
+		Fields:  slog.M{"field1": "value1", "num": 23},
+		Data:    slog.M{"key0": 234, "sub": slog.M{"key1": "val1"}},
+		Extra:   slog.M{"source": "slogtest"},
+		Attrs:   []slog.Field{slog.String("attr1", "value1")},
+		Tags:    []string{"tag1", "tag2"},
+	}
+
+	if pc, _, _, ok := runtime.Caller(1); ok {
+		frame, _ := runtime.CallersFrames([]uintptr{pc}).Next()
+		r.Caller = &frame
+	}
+
+	r.Init(false)
+	return r
+}
+
+// compare the exported fields of the records
+func diffRecord(want, got *slog.Record) string {
+	checks := []struct {
+		name      string
+		want, got any
+	}{
+		{"Time", want.Time, got.Time},
+		{"Level", want.Level, got.Level},
+		{"Channel", want.Channel, got.Channel},
+		{"Message", want.Message, got.Message},
+		{"Fields", want.Fields, got.Fields},
+		{"Data", want.Data, got.Data},
+		{"Extra", want.Extra, got.Extra},
+		{"Attrs", want.Attrs, got.Attrs},
+		{"Tags", want.Tags, got.Tags},
+	}
+
+	for _, c := range checks {
+		if !reflect.DeepEqual(c.want, c.got) {
+			return fmt.Sprintf("field %s: want %v, got %v", c.name, c.want, c.got)
+		}
+	}
+	return ""
+}
@@ -0,0 +1,199 @@
+// Package slogtest provide helpers for test the log output, capture the output of a logger,
+// normalize the volatile parts (time, caller, ids) and compare it with the golden files.
+//
+// Usage:
+//
+//	func TestOrderLog(t *testing.T) {
+//		c := slogtest.NewCapture()
+//		c.Logger.Info("order created", order.ID)
+//		c.AssertGolden(t, "order_created")
+//	}
+//
+// Update the golden files by run tests with the env: SLOGTEST_UPDATE=1 go test ./...
+package slogtest
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/gookit/slog"
+	"github.com/gookit/slog/handler"
+)
+
+var (
+	// GoldenDir the directory of the golden files
+	GoldenDir = "testdata"
+	// GoldenExt the file extension of the golden files
+	GoldenExt = ".golden"
+	// UpdateGolden write the actual output to the golden files, instead of comparing.
+	// default is enabled by the env SLOGTEST_UPDATE=1
+	UpdateGolden = os.Getenv("SLOGTEST_UPDATE") == "1"
+)
+
+// Normalizer replace the matched volatile contents to a placeholder
+type Normalizer struct {
+	Pattern *regexp.Regexp
+	Replace string
+}
+
+// NewNormalizer create a Normalizer. will panic on pattern is invalid.
+func NewNormalizer(pattern, replace string) Normalizer {
+	return Normalizer{Pattern: regexp.MustCompile(pattern), Replace: replace}
+}
+
+// DefaultNormalizers for normalize the time, caller and ids in the log output
+var DefaultNormalizers = []Normalizer{
+	// eg: 2023/01/02T15:04:05.000, 2023-01-02 15:04:05, 2023-01-02T15:04:05.123+08:00
+	NewNormalizer(`\d{4}[-/]\d{2}[-/]\d{2}[T ]\d{2}:\d{2}:\d{2}(\.\d+)?(Z|[+-]\d{2}:?\d{2})?`, "<TIME>"),
+	// eg: /path/to/main.go:23, main.go:23
+	NewNormalizer(`[\w./\\-]*\.go:\d+`, "<CALLER>"),
+	// eg: 7f2c9a3e-5b4d-4c1a-9e8f-0a1b2c3d4e5f
+	NewNormalizer(`\b[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}\b`, "<UUID>"),
+	// eg: trace id, span id
+	NewNormalizer(`\b[0-9a-f]{16,}\b`, "<ID>"),
+}
+
+// Normalize the volatile contents in the log output. default use the DefaultNormalizers
+func Normalize(s string, ns ...Normalizer) string {
+	if len(ns) == 0 {
+		ns = DefaultNormalizers
+	}
+
+	for _, n := range ns {
+		s = n.Pattern.ReplaceAllString(s, n.Replace)
+	}
+	return s
+}
+
+// GoldenFile get the golden file path by name
+func GoldenFile(name string) string {
+	return filepath.Join(GoldenDir, name+GoldenExt)
+}
+
+// AssertGolden compare the contents with the golden file, will report the first different line on mismatch.
+// If UpdateGolden=true, will write the contents to the golden file.
+func AssertGolden(t testing.TB, name, got string) bool {
+	t.Helper()
+	file := GoldenFile(name)
+
+	if UpdateGolden {
+		if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+			t.Fatalf("slogtest: create golden dir error: %v", err)
+		}
+		if err := os.WriteFile(file, []byte(got), 0644); err != nil {
+			t.Fatalf("slogtest: write golden file error: %v", err)
+		}
+		return true
+	}
+
+	bs, err := os.ReadFile(file)
+	if err != nil {
+		t.Errorf("slogtest: read golden file error: %v (run with SLOGTEST_UPDATE=1 to create it)", err)
+		return false
+	}
+
+	want := string(bs)
+	if want == got {
+		return true
+	}
+
+	t.Errorf("slogtest: output not match the golden file %s\n%s", file, diffLine(want, got))
+	return false
+}
+
+// find the first different line
+func diffLine(want, got string) string {
+	wls := strings.Split(want, "\n")
+	gls := strings.Split(got, "\n")
+
+	for i := 0; ; i++ {
+		if i >= len(wls) || i >= len(gls) || wls[i] != gls[i] {
+			return "line " + strconv.Itoa(i+1) + ":\n  want: " + lineAt(wls, i) + "\n  got:  " + lineAt(gls, i)
+		}
+	}
+}
+
+func lineAt(lines []string, i int) string {
+	if i >= len(lines) {
+		return "<EOF>"
+	}
+	return strconv.Quote(lines[i])
+}
+
+// Capture the log output of a logger, for assertion in tests.
+type Capture struct {
+	// Logger the logger for write logs, the output will be captured.
+	Logger *slog.Logger
+	// Handler the capture handler, can be changed the formatter or added to other loggers.
+	Handler *handler.IOWriterHandler
+	// Normalizers for normalize the output. default is DefaultNormalizers
+	Normalizers []Normalizer
+
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+// NewCapture create new Capture, handle all levels and use the slog.TextFormatter by default.
+//
+// Usage:
+//
+//	c := slogtest.NewCapture(func(c *slogtest.Capture) {
+//		c.Handler.SetFormatter(slog.NewJSONFormatter())
+//	})
+func NewCapture(fns ...func(c *Capture)) *Capture {
+	c := &Capture{Normalizers: DefaultNormalizers}
+	c.Handler = handler.NewIOWriter(c, slog.AllLevels)
+	c.Logger = slog.NewWithHandlers(c.Handler)
+
+	for _, fn := range fns {
+		fn(c)
+	}
+	return c
+}
+
+// Write the log contents, implements the io.Writer
+func (c *Capture) Write(p []byte) (int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.buf.Write(p)
+}
+
+// String get the raw captured output
+func (c *Capture) String() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.buf.String()
+}
+
+// Lines get the raw captured output lines, without the trailing empty line.
+func (c *Capture) Lines() []string {
+	s := strings.TrimSuffix(c.String(), "\n")
+	if s == "" {
+		return nil
+	}
+	return strings.Split(s, "\n")
+}
+
+// Normalized get the captured output, and normalized by the Normalizers
+func (c *Capture) Normalized() string {
+	return Normalize(c.String(), c.Normalizers...)
+}
+
+// Reset clear the captured output
+func (c *Capture) Reset() {
+	c.mu.Lock()
+	c.buf.Reset()
+	c.mu.Unlock()
+}
+
+// AssertGolden compare the normalized output with the golden file. see AssertGolden()
+func (c *Capture) AssertGolden(t testing.TB, name string) bool {
+	t.Helper()
+	return AssertGolden(t, name, c.Normalized())
+}
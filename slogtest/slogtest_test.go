@@ -0,0 +1,84 @@
+package slogtest_test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gookit/goutil/testutil/assert"
+	"github.com/gookit/slog"
+	"github.com/gookit/slog/handler"
+	"github.com/gookit/slog/slogtest"
+)
+
+// record the errors, instead of fail the test
+type fakeTB struct {
+	testing.TB
+	errs []string
+}
+
+func (t *fakeTB) Helper() {}
+
+func (t *fakeTB) Errorf(format string, args ...any) {
+	t.errs = append(t.errs, fmt.Sprintf(format, args...))
+}
+
+func TestNormalize(t *testing.T) {
+	s := slogtest.Normalize("[2023/01/02T15:04:05.123] [/path/to/main.go:23] id=7f2c9a3e-5b4d-4c1a-9e8f-0a1b2c3d4e5f trace=4bf92f3577b34da6a3ce929d0e0e4736")
+	assert.Eq(t, "[<TIME>] [<CALLER>] id=<UUID> trace=<ID>", s)
+
+	s = slogtest.Normalize("at 2023-01-02 15:04:05 id=42", slogtest.NewNormalizer(`id=\d+`, "id=<N>"))
+	assert.Eq(t, "at 2023-01-02 15:04:05 id=<N>", s)
+}
+
+func TestCapture(t *testing.T) {
+	c := slogtest.NewCapture(func(c *slogtest.Capture) {
+		c.Logger.ReportCaller = true
+	})
+
+	c.Logger.Info("order created")
+	c.Logger.WithData(slog.M{"request_id": "4bf92f3577b34da6"}).Warn("stock is low")
+	assert.Len(t, c.Lines(), 2)
+	assert.True(t, c.AssertGolden(t, "capture"))
+
+	c.Reset()
+	assert.Empty(t, c.String())
+	assert.Nil(t, c.Lines())
+}
+
+func TestAssertGolden(t *testing.T) {
+	tb := &fakeTB{TB: t}
+	assert.False(t, slogtest.AssertGolden(tb, "not-exists", "hello"))
+	assert.StrContains(t, tb.errs[0], "SLOGTEST_UPDATE=1")
+
+	tb.errs = nil
+	assert.False(t, slogtest.AssertGolden(tb, "capture", "[<TIME>] [application] [INFO] [<CALLER>] other message  \n"))
+	assert.StrContains(t, tb.errs[0], "line 1:")
+	assert.StrContains(t, tb.errs[0], `got:  "[<TIME>] [application] [INFO] [<CALLER>] other message  "`)
+
+	// update golden file
+	slogtest.GoldenDir = t.TempDir()
+	slogtest.UpdateGolden = true
+	defer func() {
+		slogtest.GoldenDir = "testdata"
+		slogtest.UpdateGolden = false
+	}()
+
+	assert.True(t, slogtest.AssertGolden(t, "new", "contents\n"))
+	bs, err := os.ReadFile(filepath.Join(slogtest.GoldenDir, "new.golden"))
+	assert.NoErr(t, err)
+	assert.Eq(t, "contents\n", string(bs))
+}
+
+func TestTestHandler(t *testing.T) {
+	c := slogtest.NewCapture()
+	slogtest.TestHandler(t, c.Handler)
+	assert.StrContains(t, c.String(), "handle message")
+	assert.Len(t, c.Lines(), len(slog.AllLevels)+8*50)
+
+	slogtest.TestHandler(t, handler.NewRingBufferHandler(100), func(opt *slogtest.HandlerOptions) {
+		opt.Parallel = true
+	})
+	slogtest.TestHandler(t, handler.NewLevelRouter().Route(slog.DangerLevels, c))
+}
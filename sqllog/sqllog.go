@@ -0,0 +1,113 @@
+// Package sqllog provide a sqlhooks compatible hooks, log the queries, args,
+// duration and errors through slog, and support slow-query threshold.
+//
+// Usage with github.com/qustavo/sqlhooks/v2:
+//
+//	sql.Register("mysqlWithHooks", sqlhooks.Wrap(&mysql.MySQLDriver{}, sqllog.New(logger)))
+//	db, err := sql.Open("mysqlWithHooks", dsn)
+package sqllog
+
+import (
+	"context"
+	"time"
+
+	"github.com/gookit/slog"
+)
+
+// RedactedValue for replace the redacted args
+const RedactedValue = "***"
+
+type ctxKey struct{}
+
+// Hooks implements the sqlhooks.Hooks and sqlhooks.OnErrorer
+type Hooks struct {
+	l *slog.Logger
+	// Level for log the queries. default is slog.DebugLevel
+	Level slog.Level
+	// SlowThreshold log the query at SlowLevel on duration >= SlowThreshold. 0 is disable.
+	SlowThreshold time.Duration
+	// SlowLevel for slow queries. default is slog.WarnLevel
+	SlowLevel slog.Level
+	// ErrLevel for failed queries. default is slog.ErrorLevel
+	ErrLevel slog.Level
+	// LogArgs log the query args. default is true
+	LogArgs bool
+	// Redact custom the arg value before logging. return RedactedValue for hide it.
+	//
+	// index is the arg position(start from 0).
+	Redact func(query string, index int, arg any) any
+}
+
+// New create a new Hooks
+func New(l *slog.Logger, fns ...func(h *Hooks)) *Hooks {
+	h := &Hooks{
+		l:         l,
+		Level:     slog.DebugLevel,
+		SlowLevel: slog.WarnLevel,
+		ErrLevel:  slog.ErrorLevel,
+		LogArgs:   true,
+	}
+
+	for _, fn := range fns {
+		fn(h)
+	}
+	return h
+}
+
+// RedactAll redact all args
+func RedactAll(_ string, _ int, _ any) any { return RedactedValue }
+
+// Before hook, will record the query start time.
+func (h *Hooks) Before(ctx context.Context, _ string, _ ...any) (context.Context, error) {
+	return context.WithValue(ctx, ctxKey{}, time.Now()), nil
+}
+
+// After hook, will log the query.
+func (h *Hooks) After(ctx context.Context, query string, args ...any) (context.Context, error) {
+	dur := h.since(ctx)
+
+	level := h.Level
+	fields := h.fields(query, args, dur)
+	if h.SlowThreshold > 0 && dur >= h.SlowThreshold {
+		level = h.SlowLevel
+		fields["slow"] = true
+	}
+
+	h.l.WithFields(fields).WithContext(ctx).Log(level, "sql query")
+	return ctx, nil
+}
+
+// OnError hook, will log the query and error.
+func (h *Hooks) OnError(ctx context.Context, err error, query string, args ...any) error {
+	fields := h.fields(query, args, h.since(ctx))
+	fields["error"] = err.Error()
+
+	h.l.WithFields(fields).WithContext(ctx).Log(h.ErrLevel, "sql query error")
+	return err
+}
+
+func (h *Hooks) since(ctx context.Context) time.Duration {
+	if start, ok := ctx.Value(ctxKey{}).(time.Time); ok {
+		return slog.Since(start)
+	}
+	return 0
+}
+
+func (h *Hooks) fields(query string, args []any, dur time.Duration) slog.M {
+	fields := slog.M{
+		"query":    query,
+		"duration": dur.String(),
+	}
+
+	if h.LogArgs && len(args) > 0 {
+		if h.Redact != nil {
+			vs := make([]any, len(args))
+			for i, arg := range args {
+				vs[i] = h.Redact(query, i, arg)
+			}
+			args = vs
+		}
+		fields["args"] = args
+	}
+	return fields
+}
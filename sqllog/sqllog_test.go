@@ -0,0 +1,57 @@
+package sqllog_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/gookit/goutil/byteutil"
+	"github.com/gookit/goutil/testutil/assert"
+	"github.com/gookit/slog"
+	"github.com/gookit/slog/handler"
+	"github.com/gookit/slog/sqllog"
+)
+
+func TestHooks(t *testing.T) {
+	buf := byteutil.NewBuffer()
+	h := handler.NewIOWriter(buf, slog.AllLevels)
+	h.SetFormatter(slog.NewJSONFormatter())
+
+	hooks := sqllog.New(slog.NewWithHandlers(h), func(h *sqllog.Hooks) {
+		h.SlowThreshold = 5 * time.Millisecond
+		h.Redact = func(query string, index int, arg any) any {
+			if index == 1 {
+				return sqllog.RedactedValue
+			}
+			return arg
+		}
+	})
+
+	query := "SELECT * FROM users WHERE name = ? AND password = ?"
+	ctx, err := hooks.Before(context.Background(), query, "inhere", "secret")
+	assert.NoErr(t, err)
+	_, err = hooks.After(ctx, query, "inhere", "secret")
+	assert.NoErr(t, err)
+
+	str := buf.String()
+	assert.StrContains(t, str, `"level":"DEBUG"`)
+	assert.StrContains(t, str, `"args":["inhere","***"]`)
+	assert.NotContains(t, str, "secret")
+
+	// slow query
+	buf.Reset()
+	ctx, _ = hooks.Before(context.Background(), query)
+	time.Sleep(6 * time.Millisecond)
+	_, _ = hooks.After(ctx, query)
+	assert.StrContains(t, buf.String(), `"level":"WARN"`)
+	assert.StrContains(t, buf.String(), `"slow":true`)
+
+	// on error
+	buf.Reset()
+	ctx, _ = hooks.Before(context.Background(), query)
+	err = hooks.OnError(ctx, errors.New("bad connection"), query)
+	assert.Err(t, err)
+	assert.StrContains(t, buf.String(), `"level":"ERROR"`)
+	assert.StrContains(t, buf.String(), `"error":"bad connection"`)
+}
@@ -3,8 +3,7 @@ package slog
 import (
 	"io"
 	"os"
-
-	"github.com/gookit/color"
+	"sync/atomic"
 )
 
 // SugaredLoggerFn func type.
@@ -12,6 +11,8 @@ type SugaredLoggerFn func(sl *SugaredLogger)
 
 // SugaredLogger Is a fast and usable Logger, which already contains
 // the default formatting and handling capabilities
+//
+// Same as the Logger, please change the options by Config() after the logger is used.
 type SugaredLogger struct {
 	*Logger
 	// Formatter log message formatter. default use TextFormatter
@@ -20,6 +21,19 @@ type SugaredLogger struct {
 	Output io.Writer
 	// Level for log handling. if log record level <= Level, it will be record.
 	Level Level
+	// FlushOnWrite flush the Output after write each record, on it has the Flush() method.
+	FlushOnWrite bool
+
+	// the snapshot of the sugared options for handling. see Config()
+	sugaredOpts atomic.Pointer[sugaredOptions]
+}
+
+// sugaredOptions an immutable snapshot of the SugaredLogger options.
+type sugaredOptions struct {
+	formatter    Formatter
+	output       io.Writer
+	level        Level
+	flushOnWrite bool
 }
 
 // NewStd logger instance, alias of NewStdLogger()
@@ -35,7 +49,7 @@ func NewStdLogger(fns ...SugaredLoggerFn) *SugaredLogger {
 			// sl.CallerSkip += 1
 			sl.ReportCaller = true
 			// auto enable console color
-			sl.Formatter.(*TextFormatter).EnableColor = color.SupportColor()
+			sl.Formatter.(*TextFormatter).EnableColor = ColorEnabled(os.Stdout)
 		},
 	}
 
@@ -52,55 +66,134 @@ func NewSugared(out io.Writer, level Level, fns ...SugaredLoggerFn) *SugaredLogg
 
 // NewSugaredLogger create new SugaredLogger
 func NewSugaredLogger(output io.Writer, level Level, fns ...SugaredLoggerFn) *SugaredLogger {
-	sl := &SugaredLogger{
-		Level:  level,
-		Output: output,
-		Logger: New(),
-		// default value
-		Formatter: NewTextFormatter(),
+	sl := &SugaredLogger{}
+	sl.init(output, level)
+
+	// the options snapshot is taken on first use, so the options can be set directly after create.
+	for _, fn := range fns {
+		fn(sl)
 	}
+	return sl
+}
+
+// NewJSONSugared create new SugaredLogger with JSONFormatter
+func NewJSONSugared(out io.Writer, level Level, fns ...SugaredLoggerFn) *SugaredLogger {
+	return NewSugaredLogger(out, level, append([]SugaredLoggerFn{func(sl *SugaredLogger) {
+		sl.Formatter = NewJSONFormatter()
+	}}, fns...)...)
+}
+
+// init the logger with default options
+func (sl *SugaredLogger) init(output io.Writer, level Level) {
+	sl.Logger = New()
+	sl.Level = level
+	sl.Output = output
+	sl.FlushOnWrite = false
+	// default value
+	sl.Formatter = NewTextFormatter()
+	sl.sugaredOpts.Store(nil)
 
 	// NOTICE: use self as an log handler
 	sl.AddHandler(sl)
+}
+
+// SidecarMode config the SugaredLogger for the sidecar log collectors, eg: Fluent Bit, Vector.
+//
+//   - output newline-delimited JSON to the os.Stdout, one write per record without buffering.
+//     the write blocks on the collector is slow, as the back-pressure instead of dropping records.
+//   - flush on every record, on the Output is replaced by a buffered writer.
+//   - disable the colors and the caller. can be re-enabled by the options after it.
+//
+// Usage:
+//
+//	l := slog.NewStdLogger(slog.SidecarMode)
+//	// or change the std logger
+//	slog.Std().Config(slog.SidecarMode)
+func SidecarMode(sl *SugaredLogger) {
+	sl.Output = os.Stdout
+	sl.FlushOnWrite = true
+	sl.ReportCaller = false
+	sl.Formatter = NewJSONFormatter(func(f *JSONFormatter) {
+		f.Fields = []string{
+			FieldKeyDatetime,
+			FieldKeyChannel,
+			FieldKeyLevel,
+			FieldKeyMessage,
+			FieldKeyData,
+			FieldKeyExtra,
+		}
+	})
+}
+
+// Config current logger. it is safe to change the options concurrently with logging. see Logger.Config()
+func (sl *SugaredLogger) Config(fns ...SugaredLoggerFn) *SugaredLogger {
+	// make sure the snapshot exists, the fns can use it.
+	sl.loadSugaredOpts()
 
-	return sl.Config(fns...)
+	sl.update(func() {
+		for _, fn := range fns {
+			fn(sl)
+		}
+		sl.sugaredOpts.Store(sl.newSugaredOpts())
+	})
+	return sl
 }
 
-// NewJSONSugared create new SugaredLogger with JSONFormatter
-func NewJSONSugared(out io.Writer, level Level, fns ...SugaredLoggerFn) *SugaredLogger {
-	sl := NewSugaredLogger(out, level)
-	sl.Formatter = NewJSONFormatter()
+// load the sugared options snapshot, take it from the option fields on first use.
+func (sl *SugaredLogger) loadSugaredOpts() *sugaredOptions {
+	if opts := sl.sugaredOpts.Load(); opts != nil {
+		return opts
+	}
+
+	sl.cfgMu.Lock()
+	defer sl.cfgMu.Unlock()
 
-	return sl.Config(fns...)
+	opts := sl.sugaredOpts.Load()
+	if opts == nil {
+		opts = sl.newSugaredOpts()
+		sl.sugaredOpts.Store(opts)
+	}
+	return opts
 }
 
-// Config current logger
-func (sl *SugaredLogger) Config(fns ...SugaredLoggerFn) *SugaredLogger {
-	for _, fn := range fns {
-		fn(sl)
+// take a snapshot of the sugared option fields. sl.cfgMu is held.
+func (sl *SugaredLogger) newSugaredOpts() *sugaredOptions {
+	return &sugaredOptions{
+		formatter:    sl.Formatter,
+		output:       sl.Output,
+		level:        sl.Level,
+		flushOnWrite: sl.FlushOnWrite,
 	}
-	return sl
 }
 
 // Reset the logger
 func (sl *SugaredLogger) Reset() {
-	*sl = *NewSugaredLogger(os.Stdout, DebugLevel)
+	sl.init(os.Stdout, DebugLevel)
 }
 
 // IsHandling Check if the current level can be handling
 func (sl *SugaredLogger) IsHandling(level Level) bool {
-	return sl.Level.ShouldHandling(level)
+	return sl.loadSugaredOpts().level.ShouldHandling(level)
 }
 
 // Handle log record
 func (sl *SugaredLogger) Handle(record *Record) error {
-	bts, err := sl.Formatter.Format(record)
+	opts := sl.loadSugaredOpts()
+	bts, err := opts.formatter.Format(record)
 	if err != nil {
 		return err
 	}
 
-	_, err = sl.Output.Write(bts)
-	return err
+	if _, err = opts.output.Write(bts); err != nil {
+		return err
+	}
+
+	if opts.flushOnWrite {
+		if fw, ok := opts.output.(interface{ Flush() error }); ok {
+			return fw.Flush()
+		}
+	}
+	return nil
 }
 
 // Close all log handlers, will flush and close all handlers.
@@ -113,13 +206,13 @@ func (sl *SugaredLogger) Close() error {
 		// TIP: must exclude self, because self is a handler
 		if _, ok := handler.(*SugaredLogger); !ok {
 			if err := handler.Close(); err != nil {
-				sl.err = err
+				sl.errs.add("close", handler, err)
 			}
 		}
 		return nil
 	})
 
-	return sl.err
+	return sl.errs.lastErr()
 }
 
 // Flush all logs. alias of the FlushAll()
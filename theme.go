@@ -0,0 +1,142 @@
+package slog
+
+import (
+	"io"
+	"os"
+	"strings"
+
+	"github.com/gookit/color"
+)
+
+// Theme the color theme for the TextFormatter. see TextFormatter.SetTheme()
+type Theme struct {
+	// Name of the theme
+	Name string
+	// Levels the colors of the levels, for render the level and message
+	Levels map[Level]color.Color
+	// Fields the colors of the other fields. eg: {"datetime": color.Gray}
+	Fields map[string]color.Color
+	// KeyColor the color of the field keys in the template. eg: "level=" in "level={{level}}". 0 is not colored.
+	KeyColor color.Color
+	// PlainMessage don't render the message by the level color
+	PlainMessage bool
+}
+
+// built-in color themes
+var (
+	// ThemeDefault the default theme, render the level and message by the level color
+	ThemeDefault = &Theme{Name: "default", Levels: ColorTheme}
+	// ThemeDark for the dark background terminals
+	ThemeDark = &Theme{
+		Name: "dark",
+		Levels: map[Level]color.Color{
+			PanicLevel:  color.FgLightRed,
+			FatalLevel:  color.FgLightRed,
+			ErrorLevel:  color.FgLightMagenta,
+			WarnLevel:   color.FgLightYellow,
+			NoticeLevel: color.FgLightCyan,
+			InfoLevel:   color.FgLightGreen,
+			DebugLevel:  color.FgLightBlue,
+			TraceLevel:  color.FgGray,
+		},
+		Fields: map[string]color.Color{
+			FieldKeyDatetime: color.FgGray,
+			FieldKeyCaller:   color.FgGray,
+			FieldKeyChannel:  color.FgLightBlue,
+		},
+		KeyColor: color.FgCyan,
+	}
+	// ThemeLight for the light background terminals
+	ThemeLight = &Theme{
+		Name: "light",
+		Levels: map[Level]color.Color{
+			PanicLevel:  color.FgRed,
+			FatalLevel:  color.FgRed,
+			ErrorLevel:  color.FgRed,
+			WarnLevel:   color.FgMagenta,
+			NoticeLevel: color.FgBlue,
+			InfoLevel:   color.FgGreen,
+			DebugLevel:  color.FgCyan,
+			TraceLevel:  color.FgDarkGray,
+		},
+		Fields: map[string]color.Color{
+			FieldKeyDatetime: color.FgDarkGray,
+			FieldKeyCaller:   color.FgDarkGray,
+		},
+		KeyColor:     color.FgBlue,
+		PlainMessage: true,
+	}
+	// ThemeMinimal only render the level by color, and dim the datetime
+	ThemeMinimal = &Theme{
+		Name:         "minimal",
+		Levels:       ColorTheme,
+		Fields:       map[string]color.Color{FieldKeyDatetime: color.OpFuzzy},
+		PlainMessage: true,
+	}
+)
+
+// Themes the built-in themes, can be used for select the theme by name. eg: from config
+var Themes = map[string]*Theme{
+	ThemeDefault.Name: ThemeDefault,
+	ThemeDark.Name:    ThemeDark,
+	ThemeLight.Name:   ThemeLight,
+	ThemeMinimal.Name: ThemeMinimal,
+}
+
+// ColorEnabled check the color can be enabled on the writer:
+// the ENV NO_COLOR is empty, the writer is a terminal and it supports color.
+//
+// Usage:
+//
+//	f.EnableColor = slog.ColorEnabled(os.Stdout)
+func ColorEnabled(w io.Writer) bool {
+	if os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+
+	fw, ok := w.(interface{ Fd() uintptr })
+	if !ok || !color.IsTerminal(fw.Fd()) {
+		return false
+	}
+	return color.SupportColor()
+}
+
+// SetTheme set the color theme, will enable the color on the theme is not nil.
+//
+// Usage:
+//
+//	f.SetTheme(slog.ThemeDark)
+func (f *TextFormatter) SetTheme(t *Theme) *TextFormatter {
+	if t == nil {
+		f.EnableColor = false
+		return f
+	}
+
+	f.EnableColor = true
+	f.ColorTheme = t.Levels
+	f.FieldColors = t.Fields
+	f.KeyColor = t.KeyColor
+	f.PlainMessage = t.PlainMessage
+	return f
+}
+
+// render the field key before "=" at end of the text. eg: " level=" => " \x1b[36mlevel\x1b[0m="
+func (f *TextFormatter) renderKeyColor(text string) string {
+	if !strings.HasSuffix(text, "=") {
+		return text
+	}
+
+	end := len(text) - 1
+	i := end
+	for i > 0 && isKeyChar(text[i-1]) {
+		i--
+	}
+	if i == end {
+		return text
+	}
+	return text[:i] + f.KeyColor.Render(text[i:end]) + "="
+}
+
+func isKeyChar(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '_' || c == '.' || c == '-'
+}
@@ -0,0 +1,58 @@
+package slog_test
+
+import (
+	"bytes"
+	"os"
+	"testing"
+
+	"github.com/gookit/color"
+	"github.com/gookit/goutil/testutil"
+	"github.com/gookit/goutil/testutil/assert"
+	"github.com/gookit/slog"
+)
+
+func TestTextFormatter_SetTheme(t *testing.T) {
+	r := newLogRecord("theme message")
+	r.Level = slog.WarnLevel
+	r.Init(false)
+
+	f := slog.NewTextFormatter("{{datetime}} level={{level:-5}} [file={{caller}}] {{message}}\n")
+	f.TimeFormat = "15:04"
+	f.SetTheme(slog.ThemeDark)
+	assert.True(t, f.EnableColor)
+
+	bs, err := f.Format(r)
+	assert.NoErr(t, err)
+	want := color.FgGray.Render(r.Time.Format("15:04")) + " " +
+		color.FgCyan.Render("level") + "=" + color.FgLightYellow.Render("WARN ") +
+		" [" + color.FgCyan.Render("file") + "=" + color.FgGray.Render("caller") + "] " +
+		color.FgLightYellow.Render("theme message") + "\n"
+	assert.Eq(t, want, string(bs))
+
+	// plain message
+	f.SetTheme(slog.ThemeMinimal)
+	f.SetTemplate("{{level}} {{message}}")
+	bs, err = f.Format(r)
+	assert.NoErr(t, err)
+	assert.Eq(t, color.FgYellow.Render("WARN")+" theme message", string(bs))
+
+	// select by name
+	f.SetTheme(slog.Themes["default"])
+	bs, err = f.Format(r)
+	assert.NoErr(t, err)
+	assert.Eq(t, color.FgYellow.Render("WARN")+" "+color.FgYellow.Render("theme message"), string(bs))
+
+	// disable color
+	f.SetTheme(nil)
+	bs, err = f.Format(r)
+	assert.NoErr(t, err)
+	assert.Eq(t, "WARN theme message", string(bs))
+}
+
+func TestColorEnabled(t *testing.T) {
+	assert.False(t, slog.ColorEnabled(new(bytes.Buffer)))
+
+	testutil.MockEnvValue("NO_COLOR", "1", func(_ string) {
+		assert.False(t, slog.ColorEnabled(os.Stdout))
+	})
+}
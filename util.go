@@ -1,12 +1,13 @@
 package slog
 
 import (
-	"fmt"
-	"os"
 	"path/filepath"
+	"reflect"
 	"runtime"
+	"runtime/debug"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/gookit/goutil/byteutil"
 	"github.com/gookit/goutil/strutil"
@@ -18,10 +19,83 @@ import (
 // 	defaultKnownSlogFrames int = 4
 // )
 
-// Stack that attempts to recover the data for all goroutines.
-// func getCallStacks(callerSkip int) []byte {
-// 	return nil
-// }
+// DefaultStackDepth default max frames of the stack trace. see Logger.StackDepth
+const DefaultStackDepth = 32
+
+// getCallStack get the formatted stack trace, the leading frames in the skip packages will be skipped.
+//
+// Format of each frame:
+//
+//	github.com/my/app.(*Service).Get
+//		/work/app/service.go:48
+func getCallStack(callerSkip, depth int, skipPkgs []string) string {
+	if depth <= 0 {
+		depth = DefaultStackDepth
+	}
+
+	size := depth
+	if len(skipPkgs) > 0 {
+		size += maxCallerDepth
+	}
+
+	pcs := make([]uintptr, size)
+	num := runtime.Callers(callerSkip, pcs)
+	if num < 1 {
+		return ""
+	}
+
+	var sb strings.Builder
+	var n int
+	frames := runtime.CallersFrames(pcs[:num])
+	for n < depth {
+		f, more := frames.Next()
+		if n > 0 || !inPackages(funcPackage(f.Function), skipPkgs) {
+			writeStackFrame(&sb, &f)
+			n++
+		}
+		if !more {
+			break
+		}
+	}
+	return sb.String()
+}
+
+func writeStackFrame(sb *strings.Builder, f *runtime.Frame) {
+	if sb.Len() > 0 {
+		sb.WriteByte('\n')
+	}
+	sb.WriteString(f.Function)
+	sb.WriteString("\n\t")
+	sb.WriteString(f.File)
+	sb.WriteByte(':')
+	sb.WriteString(strconv.Itoa(f.Line))
+}
+
+// errorCauses unwrap the error to the causes messages, supports the Unwrap() []error
+func errorCauses(err error) []string {
+	var causes []string
+	for {
+		switch x := err.(type) {
+		case interface{ Unwrap() error }:
+			err = x.Unwrap()
+		case interface{ Unwrap() []error }:
+			for _, e := range x.Unwrap() {
+				if e != nil {
+					causes = append(causes, e.Error())
+					causes = append(causes, errorCauses(e)...)
+				}
+			}
+			return causes
+		default:
+			return causes
+		}
+
+		if err == nil {
+			return causes
+		}
+		causes = append(causes, err.Error())
+	}
+}
 
 func buildLowerLevelName() map[Level]string {
 	mp := make(map[Level]string, len(LevelNames))
@@ -43,6 +117,78 @@ func getCaller(callerSkip int) (fr runtime.Frame, ok bool) {
 	return f, f.PC != 0
 }
 
+// maxCallerDepth max frames for find the caller on set Logger.CallerSkipPackages
+const maxCallerDepth = 16
+
+// getCallerSkipPkgs retrieves the first calling function that not in the skip packages
+func getCallerSkipPkgs(callerSkip int, skipPkgs []string) (fr runtime.Frame, ok bool) {
+	pcs := make([]uintptr, maxCallerDepth)
+	num := runtime.Callers(callerSkip, pcs)
+	if num < 1 {
+		return
+	}
+
+	frames := runtime.CallersFrames(pcs[:num])
+	for {
+		f, more := frames.Next()
+		if !inPackages(funcPackage(f.Function), skipPkgs) {
+			return f, f.PC != 0
+		}
+		if !more {
+			return
+		}
+	}
+}
+
+func inPackages(pkg string, pkgs []string) bool {
+	for _, p := range pkgs {
+		if p == pkg {
+			return true
+		}
+	}
+	return false
+}
+
+// get the package path from the func name. eg: "github.com/gookit/slog.(*Logger).Info" => "github.com/gookit/slog"
+func funcPackage(fn string) string {
+	i := strings.LastIndexByte(fn, '/')
+	if j := strings.IndexByte(fn[i+1:], '.'); j >= 0 {
+		return fn[:i+1+j]
+	}
+	return fn
+}
+
+// CallerModulePath the module path for report relative filepath of the caller. see CallerFlagRelPath
+//
+// default is the main module path of the binary, read from the build info.
+var CallerModulePath = mainModulePath()
+
+func mainModulePath() string {
+	if bi, ok := debug.ReadBuildInfo(); ok {
+		return bi.Main.Path
+	}
+	return ""
+}
+
+// get the filepath relative to the module root. eg: "handler/writer.go"
+//
+// use the package path on the caller is not in the module. eg: "github.com/gookit/goutil/strutil/check.go"
+func callerRelPath(rf *runtime.Frame) string {
+	pkg := strings.TrimSuffix(funcPackage(rf.Function), "_test")
+	file := filepath.Base(rf.File)
+
+	mod := CallerModulePath
+	if mod != "" && strings.HasPrefix(pkg, mod) {
+		if pkg == mod {
+			return file
+		}
+		if pkg[len(mod)] == '/' {
+			return pkg[len(mod)+1:] + "/" + file
+		}
+	}
+	return pkg + "/" + file
+}
+
 func formatCaller(rf *runtime.Frame, flag uint8) (cs string) {
 	lineNum := strconv.FormatInt(int64(rf.Line), 10)
 	switch flag {
@@ -68,6 +214,10 @@ func formatCaller(rf *runtime.Frame, flag uint8) (cs string) {
 	case CallerFlagFcName:
 		ss := strings.Split(rf.Function, ".")
 		return ss[len(ss)-1]
+	case CallerFlagRelPath:
+		return callerRelPath(rf) + ":" + lineNum
+	case CallerFlagPkgFile:
+		return filepath.Base(filepath.Dir(rf.File)) + "/" + filepath.Base(rf.File) + ":" + lineNum
 	default: // CallerFlagFpLine
 		return rf.File + ":" + lineNum
 	}
@@ -104,6 +254,17 @@ func formatArgsWithSpaces(vs []any) string {
 	// return byteutil.String(bb.B) // perf: Reduce one memory allocation
 }
 
+// Since get the elapsed duration from start, it will never be negative.
+//
+// If the start is from time.Now(), the monotonic clock reading is used,
+// so the wall clock adjustments(eg: NTP steps) don't affect the result.
+func Since(start time.Time) time.Duration {
+	if dur := time.Since(start); dur > 0 {
+		return dur
+	}
+	return 0
+}
+
 // EncodeToString data to string
 func EncodeToString(v any) string {
 	if mp, ok := v.(map[string]any); ok {
@@ -136,26 +297,103 @@ func mapToString(mp map[string]any) string {
 	return strutil.Byte2str(buf)
 }
 
-func parseTemplateToFields(tplStr string) []string {
-	ss := strings.Split(tplStr, "{{")
+// tplNode a parsed node of the text template
+type tplNode struct {
+	// text the literal text, on the field is empty
+	text string
+	// field name. eg: "level", "fields.user_id"
+	field string
+	// verb the fmt verb for pad the field value. eg: "%-7s"
+	verb string
+	// section the nodes in the conditional section, render them on the field is not empty.
+	// eg: {{#data}} [{{data}}]{{/data}}
+	section []*tplNode
+	isSec   bool
+}
+
+// parseTemplate parse the text template to nodes. syntax:
+//
+//   - {{field}} render the field value
+//   - {{field:-7s}} render the field value with the fmt verb, the "s" can be omitted.
+//   - {{#field}} ... {{/field}} the conditional section, only render it on the field is not empty.
+func parseTemplate(tplStr string) []*tplNode {
+	var nodes []*tplNode
+	var stack []*tplNode // the opened sections
 
-	vars := make([]string, 0, len(ss)*2)
-	for _, s := range ss {
-		if len(s) == 0 {
-			continue
+	add := func(nd *tplNode) {
+		if n := len(stack); n > 0 {
+			stack[n-1].section = append(stack[n-1].section, nd)
+		} else {
+			nodes = append(nodes, nd)
 		}
+	}
 
-		fieldAndOther := strings.SplitN(s, "}}", 2)
-		if len(fieldAndOther) < 2 {
-			vars = append(vars, s)
-		} else {
-			vars = append(vars, fieldAndOther[0], "}}"+fieldAndOther[1])
+	for tplStr != "" {
+		i := strings.Index(tplStr, "{{")
+		if i < 0 {
+			add(&tplNode{text: tplStr})
+			break
+		}
+
+		j := strings.Index(tplStr[i+2:], "}}")
+		if j < 0 {
+			add(&tplNode{text: tplStr})
+			break
+		}
+
+		if i > 0 {
+			add(&tplNode{text: tplStr[:i]})
+		}
+		tag := strings.TrimSpace(tplStr[i+2 : i+2+j])
+		tplStr = tplStr[i+4+j:]
+
+		switch {
+		case tag == "":
+			continue
+		case tag[0] == '#':
+			sec := &tplNode{field: tag[1:], isSec: true}
+			add(sec)
+			stack = append(stack, sec)
+		case tag[0] == '/':
+			if len(stack) > 0 {
+				stack = stack[:len(stack)-1]
+			}
+		default:
+			name, spec, _ := strings.Cut(tag, ":")
+			nd := &tplNode{field: name}
+			if spec != "" {
+				if c := spec[len(spec)-1]; c < 'a' || c > 'z' {
+					spec += "s"
+				}
+				nd.verb = "%" + spec
+			}
+			add(nd)
 		}
 	}
+	return nodes
+}
 
-	return vars
+// lookup the value by the path in the map. eg: "sub.key"
+func lookupPath(mp map[string]any, path string) (any, bool) {
+	if v, ok := mp[path]; ok {
+		return v, true
+	}
+
+	key, sub, found := strings.Cut(path, ".")
+	if !found {
+		return nil, false
+	}
+
+	switch tv := mp[key].(type) {
+	case map[string]any:
+		return lookupPath(tv, sub)
+	case M:
+		return lookupPath(tv, sub)
+	}
+	return nil, false
 }
 
-func printlnStderr(args ...any) {
-	_, _ = fmt.Fprintln(os.Stderr, args...)
+// check the value is empty, like the JSON omitempty
+func isEmptyAny(v any) bool {
+	return v == nil || isEmptyValue(reflect.ValueOf(v))
 }
@@ -1,53 +1,108 @@
 package slog
 
 import (
+	"runtime"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/gookit/goutil/errorx"
 	"github.com/gookit/goutil/testutil/assert"
 	"github.com/gookit/goutil/timex"
 )
 
-func revertTemplateString(ss []string) string {
+func revertTemplateString(nodes []*tplNode) string {
 	var sb strings.Builder
-	for _, s := range ss {
-		// is field
-		if s[0] >= 'a' && s[0] <= 'z' {
-			sb.WriteString("{{")
-			sb.WriteString(s)
-			// sb.WriteString("}}")
-		} else {
-			sb.WriteString(s)
+	for _, nd := range nodes {
+		switch {
+		case nd.field == "":
+			sb.WriteString(nd.text)
+		case nd.isSec:
+			sb.WriteString("{{#" + nd.field + "}}")
+			sb.WriteString(revertTemplateString(nd.section))
+			sb.WriteString("{{/" + nd.field + "}}")
+		case nd.verb != "":
+			sb.WriteString("{{" + nd.field + ":" + nd.verb[1:] + "}}")
+		default:
+			sb.WriteString("{{" + nd.field + "}}")
 		}
 	}
-
-	// sb.WriteByte('\n')
 	return sb.String()
 }
 
-func TestInner_parseTemplateToFields(t *testing.T) {
-	ss := parseTemplateToFields(NamedTemplate)
-	str := revertTemplateString(ss)
-	// dump.P(ss, str)
-	assert.Eq(t, NamedTemplate, str)
-
-	ss = parseTemplateToFields(DefaultTemplate)
-	str = revertTemplateString(ss)
-	// dump.P(ss, str)
-	assert.Eq(t, DefaultTemplate, str)
-
-	testTemplate := "[{{datetime}}] [{{level}}] {{message}} {{data}} {{extra}}"
-	ss = parseTemplateToFields(testTemplate)
-	str = revertTemplateString(ss)
-	assert.Eq(t, testTemplate, str)
-	// dump.P(ss, str)
+func TestInner_parseTemplate(t *testing.T) {
+	nodes := parseTemplate(NamedTemplate)
+	assert.Eq(t, NamedTemplate, revertTemplateString(nodes))
+
+	nodes = parseTemplate(DefaultTemplate)
+	assert.Eq(t, DefaultTemplate, revertTemplateString(nodes))
+
+	testTemplate := "[{{datetime}}] [{{level:-7s}}] {{message}}{{#data}} {{data}}{{/data}}{{#extra}} [{{extra}}{{#tags}} {{tags}}{{/tags}}]{{/extra}}"
+	nodes = parseTemplate(testTemplate)
+	assert.Eq(t, testTemplate, revertTemplateString(nodes))
+	assert.Len(t, nodes, 8)
+	assert.Eq(t, "%-7s", nodes[3].verb)
+	assert.True(t, nodes[7].isSec)
+	assert.Len(t, nodes[7].section, 4)
+
+	// verb without "s", unclosed tag and section
+	nodes = parseTemplate("{{level:5}} {{#data}}{{data}} {{message")
+	assert.Eq(t, "%5s", nodes[0].verb)
+	assert.Eq(t, "{{level:5s}} {{#data}}{{data}} {{message{{/data}}", revertTemplateString(nodes))
 }
 
 func TestUtil_EncodeToString(t *testing.T) {
 	assert.Eq(t, "{a:1}", EncodeToString(map[string]any{"a": 1}))
 }
 
+func TestUtil_Since(t *testing.T) {
+	start := time.Now()
+	assert.True(t, Since(start) >= 0)
+	// the start is after now, eg: wall clock steps back
+	assert.Eq(t, time.Duration(0), Since(time.Now().Round(0).Add(time.Hour)))
+	assert.Gt(t, int64(Since(start.Add(-time.Second))), int64(time.Second-1))
+}
+
+func TestUtil_formatCaller(t *testing.T) {
+	rf := &runtime.Frame{
+		Function: "github.com/gookit/slog/handler.(*IOWriterHandler).Handle",
+		File:     "/work/go/gookit/slog/handler/writer.go",
+		Line:     48,
+	}
+
+	assert.Eq(t, "handler/writer.go:48", formatCaller(rf, CallerFlagRelPath))
+	assert.Eq(t, "handler/writer.go:48", formatCaller(rf, CallerFlagPkgFile))
+	assert.Eq(t, "Handle", formatCaller(rf, CallerFlagFcName))
+	assert.Eq(t, "/work/go/gookit/slog/handler/writer.go:48", formatCaller(rf, CallerFlagFpLine))
+
+	// test package, and the package not in the module
+	rf.Function = "github.com/gookit/slog_test.TestLogger_ReportCaller"
+	rf.File = "/work/go/gookit/slog/logger_test.go"
+	assert.Eq(t, "logger_test.go:48", formatCaller(rf, CallerFlagRelPath))
+	assert.Eq(t, "slog/logger_test.go:48", formatCaller(rf, CallerFlagPkgFile))
+
+	rf.Function = "github.com/gookit/goutil/strutil.SafeString"
+	rf.File = "/go/pkg/mod/github.com/gookit/goutil@v0.6.0/strutil/convert.go"
+	assert.Eq(t, "github.com/gookit/goutil/strutil/convert.go:48", formatCaller(rf, CallerFlagRelPath))
+}
+
+func TestUtil_getCallerSkipPkgs(t *testing.T) {
+	assert.Eq(t, "github.com/gookit/slog", funcPackage("github.com/gookit/slog.(*Logger).Info"))
+	assert.Eq(t, "testing", funcPackage("testing.tRunner"))
+
+	var fr runtime.Frame
+	func() {
+		fr, _ = getCallerSkipPkgs(2, []string{"runtime"})
+	}()
+	assert.StrContains(t, fr.Function, "TestUtil_getCallerSkipPkgs")
+
+	// skip all frames in the slog package
+	func() {
+		fr, _ = getCallerSkipPkgs(2, []string{"github.com/gookit/slog"})
+	}()
+	assert.Eq(t, "testing.tRunner", fr.Function)
+}
+
 func TestUtil_formatArgsWithSpaces(t *testing.T) {
 	// tests for formatArgsWithSpaces
 	tests := []struct {